@@ -0,0 +1,230 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"os"
+	"sync"
+)
+
+// AdminAddrEnv 覆盖 admin API 监听地址, 未设置时只监听 127.0.0.1, 不对外
+// 网络暴露, 操作员需要在部署机器本地或者通过 SSH 端口转发访问。
+const AdminAddrEnv = "BOT_ADMIN_ADDR"
+
+func adminAddr() string {
+	if addr := os.Getenv(AdminAddrEnv); addr != "" {
+		return addr
+	}
+	return "127.0.0.1:9191"
+}
+
+var (
+	pausedMarketsMu sync.Mutex
+	pausedMarkets   = make(map[string]bool)
+)
+
+// IsMarketPaused 报告 mktKey (marketKey 的输出格式) 当前是否被 admin API
+// 暂停, RunBot 每轮处理市场前检查, 暂停的市场跳过 ProcessMarket/
+// ProcessArbitrage, 直到被 resume。
+func IsMarketPaused(mktKey string) bool {
+	pausedMarketsMu.Lock()
+	defer pausedMarketsMu.Unlock()
+	return pausedMarkets[mktKey]
+}
+
+// PauseMarket/ResumeMarket 由 admin API 调用, 不会撤销已经挂出去的单, 只
+// 阻止 RunBot 在暂停期间继续为该市场挂新单。
+func PauseMarket(mktKey string) {
+	pausedMarketsMu.Lock()
+	defer pausedMarketsMu.Unlock()
+	pausedMarkets[mktKey] = true
+}
+
+func ResumeMarket(mktKey string) {
+	pausedMarketsMu.Lock()
+	defer pausedMarketsMu.Unlock()
+	delete(pausedMarkets, mktKey)
+}
+
+func pausedMarketsSnapshot() []string {
+	pausedMarketsMu.Lock()
+	defer pausedMarketsMu.Unlock()
+	out := make([]string, 0, len(pausedMarkets))
+	for k := range pausedMarkets {
+		out = append(out, k)
+	}
+	return out
+}
+
+// adminStateDump 是 GET /admin/state 返回的内容, 汇总 orderManager、做市
+// 奖励统计、暂停中的市场和当前生效的策略参数, 供操作员不重启进程就能看
+// 到内部状态排查问题。
+type adminStateDump struct {
+	Orders           []ManagedOrder           `json:"orders"`
+	Rewards          []MarketEpochRewardEntry `json:"rewards"`
+	PausedMarkets    []string                 `json:"paused_markets"`
+	TopicProfiles    map[string]QuoteProfile  `json:"topic_profiles"`
+	MarketFeeRateBps map[int16]int64          `json:"market_fee_rate_bps"`
+	BudgetRun        spendTotals              `json:"budget_run"`
+	BudgetDaily      []DailySpendEntry        `json:"budget_daily"`
+	ErrorCounts      map[string]int           `json:"error_counts"`
+}
+
+// paramUpdateRequest 是 POST /admin/param 的请求体, 目前只支持整体替换某
+// 个 topic 的 QuoteProfile, 跟配置文件热更新 (config.go) 复用同一套
+// SetTopicQuoteProfiles/审计日志逻辑, 避免维护两套参数更新路径。
+type paramUpdateRequest struct {
+	Topic   string       `json:"topic"`
+	Profile QuoteProfile `json:"profile"`
+}
+
+// requireLoopback 是 admin API 端点的通用中间件, 即使 BOT_ADMIN_ADDR 被
+// 误配置成监听非 loopback 地址, 也拒绝非本机来源的请求, 双重保险不让下
+// 单/撤单能力意外暴露到公网。
+func requireLoopback(handler http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		host, _, err := net.SplitHostPort(r.RemoteAddr)
+		if err != nil {
+			host = r.RemoteAddr
+		}
+		ip := net.ParseIP(host)
+		if ip == nil || !ip.IsLoopback() {
+			http.Error(w, "admin API only accepts loopback requests", http.StatusForbidden)
+			return
+		}
+		handler(w, r)
+	}
+}
+
+// NewAdminMux 构造 admin API 的路由, creds 用于 /admin/cancel-all 触发两个
+// 账户的全部撤单。
+func NewAdminMux(creds *Credentials) *http.ServeMux {
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("/admin/orders", requireLoopback(func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, orderManager.Snapshot())
+	}))
+
+	mux.HandleFunc("/admin/rewards", requireLoopback(func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, makerRewards.RewardSnapshot())
+	}))
+
+	mux.HandleFunc("/admin/state", requireLoopback(func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, adminStateDump{
+			Orders:           orderManager.Snapshot(),
+			Rewards:          makerRewards.RewardSnapshot(),
+			PausedMarkets:    pausedMarketsSnapshot(),
+			TopicProfiles:    TopicQuoteProfilesSnapshot(),
+			MarketFeeRateBps: MarketFeeRateBpsSnapshot(),
+			BudgetRun:        spendBudget.RunSnapshot(),
+			BudgetDaily:      spendBudget.DailySnapshot(),
+			ErrorCounts:      ErrorCountsSnapshot(),
+		})
+	}))
+
+	mux.HandleFunc("/admin/errors", requireLoopback(func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, ErrorCountsSnapshot())
+	}))
+
+	mux.HandleFunc("/admin/budget", requireLoopback(func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, struct {
+			Run     spendTotals       `json:"run"`
+			Daily   []DailySpendEntry `json:"daily"`
+			CapUSDC float64           `json:"daily_cap_usdc"`
+		}{
+			Run:     spendBudget.RunSnapshot(),
+			Daily:   spendBudget.DailySnapshot(),
+			CapUSDC: dailySpendCapUSDC(),
+		})
+	}))
+
+	mux.HandleFunc("/admin/pause", requireLoopback(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Error(w, "POST required", http.StatusMethodNotAllowed)
+			return
+		}
+		mktKey := r.URL.Query().Get("market")
+		if mktKey == "" {
+			http.Error(w, "missing market query param", http.StatusBadRequest)
+			return
+		}
+		PauseMarket(mktKey)
+		log.Printf("admin: market %s paused", mktKey)
+		fmt.Fprintf(w, "paused %s\n", mktKey)
+	}))
+
+	mux.HandleFunc("/admin/resume", requireLoopback(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Error(w, "POST required", http.StatusMethodNotAllowed)
+			return
+		}
+		mktKey := r.URL.Query().Get("market")
+		if mktKey == "" {
+			http.Error(w, "missing market query param", http.StatusBadRequest)
+			return
+		}
+		ResumeMarket(mktKey)
+		log.Printf("admin: market %s resumed", mktKey)
+		fmt.Fprintf(w, "resumed %s\n", mktKey)
+	}))
+
+	mux.HandleFunc("/admin/param", requireLoopback(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Error(w, "POST required", http.StatusMethodNotAllowed)
+			return
+		}
+		var req paramUpdateRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, fmt.Sprintf("decode request: %v", err), http.StatusBadRequest)
+			return
+		}
+		if req.Topic == "" {
+			http.Error(w, "missing topic", http.StatusBadRequest)
+			return
+		}
+
+		profiles := TopicQuoteProfilesSnapshot()
+		before := profiles[req.Topic]
+		profiles[req.Topic] = req.Profile
+		SetTopicQuoteProfiles(profiles)
+		log.Printf("config reload: topic_profiles[%s] changed from %+v to %+v via admin API", req.Topic, before, req.Profile)
+
+		fmt.Fprintf(w, "updated topic %s\n", req.Topic)
+	}))
+
+	mux.HandleFunc("/admin/cancel-all", requireLoopback(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Error(w, "POST required", http.StatusMethodNotAllowed)
+			return
+		}
+		log.Printf("admin: cancel-all triggered")
+		cancelAllAccounts(creds)
+		fmt.Fprintln(w, "cancel-all triggered")
+	}))
+
+	return mux
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		log.Printf("admin: encode response failed: %v", err)
+	}
+}
+
+// StartAdminServer 在后台起一个只监听 loopback 的 HTTP server, 供操作员在
+// 不重启进程的情况下查看内部状态、暂停/恢复市场、调整策略参数、触发
+// 全部撤单。监听失败只打日志, 不影响主策略循环。
+func StartAdminServer(creds *Credentials) {
+	addr := adminAddr()
+	server := &http.Server{Addr: addr, Handler: NewAdminMux(creds)}
+	go func() {
+		log.Printf("admin API listening on %s", addr)
+		if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			log.Printf("admin API server failed: %v", err)
+		}
+	}()
+}
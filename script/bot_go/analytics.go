@@ -0,0 +1,141 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"tracing"
+)
+
+// MarketAnalytics 是 /analytics/market 接口返回的单个市场当日汇总, 交易所
+// 目前还没有暴露这个接口 (跟 client.go 里的 /orders、/trades、/events 一样
+// 是先按预期形状实现), 接口上线后如果字段命名不同只需要调整这个 struct
+// 和下面的解析。
+type MarketAnalytics struct {
+	EventID          int64  `json:"event_id"`
+	MarketID         int16  `json:"market_id"`
+	Title            string `json:"title"`
+	VolumeUSDC       string `json:"volume_usdc"`
+	OpenInterestUSDC string `json:"open_interest_usdc"`
+	UniqueTraders    int64  `json:"unique_traders"`
+}
+
+// GetMarketAnalytics 拉取一个市场的当日汇总数据, 走跟 fetchListPage 一样的
+// 认证 + tracing + debug 记录流程, 只是 /analytics/market 不分页, 直接
+// 解析成单个对象。
+func GetMarketAnalytics(auth Authenticator, eventID int64, marketID int16) (*MarketAnalytics, error) {
+	path := "/analytics/market"
+	url := fmt.Sprintf("%s%s?event_id=%d&market_id=%d", APIBaseURL, path, eventID, marketID)
+
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+	auth.Authenticate(req)
+	if tc, err := tracing.New(); err != nil {
+		log.Printf("generate trace context failed: %v", err)
+	} else {
+		tc.InjectHeader(req.Header)
+	}
+
+	client, err := newMarketAPIClient()
+	if err != nil {
+		return nil, fmt.Errorf("build market api client: %w", err)
+	}
+
+	start := time.Now()
+	resp, err := client.Do(req)
+	if err != nil {
+		recordDebugEntry(path, nil, nil, err, time.Since(start))
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		recordDebugEntry(path, nil, nil, err, time.Since(start))
+		return nil, err
+	}
+
+	analytics, err := decodeEnvelope[MarketAnalytics](resp.StatusCode, body)
+	if err != nil {
+		recordDebugEntry(path, nil, body, err, time.Since(start))
+		return nil, err
+	}
+
+	recordDebugEntry(path, nil, body, nil, time.Since(start))
+	return &analytics, nil
+}
+
+// printAnalyticsTable 按 events.go 里 printEventsTable 的风格渲染一份
+// 逐市场的当日汇总。
+func printAnalyticsTable(rows []*MarketAnalytics) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "EVENT ID\tMARKET ID\tTITLE\tVOLUME USDC\tOPEN INTEREST USDC\tUNIQUE TRADERS")
+	for _, a := range rows {
+		fmt.Fprintf(w, "%d\t%d\t%s\t%s\t%s\t%d\n",
+			a.EventID, a.MarketID, a.Title, a.VolumeUSDC, a.OpenInterestUSDC, a.UniqueTraders)
+	}
+	w.Flush()
+}
+
+// runReportCLI 渲染当前每个报价市场的当日汇总, 用法:
+//
+//	bot_go report [account1|account2]
+//
+// 跟 events.go 的 events list 不同, 市场列表也走 Events(auth, opts) 分页
+// 接口而不是查数据库, 这样业务方拿这个命令就够用, 不需要数据库权限。
+// 分析接口需要认证, 用哪个账户的 API Key 只影响鉴权、不影响返回内容,
+// 未指定时默认用 account1。
+func runReportCLI(args []string) {
+	account := "account1"
+	if len(args) > 0 {
+		account = args[0]
+	}
+
+	ctx := context.Background()
+	creds, err := loadCredentials(ctx)
+	if err != nil {
+		log.Fatalf("load credentials: %v", err)
+	}
+
+	var apiKey string
+	switch account {
+	case "account1":
+		apiKey = creds.Account1ApiKey
+	case "account2":
+		apiKey = creds.Account2ApiKey
+	default:
+		fmt.Println("Usage: bot_go report [account1|account2]")
+		os.Exit(1)
+	}
+	auth := APIKeyAuth{APIKey: apiKey}
+
+	events := Events(auth, ListOptions{})
+	var rows []*MarketAnalytics
+	for events.Next() {
+		event := events.Item()
+		for _, marketID := range event.MarketIDs {
+			analytics, err := GetMarketAnalytics(auth, event.EventID, marketID)
+			if err != nil {
+				log.Printf("get analytics for market %s failed: %v", marketKey(event.EventID, marketID), err)
+				continue
+			}
+			if analytics.Title == "" {
+				analytics.Title = event.Title
+			}
+			rows = append(rows, analytics)
+		}
+	}
+	if err := events.Err(); err != nil {
+		log.Fatalf("list events: %v", err)
+	}
+
+	printAnalyticsTable(rows)
+}
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"log"
+)
+
+// MarketAssignment 是 market_maker_assignments 表里一条活跃记录, 决定
+// mktKey (marketKey 的输出格式) 应该由哪个账户报价, Params 是该账户在这个
+// 市场上的自定义参数 (目前原样透出, 具体字段由调用方按需解析, 比如覆盖
+// QuoteProfile 的某几项), 不强制要求所有部署都填。
+type MarketAssignment struct {
+	AccountKey string          `json:"account_key"`
+	EventID    int64           `json:"event_id"`
+	MarketID   int16           `json:"market_id"`
+	Params     json.RawMessage `json:"params"`
+}
+
+// LoadMarketAssignments 读取全部活跃的市场分配, 按 marketKey 建索引。表上
+// 的 idx_market_maker_assignments_active_market 部分唯一索引已经保证同一
+// 时刻一个市场最多一条活跃记录, 这里按 marketKey 建 map 时如果发现重复
+// (理论上不该出现, 除非索引被绕过或者迁移之间有竞态) 就地告警, 而不是
+// 静默让后一条覆盖前一条。
+func LoadMarketAssignments(db *sql.DB) (map[string]MarketAssignment, error) {
+	rows, err := db.Query(`
+		SELECT account_key, event_id, market_id, params
+		FROM market_maker_assignments
+		WHERE active
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("query market maker assignments: %w", err)
+	}
+	defer rows.Close()
+
+	assignments := make(map[string]MarketAssignment)
+	for rows.Next() {
+		var a MarketAssignment
+		var params []byte
+		if err := rows.Scan(&a.AccountKey, &a.EventID, &a.MarketID, &params); err != nil {
+			return nil, fmt.Errorf("scan market maker assignment: %w", err)
+		}
+		a.Params = params
+
+		key := marketKey(a.EventID, a.MarketID)
+		if existing, ok := assignments[key]; ok {
+			log.Printf("⚠️ market %s has conflicting active assignments (%s and %s), keeping %s",
+				key, existing.AccountKey, a.AccountKey, existing.AccountKey)
+			continue
+		}
+		assignments[key] = a
+	}
+	return assignments, rows.Err()
+}
+
+// AssignedAccount 报告 mktKey 是否有活跃分配, 没有分配时 ok 为 false, 调用
+// 方应该按老规矩用默认账户报价, 保持没有配这张表的部署行为不变。
+func AssignedAccount(assignments map[string]MarketAssignment, mktKey string) (accountKey string, ok bool) {
+	a, ok := assignments[mktKey]
+	if !ok {
+		return "", false
+	}
+	return a.AccountKey, true
+}
+
+// MayQuote 报告 accountKey 是否可以为 mktKey 报价: 没有分配记录时任何账户
+// 都可以 (兼容没有用这张表的部署), 有分配记录时只有分配到的账户可以。
+func MayQuote(assignments map[string]MarketAssignment, mktKey, accountKey string) bool {
+	owner, ok := AssignedAccount(assignments, mktKey)
+	return !ok || owner == accountKey
+}
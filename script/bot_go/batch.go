@@ -0,0 +1,121 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"time"
+
+	"tracing"
+)
+
+// MaxBatchOrderSize caps how many orders one /place_orders call carries,
+// so a 10-20 order ladder refresh chunks into a bounded number of
+// requests instead of depending on the API accepting an unbounded batch.
+const MaxBatchOrderSize = 20
+
+// PlaceOrdersRequest is the payload for the batch order endpoint.
+type PlaceOrdersRequest struct {
+	Orders []*PlaceOrderRequest `json:"orders"`
+}
+
+// PlaceOrdersResult is one order's outcome within a batch placement,
+// indexed into the slice PlaceOrders was called with.
+type PlaceOrdersResult struct {
+	Index   int    `json:"index"`
+	OrderID string `json:"order_id"`
+	Code    int    `json:"code"`
+	Msg     string `json:"msg"`
+}
+
+// PlaceOrdersResponse mirrors PlaceOrderResponse's Code/Msg envelope, with
+// Data replaced by one result per submitted order.
+type PlaceOrdersResponse struct {
+	Code    int                 `json:"code"`
+	Msg     string              `json:"msg"`
+	Results []PlaceOrdersResult `json:"data"`
+}
+
+// PlaceOrders posts orders to the /place_orders batch endpoint in chunks
+// of at most MaxBatchOrderSize, returning one PlaceOrdersResult per order
+// in the same order they were passed in. The exchange does not expose a
+// batch endpoint as of this writing; until it does, every chunk fails at
+// the HTTP or JSON layer and PlaceOrders returns that error immediately -
+// callers should fall back to PlaceOrder per-order in that case.
+func PlaceOrders(auth Authenticator, orders []*PlaceOrderRequest) ([]PlaceOrdersResult, error) {
+	results := make([]PlaceOrdersResult, 0, len(orders))
+
+	for start := 0; start < len(orders); start += MaxBatchOrderSize {
+		end := start + MaxBatchOrderSize
+		if end > len(orders) {
+			end = len(orders)
+		}
+
+		chunkResults, err := placeOrderChunk(auth, orders[start:end], start)
+		if err != nil {
+			return results, err
+		}
+		results = append(results, chunkResults...)
+	}
+
+	return results, nil
+}
+
+func placeOrderChunk(auth Authenticator, chunk []*PlaceOrderRequest, offset int) ([]PlaceOrdersResult, error) {
+	jsonData, err := json.Marshal(PlaceOrdersRequest{Orders: chunk})
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("POST", APIBaseURL+"/place_orders", bytes.NewBuffer(jsonData))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	auth.Authenticate(req)
+	if tc, err := tracing.New(); err != nil {
+		log.Printf("generate trace context failed: %v", err)
+	} else {
+		tc.InjectHeader(req.Header)
+	}
+
+	client, err := newMarketAPIClient()
+	if err != nil {
+		return nil, fmt.Errorf("build market api client: %w", err)
+	}
+
+	start := time.Now()
+	resp, err := client.Do(req)
+	if err != nil {
+		recordDebugEntry("place_orders", jsonData, nil, err, time.Since(start))
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		recordDebugEntry("place_orders", jsonData, nil, err, time.Since(start))
+		return nil, err
+	}
+
+	var batchResp PlaceOrdersResponse
+	if err := json.Unmarshal(body, &batchResp); err != nil {
+		parseErr := fmt.Errorf("parse batch response failed: %v, body: %s", err, string(body))
+		recordDebugEntry("place_orders", jsonData, body, parseErr, time.Since(start))
+		return nil, parseErr
+	}
+	if batchResp.Code != 0 {
+		batchErr := fmt.Errorf("place orders failed: %s", batchResp.Msg)
+		recordDebugEntry("place_orders", jsonData, body, batchErr, time.Since(start))
+		return nil, batchErr
+	}
+
+	recordDebugEntry("place_orders", jsonData, body, nil, time.Since(start))
+	for i := range batchResp.Results {
+		batchResp.Results[i].Index += offset
+	}
+	return batchResp.Results, nil
+}
@@ -0,0 +1,232 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// DailySpendCapUSDCEnv 覆盖两个账户合计每天允许花费的 USDC 上限, 达到后
+// RunBot 停止继续下单直到第二天。未设置时使用 defaultDailySpendCapUSDC 这
+// 个保守占位值, 真正的资金预算定下来后再通过这个环境变量调整, 不需要改
+// 代码。
+const DailySpendCapUSDCEnv = "BOT_DAILY_SPEND_CAP_USDC"
+
+// defaultDailySpendCapUSDC 是没配置 DailySpendCapUSDCEnv 时使用的每日花费
+// 上限, 跟 quoteUptimeTargetPct/risk.DefaultLimits 里的占位阈值一样是先给
+// 一个不至于把正常运行卡住、又能防止失控循环无限下单的量级, 平台/运营
+// 定下明确预算后再调整。
+const defaultDailySpendCapUSDC = 500.0
+
+func dailySpendCapUSDC() float64 {
+	raw := os.Getenv(DailySpendCapUSDCEnv)
+	if raw == "" {
+		return defaultDailySpendCapUSDC
+	}
+	cap, err := strconv.ParseFloat(raw, 64)
+	if err != nil || cap <= 0 {
+		log.Printf("invalid %s=%q, falling back to %.2f: %v", DailySpendCapUSDCEnv, raw, defaultDailySpendCapUSDC, err)
+		return defaultDailySpendCapUSDC
+	}
+	return cap
+}
+
+// spendTotals 累计一段时间 (一个自然日, 或者当前这一轮 RunBot) 花费的
+// USDC、买到的份数和支付的手续费。
+type spendTotals struct {
+	USDCSpent      decimal.Decimal `json:"usdc_spent"`
+	TokensAcquired int64           `json:"tokens_acquired"`
+	FeesPaidUSDC   decimal.Decimal `json:"fees_paid_usdc"`
+}
+
+func (t *spendTotals) add(usdcSpent decimal.Decimal, tokens int64, feesPaidUSDC decimal.Decimal) {
+	t.USDCSpent = t.USDCSpent.Add(usdcSpent)
+	t.TokensAcquired += tokens
+	t.FeesPaidUSDC = t.FeesPaidUSDC.Add(feesPaidUSDC)
+}
+
+// budgetTracker 按自然日 (YYYY-MM-DD) 累计 spendTotals, 另外单独维护一份
+// 当前这一轮 RunBot 的累计值, 跟 rewardTracker 一样是进程内单例 + 互斥锁
+// 保护, 只在内存里跟踪、重启后清零。
+type budgetTracker struct {
+	mu    sync.Mutex
+	byDay map[string]*spendTotals
+	run   spendTotals
+
+	// lastCapAlertDay 记录上次因为触发每日花费上限而告警的日期, 避免同一
+	// 天里每轮 RunBot 都重复打一条告警日志。
+	lastCapAlertDay string
+}
+
+var spendBudget = &budgetTracker{byDay: make(map[string]*spendTotals)}
+
+// RecordSpend 登记一笔已经成功下单的花费: usdcSpent 是这笔订单的名义金额
+// (price * shares), tokens 是买到的份数, feesPaidUSDC 是这笔订单按
+// feeRateBps 折算的手续费。同时计入 at 所在自然日的累计和当前这一轮
+// RunBot 的累计。
+func (t *budgetTracker) RecordSpend(usdcSpent decimal.Decimal, tokens int64, feesPaidUSDC decimal.Decimal, at time.Time) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	day := at.Format("2006-01-02")
+	totals, ok := t.byDay[day]
+	if !ok {
+		totals = &spendTotals{}
+		t.byDay[day] = totals
+	}
+	totals.add(usdcSpent, tokens, feesPaidUSDC)
+	t.run.add(usdcSpent, tokens, feesPaidUSDC)
+}
+
+// ResetRun 清零当前这一轮 RunBot 的累计花费, 在每次 RunBot 开始时调用,
+// 这样 RunSnapshot 报告的是"这一轮"而不是自进程启动以来的总和。
+func (t *budgetTracker) ResetRun() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.run = spendTotals{}
+}
+
+// RunSnapshot 返回当前这一轮 RunBot 已经累计的花费。
+func (t *budgetTracker) RunSnapshot() spendTotals {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.run
+}
+
+// DailySpendEntry 是 DailySnapshot 里的一条记录, 供 admin API/日志展示。
+type DailySpendEntry struct {
+	Day string `json:"day"`
+	spendTotals
+}
+
+// DailySnapshot 返回目前累计的每天花费, 按日期排序, 保证输出稳定。
+func (t *budgetTracker) DailySnapshot() []DailySpendEntry {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	out := make([]DailySpendEntry, 0, len(t.byDay))
+	for day, totals := range t.byDay {
+		out = append(out, DailySpendEntry{Day: day, spendTotals: *totals})
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Day < out[j].Day })
+	return out
+}
+
+// CumulativeSnapshot 把 byDay 里全部自然日的花费加总, 作为"自进程第一次
+// 记录以来"的累计值。
+func (t *budgetTracker) CumulativeSnapshot() spendTotals {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	var total spendTotals
+	for _, totals := range t.byDay {
+		total.add(totals.USDCSpent, totals.TokensAcquired, totals.FeesPaidUSDC)
+	}
+	return total
+}
+
+// CheckDailyCap 报告今天的累计花费是否已经达到 dailySpendCapUSDC, 达到时
+// 每天只告警一次 (避免每轮 RunBot 都刷屏), 但每次调用都会如实返回是否
+// 超限, 调用方应该在超限时跳过这一轮下单。
+func (t *budgetTracker) CheckDailyCap() (halted bool, spentUSDC, capUSDC decimal.Decimal) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	day := time.Now().Format("2006-01-02")
+	spent := decimal.Zero
+	if totals, ok := t.byDay[day]; ok {
+		spent = totals.USDCSpent
+	}
+	cap := decimal.NewFromFloat(dailySpendCapUSDC())
+
+	if spent.LessThan(cap) {
+		return false, spent, cap
+	}
+
+	if t.lastCapAlertDay != day {
+		t.lastCapAlertDay = day
+		log.Printf("⚠️ daily spend cap reached: spent=%s cap=%s, halting trading for the rest of %s", spent.String(), cap.String(), day)
+	}
+	return true, spent, cap
+}
+
+// restore 用快照替换当前累计的每日花费, 只在启动时的状态恢复流程里调用。
+func (t *budgetTracker) restore(entries []DailySpendEntry) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.byDay = make(map[string]*spendTotals, len(entries))
+	for _, e := range entries {
+		totals := e.spendTotals
+		t.byDay[e.Day] = &totals
+	}
+}
+
+// logBudgetSnapshot 打印这一轮和今天累计的花费, RunBot 每个周期结束时和
+// logRewardSnapshot/logLatencySnapshot 一起调用, 供运营人员核对预算消耗
+// 进度。
+func logBudgetSnapshot() {
+	run := spendBudget.RunSnapshot()
+	log.Printf("budget this run: usdc_spent=%s tokens_acquired=%d fees_paid_usdc=%s",
+		run.USDCSpent.String(), run.TokensAcquired, run.FeesPaidUSDC.String())
+
+	today := time.Now().Format("2006-01-02")
+	for _, e := range spendBudget.DailySnapshot() {
+		if e.Day != today {
+			continue
+		}
+		log.Printf("budget today (%s): usdc_spent=%s tokens_acquired=%d fees_paid_usdc=%s cap=%.2f",
+			e.Day, e.USDCSpent.String(), e.TokensAcquired, e.FeesPaidUSDC.String(), dailySpendCapUSDC())
+	}
+}
+
+// budgetStateFile 是 spendBudget 每日快照落盘的路径, 可通过
+// BUDGET_STATE_FILE 环境变量覆盖, 未设置时落在当前工作目录下的
+// bot_budget.json, 跟 rewardStateFile 的约定一致。
+var budgetStateFile = envOrDefault("BUDGET_STATE_FILE", "bot_budget.json")
+
+type budgetStateSnapshot struct {
+	SavedAt time.Time         `json:"saved_at"`
+	Entries []DailySpendEntry `json:"entries"`
+}
+
+// SaveBudgetState 把 spendBudget 当前累计的每日花费写入 budgetStateFile,
+// 跟 SaveRewardState 一样在 RunBot 每个周期结束时调用一次, 这样重启不会
+// 丢失当天已经花掉多少额度、也不会让每日上限被绕过。
+func SaveBudgetState() error {
+	snapshot := budgetStateSnapshot{SavedAt: time.Now(), Entries: spendBudget.DailySnapshot()}
+	data, err := json.MarshalIndent(snapshot, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshal budget snapshot: %w", err)
+	}
+	if err := os.WriteFile(budgetStateFile, data, 0644); err != nil {
+		return fmt.Errorf("write budget snapshot: %w", err)
+	}
+	return nil
+}
+
+// LoadBudgetState 读取 budgetStateFile 里的快照并恢复到 spendBudget, 文件
+// 不存在时视为空快照 (进程第一次启动)。
+func LoadBudgetState() error {
+	data, err := os.ReadFile(budgetStateFile)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	var snapshot budgetStateSnapshot
+	if err := json.Unmarshal(data, &snapshot); err != nil {
+		return fmt.Errorf("parse budget snapshot: %w", err)
+	}
+	spendBudget.restore(snapshot.Entries)
+	return nil
+}
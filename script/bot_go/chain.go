@@ -0,0 +1,126 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"strconv"
+)
+
+// ChainConfig 描述钱包相关操作要用的链参数: 签名用的 EIP-712/SIWE chain
+// id, 转账 gas/USDC 保证金用的 RPC 端点和 USDC 合约地址。
+type ChainConfig struct {
+	ChainID             int
+	RPCURL              string
+	USDCContractAddress string
+	USDCDecimals        int32
+
+	// ConditionalTokensAddress 是这条链上 Gnosis ConditionalTokens 合约的
+	// 地址, prepareCondition (onchain.go) 用它注册 ctf 包派生出来的
+	// conditionId。这个仓库还没有在任何一条链上正式部署过, 先留空;
+	// prepareCondition 在空值时会直接报错而不是发到零地址。
+	ConditionalTokensAddress string
+}
+
+// chainConfigs 是这个仓库知道怎么跑的链, 按 chain id 索引。BSC 测试网是
+// 目前唯一实际验证过的环境; BSC 主网的 RPC/USDC 合约先按公开信息填,
+// 正式切换主网前需要再核对一遍。
+var chainConfigs = map[int]ChainConfig{
+	97: {
+		ChainID:             97,
+		RPCURL:              "https://data-seed-prebsc-1-s1.binance.org:8545/",
+		USDCContractAddress: "0x64544969ed7EBf5f083679233325356EbE7118e",
+		USDCDecimals:        18,
+	},
+	56: {
+		ChainID:             56,
+		RPCURL:              "https://bsc-dataseed.binance.org/",
+		USDCContractAddress: "0x8AC76a51cc950d9822D68b83fE1Ad97B32Cd580D",
+		USDCDecimals:        18,
+	},
+}
+
+// BotChainIDEnv 是覆盖运行链的环境变量, 未设置时回退到 BSC 测试网 (97),
+// 跟迁移前硬编码 ChainID = 97 的行为保持一致。
+const BotChainIDEnv = "BOT_CHAIN_ID"
+
+// ActiveChainID 返回本次运行应该使用的链 id, 来自 BOT_CHAIN_ID 环境变量,
+// 未设置或解析失败时回退到 97 (BSC 测试网)。
+func ActiveChainID() int {
+	raw := os.Getenv(BotChainIDEnv)
+	if raw == "" {
+		return 97
+	}
+	id, err := strconv.Atoi(raw)
+	if err != nil {
+		log.Printf("invalid %s=%q, falling back to BSC testnet (97): %v", BotChainIDEnv, raw, err)
+		return 97
+	}
+	return id
+}
+
+// ActiveChain 返回 ActiveChainID 对应的 ChainConfig。未知的链 id 直接
+// log.Fatalf, 因为带着零值 RPC/合约地址跑下去只会在真正发起链上操作时
+// 才炸, 不如启动时就暴露出来。
+func ActiveChain() ChainConfig {
+	id := ActiveChainID()
+	cfg, ok := chainConfigs[id]
+	if !ok {
+		log.Fatalf("unsupported %s=%d, known chains: %v", BotChainIDEnv, id, knownChainIDs())
+	}
+	return cfg
+}
+
+func knownChainIDs() []int {
+	ids := make([]int, 0, len(chainConfigs))
+	for id := range chainConfigs {
+		ids = append(ids, id)
+	}
+	return ids
+}
+
+// apiConfigResponse 是 /config 接口预期的响应形状, 用来核对本地选择的链
+// 和 API 实际服务的链是不是同一个。交易所目前没有暴露专门的 chain id
+// 查询接口, 先按这个假定形状实现 (跟 janitor.go 的 /open_orders、
+// client.go 的 /orders 等一样是先按预期形状实现), 接口上线后如果路径/
+// 字段命名不同, 只需要调整这里的请求和解析。
+type apiConfigResponse struct {
+	ChainID int `json:"chain_id"`
+}
+
+// GetAPIChainID 查询 API 报告的链 id。
+func GetAPIChainID() (int, error) {
+	resp, err := http.Get(APIBaseURL + "/config")
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return 0, err
+	}
+
+	if resp.StatusCode != 200 {
+		return 0, fmt.Errorf("HTTP %d: %s", resp.StatusCode, string(body[:min(len(body), 200)]))
+	}
+
+	var cfg apiConfigResponse
+	if err := json.Unmarshal(body, &cfg); err != nil {
+		return 0, err
+	}
+	return cfg.ChainID, nil
+}
+
+// ValidateChainAgainstAPI 检查本地选择的链 id 是否与 API 报告的链 id 一
+// 致, 避免用错误的链域名签名导致订单在错误的合约上生效/被拒绝。
+func ValidateChainAgainstAPI(reportedChainID int) error {
+	local := ActiveChainID()
+	if reportedChainID != local {
+		return fmt.Errorf("chain mismatch: bot configured for chain %d but API reports chain %d", local, reportedChainID)
+	}
+	return nil
+}
@@ -0,0 +1,198 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"time"
+
+	"tracing"
+)
+
+// OrderSummary、TradeSummary、EventSummary 是 /orders、/trades、/events
+// 列表接口返回的条目形状。交易所目前还没有暴露这几个按游标翻页的列表
+// 接口 (跟 janitor.go 的 /open_orders、/cancel_order 一样是先按预期形状
+// 实现), 接口上线后如果字段命名不同, 只需要调整这几个 struct 和下面的
+// 解析。
+type OrderSummary struct {
+	OrderID  string `json:"order_id"`
+	EventID  int64  `json:"event_id"`
+	MarketID int16  `json:"market_id"`
+	TokenID  string `json:"token_id"`
+	Side     string `json:"side"`
+	Price    string `json:"price"`
+	Status   string `json:"status"`
+}
+
+type TradeSummary struct {
+	TradeID  string `json:"trade_id"`
+	OrderID  string `json:"order_id"`
+	EventID  int64  `json:"event_id"`
+	MarketID int16  `json:"market_id"`
+	TokenID  string `json:"token_id"`
+	Price    string `json:"price"`
+	Shares   string `json:"shares"`
+}
+
+type APIEventSummary struct {
+	EventID   int64   `json:"event_id"`
+	Title     string  `json:"title"`
+	Topic     string  `json:"topic"`
+	MarketIDs []int16 `json:"market_ids"`
+}
+
+// listPageData 是三个列表接口共用的 data 字段形状: 一页数据加上取下一页
+// 要传的游标, 外面再套一层 envelope[T] 的 {code, msg} 包装。NextCursor 为
+// 空表示已经是最后一页。
+type listPageData[T any] struct {
+	Items      []T    `json:"items"`
+	NextCursor string `json:"next_cursor"`
+}
+
+// ListOptions 是三个列表接口共用的查询参数。Limit 未设置 (<=0) 时用
+// DefaultListLimit。
+type ListOptions struct {
+	Limit int
+}
+
+// DefaultListLimit 是 ListOptions.Limit 未设置时使用的单页条数, 也是这几个
+// 列表接口假设的服务端默认分页大小。
+const DefaultListLimit = 100
+
+// Page 是 /orders、/trades、/events 列表接口的游标翻页迭代器, 用法跟
+// database/sql 的 *sql.Rows 一样: for page.Next() { use(page.Item()) };
+// 结束后检查 page.Err()。翻页对调用方透明, 每当当前页耗尽就自动请求
+// 下一页, 直到服务端返回的 next_cursor 为空。
+type Page[T any] struct {
+	auth Authenticator
+	path string
+	opts ListOptions
+
+	cursor  string
+	started bool
+	done    bool
+	items   []T
+	index   int
+	current T
+	err     error
+}
+
+// newPage 构造一个还没发起任何请求的 Page, 第一次调用 Next 时才会真正
+// 拉取第一页。
+func newPage[T any](auth Authenticator, path string, opts ListOptions) *Page[T] {
+	if opts.Limit <= 0 {
+		opts.Limit = DefaultListLimit
+	}
+	return &Page[T]{auth: auth, path: path, opts: opts}
+}
+
+// Next 前进到下一条记录, 必要时透明地拉取下一页; 没有更多记录或请求出
+// 错时返回 false, 调用方应在 for 循环结束后检查 Err。
+func (p *Page[T]) Next() bool {
+	if p.err != nil {
+		return false
+	}
+
+	for p.index >= len(p.items) {
+		if p.started && p.done {
+			return false
+		}
+
+		items, nextCursor, err := fetchListPage[T](p.auth, p.path, p.opts, p.cursor)
+		if err != nil {
+			p.err = err
+			return false
+		}
+
+		p.started = true
+		p.items = items
+		p.index = 0
+		p.cursor = nextCursor
+		p.done = nextCursor == ""
+
+		if len(items) == 0 {
+			return false
+		}
+	}
+
+	p.current = p.items[p.index]
+	p.index++
+	return true
+}
+
+// Item 返回 Next 刚刚前进到的记录。
+func (p *Page[T]) Item() T {
+	return p.current
+}
+
+// Err 返回翻页过程中遇到的第一个错误 (如果有)。
+func (p *Page[T]) Err() error {
+	return p.err
+}
+
+// fetchListPage 拉取列表接口的一页数据。
+func fetchListPage[T any](auth Authenticator, path string, opts ListOptions, cursor string) ([]T, string, error) {
+	query := url.Values{}
+	query.Set("limit", fmt.Sprintf("%d", opts.Limit))
+	if cursor != "" {
+		query.Set("cursor", cursor)
+	}
+
+	req, err := http.NewRequest("GET", fmt.Sprintf("%s%s?%s", APIBaseURL, path, query.Encode()), nil)
+	if err != nil {
+		return nil, "", err
+	}
+	auth.Authenticate(req)
+	if tc, err := tracing.New(); err != nil {
+		log.Printf("generate trace context failed: %v", err)
+	} else {
+		tc.InjectHeader(req.Header)
+	}
+
+	client, err := newMarketAPIClient()
+	if err != nil {
+		return nil, "", fmt.Errorf("build market api client: %w", err)
+	}
+
+	start := time.Now()
+	resp, err := client.Do(req)
+	if err != nil {
+		recordDebugEntry(path, nil, nil, err, time.Since(start))
+		return nil, "", err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		recordDebugEntry(path, nil, nil, err, time.Since(start))
+		return nil, "", err
+	}
+
+	page, err := decodeEnvelope[listPageData[T]](resp.StatusCode, body)
+	if err != nil {
+		recordDebugEntry(path, nil, body, err, time.Since(start))
+		return nil, "", err
+	}
+
+	recordDebugEntry(path, nil, body, nil, time.Since(start))
+	return page.Items, page.NextCursor, nil
+}
+
+// Orders 返回 auth 对应账户全部订单 (不限于未完成的, 跟 GetOpenOrders 不
+// 同) 的游标翻页迭代器。
+func Orders(auth Authenticator, opts ListOptions) *Page[OrderSummary] {
+	return newPage[OrderSummary](auth, "/orders", opts)
+}
+
+// Trades 返回 auth 对应账户全部成交记录的游标翻页迭代器。
+func Trades(auth Authenticator, opts ListOptions) *Page[TradeSummary] {
+	return newPage[TradeSummary](auth, "/trades", opts)
+}
+
+// Events 返回全部事件的游标翻页迭代器, 不像 GetActiveEvents/listEvents
+// 那样直接查数据库, 而是走 API 分页拿全量列表。
+func Events(auth Authenticator, opts ListOptions) *Page[APIEventSummary] {
+	return newPage[APIEventSummary](auth, "/events", opts)
+}
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"log"
+	"sync"
+	"time"
+)
+
+// ClockSkewWarnThreshold 是本地时钟与 API 服务端时间的偏移超过多少就该
+// 告警。挂单的 Expiration 字段和 SIWE 的 issued-at 都是拿本地时间算出来
+// 的, Cloud Run 宿主机的时钟漂移会直接导致这两者被服务端判定为过期/无效。
+const ClockSkewWarnThreshold = 2 * time.Second
+
+// ClockSkewLogWindow 限制偏移告警的打印频率, 避免每次 GetDepth (每个
+// 市场每个周期都会调一次) 都刷屏。
+const ClockSkewLogWindow = 5 * time.Minute
+
+var (
+	clockSkewMu       sync.Mutex
+	lastClockSkewWarn time.Time
+)
+
+// observeServerTimestamp 用某次 API 响应带回的服务端时间戳估算本地时钟
+// 相对服务端的偏移, 超过 ClockSkewWarnThreshold 时打日志告警 (每
+// ClockSkewLogWindow 最多一次)。GetDepth 每个市场每个周期都会调用, 第一
+// 次调用天然覆盖了"启动时检查一次", 之后每轮调用天然覆盖了"周期性复
+// 查", 不需要单独起一个检查循环或专门的时间接口。
+//
+// serverUnixMillis 的单位假定是毫秒, 是深度接口没有文档说明情况下跟其余
+// 接口 unix 时间戳字段风格保持一致的猜测; 如果实际是秒, 算出来的偏移会
+// 被放大 1000 倍, 很容易从第一条告警日志里看出来并修正这里的换算。
+func observeServerTimestamp(serverUnixMillis int64) {
+	if serverUnixMillis <= 0 {
+		return
+	}
+
+	serverTime := time.UnixMilli(serverUnixMillis)
+	skew := time.Since(serverTime)
+	if skew < 0 {
+		skew = -skew
+	}
+	if skew <= ClockSkewWarnThreshold {
+		return
+	}
+
+	clockSkewMu.Lock()
+	defer clockSkewMu.Unlock()
+	if time.Since(lastClockSkewWarn) < ClockSkewLogWindow {
+		return
+	}
+	lastClockSkewWarn = time.Now()
+
+	log.Printf("clock skew warning: local clock is %s off from API server time (server=%s, local=%s); order expirations and SIWE issued-at values may be rejected",
+		skew.Round(time.Millisecond), serverTime.Format(time.RFC3339Nano), time.Now().Format(time.RFC3339Nano))
+}
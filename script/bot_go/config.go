@@ -0,0 +1,120 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"reflect"
+	"time"
+
+	"risk"
+)
+
+// ConfigFileEnv 是热更新配置文件的路径, 可通过 BOT_CONFIG_FILE 环境变量
+// 设置, 未设置时 RunConfigWatcher 直接返回, 不启用热更新。
+const ConfigFileEnv = "BOT_CONFIG_FILE"
+
+// ConfigReloadInterval 是轮询配置文件 mtime 的节奏。这个仓库目前没有引入
+// fsnotify 之类的文件监听依赖, 用简单轮询加 mtime 比较足够, 不值得为了
+// 一个配置热加载功能新增依赖。
+const ConfigReloadInterval = 10 * time.Second
+
+// DynamicConfig 是配置文件里可以热更新的字段, 覆盖策略参数
+// (topicQuoteProfiles)、手续费率表 (marketFeeRateBps) 和按账户配置的风控
+// 限额 (riskEngine)。字段留空 (JSON 里缺省或为 null) 表示"这部分不更新",
+// 而不是"清空这部分", 避免只想调一个字段却意外清掉另一个账户的风控配置。
+type DynamicConfig struct {
+	TopicProfiles    map[string]QuoteProfile `json:"topic_profiles,omitempty"`
+	MarketFeeRateBps map[int16]int64         `json:"market_fee_rate_bps,omitempty"`
+	RiskLimits       map[string]risk.Limits  `json:"risk_limits,omitempty"`
+}
+
+// configFileModTime 记录上一次成功加载的配置文件 mtime, 避免内容没变时
+// 重复应用/打印审计日志。
+var configFileModTime time.Time
+
+// loadDynamicConfig 读取并解析 configPath 里的配置。
+func loadDynamicConfig(configPath string) (DynamicConfig, error) {
+	data, err := os.ReadFile(configPath)
+	if err != nil {
+		return DynamicConfig{}, err
+	}
+	var cfg DynamicConfig
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return DynamicConfig{}, fmt.Errorf("parse dynamic config: %w", err)
+	}
+	return cfg, nil
+}
+
+// ApplyDynamicConfig 把 cfg 里非空的字段应用到运行中的策略参数/风控限额,
+// 并为每一处实际变化打一条审计日志 (变更前 -> 变更后), 供事后追查"这一轮
+// 参数是什么时候被改的、改成了什么"。
+func ApplyDynamicConfig(cfg DynamicConfig) {
+	if cfg.TopicProfiles != nil {
+		before := TopicQuoteProfilesSnapshot()
+		SetTopicQuoteProfiles(cfg.TopicProfiles)
+		logConfigDiff("topic_profiles", before, cfg.TopicProfiles)
+	}
+	if cfg.MarketFeeRateBps != nil {
+		before := MarketFeeRateBpsSnapshot()
+		SetMarketFeeRateBps(cfg.MarketFeeRateBps)
+		logConfigDiff("market_fee_rate_bps", before, cfg.MarketFeeRateBps)
+	}
+	for account, limits := range cfg.RiskLimits {
+		before := riskEngine.Limits(account)
+		riskEngine.SetLimits(account, limits)
+		logConfigDiff(fmt.Sprintf("risk_limits[%s]", account), before, limits)
+	}
+}
+
+// logConfigDiff 只在 before/after 确实不同的时候打审计日志, 避免每次轮询
+// 都刷屏。
+func logConfigDiff(field string, before, after interface{}) {
+	if reflect.DeepEqual(before, after) {
+		return
+	}
+	log.Printf("config reload: %s changed from %+v to %+v", field, before, after)
+}
+
+// RunConfigWatcher 每 ConfigReloadInterval 检查一次 BOT_CONFIG_FILE 的
+// mtime, 变化时重新加载并应用, 直到 stop 被关闭。BOT_CONFIG_FILE 未设置时
+// 直接返回, 不启用热更新, start_bot 无条件起这个 goroutine 也是安全的。
+func RunConfigWatcher(stop <-chan struct{}) {
+	configPath := os.Getenv(ConfigFileEnv)
+	if configPath == "" {
+		return
+	}
+
+	checkAndReload := func() {
+		info, err := os.Stat(configPath)
+		if err != nil {
+			log.Printf("config watcher: stat %s failed: %v", configPath, err)
+			return
+		}
+		if !info.ModTime().After(configFileModTime) {
+			return
+		}
+		cfg, err := loadDynamicConfig(configPath)
+		if err != nil {
+			log.Printf("config watcher: load %s failed: %v", configPath, err)
+			return
+		}
+		configFileModTime = info.ModTime()
+		ApplyDynamicConfig(cfg)
+	}
+
+	checkAndReload()
+
+	ticker := time.NewTicker(ConfigReloadInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			checkAndReload()
+		}
+	}
+}
@@ -0,0 +1,333 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+
+	"strategy"
+)
+
+// dashboardRefreshInterval is how often `bot_go dashboard` redraws. Fast
+// enough to feel live, slow enough not to hammer the admin API or the DB.
+const dashboardRefreshInterval = 5 * time.Second
+
+// dashboardDepthWSHost is the depth websocket server the dashboard's top of
+// book panel subscribes to.
+const dashboardDepthWSHost = "predictionmarket-websocket-depth-290128242879.asia-northeast1.run.app"
+
+// dashboardSubscribeMessage matches the depth feed's subscribe protocol.
+type dashboardSubscribeMessage struct {
+	Action   string `json:"action"`
+	EventID  int64  `json:"event_id"`
+	MarketID int16  `json:"market_id"`
+}
+
+// dashboardPriceLevel is one price level as the depth feed encodes it - its
+// quantity field is named total_quantity, unlike the REST /depth endpoint's
+// PriceLevelInfo.
+type dashboardPriceLevel struct {
+	Price         string `json:"price"`
+	TotalQuantity string `json:"total_quantity"`
+}
+
+// dashboardTokenBook is one token's book as pushed by the depth feed, best
+// price first on each side (same assumption printOrderBookLadder makes for
+// the REST response).
+type dashboardTokenBook struct {
+	LatestTradePrice string                `json:"latest_trade_price"`
+	Bids             []dashboardPriceLevel `json:"bids"`
+	Asks             []dashboardPriceLevel `json:"asks"`
+}
+
+// dashboardDepthMessage matches both of the feed's push shapes: a full
+// snapshot on subscribe (Depths populated) and an incremental update after
+// that (Changes populated). Either way every token entry present is a full
+// replacement of that token's book, so applying either kind is the same
+// operation.
+type dashboardDepthMessage struct {
+	EventID  int64                         `json:"event_id"`
+	MarketID int16                         `json:"market_id"`
+	Depths   map[string]dashboardTokenBook `json:"depths"`
+	Changes  map[string]dashboardTokenBook `json:"changes"`
+}
+
+func (m dashboardDepthMessage) updates() map[string]dashboardTokenBook {
+	if m.Depths != nil {
+		return m.Depths
+	}
+	return m.Changes
+}
+
+// dashboardBookState holds the most recently seen book per token, updated
+// by dialDashboardDepthWS and read by the render loop.
+type dashboardBookState struct {
+	mu    sync.Mutex
+	books map[string]dashboardTokenBook
+}
+
+func (s *dashboardBookState) apply(msg dashboardDepthMessage) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.books == nil {
+		s.books = make(map[string]dashboardTokenBook)
+	}
+	for tokenID, book := range msg.updates() {
+		s.books[tokenID] = book
+	}
+}
+
+func (s *dashboardBookState) snapshot() map[string]dashboardTokenBook {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	out := make(map[string]dashboardTokenBook, len(s.books))
+	for tokenID, book := range s.books {
+		out[tokenID] = book
+	}
+	return out
+}
+
+// dialDashboardDepthWS subscribes to eventID/marketID's depth feed and
+// applies every push to state until the connection drops, logging and
+// returning rather than retrying - a dropped top-of-book panel isn't fatal
+// to the rest of the dashboard, and a re-run of the command reconnects.
+func dialDashboardDepthWS(eventID int64, marketID int16, state *dashboardBookState) {
+	url := fmt.Sprintf("wss://%s/depth", dashboardDepthWSHost)
+	conn, _, err := websocket.DefaultDialer.Dial(url, nil)
+	if err != nil {
+		log.Printf("dashboard: connect depth websocket failed: %v", err)
+		return
+	}
+	defer conn.Close()
+
+	sub, _ := json.Marshal(dashboardSubscribeMessage{Action: "subscribe", EventID: eventID, MarketID: marketID})
+	if err := conn.WriteMessage(websocket.TextMessage, sub); err != nil {
+		log.Printf("dashboard: subscribe depth websocket failed: %v", err)
+		return
+	}
+
+	for {
+		_, data, err := conn.ReadMessage()
+		if err != nil {
+			log.Printf("dashboard: depth websocket read failed: %v", err)
+			return
+		}
+		var msg dashboardDepthMessage
+		if err := json.Unmarshal(data, &msg); err != nil {
+			log.Printf("dashboard: could not parse depth push: %v", err)
+			continue
+		}
+		state.apply(msg)
+	}
+}
+
+// fetchAdminJSON GETs path off this same host's own admin API and decodes
+// the response into v - the dashboard is meant to run alongside the bot
+// process it's inspecting, so it reads the loopback-only admin API the same
+// way an operator's curl would, rather than duplicating orderManager/
+// errorCounts state.
+func fetchAdminJSON(path string, v interface{}) error {
+	resp, err := http.Get(fmt.Sprintf("http://%s%s", adminAddr(), path))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("admin API %s: HTTP %d", path, resp.StatusCode)
+	}
+	return json.NewDecoder(resp.Body).Decode(v)
+}
+
+// dashboardFills fetches the most recent trades for auth on the given
+// market, stopping once limit matching fills have been collected. Failures
+// (auth errors, no trades yet) just render as an empty section rather than
+// aborting the whole redraw.
+func dashboardFills(auth Authenticator, eventID int64, marketID int16, limit int) []TradeSummary {
+	page := Trades(auth, ListOptions{Limit: DefaultListLimit})
+	var fills []TradeSummary
+	for page.Next() && len(fills) < limit {
+		trade := page.Item()
+		if trade.EventID == eventID && trade.MarketID == marketID {
+			fills = append(fills, trade)
+		}
+	}
+	if err := page.Err(); err != nil {
+		log.Printf("dashboard: fetch trades failed: %v", err)
+	}
+	return fills
+}
+
+// dashboardPosition looks up userID's net position in tokenID, logging and
+// falling back to decimal.Zero on failure so one bad lookup doesn't blank
+// out the whole positions panel.
+func dashboardPosition(tracker *strategy.PositionTracker, userID int64, tokenID string) string {
+	pos, err := tracker.NetPosition(userID, tokenID)
+	if err != nil {
+		log.Printf("dashboard: net position lookup failed for user=%d token=%s: %v", userID, tokenID, err)
+		return "?"
+	}
+	return pos.String()
+}
+
+// renderDashboard clears the terminal and prints one refresh's worth of
+// operator-facing state: resting orders, error counters and, when the
+// market/token being watched is known, its two accounts' net positions and
+// the market's most recent fills.
+func renderDashboard(market *Market, eventID int64, marketID int16, books map[string]dashboardTokenBook, orders []ManagedOrder, errorCounts map[string]int, tracker *strategy.PositionTracker, fills []TradeSummary) {
+	fmt.Print("\033[H\033[2J")
+	fmt.Printf("bot_go dashboard - %s\n", time.Now().Format(time.RFC3339))
+	if market != nil {
+		fmt.Printf("Watching: %s (event_id=%d, market_id=%d)\n", market.Title, eventID, marketID)
+
+		fmt.Println("\n-- Top of book --")
+		for i, tokenID := range market.TokenIDs {
+			outcome := tokenID
+			if i < len(market.Outcomes) {
+				outcome = market.Outcomes[i]
+			}
+			book, ok := books[tokenID]
+			if !ok {
+				fmt.Printf("  %s (token %s): no depth yet\n", outcome, shortTokenID(tokenID))
+				continue
+			}
+			bestBid, bestAsk := "-", "-"
+			if len(book.Bids) > 0 {
+				bestBid = book.Bids[0].Price
+			}
+			if len(book.Asks) > 0 {
+				bestAsk = book.Asks[0].Price
+			}
+			fmt.Printf("  %s (token %s): bid=%s ask=%s last_trade=%s\n", outcome, shortTokenID(tokenID), bestBid, bestAsk, book.LatestTradePrice)
+		}
+	}
+
+	fmt.Println("\n-- Resting orders --")
+	if len(orders) == 0 {
+		fmt.Println("  none")
+	}
+	for _, o := range orders {
+		fmt.Printf("  [%s] %s token=%s price=%s shares=%d placed_at=%s\n",
+			o.AccountKey, o.OrderID, shortTokenID(o.TokenID), o.Price, o.Shares, o.PlacedAt.Format(time.RFC3339))
+	}
+
+	if market != nil && tracker != nil {
+		fmt.Println("\n-- Net positions --")
+		for i, tokenID := range market.TokenIDs {
+			outcome := tokenID
+			if i < len(market.Outcomes) {
+				outcome = market.Outcomes[i]
+			}
+			fmt.Printf("  %s (token %s): account1=%s account2=%s\n", outcome, shortTokenID(tokenID),
+				dashboardPosition(tracker, Account1UserID, tokenID), dashboardPosition(tracker, Account2UserID, tokenID))
+		}
+	}
+
+	fmt.Println("\n-- Recent fills --")
+	if len(fills) == 0 {
+		fmt.Println("  none")
+	}
+	for _, f := range fills {
+		fmt.Printf("  %s order=%s token=%s price=%s shares=%s\n", f.TradeID, f.OrderID, shortTokenID(f.TokenID), f.Price, f.Shares)
+	}
+
+	fmt.Println("\n-- Error counters --")
+	if len(errorCounts) == 0 {
+		fmt.Println("  none")
+	}
+	for category, count := range errorCounts {
+		fmt.Printf("  %s: %d\n", category, count)
+	}
+}
+
+// runDashboardCLI implements `bot_go dashboard`, usage:
+//
+//	bot_go dashboard [event_id] [market_id]
+//
+// A plain, periodically-redrawing terminal summary for an operator watching
+// the bot run: resting orders and error counters (read off this process's
+// own loopback admin API, so it reflects a bot already running alongside
+// it), plus, when event_id/market_id are given, that market's live top of
+// book (via the depth websocket), net positions (via
+// strategy.PositionTracker) and most recent fills. There's no curses/TUI
+// dependency in the module cache this repo builds against, so this is a
+// clear-and-reprint loop rather than a real TUI.
+func runDashboardCLI(args []string) {
+	ctx := context.Background()
+
+	creds, err := loadCredentials(ctx)
+	if err != nil {
+		log.Fatalf("load credentials: %v", err)
+	}
+
+	var eventID int64
+	var marketID int16
+	haveMarket := len(args) >= 2
+	if haveMarket {
+		eventID, err = strconv.ParseInt(args[0], 10, 64)
+		if err != nil {
+			fmt.Printf("invalid event_id: %v\n", err)
+			os.Exit(1)
+		}
+		marketIDInt, err := strconv.ParseInt(args[1], 10, 16)
+		if err != nil {
+			fmt.Printf("invalid market_id: %v\n", err)
+			os.Exit(1)
+		}
+		marketID = int16(marketIDInt)
+	}
+
+	connStr := fmt.Sprintf("host=%s port=%d user=%s password=%s dbname=%s sslmode=require",
+		DBHost, DBPort, DBUser, creds.DBPassword, DBName)
+	db, err := sql.Open("postgres", connStr)
+	if err != nil {
+		log.Fatalf("connect to database: %v", err)
+	}
+	defer db.Close()
+	tracker := strategy.NewPositionTracker(db)
+
+	var market *Market
+	if haveMarket {
+		market, err = getMarket(db, eventID, marketID)
+		if err != nil {
+			log.Fatalf("look up market: %v", err)
+		}
+	}
+
+	bookState := &dashboardBookState{}
+	if haveMarket {
+		go dialDashboardDepthWS(eventID, marketID, bookState)
+	}
+
+	ticker := time.NewTicker(dashboardRefreshInterval)
+	defer ticker.Stop()
+
+	for {
+		var orders []ManagedOrder
+		if err := fetchAdminJSON("/admin/orders", &orders); err != nil {
+			log.Printf("dashboard: fetch orders failed: %v", err)
+		}
+
+		errorCounts := make(map[string]int)
+		if err := fetchAdminJSON("/admin/errors", &errorCounts); err != nil {
+			log.Printf("dashboard: fetch error counts failed: %v", err)
+		}
+
+		var fills []TradeSummary
+		if haveMarket {
+			fills = dashboardFills(APIKeyAuth{APIKey: creds.Account1ApiKey}, eventID, marketID, 10)
+		}
+
+		renderDashboard(market, eventID, marketID, bookState.snapshot(), orders, errorCounts, tracker, fills)
+
+		<-ticker.C
+	}
+}
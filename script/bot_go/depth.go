@@ -0,0 +1,161 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+
+	"github.com/shopspring/decimal"
+)
+
+// getMarket looks up eventID's market marketID directly from the events
+// table, independent of whether the event is currently active, so
+// operators can inspect depth on any market by ID.
+func getMarket(db *sql.DB, eventID int64, marketID int16) (*Market, error) {
+	var marketsJSON string
+	if err := db.QueryRow(`SELECT markets FROM events WHERE id = $1`, eventID).Scan(&marketsJSON); err != nil {
+		return nil, fmt.Errorf("query event %d: %w", eventID, err)
+	}
+
+	var marketsMap map[string]struct {
+		ID       int16    `json:"id"`
+		Title    string   `json:"title"`
+		TokenIDs []string `json:"token_ids"`
+		Outcomes []string `json:"outcomes"`
+		Closed   bool     `json:"closed"`
+	}
+	if err := json.Unmarshal([]byte(marketsJSON), &marketsMap); err != nil {
+		return nil, fmt.Errorf("parse markets for event %d: %w", eventID, err)
+	}
+
+	for _, m := range marketsMap {
+		if m.ID == marketID {
+			return &Market{ID: m.ID, Title: m.Title, TokenIDs: m.TokenIDs, Outcomes: m.Outcomes, Closed: m.Closed}, nil
+		}
+	}
+	return nil, fmt.Errorf("market %d not found in event %d", marketID, eventID)
+}
+
+// printDepthLadder renders market's depth as a readable ladder, one token
+// (outcome) at a time, so an operator can eyeball pricing without reading
+// the raw depth JSON.
+func printDepthLadder(market *Market, depth *DepthResponse) {
+	fmt.Printf("Event market: %s (market_id=%d)\n", market.Title, market.ID)
+
+	for i, tokenID := range market.TokenIDs {
+		outcome := tokenID
+		if i < len(market.Outcomes) {
+			outcome = market.Outcomes[i]
+		}
+
+		book, ok := depth.Data.Depths[tokenID]
+		fmt.Printf("\n-- %s (token %s) --\n", outcome, shortTokenID(tokenID))
+		if !ok {
+			fmt.Println("  no depth available")
+			continue
+		}
+		printOrderBookLadder(book)
+	}
+}
+
+// printOrderBookLadder prints bids and asks side by side, best price first
+// on both sides, followed by spread/mid/implied probability.
+func printOrderBookLadder(book DepthBook) {
+	fmt.Printf("%-14s %-10s | %-10s %-14s\n", "bid qty", "bid px", "ask px", "ask qty")
+
+	rows := len(book.Bids)
+	if len(book.Asks) > rows {
+		rows = len(book.Asks)
+	}
+	for i := 0; i < rows; i++ {
+		var bidQty, bidPrice, askPrice, askQty string
+		if i < len(book.Bids) {
+			bidQty, bidPrice = book.Bids[i].Quantity, book.Bids[i].Price
+		}
+		if i < len(book.Asks) {
+			askPrice, askQty = book.Asks[i].Price, book.Asks[i].Quantity
+		}
+		fmt.Printf("%-14s %-10s | %-10s %-14s\n", bidQty, bidPrice, askPrice, askQty)
+	}
+
+	if len(book.Bids) == 0 || len(book.Asks) == 0 {
+		fmt.Println("insufficient depth to compute spread/mid")
+		return
+	}
+
+	bestBid, err := decimal.NewFromString(book.Bids[0].Price)
+	if err != nil {
+		fmt.Printf("bad bid price %q: %v\n", book.Bids[0].Price, err)
+		return
+	}
+	bestAsk, err := decimal.NewFromString(book.Asks[0].Price)
+	if err != nil {
+		fmt.Printf("bad ask price %q: %v\n", book.Asks[0].Price, err)
+		return
+	}
+
+	spread := bestAsk.Sub(bestBid)
+	mid := bestBid.Add(bestAsk).Div(decimal.NewFromInt(2))
+	fmt.Printf("spread=%s mid=%s implied_probability=%s%%\n", spread.String(), mid.String(), mid.Mul(decimal.NewFromInt(100)).StringFixed(2))
+}
+
+func shortTokenID(tokenID string) string {
+	if len(tokenID) <= 20 {
+		return tokenID
+	}
+	return tokenID[:20] + "..."
+}
+
+// runDepthCLI 实现 `bot_go depth` 命令, 用法:
+//
+//	bot_go depth <event_id> <market_id>
+//
+// 拉取指定市场的深度并渲染成易读的档位表 (两个 token 并排, 含价差/中间价/
+// 隐含概率), 方便排查行情问题时不用直接读原始 JSON。
+func runDepthCLI(args []string) {
+	if len(args) < 2 {
+		fmt.Println("Usage: bot_go depth <event_id> <market_id>")
+		os.Exit(1)
+	}
+
+	eventID, err := strconv.ParseInt(args[0], 10, 64)
+	if err != nil {
+		fmt.Printf("invalid event_id: %v\n", err)
+		os.Exit(1)
+	}
+	marketIDInt, err := strconv.ParseInt(args[1], 10, 16)
+	if err != nil {
+		fmt.Printf("invalid market_id: %v\n", err)
+		os.Exit(1)
+	}
+	marketID := int16(marketIDInt)
+
+	creds, err := loadCredentials(context.Background())
+	if err != nil {
+		log.Fatalf("load credentials: %v", err)
+	}
+
+	connStr := fmt.Sprintf("host=%s port=%d user=%s password=%s dbname=%s sslmode=require",
+		DBHost, DBPort, DBUser, creds.DBPassword, DBName)
+	db, err := sql.Open("postgres", connStr)
+	if err != nil {
+		log.Fatalf("connect to database: %v", err)
+	}
+	defer db.Close()
+
+	market, err := getMarket(db, eventID, marketID)
+	if err != nil {
+		log.Fatalf("look up market: %v", err)
+	}
+
+	depth, err := GetDepth(eventID, marketID)
+	if err != nil {
+		log.Fatalf("get depth: %v", err)
+	}
+
+	printDepthLadder(market, depth)
+}
@@ -0,0 +1,112 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// APIErrorClass 把 API 返回的错误粗分成几类, 让重试/告警逻辑可以按类型
+// 分支处理, 而不是对 Msg 文本做字符串匹配。
+type APIErrorClass int
+
+const (
+	// ClassUnknown 是分类不出来的错误, 按不可重试处理。
+	ClassUnknown APIErrorClass = iota
+	// ClassAuth 是鉴权失败 (api key 无效/过期、签名校验不过), 重试没用,
+	// 需要人工介入或重新登录。
+	ClassAuth
+	// ClassValidation 是请求参数本身不合法 (价格越界、余额不足等), 原样
+	// 重试也不会成功。
+	ClassValidation
+	// ClassRateLimit 是被限流, 退避之后重试通常能成功。
+	ClassRateLimit
+	// ClassServer 是服务端内部错误/暂时不可用, 退避之后重试通常能成功。
+	ClassServer
+)
+
+func (c APIErrorClass) String() string {
+	switch c {
+	case ClassAuth:
+		return "auth"
+	case ClassValidation:
+		return "validation"
+	case ClassRateLimit:
+		return "rate_limit"
+	case ClassServer:
+		return "server"
+	default:
+		return "unknown"
+	}
+}
+
+// APIError 是 {code, msg} 响应体解析失败 (code != 0) 或 HTTP 层非 2xx 时
+// 返回的错误, 携带分类结果供调用方决定是否重试。
+type APIError struct {
+	Code       int
+	Msg        string
+	HTTPStatus int
+	Class      APIErrorClass
+	Retryable  bool
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("api error [%s]: code=%d http_status=%d msg=%s", e.Class, e.Code, e.HTTPStatus, e.Msg)
+}
+
+// classifyAPIError 用 HTTP 状态码分类 API 错误。这个 API 的业务 code 目前
+// 没有文档化的分级含义 (各接口各用各的非零值表示失败), 所以先只依赖
+// HTTP 状态码, 需要更细的分类时再按具体 code 值补充例外。
+func classifyAPIError(httpStatus int) APIErrorClass {
+	switch {
+	case httpStatus == http.StatusUnauthorized || httpStatus == http.StatusForbidden:
+		return ClassAuth
+	case httpStatus == http.StatusTooManyRequests:
+		return ClassRateLimit
+	case httpStatus == http.StatusBadRequest || httpStatus == http.StatusUnprocessableEntity:
+		return ClassValidation
+	case httpStatus >= 500:
+		return ClassServer
+	default:
+		return ClassUnknown
+	}
+}
+
+// newAPIError 构造一个已经分类好的 APIError, Retryable 由 Class 决定
+// (限流/服务端错误可重试, 其余不可重试)。
+func newAPIError(code int, msg string, httpStatus int) *APIError {
+	class := classifyAPIError(httpStatus)
+	return &APIError{
+		Code:       code,
+		Msg:        msg,
+		HTTPStatus: httpStatus,
+		Class:      class,
+		Retryable:  class == ClassRateLimit || class == ClassServer,
+	}
+}
+
+// envelope 是这个 API 几乎所有接口共用的响应外壳。
+type envelope[T any] struct {
+	Code int    `json:"code"`
+	Msg  string `json:"msg"`
+	Data T      `json:"data"`
+}
+
+// decodeEnvelope 把响应 body 解析成 {code, msg, data} 外壳, code 非零或
+// HTTP 状态码非 2xx 时返回一个分类好的 *APIError, 而不是把 Data 当成
+// 有效值返回。调用方不再需要各自重复"解析 -> 判断 code -> 拼错误信息"
+// 这套逻辑。
+func decodeEnvelope[T any](httpStatus int, body []byte) (T, error) {
+	var parsed envelope[T]
+	var zero T
+
+	if err := json.Unmarshal(body, &parsed); err != nil {
+		return zero, fmt.Errorf("parse response failed: %w, body: %s", err, string(body))
+	}
+
+	if httpStatus < 200 || httpStatus >= 300 || parsed.Code != 0 {
+		return zero, newAPIError(parsed.Code, parsed.Msg, httpStatus)
+	}
+
+	return parsed.Data, nil
+}
@@ -0,0 +1,32 @@
+package main
+
+import "sync"
+
+// errorCounts 按 category 累计进程启动以来的失败次数, 跟 admin.go 的
+// pausedMarkets 一样是内存里的 map+mutex, 没有接外部指标系统, 只是让
+// dashboard/admin API 能看到"哪类操作在出错、出了多少次", 不用现场翻日志
+// 数数。
+var (
+	errorCountsMu sync.Mutex
+	errorCounts   = make(map[string]int)
+)
+
+// IncrementErrorCount 记录一次 category 类别的失败, 调用方各自决定什么算
+// 一次失败 (比如 PlaceOrder 返回 err、CancelAllOrders 返回 err)。
+func IncrementErrorCount(category string) {
+	errorCountsMu.Lock()
+	defer errorCountsMu.Unlock()
+	errorCounts[category]++
+}
+
+// ErrorCountsSnapshot 返回当前累计的错误计数, 供 /admin/errors 和 dashboard
+// 读取, 不影响原 map。
+func ErrorCountsSnapshot() map[string]int {
+	errorCountsMu.Lock()
+	defer errorCountsMu.Unlock()
+	out := make(map[string]int, len(errorCounts))
+	for k, v := range errorCounts {
+		out[k] = v
+	}
+	return out
+}
@@ -0,0 +1,198 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"text/tabwriter"
+	"time"
+)
+
+// EventSummary is the event/market shape the events CLI renders. Unlike
+// Event (used by the trading loop, which only carries what ProcessMarket
+// needs), it also carries end date and closed/resolved flags so operators
+// can see an event's lifecycle at a glance instead of running psql by hand.
+type EventSummary struct {
+	ID       int64             `json:"id"`
+	Title    string            `json:"title"`
+	EndDate  *time.Time        `json:"end_date,omitempty"`
+	Closed   bool              `json:"closed"`
+	Resolved bool              `json:"resolved"`
+	Markets  map[string]Market `json:"markets"`
+}
+
+type eventRow interface {
+	Scan(dest ...any) error
+}
+
+func scanEventSummary(row eventRow) (*EventSummary, error) {
+	var e EventSummary
+	var endDate sql.NullTime
+	var marketsJSON string
+
+	if err := row.Scan(&e.ID, &e.Title, &endDate, &e.Closed, &e.Resolved, &marketsJSON); err != nil {
+		return nil, err
+	}
+	if endDate.Valid {
+		e.EndDate = &endDate.Time
+	}
+
+	var marketsMap map[string]struct {
+		ID       int16    `json:"id"`
+		Title    string   `json:"title"`
+		TokenIDs []string `json:"token_ids"`
+		Outcomes []string `json:"outcomes"`
+		Closed   bool     `json:"closed"`
+	}
+	if err := json.Unmarshal([]byte(marketsJSON), &marketsMap); err != nil {
+		return nil, fmt.Errorf("parse markets for event %d: %w", e.ID, err)
+	}
+	e.Markets = make(map[string]Market, len(marketsMap))
+	for key, m := range marketsMap {
+		e.Markets[key] = Market{ID: m.ID, Title: m.Title, TokenIDs: m.TokenIDs, Outcomes: m.Outcomes, Closed: m.Closed}
+	}
+
+	return &e, nil
+}
+
+// listEvents returns every event in the DB, active or not, newest first
+// query left to the caller (ordered by id here for stable table output).
+func listEvents(db *sql.DB) ([]EventSummary, error) {
+	rows, err := db.Query(`SELECT id, title, end_date, closed, resolved, markets FROM events ORDER BY id`)
+	if err != nil {
+		return nil, fmt.Errorf("query events: %w", err)
+	}
+	defer rows.Close()
+
+	var events []EventSummary
+	for rows.Next() {
+		e, err := scanEventSummary(rows)
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, *e)
+	}
+	return events, rows.Err()
+}
+
+func getEventSummary(db *sql.DB, eventID int64) (*EventSummary, error) {
+	row := db.QueryRow(`SELECT id, title, end_date, closed, resolved, markets FROM events WHERE id = $1`, eventID)
+	e, err := scanEventSummary(row)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("event %d not found", eventID)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("query event %d: %w", eventID, err)
+	}
+	return e, nil
+}
+
+func printEventsTable(events []EventSummary) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tTITLE\tMARKETS\tEND DATE\tCLOSED\tRESOLVED")
+	for _, e := range events {
+		endDate := "-"
+		if e.EndDate != nil {
+			endDate = e.EndDate.Format(time.RFC3339)
+		}
+		fmt.Fprintf(w, "%d\t%s\t%d\t%s\t%t\t%t\n", e.ID, e.Title, len(e.Markets), endDate, e.Closed, e.Resolved)
+	}
+	w.Flush()
+}
+
+func printEventDetail(e *EventSummary) {
+	endDate := "-"
+	if e.EndDate != nil {
+		endDate = e.EndDate.Format(time.RFC3339)
+	}
+	fmt.Printf("Event %d: %s\n", e.ID, e.Title)
+	fmt.Printf("end_date=%s closed=%t resolved=%t\n", endDate, e.Closed, e.Resolved)
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "\nMARKET ID\tTITLE\tCLOSED\tTOKEN IDS\tOUTCOMES")
+	for _, m := range e.Markets {
+		fmt.Fprintf(w, "%d\t%s\t%t\t%v\t%v\n", m.ID, m.Title, m.Closed, m.TokenIDs, m.Outcomes)
+	}
+	w.Flush()
+}
+
+func printJSON(v any) {
+	out, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		log.Fatalf("marshal json: %v", err)
+	}
+	fmt.Println(string(out))
+}
+
+func connectEventsDB() *sql.DB {
+	creds, err := loadCredentials(context.Background())
+	if err != nil {
+		log.Fatalf("load credentials: %v", err)
+	}
+	connStr := fmt.Sprintf("host=%s port=%d user=%s password=%s dbname=%s sslmode=require",
+		DBHost, DBPort, DBUser, creds.DBPassword, DBName)
+	db, err := sql.Open("postgres", connStr)
+	if err != nil {
+		log.Fatalf("connect to database: %v", err)
+	}
+	return db
+}
+
+// runEventsCLI 实现 `bot_go events` 命令, 用法:
+//
+//	bot_go events list [json]
+//	bot_go events show <event_id> [json]
+//
+// 直接从数据库读取事件及其市场列表 (含结束时间/关闭/结算状态), 供运营
+// 排查问题时使用, 不用再手写 psql 查询生产库。
+func runEventsCLI(args []string) {
+	usage := "Usage: bot_go events list [json] | bot_go events show <event_id> [json]"
+	if len(args) < 1 {
+		fmt.Println(usage)
+		os.Exit(1)
+	}
+
+	db := connectEventsDB()
+	defer db.Close()
+
+	switch args[0] {
+	case "list":
+		events, err := listEvents(db)
+		if err != nil {
+			log.Fatalf("list events: %v", err)
+		}
+		if len(args) > 1 && args[1] == "json" {
+			printJSON(events)
+		} else {
+			printEventsTable(events)
+		}
+
+	case "show":
+		if len(args) < 2 {
+			fmt.Println(usage)
+			os.Exit(1)
+		}
+		eventID, err := strconv.ParseInt(args[1], 10, 64)
+		if err != nil {
+			fmt.Printf("invalid event_id: %v\n", err)
+			os.Exit(1)
+		}
+		event, err := getEventSummary(db, eventID)
+		if err != nil {
+			log.Fatalf("show event: %v", err)
+		}
+		if len(args) > 2 && args[2] == "json" {
+			printJSON(event)
+		} else {
+			printEventDetail(event)
+		}
+
+	default:
+		fmt.Println(usage)
+		os.Exit(1)
+	}
+}
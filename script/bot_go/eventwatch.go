@@ -0,0 +1,157 @@
+package main
+
+import (
+	"database/sql"
+	"encoding/json"
+	"log"
+	"time"
+)
+
+// EventWatchInterval 是事件变更巡检的轮询节奏, 远比 IntervalMinutes 的整轮
+// 报价周期短, 只用来尽快发现"新事件上线/事件关闭/事件结算/事件过期", 好
+// 触发一次提前的 RunBot、并立即撤销已经不活跃市场的挂单, 而不是等到下一个
+// 完整周期或者靠下单失败才发现。events 表由撮合服务写入, 这个仓库没有权限
+// 也没有必要为它加 NOTIFY 触发器, 所以跟 RunConfigWatcher 一样选择轮询而不
+// 是 LISTEN/NOTIFY。
+const EventWatchInterval = 30 * time.Second
+
+// eventWatermark 记录目前已经处理过的 events 表变更的高水位, 用
+// created_at/closed_at/resolved_at 里最新的一个做水位线: 新增事件推进
+// created_at, 关闭/结算推进 closed_at/resolved_at, 三者任意一个超过水位线
+// 就说明有变更需要处理。
+type eventWatermark struct {
+	seen time.Time
+}
+
+// eventChange 是一次巡检里发现的一个 event 当前状态, 用来判断这个 event
+// 是不是已经不再活跃 (关闭/结算/过期), 需要撤单并标记市场不活跃。
+type eventChange struct {
+	EventID   int64
+	Closed    bool
+	Resolved  bool
+	EndDate   *time.Time
+	MarketIDs []int16
+}
+
+// inactive 报告这个 event 是否已经不该继续报价: 关闭、结算, 或者已经过了
+// end_date。
+func (c eventChange) inactive() bool {
+	return c.Closed || c.Resolved || (c.EndDate != nil && !c.EndDate.After(time.Now()))
+}
+
+// checkEventChanges 查询 created_at/closed_at/resolved_at 晚于 w.seen 的
+// event, 把水位线推进到这批变更里最新的时间戳, 返回发现的变更。
+func (w *eventWatermark) checkEventChanges(db *sql.DB) ([]eventChange, error) {
+	rows, err := db.Query(`
+		SELECT id, closed, resolved, end_date, created_at, closed_at, resolved_at, markets
+		FROM events
+		WHERE created_at > $1
+		   OR closed_at > $1
+		   OR resolved_at > $1
+	`, w.seen)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var changes []eventChange
+	for rows.Next() {
+		var id int64
+		var closed, resolved bool
+		var endDate, createdAt sql.NullTime
+		var closedAt, resolvedAt sql.NullTime
+		var marketsJSON string
+		if err := rows.Scan(&id, &closed, &resolved, &endDate, &createdAt, &closedAt, &resolvedAt, &marketsJSON); err != nil {
+			return changes, err
+		}
+
+		change := eventChange{EventID: id, Closed: closed, Resolved: resolved}
+		if endDate.Valid {
+			change.EndDate = &endDate.Time
+		}
+		if ids, err := decodeMarketIDs(marketsJSON); err != nil {
+			log.Printf("event watcher: parse markets for event %d: %v", id, err)
+		} else {
+			change.MarketIDs = ids
+		}
+		changes = append(changes, change)
+
+		if createdAt.Valid && createdAt.Time.After(w.seen) {
+			w.seen = createdAt.Time
+		}
+		if closedAt.Valid && closedAt.Time.After(w.seen) {
+			w.seen = closedAt.Time
+		}
+		if resolvedAt.Valid && resolvedAt.Time.After(w.seen) {
+			w.seen = resolvedAt.Time
+		}
+	}
+	return changes, rows.Err()
+}
+
+// decodeMarketIDs 从 events.markets 那列 JSONB 里取出全部 market id, 复用
+// GetActiveEvents 已经用过的形状, 不需要额外定义一遍。
+func decodeMarketIDs(marketsJSON string) ([]int16, error) {
+	var marketsMap map[string]struct {
+		ID int16 `json:"id"`
+	}
+	if err := json.Unmarshal([]byte(marketsJSON), &marketsMap); err != nil {
+		return nil, err
+	}
+	ids := make([]int16, 0, len(marketsMap))
+	for _, m := range marketsMap {
+		ids = append(ids, m.ID)
+	}
+	return ids, nil
+}
+
+// deactivateMarkets 为 change 里的每个 market 标记 admin 暂停 (阻止 RunBot
+// 之后继续挂新单), 并立即撤销 sessions 在这些市场上的挂单, 不用等下一轮
+// RunStaleOrderJanitor 巡检才清理。
+func deactivateMarkets(sessions []*Session, change eventChange) {
+	for _, marketID := range change.MarketIDs {
+		mktKey := marketKey(change.EventID, marketID)
+		if !IsMarketPaused(mktKey) {
+			PauseMarket(mktKey)
+			log.Printf("event watcher: event %d closed/resolved/expired, marking market %s inactive", change.EventID, mktKey)
+		}
+	}
+	cancelMarketOrders(sessions, change.EventID, change.MarketIDs)
+}
+
+// RunEventWatcher 每 EventWatchInterval 轮询一次 events 表: 发现的每个新增
+// /关闭/结算事件都会往 trigger 发一个信号让主循环提前跑一轮 RunBot; 一旦
+// 事件变成不活跃 (关闭/结算/过期), 额外立即暂停并撤销它名下市场的挂单,
+// 不依赖下一轮下单失败才发现。trigger 是带缓冲的 channel, 已经有一个待
+// 处理信号时直接丢弃这次, 反正下一轮 RunBot 总会重新查询全部活跃事件。
+func RunEventWatcher(db *sql.DB, sessions []*Session, trigger chan<- struct{}, stop <-chan struct{}) {
+	w := &eventWatermark{seen: time.Now()}
+
+	ticker := time.NewTicker(EventWatchInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			changes, err := w.checkEventChanges(db)
+			if err != nil {
+				log.Printf("event watcher: query failed: %v", err)
+				continue
+			}
+			if len(changes) == 0 {
+				continue
+			}
+			for _, change := range changes {
+				if change.inactive() {
+					deactivateMarkets(sessions, change)
+				}
+			}
+			select {
+			case trigger <- struct{}{}:
+			default:
+			}
+		}
+	}
+}
@@ -0,0 +1,109 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"strconv"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"units"
+)
+
+// OrderExpiryRefreshWindow 是挂单临近过期时提前多久重新签名/换新, 留出
+// 足够时间让新单在旧单真正过期前挂上盘口, 不出现报价空档。
+const OrderExpiryRefreshWindow = 2 * time.Minute
+
+// OrderExpiryCheckInterval 是巡检一次跟踪中挂单是否临近过期的节奏, 跟
+// StaleOrderJanitor 一样独立于主策略循环运行。
+const OrderExpiryCheckInterval = 30 * time.Second
+
+// parseOrderExpiration 把 PlaceOrderRequest.Expiration (unix 秒的字符串,
+// "0" 表示没有过期时间/GTC) 解析成 *time.Time。CreateBuyOrder/
+// CreateNegRiskBuyOrder 目前一直传 "0" (GTD 订单还没有接入), 这个函数在
+// 那之前总是返回 nil, RunOrderExpiryRefresher 也就一直是空转, 接入 GTD
+// 之后不需要再改这里。
+func parseOrderExpiration(expiration string) *time.Time {
+	seconds, err := strconv.ParseInt(expiration, 10, 64)
+	if err != nil || seconds <= 0 {
+		return nil
+	}
+	t := time.Unix(seconds, 0)
+	return &t
+}
+
+// refreshExpiringOrder 用 tracked 记录的 token/价格/份数重新签名下一笔新
+// 单, 新单先挂上去确认成功之后再撤销旧单, 顺序反过来的话 (先撤后挂)
+// 中间会有一小段没有报价的空档。跟其他下单点一样, 挂新单之前先过一遍
+// session.Allow, 避免大量临近过期的挂单在这个刷新循环里绕过风控敞口/
+// 节流限制。
+func refreshExpiringOrder(session *Session, tracked ManagedOrder) error {
+	price, err := decimal.NewFromString(tracked.Price)
+	if err != nil {
+		return fmt.Errorf("parse tracked price: %w", err)
+	}
+
+	mktKey := marketKey(tracked.EventID, tracked.MarketID)
+	notional := units.Notional(price, tracked.Shares)
+	if err := session.Allow(mktKey, notional); err != nil {
+		return fmt.Errorf("refresh order blocked: %w", err)
+	}
+
+	feeRateBps := FeeRateBpsForMarket(tracked.MarketID)
+	newOrder, err := session.CreateBuyOrder(tracked.TokenID, price, tracked.Shares, tracked.EventID, tracked.MarketID, feeRateBps)
+	if err != nil {
+		return fmt.Errorf("re-sign order: %w", err)
+	}
+
+	newOrderID, err := session.PlaceOrder(newOrder)
+	if err != nil {
+		return fmt.Errorf("place refreshed order: %w", err)
+	}
+
+	if err := CancelOrder(session.Authenticator(), tracked.OrderID); err != nil {
+		return fmt.Errorf("cancel expiring order %s: %w", tracked.OrderID, err)
+	}
+	orderManager.Forget(tracked.OrderID)
+
+	log.Printf("order expiry refresh: replaced order %s with %s (account=%s token=%s price=%s) before expiration",
+		tracked.OrderID, newOrderID, session.AccountKey, tracked.TokenID, tracked.Price)
+	return nil
+}
+
+// sweepExpiringOrders 检查 sessions 里每个账户跟踪中的挂单, 距离
+// ExpiresAt 不到 OrderExpiryRefreshWindow 的就重新签名/换新, 单笔刷新失败
+// 只记录日志, 不影响其他挂单。
+func sweepExpiringOrders(sessions []*Session) {
+	now := time.Now()
+
+	for _, session := range sessions {
+		for _, tracked := range orderManager.Snapshot() {
+			if tracked.AccountKey != session.AccountKey || tracked.ExpiresAt == nil {
+				continue
+			}
+			if tracked.ExpiresAt.Sub(now) > OrderExpiryRefreshWindow {
+				continue
+			}
+			if err := refreshExpiringOrder(session, tracked); err != nil {
+				log.Printf("order expiry refresh: order %s for %s failed: %v", tracked.OrderID, session.AccountKey, err)
+			}
+		}
+	}
+}
+
+// RunOrderExpiryRefresher 每 OrderExpiryCheckInterval 巡检一次 sessions 里
+// 临近过期的挂单并无缝续期, 直到 stop 被关闭。跟订单核对循环一样依赖
+// 进程内的 orderManager, 所以跟主循环同一个进程跑。
+func RunOrderExpiryRefresher(sessions []*Session, stop <-chan struct{}) {
+	ticker := time.NewTicker(OrderExpiryCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			sweepExpiringOrders(sessions)
+		}
+	}
+}
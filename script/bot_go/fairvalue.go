@@ -0,0 +1,146 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+
+	"httpclient"
+)
+
+// FairValueProvider 估计一个 token 的公允价, 供策略按公允价 ± edge 挂单,
+// 而不是照抄盘口上恰好出现的价格。不同实现可以来自当前盘口中值、外部
+// 赔率 API、或按市场手工配置的静态值, ProcessMarket 只依赖这个接口。
+type FairValueProvider interface {
+	FairValue(ctx context.Context, eventID int64, marketID int16, tokenID string) (decimal.Decimal, error)
+}
+
+// fairValueProvider 是 ProcessMarket 使用的公允价来源, 默认取当前盘口的
+// 买1/卖1中值; 换成外部赔率源或静态配置时替换这个变量即可。
+var fairValueProvider FairValueProvider = MidBookFairValue{}
+
+// MidBookFairValue 用当前盘口买1/卖1价的中值作为公允价估计。
+type MidBookFairValue struct {
+	// GetDepth 获取市场深度, 默认使用包级 GetDepth, 测试时可替换成 stub。
+	GetDepth func(eventID int64, marketID int16) (*DepthResponse, error)
+}
+
+// FairValue 实现 FairValueProvider。
+func (p MidBookFairValue) FairValue(ctx context.Context, eventID int64, marketID int16, tokenID string) (decimal.Decimal, error) {
+	getDepth := p.GetDepth
+	if getDepth == nil {
+		getDepth = GetDepth
+	}
+
+	depth, err := getDepth(eventID, marketID)
+	if err != nil {
+		return decimal.Decimal{}, fmt.Errorf("get depth failed: %w", err)
+	}
+
+	book, ok := depth.Data.Depths[tokenID]
+	if !ok || len(book.Bids) == 0 || len(book.Asks) == 0 {
+		return decimal.Decimal{}, fmt.Errorf("no two-sided book for token %s", tokenID)
+	}
+
+	bid, err := decimal.NewFromString(book.Bids[0].Price)
+	if err != nil {
+		return decimal.Decimal{}, fmt.Errorf("parse bid price: %w", err)
+	}
+	ask, err := decimal.NewFromString(book.Asks[0].Price)
+	if err != nil {
+		return decimal.Decimal{}, fmt.Errorf("parse ask price: %w", err)
+	}
+
+	return bid.Add(ask).Div(decimal.NewFromInt(2)), nil
+}
+
+// fairValueHTTPConfig 让外部赔率 API 也能像市场 API/Privy 一样单独配置
+// 代理和 CA 证书, 通过 BOT_FAIR_VALUE_PROXY_URL 等环境变量设置。
+var fairValueHTTPConfig = httpclient.FromEnv("BOT_FAIR_VALUE")
+
+func newFairValueClient() (*http.Client, error) {
+	return httpclient.New(fairValueHTTPConfig, 10*time.Second)
+}
+
+// ExternalOddsFairValue 从外部赔率 API 拉取该 token 的隐含概率作为公允价
+// 估计。目前没有接入任何具体供应商, 这里只约定"给一个 URL, 返回一个
+// 0-1 之间的概率"这个最小接口: 接入真正的赔率源时把 FetchProbability
+// 换成对应供应商的响应解析逻辑即可, TokenURLs 保持不变。
+type ExternalOddsFairValue struct {
+	// TokenURLs 把 tokenID 映射到该 token 概率的外部数据源 URL。
+	TokenURLs map[string]string
+	// FetchProbability 从给定 URL 取一个 0-1 之间的概率, 默认实现
+	// (fetchProbability) 发起 HTTP GET 并把响应体当作纯数字字符串解析。
+	FetchProbability func(ctx context.Context, url string) (decimal.Decimal, error)
+}
+
+// FairValue 实现 FairValueProvider。
+func (p ExternalOddsFairValue) FairValue(ctx context.Context, eventID int64, marketID int16, tokenID string) (decimal.Decimal, error) {
+	url, ok := p.TokenURLs[tokenID]
+	if !ok {
+		return decimal.Decimal{}, fmt.Errorf("no external odds source configured for token %s", tokenID)
+	}
+
+	fetch := p.FetchProbability
+	if fetch == nil {
+		fetch = fetchProbability
+	}
+	return fetch(ctx, url)
+}
+
+// fetchProbability 是 ExternalOddsFairValue 的默认 FetchProbability: 发起
+// HTTP GET, 把响应体去掉首尾空白后当作一个十进制概率字符串解析。
+func fetchProbability(ctx context.Context, url string) (decimal.Decimal, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return decimal.Decimal{}, err
+	}
+
+	client, err := newFairValueClient()
+	if err != nil {
+		return decimal.Decimal{}, fmt.Errorf("build fair value client: %w", err)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return decimal.Decimal{}, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return decimal.Decimal{}, err
+	}
+
+	prob, err := decimal.NewFromString(strings.TrimSpace(string(body)))
+	if err != nil {
+		return decimal.Decimal{}, fmt.Errorf("parse probability failed: %v, body: %s", err, string(body))
+	}
+	return prob, nil
+}
+
+// fairValueKey 生成 StaticFairValue.Values 用的 map key。
+func fairValueKey(eventID int64, marketID int16, tokenID string) string {
+	return fmt.Sprintf("%d/%d/%s", eventID, marketID, tokenID)
+}
+
+// StaticFairValue 按 (eventID, marketID, tokenID) 手工配置公允价, 用于还
+// 没接入任何数据源、但想先给个基准价试跑策略的场景。
+type StaticFairValue struct {
+	Values map[string]decimal.Decimal
+}
+
+// FairValue 实现 FairValueProvider。
+func (p StaticFairValue) FairValue(ctx context.Context, eventID int64, marketID int16, tokenID string) (decimal.Decimal, error) {
+	key := fairValueKey(eventID, marketID, tokenID)
+	v, ok := p.Values[key]
+	if !ok {
+		return decimal.Decimal{}, fmt.Errorf("no static fair value configured for %s", key)
+	}
+	return v, nil
+}
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"log"
+	"os"
+	"strconv"
+
+	"github.com/shopspring/decimal"
+)
+
+// MaxTakerImpactTicksEnv 覆盖吃单腿允许把盘口推动多少个 tick, 未设置时
+// 默认 20 个 TickSize (0.002), 平台目前没有公布"正常冲击"的具体门槛,
+// 先按经验值兜底, 后续按实际市场深度数据调整。
+const MaxTakerImpactTicksEnv = "MAX_TAKER_IMPACT_TICKS"
+
+func maxTakerImpactTicks() decimal.Decimal {
+	raw := os.Getenv(MaxTakerImpactTicksEnv)
+	if raw == "" {
+		return decimal.NewFromInt(20)
+	}
+	ticks, err := strconv.ParseInt(raw, 10, 64)
+	if err != nil || ticks <= 0 {
+		log.Printf("invalid %s=%q, falling back to 20: %v", MaxTakerImpactTicksEnv, raw, err)
+		return decimal.NewFromInt(20)
+	}
+	return decimal.NewFromInt(ticks)
+}
+
+// estimatePostTradeAskPrice 按 asks (由低到高排序的卖盘) 模拟吃掉 shares
+// 份之后盘口会停在哪个价位: 依次消耗档位数量, 用最后被吃到的那一档价格
+// 近似成交后的新买1/卖1, 而不是精确到手续费/滑点的成交均价。asks 深度
+// 不足以吃满 shares 时, 返回最后一档的价格 (盘口能报出的最差情况)。
+func estimatePostTradeAskPrice(asks []PriceLevelInfo, shares int64) (decimal.Decimal, error) {
+	remaining := decimal.NewFromInt(shares)
+	var lastPrice decimal.Decimal
+
+	for _, level := range asks {
+		price, err := decimal.NewFromString(level.Price)
+		if err != nil {
+			return decimal.Decimal{}, err
+		}
+		qty, err := decimal.NewFromString(level.Quantity)
+		if err != nil {
+			return decimal.Decimal{}, err
+		}
+		lastPrice = price
+		remaining = remaining.Sub(qty)
+		if remaining.LessThanOrEqual(decimal.Zero) {
+			break
+		}
+	}
+
+	return lastPrice, nil
+}
+
+// CheckTakerImpact 估算吃单账户按 shares 份吃掉 tokenID 的卖盘之后, 盘口
+// 会被推动多少个 tick, 超过 maxTakerImpactTicks 时不允许这一腿成交, 避免
+// 走量的 bot 在盘口很薄的市场里把价格推得肉眼可见。asks 为空 (没有可吃
+// 的卖单深度) 时无法估算, 放行交给交易所自己的下单校验去处理。
+func CheckTakerImpact(asks []PriceLevelInfo, bestAsk decimal.Decimal, shares int64) (allowed bool, impactTicks decimal.Decimal, err error) {
+	if len(asks) == 0 {
+		return true, decimal.Zero, nil
+	}
+
+	postTradePrice, err := estimatePostTradeAskPrice(asks, shares)
+	if err != nil {
+		return false, decimal.Zero, err
+	}
+
+	impactTicks = postTradePrice.Sub(bestAsk).Abs().Div(TickSize)
+	return impactTicks.LessThanOrEqual(maxTakerImpactTicks()), impactTicks, nil
+}
@@ -0,0 +1,246 @@
+package main
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"time"
+
+	"tracing"
+)
+
+// MaxOpenOrderAge 是挂单允许存活的最长时间, 超过后 StaleOrderJanitor 会
+// 主动撤销它, 用于清理策略进程崩溃后遗留、没人跟进的孤儿单。
+const MaxOpenOrderAge = 10 * time.Minute
+
+// JanitorInterval 是 StaleOrderJanitor 两次巡检之间的间隔。
+const JanitorInterval = 2 * time.Minute
+
+// OpenOrder 是 /open_orders 接口返回的一条挂单记录。交易所目前没有暴露
+// 按账户查询挂单列表的接口, StaleOrderJanitor 先按这个形状实现; 接口
+// 上线后如果字段命名不同, 只需要调整这个 struct 和下面的解析。
+type OpenOrder struct {
+	OrderID   string `json:"order_id"`
+	EventID   int64  `json:"event_id"`
+	MarketID  int16  `json:"market_id"`
+	TokenID   string `json:"token_id"`
+	CreatedAt int64  `json:"created_at"` // unix seconds
+}
+
+type openOrdersResponse struct {
+	Code int         `json:"code"`
+	Msg  string      `json:"msg"`
+	Data []OpenOrder `json:"data"`
+}
+
+// GetOpenOrders 拉取 auth 对应账户的全部未完成挂单。
+func GetOpenOrders(auth Authenticator) ([]OpenOrder, error) {
+	req, err := http.NewRequest("GET", APIBaseURL+"/open_orders", nil)
+	if err != nil {
+		return nil, err
+	}
+	auth.Authenticate(req)
+	if tc, err := tracing.New(); err != nil {
+		log.Printf("generate trace context failed: %v", err)
+	} else {
+		tc.InjectHeader(req.Header)
+	}
+
+	client, err := newMarketAPIClient()
+	if err != nil {
+		return nil, fmt.Errorf("build market api client: %w", err)
+	}
+
+	start := time.Now()
+	resp, err := client.Do(req)
+	if err != nil {
+		recordDebugEntry("open_orders", nil, nil, err, time.Since(start))
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		recordDebugEntry("open_orders", nil, nil, err, time.Since(start))
+		return nil, err
+	}
+
+	var parsed openOrdersResponse
+	if err := json.Unmarshal(body, &parsed); err != nil {
+		parseErr := fmt.Errorf("parse open orders response failed: %v, body: %s", err, string(body))
+		recordDebugEntry("open_orders", nil, body, parseErr, time.Since(start))
+		return nil, parseErr
+	}
+	if parsed.Code != 0 {
+		getErr := fmt.Errorf("get open orders failed: %s", parsed.Msg)
+		recordDebugEntry("open_orders", nil, body, getErr, time.Since(start))
+		return nil, getErr
+	}
+
+	recordDebugEntry("open_orders", nil, body, nil, time.Since(start))
+	return parsed.Data, nil
+}
+
+// CancelOrder 撤销单个订单。交易所同样还没暴露按 id 撤单的接口, 目前只有
+// CancelAllOrders (撤销账户全部挂单); StaleOrderJanitor 先按 /cancel_order
+// 实现, 接口上线前调用会在 HTTP/JSON 层直接失败。
+func CancelOrder(auth Authenticator, orderID string) error {
+	jsonData, err := json.Marshal(struct {
+		OrderID string `json:"order_id"`
+	}{OrderID: orderID})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest("POST", APIBaseURL+"/cancel_order", bytes.NewBuffer(jsonData))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	auth.Authenticate(req)
+
+	client, err := newMarketAPIClient()
+	if err != nil {
+		return fmt.Errorf("build market api client: %w", err)
+	}
+
+	start := time.Now()
+	resp, err := client.Do(req)
+	if err != nil {
+		recordDebugEntry("cancel_order", jsonData, nil, err, time.Since(start))
+		return err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		recordDebugEntry("cancel_order", jsonData, nil, err, time.Since(start))
+		return err
+	}
+
+	var result struct {
+		Code int    `json:"code"`
+		Msg  string `json:"msg"`
+	}
+	if err := json.Unmarshal(body, &result); err != nil {
+		parseErr := fmt.Errorf("parse cancel order response failed: %v, body: %s", err, string(body))
+		recordDebugEntry("cancel_order", jsonData, body, parseErr, time.Since(start))
+		return parseErr
+	}
+	if result.Code != 0 {
+		cancelErr := fmt.Errorf("cancel order %s failed: %s", orderID, result.Msg)
+		recordDebugEntry("cancel_order", jsonData, body, cancelErr, time.Since(start))
+		return cancelErr
+	}
+
+	recordDebugEntry("cancel_order", jsonData, body, nil, time.Since(start))
+	return nil
+}
+
+// activeMarketKeys 返回当前活跃 (未关闭/未结算/未过期) 的事件+市场组合的
+// marketKey 集合, sweepStaleOrders 用它判断一笔挂单所在的市场是否已经
+// 不再活跃。
+func activeMarketKeys(db *sql.DB) (map[string]bool, error) {
+	events, err := GetActiveEvents(db)
+	if err != nil {
+		return nil, fmt.Errorf("get active events failed: %w", err)
+	}
+
+	keys := make(map[string]bool)
+	for _, event := range events {
+		for _, market := range event.Markets {
+			keys[marketKey(event.ID, market.ID)] = true
+		}
+	}
+	return keys, nil
+}
+
+// sweepStaleOrders 检查 sessions 里每个账户的挂单, 撤销超过 maxAge 的、
+// 或者所在市场已经不在 activeKeys 里的挂单。单个账户/单个订单撤销失败
+// 只记录日志, 不影响其他账户或其他订单的清理。
+func sweepStaleOrders(sessions []*Session, activeKeys map[string]bool, maxAge time.Duration) {
+	now := time.Now()
+
+	for _, session := range sessions {
+		orders, err := GetOpenOrders(session.Authenticator())
+		if err != nil {
+			log.Printf("janitor: get open orders for %s failed: %v", session.AccountKey, err)
+			continue
+		}
+
+		for _, order := range orders {
+			age := now.Sub(time.Unix(order.CreatedAt, 0))
+			stale := age > maxAge
+			orphaned := !activeKeys[marketKey(order.EventID, order.MarketID)]
+			if !stale && !orphaned {
+				continue
+			}
+
+			reason := "stale"
+			if orphaned {
+				reason = "market no longer active"
+			}
+			log.Printf("janitor: cancelling order %s for %s (%s, age=%s)", order.OrderID, session.AccountKey, reason, age.Round(time.Second))
+			if err := CancelOrder(session.Authenticator(), order.OrderID); err != nil {
+				log.Printf("janitor: cancel order %s for %s failed: %v", order.OrderID, session.AccountKey, err)
+			}
+		}
+	}
+}
+
+// cancelMarketOrders 撤销 sessions 在 (eventID, 某个 marketIDs 里的
+// marketID) 上的全部挂单, 供 event watcher 在事件关闭/结算/过期时立即清理
+// 用, 跟 sweepStaleOrders 一样单个订单撤销失败只记录日志, 不影响其他订单。
+func cancelMarketOrders(sessions []*Session, eventID int64, marketIDs []int16) {
+	if len(marketIDs) == 0 {
+		return
+	}
+	markets := make(map[int16]bool, len(marketIDs))
+	for _, id := range marketIDs {
+		markets[id] = true
+	}
+
+	for _, session := range sessions {
+		orders, err := GetOpenOrders(session.Authenticator())
+		if err != nil {
+			log.Printf("event watcher: get open orders for %s failed: %v", session.AccountKey, err)
+			continue
+		}
+
+		for _, order := range orders {
+			if order.EventID != eventID || !markets[order.MarketID] {
+				continue
+			}
+			log.Printf("event watcher: cancelling order %s for %s (market %s no longer active)",
+				order.OrderID, session.AccountKey, marketKey(eventID, order.MarketID))
+			if err := CancelOrder(session.Authenticator(), order.OrderID); err != nil {
+				log.Printf("event watcher: cancel order %s for %s failed: %v", order.OrderID, session.AccountKey, err)
+			}
+		}
+	}
+}
+
+// RunStaleOrderJanitor 在独立于主策略循环的节奏下, 每隔 interval 巡检一次
+// sessions 的挂单并清理孤儿单, 直到 stop 被关闭。
+func RunStaleOrderJanitor(db *sql.DB, sessions []*Session, interval, maxAge time.Duration, stop <-chan struct{}) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			activeKeys, err := activeMarketKeys(db)
+			if err != nil {
+				log.Printf("janitor: %v", err)
+				continue
+			}
+			sweepStaleOrders(sessions, activeKeys, maxAge)
+		}
+	}
+}
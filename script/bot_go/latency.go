@@ -0,0 +1,148 @@
+package main
+
+import (
+	"log"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Stage identifies one leg of an order's path through the bot, from depth
+// fetch to the exchange's fill/reject ack, so latency can be attributed to
+// where the ~hundreds of ms per order actually go instead of only seeing
+// the total.
+type Stage string
+
+const (
+	StageDepthFetch    Stage = "depth_fetch"
+	StagePricing       Stage = "pricing"
+	StageSigning       Stage = "signing"
+	StageHTTPRoundTrip Stage = "http_round_trip"
+	StageEngineAck     Stage = "engine_ack"
+)
+
+// stageStats accumulates count/min/max/sum for one stage, enough to report
+// average and worst-case latency without pulling in a metrics dependency
+// for what is, so far, an in-process log summary. latencyExporter is the
+// extension point real OpenTelemetry histograms hang off once the tracing
+// plumbing from purplecity/PredictionMarket#synth-2139 lands.
+type stageStats struct {
+	count int64
+	sum   time.Duration
+	min   time.Duration
+	max   time.Duration
+}
+
+func (s *stageStats) record(d time.Duration) {
+	if s.count == 0 || d < s.min {
+		s.min = d
+	}
+	if d > s.max {
+		s.max = d
+	}
+	s.sum += d
+	s.count++
+}
+
+// LatencyStats is a stage's aggregated timing, exposed to callers of
+// LatencySnapshot.
+type LatencyStats struct {
+	Stage Stage
+	Count int64
+	Avg   time.Duration
+	Min   time.Duration
+	Max   time.Duration
+}
+
+var (
+	latencyMu    sync.Mutex
+	latencyStats = make(map[Stage]*stageStats)
+
+	// latencyExporter, if set, receives every recorded stage sample in
+	// addition to the in-process aggregation above. It is the extension
+	// point OpenTelemetry export hangs off once that work lands; nil is a
+	// safe no-op default.
+	latencyExporter func(stage Stage, d time.Duration)
+)
+
+// SetLatencyExporter installs fn to receive every stage timing as it's
+// recorded, e.g. to feed an OTel histogram. Pass nil to disable.
+func SetLatencyExporter(fn func(stage Stage, d time.Duration)) {
+	latencyMu.Lock()
+	defer latencyMu.Unlock()
+	latencyExporter = fn
+}
+
+// recordLatencyStage records one timing sample for stage.
+func recordLatencyStage(stage Stage, d time.Duration) {
+	latencyMu.Lock()
+	stats, ok := latencyStats[stage]
+	if !ok {
+		stats = &stageStats{}
+		latencyStats[stage] = stats
+	}
+	stats.record(d)
+	exporter := latencyExporter
+	latencyMu.Unlock()
+
+	if exporter != nil {
+		exporter(stage, d)
+	}
+}
+
+// LatencySnapshot returns the current aggregated stats for every stage
+// that has recorded at least one sample, ordered depth_fetch -> pricing ->
+// signing -> http_round_trip -> engine_ack, matching the order they occur
+// in an order's lifecycle.
+func LatencySnapshot() []LatencyStats {
+	order := []Stage{StageDepthFetch, StagePricing, StageSigning, StageHTTPRoundTrip, StageEngineAck}
+
+	latencyMu.Lock()
+	defer latencyMu.Unlock()
+
+	snapshot := make([]LatencyStats, 0, len(latencyStats))
+	seen := make(map[Stage]bool, len(order))
+	for _, stage := range order {
+		if stats, ok := latencyStats[stage]; ok {
+			snapshot = append(snapshot, statsToLatencyStats(stage, stats))
+			seen[stage] = true
+		}
+	}
+	// Any stage not in the known lifecycle order (custom stages recorded
+	// by callers) is appended afterward, sorted for stable output.
+	var extra []Stage
+	for stage := range latencyStats {
+		if !seen[stage] {
+			extra = append(extra, stage)
+		}
+	}
+	sort.Slice(extra, func(i, j int) bool { return extra[i] < extra[j] })
+	for _, stage := range extra {
+		snapshot = append(snapshot, statsToLatencyStats(stage, latencyStats[stage]))
+	}
+
+	return snapshot
+}
+
+func statsToLatencyStats(stage Stage, s *stageStats) LatencyStats {
+	avg := time.Duration(0)
+	if s.count > 0 {
+		avg = s.sum / time.Duration(s.count)
+	}
+	return LatencyStats{Stage: stage, Count: s.count, Avg: avg, Min: s.min, Max: s.max}
+}
+
+// logLatencySnapshot writes one log line per stage with samples so far,
+// via the standard logger start_bot already routes to bot.log and stdout.
+// RunBot calls this at the end of every cycle, so operators tailing the
+// log can see where per-order time is going without a separate dashboard.
+func logLatencySnapshot() {
+	snapshot := LatencySnapshot()
+	if len(snapshot) == 0 {
+		return
+	}
+
+	for _, s := range snapshot {
+		log.Printf("latency stage=%s count=%d avg=%s min=%s max=%s", s.Stage, s.Count, s.Avg, s.Min, s.Max)
+	}
+}
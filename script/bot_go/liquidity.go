@@ -0,0 +1,167 @@
+package main
+
+import (
+	"math"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// MaxTimeToExpiryCredit 是时间到期分量能贡献的最长"剩余时间", 超过这个
+// 值的市场 (例如几个月后才结算的事件) 不会因为剩余时间更长而额外加分,
+// 避免评分被少数远期市场主导。
+const MaxTimeToExpiryCredit = 7 * 24 * time.Hour
+
+// LiquidityInput 是给某个市场打分需要的原始信号, 全部来自已经在
+// ProcessMarket 里取到的数据, 不需要额外请求。
+type LiquidityInput struct {
+	// Volume 是该市场所属 event 的累计成交量 (USDC)。
+	Volume decimal.Decimal
+	// DepthUSDC 是买卖盘口前几档的名义价值 (USDC), 越厚说明越容易成交。
+	DepthUSDC decimal.Decimal
+	// TimeToExpiry 是距离结算还有多久, 越临近结算的市场留给挂单成交、
+	// 平仓的时间越少。
+	TimeToExpiry time.Duration
+}
+
+// ScoreMarket 把 volume/depth/time-to-expiry 三个信号合成一个非负分数,
+// 分数越高说明这个市场越值得多分配报价预算。三个分量都先做
+// log1p 压缩, 避免个别极端值 (比如一个大户市场的成交量) 把其余市场的
+// 分数全部挤到接近零。
+func ScoreMarket(in LiquidityInput) float64 {
+	volumeScore := math.Log1p(clampNonNegative(in.Volume))
+	depthScore := math.Log1p(clampNonNegative(in.DepthUSDC))
+
+	ttl := in.TimeToExpiry
+	if ttl < 0 {
+		ttl = 0
+	}
+	if ttl > MaxTimeToExpiryCredit {
+		ttl = MaxTimeToExpiryCredit
+	}
+	timeScore := ttl.Hours() / MaxTimeToExpiryCredit.Hours()
+
+	// volume/depth 是流动性有多好的直接信号, 权重更高; 剩余时间只是个
+	// 次要的调节项 (临近结算的市场即使流动性好, 也没剩多少时间吃到
+	// 挂单成交)。
+	return volumeScore*0.5 + depthScore*0.3 + timeScore*0.2
+}
+
+func clampNonNegative(d decimal.Decimal) float64 {
+	if d.IsNegative() {
+		return 0
+	}
+	f, _ := d.Float64()
+	return f
+}
+
+// allocateMarketBudgets 给 events 里的每个市场打分并分配这一轮的报价
+// 预算, 返回按 marketKey(eventID, marketID) 索引的 map。总预算是
+// OrderUSDC 乘以市场数量, 保证平均下来和原来的固定 OrderUSDC 打平, 只是
+// 把预算从流动性差的市场挪给流动性好的市场; 每个市场至少能拿到
+// MinMarketBudgetUSDC, 避免冷门市场直接被分配到报价不了的金额。
+func allocateMarketBudgets(events []Event) map[string]float64 {
+	scores := make(map[string]float64)
+	marketCount := 0
+
+	for _, event := range events {
+		for _, market := range event.Markets {
+			marketCount++
+			key := marketKey(event.ID, market.ID)
+
+			var depthUSDC decimal.Decimal
+			if depth, err := GetDepth(event.ID, market.ID); err == nil {
+				depthUSDC = depthNotionalUSDC(depth)
+			}
+
+			var timeToExpiry time.Duration
+			if event.EndDate.Valid {
+				timeToExpiry = time.Until(event.EndDate.Time)
+			} else {
+				timeToExpiry = MaxTimeToExpiryCredit
+			}
+
+			scores[key] = ScoreMarket(LiquidityInput{
+				Volume:       event.Volume,
+				DepthUSDC:    depthUSDC,
+				TimeToExpiry: timeToExpiry,
+			})
+		}
+	}
+
+	if marketCount == 0 {
+		return map[string]float64{}
+	}
+
+	totalBudgetUSDC := OrderUSDC * float64(marketCount)
+	return AllocateBudget(scores, totalBudgetUSDC, MinMarketBudgetUSDC)
+}
+
+// depthNotionalUSDC 把一个市场盘口前几档的买卖单加总成名义价值 (USDC),
+// 作为流动性打分的深度分量。
+func depthNotionalUSDC(depth *DepthResponse) decimal.Decimal {
+	total := decimal.Zero
+	for _, book := range depth.Data.Depths {
+		total = total.Add(levelsNotional(book.Bids)).Add(levelsNotional(book.Asks))
+	}
+	return total
+}
+
+func levelsNotional(levels []PriceLevelInfo) decimal.Decimal {
+	total := decimal.Zero
+	for _, level := range levels {
+		price, err := decimal.NewFromString(level.Price)
+		if err != nil {
+			continue
+		}
+		qty, err := decimal.NewFromString(level.Quantity)
+		if err != nil {
+			continue
+		}
+		total = total.Add(price.Mul(qty))
+	}
+	return total
+}
+
+// MinMarketBudgetUSDC 是流动性打分再低也要给一个市场分配的最低报价预算。
+const MinMarketBudgetUSDC = OrderUSDC / 4
+
+// AllocateBudget 把 totalBudgetUSDC 按 scores 里的分数比例分给每个市场,
+// 每个市场至少拿到 minUSDC (避免评分很低的市场直接被分到 0、完全停止
+// 报价)。scores 全为零或为空时退化成对所有市场平均分配。
+func AllocateBudget(scores map[string]float64, totalBudgetUSDC, minUSDC float64) map[string]float64 {
+	allocation := make(map[string]float64, len(scores))
+	if len(scores) == 0 {
+		return allocation
+	}
+
+	var sum float64
+	for _, s := range scores {
+		if s > 0 {
+			sum += s
+		}
+	}
+
+	if sum <= 0 {
+		equalShare := totalBudgetUSDC / float64(len(scores))
+		if equalShare < minUSDC {
+			equalShare = minUSDC
+		}
+		for key := range scores {
+			allocation[key] = equalShare
+		}
+		return allocation
+	}
+
+	for key, s := range scores {
+		if s < 0 {
+			s = 0
+		}
+		share := totalBudgetUSDC * (s / sum)
+		if share < minUSDC {
+			share = minUSDC
+		}
+		allocation[key] = share
+	}
+	return allocation
+}
@@ -2,7 +2,7 @@ package main
 
 import (
 	"bytes"
-	"crypto/ecdsa"
+	"context"
 	"database/sql"
 	"encoding/json"
 	"fmt"
@@ -11,79 +11,233 @@ import (
 	"math/rand"
 	"net/http"
 	"os"
+	"strconv"
+	"sync"
 	"time"
 
-	"bot_go/eip712"
+	"eip712"
 
-	"github.com/ethereum/go-ethereum/common/hexutil"
-	"github.com/ethereum/go-ethereum/crypto"
 	_ "github.com/lib/pq"
+	"github.com/redis/go-redis/v9"
 	"github.com/shopspring/decimal"
+	"httpclient"
+	"privy"
+	"risk"
+	"secrets"
+	"tracing"
+	"units"
 )
 
 // 配置常量
 const (
 	// API 地址
-	PrivyNonceURL = "https://auth.privy.io/api/v1/siwe/init"
-	PrivyAuthURL  = "https://auth.privy.io/api/v1/siwe/authenticate"
-	APIBaseURL    = "https://predictionmarket-api-290128242879.asia-northeast1.run.app/api" // 预测市场 API 地址
+	APIBaseURL = "https://predictionmarket-api-290128242879.asia-northeast1.run.app/api" // 预测市场 API 地址
 
-	// Privy 请求头
-	PrivyAppID  = "cmi5m5vdz006lks0cbixho6k0"
-	PrivyClient = "react-auth:3.6.1"
-	PrivyOrigin = "https://deepsense-website-290128242879.asia-northeast1.run.app"
-
-	// 数据库配置
-	DBHost     = "34.146.110.159"
-	DBPort     = 5432
-	DBUser     = "postgres"
-	DBPassword = "0gZUDGsz1sFy0avm2VHd!"
-	DBName     = "deepsense"
-
-	// 链 ID
-	ChainID = 97
+	// 数据库配置 (密码等敏感信息通过 loadCredentials 从 CredentialsProvider 获取)
+	DBHost = "34.146.110.159"
+	DBPort = 5432
+	DBUser = "postgres"
+	DBName = "deepsense"
 
 	// 定时执行间隔
 	IntervalMinutes = 30
 
 	// 下单金额 (USDC)
 	OrderUSDC = 2.0
+
+	// 套利下单金额 (USDC), 即同时买入 Yes+No 一篮子的总成本上限
+	ArbOrderUSDC = 2.0
+
+	// 单个市场累计套利敞口上限 (USDC), 超过则跳过该市场直到敞口被平仓
+	ArbMaxPositionUSDC = 20.0
+
+	// DefaultFeeRateBps 默认手续费率 (基点), 未按市场单独配置时使用
+	DefaultFeeRateBps = 0
 )
 
-// 账户信息
-var (
+// marketFeeRateBpsMu 保护 marketFeeRateBps, 因为 ReloadDynamicConfig 会在
+// 主策略循环之外的 goroutine 里替换整张表。
+var marketFeeRateBpsMu sync.RWMutex
+
+// marketFeeRateBps 按市场 ID 覆盖手续费率 (基点), 未命中则回退到
+// DefaultFeeRateBps。交易所目前对所有市场统一收费, 该表暂时为空占位,
+// 后续如有分级费率再填入, 也可以通过 bot config 文件热更新, 见 config.go。
+var marketFeeRateBps = map[int16]int64{}
+
+// FeeRateBpsForMarket 返回给定市场下单时应使用的手续费率 (基点)。
+func FeeRateBpsForMarket(marketID int16) int64 {
+	marketFeeRateBpsMu.RLock()
+	defer marketFeeRateBpsMu.RUnlock()
+	if rate, ok := marketFeeRateBps[marketID]; ok {
+		return rate
+	}
+	return DefaultFeeRateBps
+}
+
+// SetMarketFeeRateBps 整体替换 marketFeeRateBps, 供 ReloadDynamicConfig
+// 热更新手续费率使用。
+func SetMarketFeeRateBps(rates map[int16]int64) {
+	marketFeeRateBpsMu.Lock()
+	defer marketFeeRateBpsMu.Unlock()
+	marketFeeRateBps = rates
+}
+
+// MarketFeeRateBpsSnapshot 返回 marketFeeRateBps 当前内容的拷贝, 供
+// ReloadDynamicConfig 比较变更用于审计日志。
+func MarketFeeRateBpsSnapshot() map[int16]int64 {
+	marketFeeRateBpsMu.RLock()
+	defer marketFeeRateBpsMu.RUnlock()
+	out := make(map[int16]int64, len(marketFeeRateBps))
+	for k, v := range marketFeeRateBps {
+		out[k] = v
+	}
+	return out
+}
+
+// feeAdjustedOppositePrice 计算对敲另一腿的挂单价, 使成交后到手金额扣除
+// 手续费 (从赎回/成交的净收益一侧收取) 仍不低于两腿总成本, 而不是简单假设
+// 手续费为 0 时的 (1 - price)。
+func feeAdjustedOppositePrice(price decimal.Decimal, feeRateBps int64) decimal.Decimal {
+	feeRate := decimal.NewFromInt(feeRateBps).Div(decimal.NewFromInt(10000))
+	netPayout := decimal.NewFromInt(1).Mul(decimal.NewFromInt(1).Sub(feeRate))
+	return netPayout.Sub(price)
+}
+
+// feeRateFraction 把基点手续费率换算成小数, 跟 feeAdjustedOppositePrice/
+// ProcessArbitrage/ProcessNegRiskArbitrage 里各自重复的换算是同一个公式,
+// spendBudget 记录一笔订单实付手续费时也用它。
+func feeRateFraction(feeRateBps int64) decimal.Decimal {
+	return decimal.NewFromInt(feeRateBps).Div(decimal.NewFromInt(10000))
+}
+
+// 账户信息 (非敏感字段: 私钥/API Key 见 Credentials)
+const (
 	// 账户1: 吃单账号 (user_id=16)
-	Account1PrivateKey           = "3f060945b644e0f3d1b9db8481dcdc62c7f8cd6628c8c271c983f0db6e279653"
-	Account1Address              = "0x62924ea9188Ad1228eEa76931B595c781b72b664"
-	Account1FetchTokenPrivateKey = "b0be8b6d672323dbbd54c5130c70b4a4384560104b7f19e9b9c7bbc674b10e51"
-	Account1FetchTokenPublicKey  = "0xC130e75851A2cF13D3BdB0D76471F9f30Cab136A"
-	Account1ApiKey               = "cmio6moiu00s1jx0b7oaro1kt"
+	Account1Address             = "0x62924ea9188Ad1228eEa76931B595c781b72b664"
+	Account1FetchTokenPublicKey = "0xC130e75851A2cF13D3BdB0D76471F9f30Cab136A"
+	Account1UserID              = 16
 	// 账户2: 挂单账号 (user_id=26)
-	Account2PrivateKey           = "78fb9ba7c9796c3c22067862f3841d4051ec198b92e1ce84c81772ec6e0dfa72"
-	Account2Address              = "0xF3D4d60F7562e505383d992E33e8E3cf5e79A7de"
-	Account2FetchTokenPrivateKey = "3698259e1c6623f313e59e30d194045efb1cd94f0d7fea85e423fc0ee4c13282"
-	Account2FetchTokenPublicKey  = "0x3407C5690e06c2A477C821F20D568Ce3c1692D9b"
-	Account2ApiKey               = "cmj2ivxmb00owl40cvtmuz2j7"
+	Account2Address             = "0xF3D4d60F7562e505383d992E33e8E3cf5e79A7de"
+	Account2FetchTokenPublicKey = "0x3407C5690e06c2A477C821F20D568Ce3c1692D9b"
+	Account2UserID              = 26
+
+	// 风控引擎中标识两个账户的 key
+	RiskAccount1 = "account1"
+	RiskAccount2 = "account2"
+
+	// Kill switch: bot 每个周期检查该 Redis key, 置位后停止下单并撤销
+	// 两个账户的全部挂单, 供紧急情况下人工介入
+	KillSwitchRedisAddr = "127.0.0.1:8889"
+	KillSwitchRedisDB   = 0
+	KillSwitchRedisKey  = "bot_go:kill_switch"
+)
+
+// riskEngine 在下单前检查并累计每个账户的敞口/单市场下单次数, 避免单次
+// 循环异常时无限制地反复下单; 默认限额可通过 SetLimits 在运行期调整。
+var riskEngine = risk.NewEngine()
+
+// quoteThrottle 在 riskEngine 之外额外限制下单/撤单的调用节奏: 单个市场
+// 的最小 requote 间隔, 以及每个账户每分钟的下单动作预算, 防止策略出 bug
+// 后陷入 cancel/replace 死循环把 API 打爆。违规次数可通过
+// quoteThrottle.Violations() 读出, 用于监控告警。
+var quoteThrottle = risk.NewThrottle()
+
+// 凭据名称: 对应 CredentialsProvider 中的密钥/密码条目
+const (
+	credDBPassword               = "DB_PASSWORD"
+	credAccount1PrivateKey       = "ACCOUNT1_PRIVATE_KEY"
+	credAccount1FetchTokenPriKey = "ACCOUNT1_FETCH_TOKEN_PRIVATE_KEY"
+	credAccount1ApiKey           = "ACCOUNT1_API_KEY"
+	credAccount2PrivateKey       = "ACCOUNT2_PRIVATE_KEY"
+	credAccount2FetchTokenPriKey = "ACCOUNT2_FETCH_TOKEN_PRIVATE_KEY"
+	credAccount2ApiKey           = "ACCOUNT2_API_KEY"
+	credKillSwitchRedisPassword  = "KILL_SWITCH_REDIS_PASSWORD"
+	credTreasuryPrivateKey       = "TREASURY_PRIVATE_KEY"
 )
 
-// NonceResponse Privy nonce 响应
-type NonceResponse struct {
-	Nonce     string `json:"nonce"`
-	Address   string `json:"address"`
-	ExpiresAt string `json:"expires_at"`
+// Credentials 持有运行 bot 所需的全部敏感值, 统一通过 loadCredentials 解析,
+// 避免在源码中出现明文密码/私钥。
+type Credentials struct {
+	DBPassword               string
+	Account1PrivateKey       string
+	Account1FetchTokenPriKey string
+	Account1ApiKey           string
+	Account2PrivateKey       string
+	Account2FetchTokenPriKey string
+	Account2ApiKey           string
+	KillSwitchRedisPassword  string
+	TreasuryPrivateKey       string
 }
 
-// AuthResponse Privy 认证响应
-type AuthResponse struct {
-	User          any    `json:"user"`
-	Token         string `json:"token"`
-	IdentityToken string `json:"identity_token"`
+// newCredentialsProvider 根据 CREDENTIALS_PROVIDER 环境变量选择凭据来源,
+// 默认使用环境变量 (适合本地开发/容器注入), 生产部署可切换为 gcp 或 vault。
+func newCredentialsProvider(ctx context.Context) (secrets.CredentialsProvider, error) {
+	switch os.Getenv("CREDENTIALS_PROVIDER") {
+	case "", "env":
+		return secrets.EnvProvider{}, nil
+	case "file":
+		return secrets.FileProvider{Dir: os.Getenv("CREDENTIALS_DIR")}, nil
+	case "gcp":
+		manager, err := secrets.NewManager(ctx, os.Getenv("GCP_PROJECT_ID"), 5*time.Minute)
+		if err != nil {
+			return nil, fmt.Errorf("create secret manager: %w", err)
+		}
+		return secrets.SecretManagerProvider{Manager: manager}, nil
+	case "vault":
+		return secrets.VaultProvider{
+			Addr:      os.Getenv("VAULT_ADDR"),
+			Token:     os.Getenv("VAULT_TOKEN"),
+			MountPath: os.Getenv("VAULT_MOUNT_PATH"),
+		}, nil
+	default:
+		return nil, fmt.Errorf("unknown CREDENTIALS_PROVIDER %q", os.Getenv("CREDENTIALS_PROVIDER"))
+	}
+}
+
+// loadCredentials 解析运行 bot 所需的全部凭据。
+func loadCredentials(ctx context.Context) (*Credentials, error) {
+	provider, err := newCredentialsProvider(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("select credentials provider: %w", err)
+	}
+
+	get := func(name string) (string, error) {
+		value, err := provider.GetCredential(ctx, name)
+		if err != nil {
+			return "", fmt.Errorf("load credential %q: %w", name, err)
+		}
+		return value, nil
+	}
+
+	creds := &Credentials{}
+	for name, dst := range map[string]*string{
+		credDBPassword:               &creds.DBPassword,
+		credAccount1PrivateKey:       &creds.Account1PrivateKey,
+		credAccount1FetchTokenPriKey: &creds.Account1FetchTokenPriKey,
+		credAccount1ApiKey:           &creds.Account1ApiKey,
+		credAccount2PrivateKey:       &creds.Account2PrivateKey,
+		credAccount2FetchTokenPriKey: &creds.Account2FetchTokenPriKey,
+		credAccount2ApiKey:           &creds.Account2ApiKey,
+		credKillSwitchRedisPassword:  &creds.KillSwitchRedisPassword,
+		credTreasuryPrivateKey:       &creds.TreasuryPrivateKey,
+	} {
+		value, err := get(name)
+		if err != nil {
+			return nil, err
+		}
+		*dst = value
+	}
+
+	return creds, nil
 }
 
 // Event 数据库中的事件
 type Event struct {
 	ID      int64
 	Title   string
+	Topic   string
+	Volume  decimal.Decimal
+	EndDate sql.NullTime
 	Markets map[string]Market
 }
 
@@ -139,6 +293,11 @@ type PlaceOrderRequest struct {
 	MarketID      int16  `json:"market_id"`
 	Price         string `json:"price"`
 	OrderType     string `json:"order_type"`
+
+	// Shares 不参与请求序列化 (json:"-"), 只是把构造时已经算好的份数带给
+	// Session.PlaceOrder 用于登记进 orderManager, 供重复挂单检测按
+	// (账户, token, 价格, 份数) 这个指纹比对使用, 避免额外再传一个参数。
+	Shares int64 `json:"-"`
 }
 
 // PlaceOrderResponse 下单响应
@@ -148,155 +307,59 @@ type PlaceOrderResponse struct {
 	Data string `json:"data"`
 }
 
-// PersonalSign 使用以太坊私钥签名消息
-func PersonalSign(message string, privateKey *ecdsa.PrivateKey) (string, error) {
-	fullMessage := fmt.Sprintf("\x19Ethereum Signed Message:\n%d%s", len(message), message)
-	hash := crypto.Keccak256Hash([]byte(fullMessage))
-	signatureBytes, err := crypto.Sign(hash.Bytes(), privateKey)
-	if err != nil {
-		return "", err
-	}
-	signatureBytes[64] += 27
-	return hexutil.Encode(signatureBytes), nil
-}
-
-// GetPrivyNonce 获取 Privy nonce
-func GetPrivyNonce(address string) (*NonceResponse, error) {
-	payload := map[string]string{"address": address}
-	jsonData, err := json.Marshal(payload)
-	if err != nil {
-		return nil, err
-	}
-
-	req, err := http.NewRequest("POST", PrivyNonceURL, bytes.NewBuffer(jsonData))
-	if err != nil {
-		return nil, err
-	}
-
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("Origin", PrivyOrigin)
-	req.Header.Set("Referer", PrivyOrigin+"/")
-	req.Header.Set("privy-app-id", PrivyAppID)
-	req.Header.Set("privy-client", PrivyClient)
-
-	client := &http.Client{Timeout: 30 * time.Second}
-	resp, err := client.Do(req)
-	if err != nil {
-		return nil, err
-	}
-	defer resp.Body.Close()
-
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, err
-	}
-
-	if resp.StatusCode != 200 {
-		return nil, fmt.Errorf("nonce request failed: %s", string(body))
-	}
-
-	var nonceResp NonceResponse
-	if err := json.Unmarshal(body, &nonceResp); err != nil {
-		return nil, err
-	}
+// marketAPIHTTPConfig and privyHTTPConfig let operators route the market
+// API and Privy separately through a proxy (e.g. Privy needs a different
+// egress path than the market API) and trust a private CA, via
+// BOT_MARKET_API_PROXY_URL/BOT_MARKET_API_CA_CERT_PATH/
+// BOT_MARKET_API_TLS_INSECURE_SKIP_VERIFY and their BOT_PRIVY_ equivalents.
+// Unset, both behave exactly like the bare *http.Client{} this code used
+// before.
+var (
+	marketAPIHTTPConfig = httpclient.FromEnv("BOT_MARKET_API")
+	privyHTTPConfig     = httpclient.FromEnv("BOT_PRIVY")
+)
 
-	return &nonceResp, nil
+// newMarketAPIClient returns an *http.Client for calls to the prediction
+// market API, applying marketAPIHTTPConfig.
+func newMarketAPIClient() (*http.Client, error) {
+	return httpclient.New(marketAPIHTTPConfig, 30*time.Second)
 }
 
-// GetPrivyToken 获取 Privy token
-func GetPrivyToken(address, privateKeyHex, nonce string) (*AuthResponse, error) {
-	// 构建 SIWE 消息
-	issuedAt := time.Now().UTC().Format("2006-01-02T15:04:05.000Z")
-	message := fmt.Sprintf("deepsense-website-290128242879.asia-northeast1.run.app wants you to sign in with your Ethereum account:\n%s\n\nBy signing, you are proving you own this wallet and logging in. This does not initiate a transaction or cost any fees.\n\nURI: https://deepsense-website-290128242879.asia-northeast1.run.app\nVersion: 1\nChain ID: %d\nNonce: %s\nIssued At: %s\nResources:\n- https://privy.io", address, ChainID, nonce, issuedAt)
-
-	log.Println("message: ", message)
-	// 签名
-	privKey, err := crypto.HexToECDSA(privateKeyHex)
-	if err != nil {
-		return nil, fmt.Errorf("invalid private key: %v", err)
-	}
-
-	signature, err := PersonalSign(message, privKey)
-	if err != nil {
-		return nil, fmt.Errorf("sign failed: %v", err)
-	}
-
-	// 构建请求
-	payload := map[string]any{
-		"message":          message,
-		"signature":        signature,
-		"walletClientType": "metamask",
-		"connectorType":    "injected",
-		"mode":             "login-or-sign-up",
-		//"chainId":          fmt.Sprintf("eip155:%d", ChainID),
-	}
-	jsonData, err := json.Marshal(payload)
-	if err != nil {
-		return nil, err
-	}
-
-	req, err := http.NewRequest("POST", PrivyAuthURL, bytes.NewBuffer(jsonData))
-	if err != nil {
-		return nil, err
-	}
-
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("Origin", PrivyOrigin)
-	req.Header.Set("Referer", PrivyOrigin+"/")
-	req.Header.Set("privy-app-id", PrivyAppID)
-	req.Header.Set("privy-client", PrivyClient)
-	// req.Header.Set("privy-ca-id", "24f5d304-8f84-41c7-bf34-638a957152b7")
-	// req.Header.Set("User-Agent", "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/143.0.0.0 Safari/537.36")
-
-	client := &http.Client{Timeout: 30 * time.Second}
-	resp, err := client.Do(req)
-	if err != nil {
-		return nil, err
-	}
-	defer resp.Body.Close()
-
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, err
-	}
-
-	if resp.StatusCode != 200 {
-		return nil, fmt.Errorf("auth request failed: %s", string(body))
-	}
-
-	var authResp AuthResponse
-	if err := json.Unmarshal(body, &authResp); err != nil {
-		return nil, err
+func newPrivyClient() *privy.Client {
+	cfg := privy.DefaultConfig()
+	cfg.ChainID = int64(ActiveChain().ChainID)
+	if client, err := httpclient.New(privyHTTPConfig, 30*time.Second); err != nil {
+		log.Printf("build privy http client failed, using default: %v", err)
+	} else {
+		cfg.HTTPClient = client
 	}
-
-	return &authResp, nil
+	return privy.NewClient(cfg)
 }
 
-// Authenticate 完整的认证流程
+var privyClient = newPrivyClient()
+
+// Authenticate 完整的认证流程: 用私钥对应的钱包登录 Privy, 返回 identity token。
 func Authenticate(address, privateKey string) (string, error) {
 	log.Printf("Authenticating %s...", address)
 
-	// 1. 获取 nonce
-	nonceResp, err := GetPrivyNonce(address)
+	signer, err := privy.NewPrivateKeySigner(privateKey)
 	if err != nil {
-		return "", fmt.Errorf("get nonce failed: %v", err)
+		return "", fmt.Errorf("build signer: %v", err)
 	}
-	log.Printf("Got nonce: %s", nonceResp.Nonce)
 
-	// 2. 获取 token
-	authResp, err := GetPrivyToken(address, privateKey, nonceResp.Nonce)
+	session, err := privyClient.Login(context.Background(), signer)
 	if err != nil {
-		return "", fmt.Errorf("get token failed: %v", err)
+		return "", fmt.Errorf("privy login failed: %v", err)
 	}
 	log.Printf("Got identity_token for %s", address)
 
-	return authResp.IdentityToken, nil
+	return session.IdentityToken, nil
 }
 
 // GetActiveEvents 从数据库获取活跃事件（未关闭、未解决、未过期）
 func GetActiveEvents(db *sql.DB) ([]Event, error) {
 	query := `
-		SELECT id, title, markets
+		SELECT id, title, topic, volume, end_date, markets
 		FROM events
 		WHERE closed = false AND resolved = false AND (end_date IS NULL OR end_date > NOW())
 		ORDER BY id
@@ -310,10 +373,15 @@ func GetActiveEvents(db *sql.DB) ([]Event, error) {
 	var events []Event
 	for rows.Next() {
 		var e Event
+		var volume string
 		var marketsJSON string
-		if err := rows.Scan(&e.ID, &e.Title, &marketsJSON); err != nil {
+		if err := rows.Scan(&e.ID, &e.Title, &e.Topic, &volume, &e.EndDate, &marketsJSON); err != nil {
 			return nil, err
 		}
+		if e.Volume, err = decimal.NewFromString(volume); err != nil {
+			log.Printf("Failed to parse volume for event %d: %v", e.ID, err)
+			e.Volume = decimal.Zero
+		}
 
 		// 解析 markets JSON
 		var marketsMap map[string]struct {
@@ -374,33 +442,71 @@ func GetDepth(eventID int64, marketID int16) (*DepthResponse, error) {
 		return nil, fmt.Errorf("depth API error: %s", depthResp.Msg)
 	}
 
+	observeServerTimestamp(depthResp.Data.Timestamp)
+
 	return &depthResp, nil
 }
 
 // SignOrderLocal 使用本地 eip712 模块签名订单
 func SignOrderLocal(privateKey string, order *eip712.OrderInput) (string, error) {
-	return eip712.SignOrderInput(privateKey, ChainID, order)
+	return eip712.SignOrderInput(privateKey, ActiveChain().ChainID, order)
+}
+
+// Authenticator applies one authentication scheme to an outgoing API
+// request, so PlaceOrder/CancelAllOrders/etc. don't need to know whether
+// the caller is authenticating with an API key or a Privy identity token.
+type Authenticator interface {
+	Authenticate(req *http.Request)
+}
+
+// APIKeyAuth authenticates with the `x-api-key` header the prediction
+// market API issues per account.
+type APIKeyAuth struct {
+	APIKey string
+}
+
+// Authenticate sets the x-api-key header.
+func (a APIKeyAuth) Authenticate(req *http.Request) {
+	req.Header.Set("x-api-key", a.APIKey)
+}
+
+// PrivyTokenAuth authenticates with a Privy identity token as a bearer
+// token, for endpoints that accept a logged-in session instead of an API key.
+type PrivyTokenAuth struct {
+	IdentityToken string
+}
+
+// Authenticate sets the Authorization header.
+func (a PrivyTokenAuth) Authenticate(req *http.Request) {
+	req.Header.Set("Authorization", "Bearer "+a.IdentityToken)
 }
 
 // CancelAllOrders 取消所有未完成订单
-func CancelAllOrders(apiKey string) error {
+func CancelAllOrders(auth Authenticator) error {
 	req, err := http.NewRequest("POST", APIBaseURL+"/cancel_all_orders", nil)
 	if err != nil {
 		return err
 	}
 
 	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("x-api-key", apiKey)
+	auth.Authenticate(req)
 
-	client := &http.Client{Timeout: 30 * time.Second}
+	client, err := newMarketAPIClient()
+	if err != nil {
+		return fmt.Errorf("build market api client: %w", err)
+	}
+
+	start := time.Now()
 	resp, err := client.Do(req)
 	if err != nil {
+		recordDebugEntry("cancel_all_orders", nil, nil, err, time.Since(start))
 		return err
 	}
 	defer resp.Body.Close()
 
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
+		recordDebugEntry("cancel_all_orders", nil, nil, err, time.Since(start))
 		return err
 	}
 
@@ -409,69 +515,190 @@ func CancelAllOrders(apiKey string) error {
 		Msg  string `json:"msg"`
 	}
 	if err := json.Unmarshal(body, &result); err != nil {
-		return fmt.Errorf("parse response failed: %v, body: %s", err, string(body))
+		parseErr := fmt.Errorf("parse response failed: %v, body: %s", err, string(body))
+		recordDebugEntry("cancel_all_orders", nil, body, parseErr, time.Since(start))
+		return parseErr
 	}
 
 	if result.Code != 0 {
-		return fmt.Errorf("cancel all orders failed: %s", result.Msg)
+		cancelErr := fmt.Errorf("cancel all orders failed: %s", result.Msg)
+		recordDebugEntry("cancel_all_orders", nil, body, cancelErr, time.Since(start))
+		return cancelErr
 	}
 
+	recordDebugEntry("cancel_all_orders", nil, body, nil, time.Since(start))
 	log.Printf("All orders cancelled successfully")
 	return nil
 }
 
+// newKillSwitchRedisClient 创建 kill switch 检查用的 Redis 客户端。
+func newKillSwitchRedisClient(creds *Credentials) *redis.Client {
+	return redis.NewClient(&redis.Options{
+		Addr:     KillSwitchRedisAddr,
+		Password: creds.KillSwitchRedisPassword,
+		DB:       KillSwitchRedisDB,
+	})
+}
+
+// isKillSwitchEngaged 检查 kill switch 标志位是否被置位。Redis 不可达时
+// 保守地视为未触发, 避免 Redis 故障被放大成整个 bot 停摆, 但会记录日志
+// 方便排查。
+func isKillSwitchEngaged(ctx context.Context, rdb *redis.Client) bool {
+	val, err := rdb.Get(ctx, KillSwitchRedisKey).Result()
+	if err == redis.Nil {
+		return false
+	}
+	if err != nil {
+		log.Printf("kill switch check failed, treating as disengaged: %v", err)
+		return false
+	}
+	return val == "1"
+}
+
+// cancelAllAccounts 取消账户1和账户2的全部挂单, kill switch 触发或
+// `bot_go kill` CLI 手动执行时使用。
+func cancelAllAccounts(creds *Credentials) {
+	if err := CancelAllOrders(APIKeyAuth{APIKey: creds.Account1ApiKey}); err != nil {
+		IncrementErrorCount("cancel_all_orders")
+		log.Printf("cancel account1 orders failed: %v", err)
+	}
+	if err := CancelAllOrders(APIKeyAuth{APIKey: creds.Account2ApiKey}); err != nil {
+		IncrementErrorCount("cancel_all_orders")
+		log.Printf("cancel account2 orders failed: %v", err)
+	}
+}
+
+// debugRecordFile 为空时调试录制关闭; 设置 BOT_DEBUG_RECORD_FILE 环境变量
+// 后, PlaceOrder/CancelAllOrders 会把每次请求的报文、响应和耗时追加写入
+// 该文件 (JSON Lines), 供 load_gen 的 replay 流程或人工回归排查复用。
+var debugRecordFile = os.Getenv("BOT_DEBUG_RECORD_FILE")
+
+// debugRecord 是调试录制文件里的一行。
+type debugRecord struct {
+	Timestamp string `json:"timestamp"`
+	Endpoint  string `json:"endpoint"`
+	Request   string `json:"request,omitempty"`
+	Response  string `json:"response,omitempty"`
+	Error     string `json:"error,omitempty"`
+	LatencyMs int64  `json:"latency_ms"`
+}
+
+// recordDebugEntry 在 debugRecordFile 未设置时是无操作; 写入失败只记录日志,
+// 不影响下单/撤单本身的结果。
+func recordDebugEntry(endpoint string, request, response []byte, callErr error, latency time.Duration) {
+	if debugRecordFile == "" {
+		return
+	}
+
+	entry := debugRecord{
+		Timestamp: time.Now().Format(time.RFC3339Nano),
+		Endpoint:  endpoint,
+		Request:   string(request),
+		Response:  string(response),
+		LatencyMs: latency.Milliseconds(),
+	}
+	if callErr != nil {
+		entry.Error = callErr.Error()
+	}
+
+	line, err := json.Marshal(entry)
+	if err != nil {
+		log.Printf("debug record encode failed: %v", err)
+		return
+	}
+
+	f, err := os.OpenFile(debugRecordFile, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		log.Printf("debug record open failed: %v", err)
+		return
+	}
+	defer f.Close()
+	if _, err := f.Write(append(line, '\n')); err != nil {
+		log.Printf("debug record write failed: %v", err)
+	}
+}
+
 // PlaceOrder 下单
-func PlaceOrder(apiKey string, order *PlaceOrderRequest) error {
+func PlaceOrder(auth Authenticator, order *PlaceOrderRequest) error {
+	client, err := newMarketAPIClient()
+	if err != nil {
+		return fmt.Errorf("build market api client: %w", err)
+	}
+	_, err = placeOrderWithClient(client, auth, order)
+	return err
+}
+
+// placeOrderWithClient 是 PlaceOrder 的实现, 接受调用方自己的 *http.Client,
+// 供 Session 复用已缓存的 client 而不必每次下单都新建一个, 成功时返回
+// 交易所分配的 order id, 供 Session.PlaceOrder 登记进 orderManager。
+func placeOrderWithClient(client *http.Client, auth Authenticator, order *PlaceOrderRequest) (string, error) {
 	jsonData, err := json.Marshal(order)
 	if err != nil {
-		return err
+		return "", err
 	}
 
 	req, err := http.NewRequest("POST", APIBaseURL+"/place_order", bytes.NewBuffer(jsonData))
 	if err != nil {
-		return err
+		return "", err
 	}
 
 	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("x-api-key", apiKey)
+	auth.Authenticate(req)
+	if tc, err := tracing.New(); err != nil {
+		log.Printf("generate trace context failed: %v", err)
+	} else {
+		tc.InjectHeader(req.Header)
+	}
 
-	client := &http.Client{Timeout: 30 * time.Second}
+	start := time.Now()
+	roundTripStart := time.Now()
 	resp, err := client.Do(req)
+	recordLatencyStage(StageHTTPRoundTrip, time.Since(roundTripStart))
 	if err != nil {
-		return err
+		recordDebugEntry("place_order", jsonData, nil, err, time.Since(start))
+		return "", err
 	}
 	defer resp.Body.Close()
 
+	ackStart := time.Now()
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return err
+		recordDebugEntry("place_order", jsonData, nil, err, time.Since(start))
+		return "", err
 	}
 
 	var orderResp PlaceOrderResponse
 	if err := json.Unmarshal(body, &orderResp); err != nil {
-		return fmt.Errorf("parse response failed: %v, body: %s", err, string(body))
+		parseErr := fmt.Errorf("parse response failed: %v, body: %s", err, string(body))
+		recordDebugEntry("place_order", jsonData, body, parseErr, time.Since(start))
+		return "", parseErr
 	}
+	recordLatencyStage(StageEngineAck, time.Since(ackStart))
 
 	if orderResp.Code != 0 {
-		return fmt.Errorf("place order failed: %s", orderResp.Msg)
+		placeErr := fmt.Errorf("place order failed: %s", orderResp.Msg)
+		recordDebugEntry("place_order", jsonData, body, placeErr, time.Since(start))
+		return "", placeErr
 	}
 
+	recordDebugEntry("place_order", jsonData, body, nil, time.Since(start))
 	log.Printf("Order placed successfully, order_id=%s", orderResp.Data)
-	return nil
+	return orderResp.Data, nil
 }
 
-// CreateBuyOrder 创建买单
-func CreateBuyOrder(privateKey, address, tokenID string, price decimal.Decimal, shares int64, eventID int64, marketID int16) (*PlaceOrderRequest, error) {
-	salt := time.Now().Unix()
-
-	// 10^18
-	unit := decimal.NewFromInt(10).Pow(decimal.NewFromInt(18))
+// CreateBuyOrder 创建买单, feeRateBps 为该市场的手续费率 (基点), 通过
+// FeeRateBpsForMarket 获取, 会写入签名和下单请求中的 FeeRateBps 字段。
+func CreateBuyOrder(privateKey, address, tokenID string, price decimal.Decimal, shares int64, eventID int64, marketID int16, feeRateBps int64) (*PlaceOrderRequest, error) {
+	saltBig, err := eip712.GenerateSalt()
+	if err != nil {
+		return nil, fmt.Errorf("generate salt failed: %v", err)
+	}
+	salt := saltBig.Int64()
 
-	// takerAmount = shares * 10^18
-	takerAmount := decimal.NewFromInt(shares).Mul(unit).String()
+	feeRateBpsStr := fmt.Sprintf("%d", feeRateBps)
 
-	// makerAmount = (shares * price) * 10^18
-	makerAmount := decimal.NewFromInt(shares).Mul(price).Mul(unit).String()
+	takerAmount := units.TakerAmountUnits(shares).String()
+	makerAmount := units.MakerAmountUnits(price, shares).String()
 
 	// 构建签名订单
 	orderInput := &eip712.OrderInput{
@@ -484,13 +711,19 @@ func CreateBuyOrder(privateKey, address, tokenID string, price decimal.Decimal,
 		TakerAmount:   takerAmount,
 		Expiration:    "0",
 		Nonce:         "0",
-		FeeRateBps:    "0",
+		FeeRateBps:    feeRateBpsStr,
 		Side:          0, // buy
 		SignatureType: 0,
 	}
 
+	if err := eip712.ValidateOrderInput(orderInput); err != nil {
+		return nil, fmt.Errorf("invalid order input: %v", err)
+	}
+
 	// 使用本地 eip712 模块签名
+	signingStart := time.Now()
 	signature, err := SignOrderLocal(privateKey, orderInput)
+	recordLatencyStage(StageSigning, time.Since(signingStart))
 	if err != nil {
 		return nil, fmt.Errorf("sign order failed: %v", err)
 	}
@@ -498,7 +731,7 @@ func CreateBuyOrder(privateKey, address, tokenID string, price decimal.Decimal,
 	// 构建下单请求
 	orderReq := &PlaceOrderRequest{
 		Expiration:    "0",
-		FeeRateBps:    "0",
+		FeeRateBps:    feeRateBpsStr,
 		Maker:         address,
 		MakerAmount:   makerAmount,
 		Nonce:         "0",
@@ -514,13 +747,16 @@ func CreateBuyOrder(privateKey, address, tokenID string, price decimal.Decimal,
 		MarketID:      marketID,
 		Price:         price.String(),
 		OrderType:     "limit",
+		Shares:        shares,
 	}
 
 	return orderReq, nil
 }
 
-// ProcessMarket 处理单个市场
-func ProcessMarket(event Event, market Market) error {
+// ProcessMarket 处理单个市场。budgetUSDC 是这个市场这一轮应该用于挂单
+// 的名义金额, 由 RunBot 按流动性打分分配, 取不到分配值时调用方应传入
+// QuoteProfileForTopic(event.Topic).OrderUSDC 作为兜底。
+func ProcessMarket(event Event, market Market, account1, account2 *Session, budgetUSDC float64) error {
 	log.Printf("Processing event %d (%s), market %d (%s)", event.ID, event.Title, market.ID, market.Title)
 
 	if market.Closed {
@@ -537,21 +773,36 @@ func ProcessMarket(event Event, market Market) error {
 	token1ID := market.TokenIDs[1] // No/第二个结果
 
 	// 获取深度
+	depthStart := time.Now()
 	depth, err := GetDepth(event.ID, market.ID)
+	recordLatencyStage(StageDepthFetch, time.Since(depthStart))
 	if err != nil {
 		return fmt.Errorf("get depth failed: %v", err)
 	}
 
+	pricingStart := time.Now()
+
+	// 按 event 所属 topic 取策略参数 (下单金额、让价 tick 数等), 未配置
+	// 该 topic 时回退到全局默认值。
+	profile := QuoteProfileForTopic(event.Topic)
+
 	// 检查 token_1 的买1价
 	var price decimal.Decimal
 	token1Depth, ok := depth.Data.Depths[token1ID]
 	if ok && len(token1Depth.Bids) > 0 {
-		// 有买1价，使用该价格
-		var err error
-		price, err = decimal.NewFromString(token1Depth.Bids[0].Price)
+		// 有买1价, 按 quoteMode 决定是照抄买1价还是 penny-in/join 挂单
+		bestBid, err := decimal.NewFromString(token1Depth.Bids[0].Price)
 		if err != nil {
 			log.Printf("Failed to parse bid price: %v", err)
 			price = decimal.NewFromFloat(0.3 + rand.Float64()*0.2) // 0.3-0.5
+		} else {
+			fairValue, fvErr := fairValueProvider.FairValue(context.Background(), event.ID, market.ID, token1ID)
+			if fvErr != nil {
+				log.Printf("Fair value estimate unavailable for token_1, quoting without it: %v", fvErr)
+				fairValue = decimal.Decimal{}
+			}
+			price = quotePrice(bestBid, fairValue, profile.SpreadTicks)
+			EvaluateShadowQuote(marketKey(event.ID, market.ID), bestBid, fairValue, profile.SpreadTicks, price)
 		}
 	} else {
 		// 没有买1价，随机生成 0.3-0.5
@@ -559,62 +810,255 @@ func ProcessMarket(event Event, market Market) error {
 		log.Printf("No bids found for token_1, using random price: %s", price.String())
 	}
 
-	// 计算 shares: 2美金除以价格然后截断
-	shares := decimal.NewFromFloat(OrderUSDC).Div(price).IntPart()
+	// 计算 shares: 按流动性分配到的下单金额除以价格然后截断
+	shares := decimal.NewFromFloat(budgetUSDC).Div(price).IntPart()
 	if shares <= 0 {
 		shares = 1
 	}
 
-	// 相反价格 (1 - price)
-	oppositePrice := decimal.NewFromInt(1).Sub(price)
+	feeRateBps := FeeRateBpsForMarket(market.ID)
+
+	// 相反价格 (1 - price), 按手续费率折算到净到手金额, 使两腿总成本扣费后
+	// 仍不超过 1, 避免手续费打开后按原价对敲反而亏钱。
+	oppositePrice := feeAdjustedOppositePrice(price, feeRateBps)
+	recordLatencyStage(StagePricing, time.Since(pricingStart))
 
 	log.Printf("Token0: %s, Token1: %s", token0ID[:20]+"...", token1ID[:20]+"...")
-	log.Printf("Price: %s, Opposite: %s, Shares: %d", price.String(), oppositePrice.String(), shares)
+	log.Printf("Price: %s, Opposite: %s, Shares: %d, FeeRateBps: %d", price.String(), oppositePrice.String(), shares, feeRateBps)
 
-	// 账户2 挂 token_1 买单 (先挂单)
-	order2, err := CreateBuyOrder(Account2PrivateKey, Account2Address, token1ID, price, shares, event.ID, market.ID)
-	if err != nil {
-		return fmt.Errorf("create order2 failed: %v", err)
-	}
+	mktKey := marketKey(event.ID, market.ID)
+	notional := units.Notional(price, shares)
 
-	log.Printf("Account2 placing order on token_1 at price %s...", price.String())
-	if err := PlaceOrder(Account2ApiKey, order2); err != nil {
-		log.Printf("Account2 place order failed: %v", err)
+	// 账户2 挂 token_1 买单 (先挂单)
+	var makerOrderID string
+	if err := account2.Allow(mktKey, notional); err != nil {
+		log.Printf("Account2 order blocked: %v", err)
+	} else if orderManager.HasEquivalent(account2.AccountKey, token1ID, price.String(), shares) {
+		log.Printf("Account2 order on token_1 at price %s for %d shares already resting, skipping duplicate", price.String(), shares)
 	} else {
-		log.Printf("Account2 order placed successfully")
+		order2, err := account2.CreateBuyOrder(token1ID, price, shares, event.ID, market.ID, feeRateBps)
+		if err != nil {
+			return fmt.Errorf("create order2 failed: %v", err)
+		}
+
+		log.Printf("Account2 placing order on token_1 at price %s...", price.String())
+		var placeErr error
+		makerOrderID, placeErr = account2.PlaceOrder(order2)
+		makerRewards.RecordMakerQuoteAttempt(mktKey, notional, placeErr == nil, time.Now())
+		if placeErr != nil {
+			log.Printf("Account2 place order failed: %v", placeErr)
+		} else {
+			log.Printf("Account2 order placed successfully")
+			spendBudget.RecordSpend(notional, shares, notional.Mul(feeRateFraction(feeRateBps)), time.Now())
+		}
 	}
 
-	// 等待 6 秒
-	log.Printf("Waiting 6 seconds...")
-	time.Sleep(6 * time.Second)
+	// 等待固定/随机延迟, 打开 MAKER_TAKER_CONFIRM_RESTING 时额外确认账户2
+	// 的做市单已经出现在盘口上再让账户1吃单, 而不是只靠猜的延迟。
+	WaitBeforeCrossing(account2.Authenticator(), makerOrderID)
 
 	// 账户1 挂 token_0 买单 (吃单)
-	order1, err := CreateBuyOrder(Account1PrivateKey, Account1Address, token0ID, oppositePrice, shares, event.ID, market.ID)
+	if err := account1.Allow(mktKey, notional); err != nil {
+		log.Printf("Account1 order blocked: %v", err)
+		return nil
+	}
+
+	if token0Depth, ok := depth.Data.Depths[token0ID]; ok && len(token0Depth.Asks) > 0 {
+		bestAsk, err := decimal.NewFromString(token0Depth.Asks[0].Price)
+		if err != nil {
+			log.Printf("Failed to parse token_0 best ask, skipping impact check: %v", err)
+		} else {
+			allowed, impactTicks, err := CheckTakerImpact(token0Depth.Asks, bestAsk, shares)
+			if err != nil {
+				log.Printf("Taker impact estimate failed, skipping impact check: %v", err)
+			} else if !allowed {
+				log.Printf("Account1 order on token_0 skipped: estimated impact %s ticks exceeds limit %s", impactTicks.String(), maxTakerImpactTicks().String())
+				return nil
+			}
+		}
+	}
+
+	if orderManager.HasEquivalent(account1.AccountKey, token0ID, oppositePrice.String(), shares) {
+		log.Printf("Account1 order on token_0 at price %s for %d shares already resting, skipping duplicate", oppositePrice.String(), shares)
+		return nil
+	}
+
+	order1, err := account1.CreateBuyOrder(token0ID, oppositePrice, shares, event.ID, market.ID, feeRateBps)
 	if err != nil {
 		return fmt.Errorf("create order1 failed: %v", err)
 	}
 
 	log.Printf("Account1 placing order on token_0 at price %s...", oppositePrice.String())
-	if err := PlaceOrder(Account1ApiKey, order1); err != nil {
+	if _, err := account1.PlaceOrder(order1); err != nil {
 		log.Printf("Account1 place order failed: %v", err)
 	} else {
 		log.Printf("Account1 order placed successfully")
+		account1Notional := units.Notional(oppositePrice, shares)
+		spendBudget.RecordSpend(account1Notional, shares, account1Notional.Mul(feeRateFraction(feeRateBps)), time.Now())
+	}
+
+	return nil
+}
+
+// arbExposureMu 保护 arbExposure, bot 目前没有其他持久化状态存储, 敞口只在
+// 进程内存中累计。
+var arbExposureMu sync.Mutex
+var arbExposure = make(map[string]decimal.Decimal)
+
+// marketKey 生成事件/市场维度的 map key, 供套利敞口跟踪和风控引擎共用。
+func marketKey(eventID int64, marketID int16) string {
+	return fmt.Sprintf("%d/%d", eventID, marketID)
+}
+
+// reserveArbExposure 在下单前尝试为该市场预留 notional 敞口, 超过
+// ArbMaxPositionUSDC 则拒绝, 避免同一篮子机会被反复无限加仓。
+func reserveArbExposure(key string, notional decimal.Decimal) bool {
+	arbExposureMu.Lock()
+	defer arbExposureMu.Unlock()
+
+	current := arbExposure[key]
+	if current.Add(notional).GreaterThan(decimal.NewFromFloat(ArbMaxPositionUSDC)) {
+		return false
+	}
+	arbExposure[key] = current.Add(notional)
+	return true
+}
+
+// ProcessArbitrage 检查同一市场 Yes/No 两个 token 的卖1价之和是否低于 1
+// (扣除手续费后), 如果存在无风险套利机会, 则用两个账户各买入一份组成
+// 一篮子完整头寸, 是现有两 token 对敲逻辑的自然延伸。
+func ProcessArbitrage(event Event, market Market, account1, account2 *Session) error {
+	if market.Closed {
+		return nil
+	}
+
+	if len(market.TokenIDs) < 2 {
+		return nil
+	}
+
+	token0ID := market.TokenIDs[0] // Yes/第一个结果
+	token1ID := market.TokenIDs[1] // No/第二个结果
+
+	// 获取深度
+	depth, err := GetDepth(event.ID, market.ID)
+	if err != nil {
+		return fmt.Errorf("get depth failed: %v", err)
+	}
+
+	token0Depth, ok := depth.Data.Depths[token0ID]
+	if !ok || len(token0Depth.Asks) == 0 {
+		return nil
+	}
+	token1Depth, ok := depth.Data.Depths[token1ID]
+	if !ok || len(token1Depth.Asks) == 0 {
+		return nil
+	}
+
+	ask0, err := decimal.NewFromString(token0Depth.Asks[0].Price)
+	if err != nil {
+		return fmt.Errorf("parse token0 ask price failed: %v", err)
+	}
+	ask1, err := decimal.NewFromString(token1Depth.Asks[0].Price)
+	if err != nil {
+		return fmt.Errorf("parse token1 ask price failed: %v", err)
+	}
+
+	totalCost := ask0.Add(ask1)
+
+	// 手续费从赎回时的 1 美金收益中扣除, 因此拿净收益 (而不是抬高成本) 和
+	// 总成本比较, 费率开启后仍能准确判断是否有利可图。
+	feeRateBps := FeeRateBpsForMarket(market.ID)
+	feeRate := decimal.NewFromInt(feeRateBps).Div(decimal.NewFromInt(10000))
+	netPayout := decimal.NewFromInt(1).Mul(decimal.NewFromInt(1).Sub(feeRate))
+	if !totalCost.LessThan(netPayout) {
+		return nil
+	}
+
+	// 计算一篮子可以买多少份: ArbOrderUSDC 除以两腿总成本
+	shares := decimal.NewFromFloat(ArbOrderUSDC).Div(totalCost).IntPart()
+	if shares <= 0 {
+		shares = 1
+	}
+
+	notional := units.Notional(totalCost, shares)
+	key := marketKey(event.ID, market.ID)
+	if !reserveArbExposure(key, notional) {
+		log.Printf("Arb market %d/%d at position cap, skipping opportunity", event.ID, market.ID)
+		return nil
+	}
+
+	log.Printf("Arb opportunity on event %d market %d: ask0=%s ask1=%s total=%s shares=%d",
+		event.ID, market.ID, ask0.String(), ask1.String(), totalCost.String(), shares)
+
+	// 账户2 买入 token_1
+	if err := account2.Allow(key, units.Notional(ask1, shares)); err != nil {
+		log.Printf("Account2 arb order blocked: %v", err)
+	} else if orderManager.HasEquivalent(account2.AccountKey, token1ID, ask1.String(), shares) {
+		log.Printf("Account2 arb order on token_1 at price %s for %d shares already resting, skipping duplicate", ask1.String(), shares)
+	} else {
+		order2, err := account2.CreateBuyOrder(token1ID, ask1, shares, event.ID, market.ID, feeRateBps)
+		if err != nil {
+			return fmt.Errorf("create arb order2 failed: %v", err)
+		}
+		if _, err := account2.PlaceOrder(order2); err != nil {
+			log.Printf("Account2 arb order failed: %v", err)
+		} else {
+			log.Printf("Account2 arb order placed successfully")
+			ask1Notional := units.Notional(ask1, shares)
+			spendBudget.RecordSpend(ask1Notional, shares, ask1Notional.Mul(feeRateFraction(feeRateBps)), time.Now())
+		}
+	}
+
+	// 账户1 买入 token_0
+	if err := account1.Allow(key, units.Notional(ask0, shares)); err != nil {
+		log.Printf("Account1 arb order blocked: %v", err)
+		return nil
+	}
+	if orderManager.HasEquivalent(account1.AccountKey, token0ID, ask0.String(), shares) {
+		log.Printf("Account1 arb order on token_0 at price %s for %d shares already resting, skipping duplicate", ask0.String(), shares)
+		return nil
+	}
+	order1, err := account1.CreateBuyOrder(token0ID, ask0, shares, event.ID, market.ID, feeRateBps)
+	if err != nil {
+		return fmt.Errorf("create arb order1 failed: %v", err)
+	}
+	if _, err := account1.PlaceOrder(order1); err != nil {
+		log.Printf("Account1 arb order failed: %v", err)
+	} else {
+		log.Printf("Account1 arb order placed successfully")
+		ask0Notional := units.Notional(ask0, shares)
+		spendBudget.RecordSpend(ask0Notional, shares, ask0Notional.Mul(feeRateFraction(feeRateBps)), time.Now())
 	}
 
 	return nil
 }
 
 // RunBot 执行一次机器人任务
-func RunBot(db *sql.DB) error {
+func RunBot(db *sql.DB, creds *Credentials, account1, account2 *Session) error {
 	log.Println("======= Bot execution started =======")
 
+	ctx := context.Background()
+	rdb := newKillSwitchRedisClient(creds)
+	defer rdb.Close()
+	if isKillSwitchEngaged(ctx, rdb) {
+		log.Println("Kill switch engaged, cancelling all open orders and skipping this cycle")
+		cancelAllAccounts(creds)
+		return nil
+	}
+
+	if halted, spent, cap := spendBudget.CheckDailyCap(); halted {
+		log.Printf("Daily spend cap reached (spent=%s cap=%s), skipping this cycle", spent.String(), cap.String())
+		return nil
+	}
+	spendBudget.ResetRun()
+
 	// 1. 认证两个账户
-	// token1, err := Authenticate(Account1FetchTokenPublicKey, Account1FetchTokenPrivateKey)
+	// token1, err := Authenticate(Account1FetchTokenPublicKey, creds.Account1FetchTokenPriKey)
 	// if err != nil {
 	// 	return fmt.Errorf("account1 auth failed: %v", err)
 	// }
 
-	// token2, err := Authenticate(Account2FetchTokenPublicKey, Account2FetchTokenPrivateKey)
+	// token2, err := Authenticate(Account2FetchTokenPublicKey, creds.Account2FetchTokenPriKey)
 	// if err != nil {
 	// 	return fmt.Errorf("account2 auth failed: %v", err)
 	// }
@@ -627,18 +1071,74 @@ func RunBot(db *sql.DB) error {
 
 	log.Printf("Found %d active events", len(events))
 
-	// 3. 处理每个事件的每个市场
+	// 3. 按流动性 (成交量/盘口深度/距结算剩余时间) 给每个市场打分, 分配
+	// 这一轮的报价预算, 而不是所有市场平摊同一个 OrderUSDC。
+	budgets := allocateMarketBudgets(events)
+
+	// market_maker_assignments 表没有任何活跃记录时 assignments 是空 map,
+	// MayQuote 对任何账户都放行, 老部署 (没建/没用这张表) 行为不变。
+	assignments, err := LoadMarketAssignments(db)
+	if err != nil {
+		log.Printf("load market maker assignments failed, ignoring assignment table this cycle: %v", err)
+		assignments = nil
+	}
+
+	// 4. 处理每个事件的每个市场
 	for _, event := range events {
+		if err := ProcessNegRiskArbitrage(event, account1); err != nil {
+			log.Printf("Process neg-risk arbitrage failed: %v", err)
+		}
 		for _, market := range event.Markets {
-			if err := ProcessMarket(event, market); err != nil {
+			mktKey := marketKey(event.ID, market.ID)
+			if IsMarketPaused(mktKey) {
+				log.Printf("market %s paused via admin API, skipping", mktKey)
+				continue
+			}
+			if !MayQuote(assignments, mktKey, account2.AccountKey) {
+				owner, _ := AssignedAccount(assignments, mktKey)
+				log.Printf("market %s assigned to %s, not %s, skipping", mktKey, owner, account2.AccountKey)
+				continue
+			}
+			budgetUSDC := budgets[mktKey]
+			if budgetUSDC <= 0 {
+				budgetUSDC = QuoteProfileForTopic(event.Topic).OrderUSDC
+			}
+
+			// 按过去 24 小时相对每日成交量目标的进度伸缩这一轮的挂单金额,
+			// 领先目标太多就直接跳过这一轮 (降低频率), 落后就放大金额
+			// (追赶), 而不是不管做市量进度都挂固定的 budgetUSDC。
+			if ShouldSkipCycleForVolumeTarget(mktKey) {
+				log.Printf("market %s already ahead of its daily volume target, skipping this cycle", mktKey)
+				continue
+			}
+			budgetUSDC *= VolumeTargetMultiplier(mktKey)
+
+			if err := ProcessMarket(event, market, account1, account2, budgetUSDC); err != nil {
 				log.Printf("Process market failed: %v", err)
 				// 继续处理下一个市场
 			}
+			if err := ProcessArbitrage(event, market, account1, account2); err != nil {
+				log.Printf("Process arbitrage failed: %v", err)
+			}
 			// 每个市场之间稍微等待一下
 			time.Sleep(1 * time.Second)
 		}
 	}
 
+	if err := SaveState(); err != nil {
+		log.Printf("save state snapshot failed: %v", err)
+	}
+	if err := SaveRewardState(); err != nil {
+		log.Printf("save reward snapshot failed: %v", err)
+	}
+	if err := SaveBudgetState(); err != nil {
+		log.Printf("save budget snapshot failed: %v", err)
+	}
+
+	logLatencySnapshot()
+	logRewardSnapshot()
+	logBudgetSnapshot()
+	logShadowDiffReport()
 	log.Println("======= Bot execution completed =======")
 	return nil
 }
@@ -658,9 +1158,32 @@ func start_bot() {
 
 	log.Println("Market Making Bot starting...")
 
+	// 加载凭据 (私钥/API Key/数据库密码)
+	creds, err := loadCredentials(context.Background())
+	if err != nil {
+		log.Fatalf("Failed to load credentials: %v", err)
+	}
+
+	// 获取两个账户的运行锁, 防止这套私钥/API Key 被另一个 bot 部署同时
+	// 使用而互相对敲/重复下单; 拿不到锁直接退出, 不重试抢占。
+	lockRdb := newKillSwitchRedisClient(creds)
+	defer lockRdb.Close()
+
+	account1Lock, err := AcquireRunLock(lockRdb, RiskAccount1)
+	if err != nil {
+		log.Fatalf("Failed to acquire run lock for account1: %v", err)
+	}
+	defer account1Lock.Release()
+
+	account2Lock, err := AcquireRunLock(lockRdb, RiskAccount2)
+	if err != nil {
+		log.Fatalf("Failed to acquire run lock for account2: %v", err)
+	}
+	defer account2Lock.Release()
+
 	// 连接数据库
 	connStr := fmt.Sprintf("host=%s port=%d user=%s password=%s dbname=%s sslmode=require",
-		DBHost, DBPort, DBUser, DBPassword, DBName)
+		DBHost, DBPort, DBUser, creds.DBPassword, DBName)
 	db, err := sql.Open("postgres", connStr)
 	if err != nil {
 		log.Fatalf("Failed to connect to database: %v", err)
@@ -672,25 +1195,561 @@ func start_bot() {
 	}
 	log.Println("Connected to database")
 
+	// 构造两个账户的 Session, 只在进程启动时做一次, 后续每轮 RunBot 循环
+	// 复用, 策略函数只拿 Session, 不再直接接触私钥/API Key。
+	account1, err := NewSession(RiskAccount1, Account1Address, creds.Account1PrivateKey, Account1FetchTokenPublicKey, creds.Account1FetchTokenPriKey, creds.Account1ApiKey)
+	if err != nil {
+		log.Fatalf("Failed to build account1 session: %v", err)
+	}
+	account2, err := NewSession(RiskAccount2, Account2Address, creds.Account2PrivateKey, Account2FetchTokenPublicKey, creds.Account2FetchTokenPriKey, creds.Account2ApiKey)
+	if err != nil {
+		log.Fatalf("Failed to build account2 session: %v", err)
+	}
+
+	// 恢复上一次退出前的挂单快照, 并用交易所 open orders 接口和数据库
+	// trades 表核对, 避免进程崩溃重启后忘记自己下过哪些还没成交的单。
+	if err := LoadAndReconcileState(db, []*Session{account1, account2}); err != nil {
+		log.Printf("Failed to reconcile bot state: %v", err)
+	}
+	if err := LoadRewardState(); err != nil {
+		log.Printf("Failed to load reward state: %v", err)
+	}
+	if err := LoadBudgetState(); err != nil {
+		log.Printf("Failed to load budget state: %v", err)
+	}
+
+	// 监听 BOT_CONFIG_FILE, 独立于下面的下单节奏轮询是否有新的策略参数/
+	// 手续费率/风控限额, 未设置该环境变量时是空操作。
+	go RunConfigWatcher(nil)
+
+	// 起一个只监听 loopback 的 admin API, 让操作员不用重启进程就能查看
+	// 内部状态、暂停/恢复某个市场、临时调整策略参数或者触发全部撤单。
+	StartAdminServer(creds)
+
+	// 核对 orderManager 里 PlaceOrder 认为下成功的挂单, OrderVerifyDelay
+	// 之后仍然既不在盘口也没有成交记录的话就是"应答成功但订单其实不
+	// 存在", 这个核对依赖进程内的 orderManager, 所以跟主循环同一个进程
+	// 跑, 不像 janitor/quote-monitor 那样拆成独立子命令。
+	go RunOrderVerificationLoop(db, []*Session{account1, account2}, nil)
+
+	// 巡检临近过期的挂单并无缝续期; GTD 订单还没有接入 (Expiration 目前
+	// 一直传 "0"), 这个循环先跑着, 接入之后自动生效, 不需要再改调用点。
+	go RunOrderExpiryRefresher([]*Session{account1, account2}, nil)
+
 	// 立即执行一次
-	if err := RunBot(db); err != nil {
+	if err := RunBot(db, creds, account1, account2); err != nil {
 		log.Printf("Bot execution failed: %v", err)
 	}
 
+	// 每 EventWatchInterval 巡检一次 events 表, 有新增/关闭/结算事件时提前
+	// 触发一轮 RunBot, 不用等到下一个完整的 IntervalMinutes 周期才开始/
+	// 停止报价。
+	eventTrigger := make(chan struct{}, 1)
+	go RunEventWatcher(db, []*Session{account1, account2}, eventTrigger, nil)
+
 	// 定时执行
 	ticker := time.NewTicker(time.Duration(IntervalMinutes) * time.Minute)
 	defer ticker.Stop()
 
-	log.Printf("Bot will run every %d minutes", IntervalMinutes)
+	log.Printf("Bot will run every %d minutes, or sooner on event changes", IntervalMinutes)
 
-	for range ticker.C {
-		if err := RunBot(db); err != nil {
+	for {
+		select {
+		case <-ticker.C:
+		case <-eventTrigger:
+			log.Println("event watcher: detected event change, running bot early")
+		}
+		if err := RunBot(db, creds, account1, account2); err != nil {
 			log.Printf("Bot execution failed: %v", err)
 		}
+		ticker.Reset(time.Duration(IntervalMinutes) * time.Minute)
+	}
+}
+
+// runRiskCLI 支持在不重启 bot 进程的前提下调整风控限额, 用法:
+//
+//	bot_go risk show <account>
+//	bot_go risk set-limit <account> <maxOpenNotional> <maxOrdersPerMarket> <maxDailyLossUSDC>
+//	bot_go risk ban <account> <eventID> <marketID>
+//	bot_go risk unban <account> <eventID> <marketID>
+func runRiskCLI(args []string) {
+	if len(args) < 2 {
+		fmt.Println("Usage: bot_go risk show|set-limit|ban|unban <account> ...")
+		os.Exit(1)
+	}
+
+	action, account := args[0], args[1]
+	switch action {
+	case "show":
+		limits := riskEngine.Limits(account)
+		fmt.Printf("account=%s maxOpenNotional=%s maxOrdersPerMarket=%d maxDailyLossUSDC=%s bannedMarkets=%d\n",
+			account, limits.MaxOpenNotional.String(), limits.MaxOrdersPerMarket, limits.MaxDailyLossUSDC.String(), len(limits.BannedMarkets))
+
+	case "set-limit":
+		if len(args) < 5 {
+			fmt.Println("Usage: bot_go risk set-limit <account> <maxOpenNotional> <maxOrdersPerMarket> <maxDailyLossUSDC>")
+			os.Exit(1)
+		}
+		maxOpenNotional, err := decimal.NewFromString(args[2])
+		if err != nil {
+			log.Fatalf("invalid maxOpenNotional: %v", err)
+		}
+		maxOrdersPerMarket, err := strconv.Atoi(args[3])
+		if err != nil {
+			log.Fatalf("invalid maxOrdersPerMarket: %v", err)
+		}
+		maxDailyLoss, err := decimal.NewFromString(args[4])
+		if err != nil {
+			log.Fatalf("invalid maxDailyLossUSDC: %v", err)
+		}
+		riskEngine.SetLimits(account, risk.Limits{
+			MaxOpenNotional:    maxOpenNotional,
+			MaxOrdersPerMarket: maxOrdersPerMarket,
+			MaxDailyLossUSDC:   maxDailyLoss,
+			BannedMarkets:      riskEngine.Limits(account).BannedMarkets,
+		})
+		fmt.Printf("updated limits for account %s\n", account)
+
+	case "ban", "unban":
+		if len(args) < 4 {
+			fmt.Printf("Usage: bot_go risk %s <account> <eventID> <marketID>\n", action)
+			os.Exit(1)
+		}
+		eventID, err := strconv.ParseInt(args[2], 10, 64)
+		if err != nil {
+			log.Fatalf("invalid eventID: %v", err)
+		}
+		marketID, err := strconv.ParseInt(args[3], 10, 16)
+		if err != nil {
+			log.Fatalf("invalid marketID: %v", err)
+		}
+		key := marketKey(eventID, int16(marketID))
+		if action == "ban" {
+			riskEngine.BanMarket(account, key)
+			fmt.Printf("banned market %s for account %s\n", key, account)
+		} else {
+			riskEngine.UnbanMarket(account, key)
+			fmt.Printf("unbanned market %s for account %s\n", key, account)
+		}
+
+	default:
+		fmt.Println("Usage: bot_go risk show|set-limit|ban|unban <account> ...")
+		os.Exit(1)
+	}
+}
+
+// runThrottleCLI 实现 `bot_go throttle` 命令, 用法:
+//
+//	bot_go throttle show <account>
+//	bot_go throttle set-limit <account> <minRequoteIntervalMs> <maxActionsPerMinute>
+//	bot_go throttle violations
+//
+// violations 打印 quoteThrottle 记录的每个 account:reason 组合被拦截的
+// 次数, 供监控/告警拉取, 而不需要单独接入 Prometheus 之类的指标系统。
+func runThrottleCLI(args []string) {
+	if len(args) < 1 {
+		fmt.Println("Usage: bot_go throttle show|set-limit|violations <account> ...")
+		os.Exit(1)
+	}
+
+	switch args[0] {
+	case "show":
+		if len(args) < 2 {
+			fmt.Println("Usage: bot_go throttle show <account>")
+			os.Exit(1)
+		}
+		limits := quoteThrottle.Limits(args[1])
+		fmt.Printf("account=%s minRequoteInterval=%s maxActionsPerMinute=%d\n",
+			args[1], limits.MinRequoteInterval, limits.MaxActionsPerMinute)
+
+	case "set-limit":
+		if len(args) < 4 {
+			fmt.Println("Usage: bot_go throttle set-limit <account> <minRequoteIntervalMs> <maxActionsPerMinute>")
+			os.Exit(1)
+		}
+		minIntervalMs, err := strconv.Atoi(args[2])
+		if err != nil {
+			log.Fatalf("invalid minRequoteIntervalMs: %v", err)
+		}
+		maxActionsPerMinute, err := strconv.Atoi(args[3])
+		if err != nil {
+			log.Fatalf("invalid maxActionsPerMinute: %v", err)
+		}
+		quoteThrottle.SetLimits(args[1], risk.ThrottleLimits{
+			MinRequoteInterval:  time.Duration(minIntervalMs) * time.Millisecond,
+			MaxActionsPerMinute: maxActionsPerMinute,
+		})
+		fmt.Printf("updated throttle limits for account %s\n", args[1])
+
+	case "violations":
+		violations := quoteThrottle.Violations()
+		if len(violations) == 0 {
+			fmt.Println("no quote stuffing violations recorded")
+			return
+		}
+		for key, count := range violations {
+			fmt.Printf("%s: %d\n", key, count)
+		}
+
+	default:
+		fmt.Println("Usage: bot_go throttle show|set-limit|violations <account> ...")
+		os.Exit(1)
+	}
+}
+
+// runKillCLI 实现 `bot_go kill` 命令, 用法:
+//
+//	bot_go kill set|clear|status
+//
+// set 会立即置位 kill switch 并撤销两个账户的全部挂单; bot 下一个周期开
+// 始前也会检查同一个标志位, 不需要重启进程。
+func runKillCLI(args []string) {
+	ctx := context.Background()
+
+	creds, err := loadCredentials(ctx)
+	if err != nil {
+		log.Fatalf("load credentials: %v", err)
+	}
+
+	rdb := newKillSwitchRedisClient(creds)
+	defer rdb.Close()
+
+	action := "set"
+	if len(args) > 0 {
+		action = args[0]
+	}
+
+	switch action {
+	case "set":
+		if err := rdb.Set(ctx, KillSwitchRedisKey, "1", 0).Err(); err != nil {
+			log.Fatalf("set kill switch: %v", err)
+		}
+		fmt.Println("kill switch engaged, cancelling all open orders...")
+		cancelAllAccounts(creds)
+
+	case "clear":
+		if err := rdb.Del(ctx, KillSwitchRedisKey).Err(); err != nil {
+			log.Fatalf("clear kill switch: %v", err)
+		}
+		fmt.Println("kill switch cleared")
+
+	case "status":
+		if isKillSwitchEngaged(ctx, rdb) {
+			fmt.Println("kill switch: ENGAGED")
+		} else {
+			fmt.Println("kill switch: disengaged")
+		}
+
+	default:
+		fmt.Println("Usage: bot_go kill set|clear|status")
+		os.Exit(1)
+	}
+}
+
+// runJanitorCLI 作为独立进程运行 StaleOrderJanitor, 与 start_bot 的主策略
+// 循环完全分开, 用法:
+//
+//	bot_go janitor
+func runJanitorCLI(args []string) {
+	ctx := context.Background()
+
+	creds, err := loadCredentials(ctx)
+	if err != nil {
+		log.Fatalf("load credentials: %v", err)
+	}
+
+	connStr := fmt.Sprintf("host=%s port=%d user=%s password=%s dbname=%s sslmode=require",
+		DBHost, DBPort, DBUser, creds.DBPassword, DBName)
+	db, err := sql.Open("postgres", connStr)
+	if err != nil {
+		log.Fatalf("connect to database: %v", err)
+	}
+	defer db.Close()
+
+	account1, err := NewSession(RiskAccount1, Account1Address, creds.Account1PrivateKey, Account1FetchTokenPublicKey, creds.Account1FetchTokenPriKey, creds.Account1ApiKey)
+	if err != nil {
+		log.Fatalf("build account1 session: %v", err)
+	}
+	account2, err := NewSession(RiskAccount2, Account2Address, creds.Account2PrivateKey, Account2FetchTokenPublicKey, creds.Account2FetchTokenPriKey, creds.Account2ApiKey)
+	if err != nil {
+		log.Fatalf("build account2 session: %v", err)
+	}
+
+	log.Printf("Stale order janitor starting, interval=%s maxAge=%s", JanitorInterval, MaxOpenOrderAge)
+	RunStaleOrderJanitor(db, []*Session{account1, account2}, JanitorInterval, MaxOpenOrderAge, nil)
+}
+
+// TWAPConfig 描述一次 TWAP 拆单任务: 把 TotalShares 拆成随机大小的子单,
+// 在 Horizon 时间内陆续下出, 每笔子单大小同时受 MinSliceShares/
+// MaxSliceShares 和 MaxParticipationRate (相对当前深度可成交量的占比)
+// 限制, 避免一次性下单打穿盘口或被其他交易者识别出拆单模式。
+type TWAPConfig struct {
+	Account              string // "account1" or "account2"
+	EventID              int64
+	MarketID             int16
+	TokenID              string
+	TotalShares          int64
+	Horizon              time.Duration
+	MinSliceShares       int64
+	MaxSliceShares       int64
+	MaxParticipationRate decimal.Decimal
+}
+
+// accountCredentials resolves the private key, address and API key for
+// one of the two accounts bot_go already knows about.
+func accountCredentials(creds *Credentials, account string) (privateKey, address, apiKey string, err error) {
+	switch account {
+	case "account1":
+		return creds.Account1PrivateKey, Account1Address, creds.Account1ApiKey, nil
+	case "account2":
+		return creds.Account2PrivateKey, Account2Address, creds.Account2ApiKey, nil
+	default:
+		return "", "", "", fmt.Errorf("unknown account %q, expected account1 or account2", account)
+	}
+}
+
+// bestAskPrice returns the lowest ask in book, i.e. the price a buy slice
+// can expect to execute at.
+func bestAskPrice(book DepthBook) (decimal.Decimal, error) {
+	if len(book.Asks) == 0 {
+		return decimal.Zero, fmt.Errorf("no asks in book")
+	}
+	price, err := decimal.NewFromString(book.Asks[0].Price)
+	if err != nil {
+		return decimal.Zero, fmt.Errorf("parse ask price %q: %w", book.Asks[0].Price, err)
+	}
+	return price, nil
+}
+
+// participationCap returns the largest slice allowed by rate against the
+// ask-side liquidity currently visible in book, i.e. the participation
+// limit for a buy slice.
+func participationCap(book DepthBook, rate decimal.Decimal) int64 {
+	total := decimal.Zero
+	for _, level := range book.Asks {
+		qty, err := decimal.NewFromString(level.Quantity)
+		if err != nil {
+			continue
+		}
+		total = total.Add(qty)
+	}
+	return total.Mul(rate).IntPart()
+}
+
+// nextSliceSize picks a randomized slice size within [MinSliceShares,
+// MaxSliceShares], then clamps it to what's left to execute and to the
+// current participation cap so a single slice never dominates the book.
+func nextSliceSize(cfg TWAPConfig, remaining int64, book DepthBook, rng *rand.Rand) int64 {
+	minShares, maxShares := cfg.MinSliceShares, cfg.MaxSliceShares
+	if maxShares < minShares {
+		maxShares = minShares
+	}
+
+	size := minShares
+	if maxShares > minShares {
+		size = minShares + rng.Int63n(maxShares-minShares+1)
+	}
+
+	if size > remaining {
+		size = remaining
+	}
+	if cap := participationCap(book, cfg.MaxParticipationRate); cap > 0 && size > cap {
+		size = cap
+	}
+	return size
+}
+
+// randomizedWait picks how long to wait before the next slice: the time
+// remaining until deadline split across an estimate of the slices left,
+// jittered +/-50% so the cadence doesn't look mechanical.
+func randomizedWait(deadline time.Time, remaining, avgSliceShares int64, rng *rand.Rand) time.Duration {
+	timeLeft := time.Until(deadline)
+	if timeLeft <= 0 || avgSliceShares <= 0 {
+		return time.Second
+	}
+
+	slicesLeft := remaining / avgSliceShares
+	if slicesLeft < 1 {
+		slicesLeft = 1
+	}
+	base := timeLeft / time.Duration(slicesLeft)
+
+	jitter := 0.5 + rng.Float64() // [0.5, 1.5)
+	wait := time.Duration(float64(base) * jitter)
+	if wait < time.Second {
+		wait = time.Second
+	}
+	if wait > timeLeft {
+		wait = timeLeft
+	}
+	return wait
+}
+
+// ExecuteTWAP slices cfg.TotalShares into randomized child buy orders and
+// places them over cfg.Horizon, re-checking depth before every slice so
+// each one respects cfg.MaxParticipationRate against current liquidity.
+// Sell-side TWAP isn't supported yet since bot_go has no CreateSellOrder.
+func ExecuteTWAP(creds *Credentials, cfg TWAPConfig) error {
+	privateKey, address, apiKey, err := accountCredentials(creds, cfg.Account)
+	if err != nil {
+		return err
+	}
+	feeRateBps := FeeRateBpsForMarket(cfg.MarketID)
+
+	deadline := time.Now().Add(cfg.Horizon)
+	rng := rand.New(rand.NewSource(time.Now().UnixNano()))
+	remaining := cfg.TotalShares
+
+	for remaining > 0 {
+		depthResp, err := GetDepth(cfg.EventID, cfg.MarketID)
+		if err != nil {
+			return fmt.Errorf("get depth: %w", err)
+		}
+		book, ok := depthResp.Data.Depths[cfg.TokenID]
+		if !ok {
+			return fmt.Errorf("token %s not present in depth response", cfg.TokenID)
+		}
+
+		sliceShares := nextSliceSize(cfg, remaining, book, rng)
+		if sliceShares <= 0 {
+			log.Printf("⏸️  TWAP %s: no participation room this cycle, waiting", cfg.TokenID)
+			time.Sleep(randomizedWait(deadline, remaining, cfg.MinSliceShares, rng))
+			continue
+		}
+
+		price, err := bestAskPrice(book)
+		if err != nil {
+			return fmt.Errorf("determine execution price: %w", err)
+		}
+
+		order, err := CreateBuyOrder(privateKey, address, cfg.TokenID, price, sliceShares, cfg.EventID, cfg.MarketID, feeRateBps)
+		if err != nil {
+			return fmt.Errorf("build slice order: %w", err)
+		}
+		if err := PlaceOrder(APIKeyAuth{APIKey: apiKey}, order); err != nil {
+			log.Printf("⚠️  TWAP slice failed, will retry next cycle: %v", err)
+		} else {
+			remaining -= sliceShares
+			log.Printf("✅ TWAP slice placed: %d/%d shares of %s at %s", cfg.TotalShares-remaining, cfg.TotalShares, cfg.TokenID, price.String())
+		}
+
+		if remaining <= 0 {
+			break
+		}
+		time.Sleep(randomizedWait(deadline, remaining, sliceShares, rng))
+	}
+
+	log.Printf("🏁 TWAP execution complete for %s", cfg.TokenID)
+	return nil
+}
+
+// runExecuteCLI 实现 `bot_go execute` 命令, 用法:
+//
+//	bot_go execute <account1|account2> <eventID> <marketID> <tokenID> <totalShares> <horizonSeconds> [maxParticipationRate]
+//
+// maxParticipationRate 默认为 0.1 (即每次拆单最多吃掉当前卖一侧深度的
+// 10%), minSliceShares/maxSliceShares 目前固定为 totalShares 的 2%/10%,
+// 需要更细粒度控制时再加参数。
+func runExecuteCLI(args []string) {
+	if len(args) < 6 {
+		fmt.Println("Usage: bot_go execute <account1|account2> <eventID> <marketID> <tokenID> <totalShares> <horizonSeconds> [maxParticipationRate]")
+		os.Exit(1)
+	}
+
+	account := args[0]
+	eventID, err := strconv.ParseInt(args[1], 10, 64)
+	if err != nil {
+		log.Fatalf("invalid eventID: %v", err)
+	}
+	marketID, err := strconv.ParseInt(args[2], 10, 16)
+	if err != nil {
+		log.Fatalf("invalid marketID: %v", err)
+	}
+	tokenID := args[3]
+	totalShares, err := strconv.ParseInt(args[4], 10, 64)
+	if err != nil {
+		log.Fatalf("invalid totalShares: %v", err)
+	}
+	horizonSeconds, err := strconv.ParseInt(args[5], 10, 64)
+	if err != nil {
+		log.Fatalf("invalid horizonSeconds: %v", err)
+	}
+
+	maxParticipationRate := decimal.NewFromFloat(0.1)
+	if len(args) >= 7 {
+		maxParticipationRate, err = decimal.NewFromString(args[6])
+		if err != nil {
+			log.Fatalf("invalid maxParticipationRate: %v", err)
+		}
+	}
+
+	cfg := TWAPConfig{
+		Account:              account,
+		EventID:              eventID,
+		MarketID:             int16(marketID),
+		TokenID:              tokenID,
+		TotalShares:          totalShares,
+		Horizon:              time.Duration(horizonSeconds) * time.Second,
+		MinSliceShares:       max(1, totalShares/50),
+		MaxSliceShares:       max(1, totalShares/10),
+		MaxParticipationRate: maxParticipationRate,
+	}
+
+	creds, err := loadCredentials(context.Background())
+	if err != nil {
+		log.Fatalf("load credentials: %v", err)
+	}
+
+	if err := ExecuteTWAP(creds, cfg); err != nil {
+		log.Fatalf("TWAP execution failed: %v", err)
 	}
 }
 
 func main() {
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "depth":
+			runDepthCLI(os.Args[2:])
+			return
+		case "events":
+			runEventsCLI(os.Args[2:])
+			return
+		case "execute":
+			runExecuteCLI(os.Args[2:])
+			return
+		case "throttle":
+			runThrottleCLI(os.Args[2:])
+			return
+		case "onchain":
+			runOnchainCLI(os.Args[2:])
+			return
+		case "risk":
+			runRiskCLI(os.Args[2:])
+			return
+		case "kill":
+			runKillCLI(os.Args[2:])
+			return
+		case "janitor":
+			runJanitorCLI(os.Args[2:])
+			return
+		case "quote-monitor":
+			runQuoteMonitorCLI(os.Args[2:])
+			return
+		case "operators":
+			runOperatorsCLI(os.Args[2:])
+			return
+		case "report":
+			runReportCLI(os.Args[2:])
+			return
+		case "dashboard":
+			runDashboardCLI(os.Args[2:])
+			return
+		case "web-dashboard":
+			runWebDashboardCLI(os.Args[2:])
+			return
+		}
+	}
+
 	//start_bot()
 	//CancelAllOrders("cmjrw9b3b0330la0d1qgu0gb1")
 }
@@ -0,0 +1,206 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"eip712"
+
+	"github.com/shopspring/decimal"
+	"units"
+)
+
+// SignNegRiskOrderLocal 跟 SignOrderLocal 类似, 但签名用的是 NegRiskAdapter
+// 域名而不是 CTFExchange 域名, 供分类事件 (同一 event 下多个互斥结果市场)
+// 的一篮子头寸下单使用。
+func SignNegRiskOrderLocal(privateKey string, order *eip712.OrderInput) (string, error) {
+	return eip712.SignNegRiskOrderInput(privateKey, ActiveChain().ChainID, order)
+}
+
+// CreateNegRiskBuyOrder 跟 CreateBuyOrder 结构完全一样, 唯一区别是签名走
+// SignNegRiskOrderLocal, 用于分类事件一篮子下单的每一条腿。
+func CreateNegRiskBuyOrder(privateKey, address, tokenID string, price decimal.Decimal, shares int64, eventID int64, marketID int16, feeRateBps int64) (*PlaceOrderRequest, error) {
+	saltBig, err := eip712.GenerateSalt()
+	if err != nil {
+		return nil, fmt.Errorf("generate salt failed: %v", err)
+	}
+	salt := saltBig.Int64()
+
+	feeRateBpsStr := fmt.Sprintf("%d", feeRateBps)
+
+	takerAmount := units.TakerAmountUnits(shares).String()
+	makerAmount := units.MakerAmountUnits(price, shares).String()
+
+	orderInput := &eip712.OrderInput{
+		Salt:          fmt.Sprintf("%d", salt),
+		Maker:         address,
+		Signer:        address,
+		Taker:         "0x0000000000000000000000000000000000000000",
+		TokenId:       tokenID,
+		MakerAmount:   makerAmount,
+		TakerAmount:   takerAmount,
+		Expiration:    "0",
+		Nonce:         "0",
+		FeeRateBps:    feeRateBpsStr,
+		Side:          0, // buy
+		SignatureType: 0,
+	}
+
+	if err := eip712.ValidateOrderInput(orderInput); err != nil {
+		return nil, fmt.Errorf("invalid neg-risk order input: %v", err)
+	}
+
+	signingStart := time.Now()
+	signature, err := SignNegRiskOrderLocal(privateKey, orderInput)
+	recordLatencyStage(StageSigning, time.Since(signingStart))
+	if err != nil {
+		return nil, fmt.Errorf("sign neg-risk order failed: %v", err)
+	}
+
+	return &PlaceOrderRequest{
+		Expiration:    "0",
+		FeeRateBps:    feeRateBpsStr,
+		Maker:         address,
+		MakerAmount:   makerAmount,
+		Nonce:         "0",
+		Salt:          salt,
+		Side:          "buy",
+		Signature:     signature,
+		SignatureType: 0,
+		Signer:        address,
+		Taker:         "0x0000000000000000000000000000000000000000",
+		TakerAmount:   takerAmount,
+		TokenId:       tokenID,
+		EventID:       eventID,
+		MarketID:      marketID,
+		Price:         price.String(),
+		OrderType:     "limit",
+		Shares:        shares,
+	}, nil
+}
+
+// CreateNegRiskBuyOrder 用该账户的私钥/地址创建一篮子下单里的一条腿, 其余
+// 参数与包级 CreateNegRiskBuyOrder 相同。
+func (s *Session) CreateNegRiskBuyOrder(tokenID string, price decimal.Decimal, shares int64, eventID int64, marketID int16, feeRateBps int64) (*PlaceOrderRequest, error) {
+	return CreateNegRiskBuyOrder(s.PrivateKey, s.Address, tokenID, price, shares, eventID, marketID, feeRateBps)
+}
+
+// negRiskLeg 是一篮子里的一条腿: 某个分类市场里代表"该结果没有发生"的 No
+// token 及其当前卖1价。
+type negRiskLeg struct {
+	MarketID int16
+	TokenID  string
+	Ask      decimal.Decimal
+}
+
+// buildNegRiskNoBasket 为一个分类事件 (同一 event 下有多个互斥结果市场)
+// 收集每个未收盘市场的 No token (TokenIDs[1], 跟 ProcessArbitrage 里 Yes/No
+// 的排列约定一致) 卖1价, 任何一个市场缺深度都会导致整个篮子跳过, 因为
+// 篮子要求同时买齐全部互斥结果的 No token。
+func buildNegRiskNoBasket(event Event) ([]negRiskLeg, error) {
+	legs := make([]negRiskLeg, 0, len(event.Markets))
+
+	for _, market := range event.Markets {
+		if market.Closed {
+			return nil, nil
+		}
+		if len(market.TokenIDs) < 2 {
+			return nil, nil
+		}
+
+		noTokenID := market.TokenIDs[1]
+
+		depth, err := GetDepth(event.ID, market.ID)
+		if err != nil {
+			return nil, fmt.Errorf("get depth for market %d failed: %v", market.ID, err)
+		}
+
+		noDepth, ok := depth.Data.Depths[noTokenID]
+		if !ok || len(noDepth.Asks) == 0 {
+			return nil, nil
+		}
+
+		ask, err := decimal.NewFromString(noDepth.Asks[0].Price)
+		if err != nil {
+			return nil, fmt.Errorf("parse no token ask price failed: %v", err)
+		}
+
+		legs = append(legs, negRiskLeg{MarketID: market.ID, TokenID: noTokenID, Ask: ask})
+	}
+
+	return legs, nil
+}
+
+// ProcessNegRiskArbitrage 检查一个分类事件里买齐全部互斥结果的 No token 的
+// 总成本是否低于篮子到期的净赎回价值 (N 个结果里保证有 N-1 个 No 会赢, 赢
+// 一份赔 1 美金), 如果存在无风险套利机会则用 account1 买齐整个篮子。跟
+// ProcessArbitrage 处理同一市场内 Yes/No 对敲不同, 这里处理的是跨市场
+// (event 维度) 的篮子, 只有 event 下有 2 个及以上市场时才有意义。
+func ProcessNegRiskArbitrage(event Event, account1 *Session) error {
+	if len(event.Markets) < 2 {
+		return nil
+	}
+
+	legs, err := buildNegRiskNoBasket(event)
+	if err != nil {
+		return err
+	}
+	if len(legs) < 2 {
+		return nil
+	}
+
+	totalCost := decimal.Zero
+	for _, leg := range legs {
+		totalCost = totalCost.Add(leg.Ask)
+	}
+
+	winningLegs := decimal.NewFromInt(int64(len(legs) - 1))
+
+	// 手续费从赎回时的收益里扣除, 用净赔付 (而不是抬高成本) 和总成本比较。
+	feeRateBps := DefaultFeeRateBps
+	feeRate := decimal.NewFromInt(int64(feeRateBps)).Div(decimal.NewFromInt(10000))
+	netPayout := winningLegs.Mul(decimal.NewFromInt(1).Sub(feeRate))
+	if !totalCost.LessThan(netPayout) {
+		return nil
+	}
+
+	shares := decimal.NewFromFloat(ArbOrderUSDC).Div(totalCost).IntPart()
+	if shares <= 0 {
+		shares = 1
+	}
+
+	notional := units.Notional(totalCost, shares)
+	key := fmt.Sprintf("negrisk/%d", event.ID)
+	if !reserveArbExposure(key, notional) {
+		log.Printf("NegRisk event %d at position cap, skipping opportunity", event.ID)
+		return nil
+	}
+
+	log.Printf("NegRisk opportunity on event %d: legs=%d total=%s shares=%d", event.ID, len(legs), totalCost.String(), shares)
+
+	if err := account1.Allow(key, notional); err != nil {
+		log.Printf("Account1 neg-risk basket blocked: %v", err)
+		return nil
+	}
+
+	for _, leg := range legs {
+		if orderManager.HasEquivalent(account1.AccountKey, leg.TokenID, leg.Ask.String(), shares) {
+			log.Printf("Account1 neg-risk leg (market %d) at price %s for %d shares already resting, skipping duplicate", leg.MarketID, leg.Ask.String(), shares)
+			continue
+		}
+		order, err := account1.CreateNegRiskBuyOrder(leg.TokenID, leg.Ask, shares, event.ID, leg.MarketID, int64(feeRateBps))
+		if err != nil {
+			return fmt.Errorf("create neg-risk order failed: %v", err)
+		}
+		if _, err := account1.PlaceOrder(order); err != nil {
+			log.Printf("Account1 neg-risk leg (market %d) failed: %v", leg.MarketID, err)
+			continue
+		}
+		log.Printf("Account1 neg-risk leg (market %d) placed successfully", leg.MarketID)
+		legNotional := units.Notional(leg.Ask, shares)
+		spendBudget.RecordSpend(legNotional, shares, legNotional.Mul(feeRateFraction(int64(feeRateBps))), time.Now())
+	}
+
+	return nil
+}
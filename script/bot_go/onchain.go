@@ -0,0 +1,344 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"crypto/ecdsa"
+	"fmt"
+	"log"
+	"math/big"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/shopspring/decimal"
+
+	"nonce"
+	"units"
+)
+
+// ethclientNonceSource adapts an *ethclient.Client to nonce.Source, the
+// same way onchain_send's Sender does - the shared nonce package stays
+// free of a go-ethereum dependency, so each chain-facing module supplies
+// this small adapter itself.
+type ethclientNonceSource struct {
+	client *ethclient.Client
+}
+
+func (s ethclientNonceSource) PendingNonceAt(ctx context.Context, address string) (uint64, error) {
+	return s.client.PendingNonceAt(ctx, common.HexToAddress(address))
+}
+
+// FundTarget is one bot account's desired BNB gas and USDC collateral
+// balance; fundAccounts tops accounts up to these targets, it never
+// drains a balance already above target.
+type FundTarget struct {
+	Address    common.Address
+	BNBTarget  decimal.Decimal // in BNB, not wei
+	USDCTarget decimal.Decimal // in USDC, not raw token units
+}
+
+// loadFundTargets reads one target per line from path, formatted as
+// "<address> <bnbTarget> <usdcTarget>" (whitespace separated), so
+// standing up a batch of load-test accounts is a text file, not a
+// MetaMask session.
+func loadFundTargets(path string) ([]FundTarget, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("open targets file: %w", err)
+	}
+	defer f.Close()
+
+	var targets []FundTarget
+	scanner := bufio.NewScanner(f)
+	lineNum := 0
+	for scanner.Scan() {
+		lineNum++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		fields := strings.Fields(line)
+		if len(fields) != 3 {
+			return nil, fmt.Errorf("targets file line %d: expected \"<address> <bnbTarget> <usdcTarget>\", got %q", lineNum, line)
+		}
+
+		bnbTarget, err := decimal.NewFromString(fields[1])
+		if err != nil {
+			return nil, fmt.Errorf("targets file line %d: invalid bnbTarget: %w", lineNum, err)
+		}
+		usdcTarget, err := decimal.NewFromString(fields[2])
+		if err != nil {
+			return nil, fmt.Errorf("targets file line %d: invalid usdcTarget: %w", lineNum, err)
+		}
+
+		targets = append(targets, FundTarget{
+			Address:    common.HexToAddress(fields[0]),
+			BNBTarget:  bnbTarget,
+			USDCTarget: usdcTarget,
+		})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("read targets file: %w", err)
+	}
+	return targets, nil
+}
+
+// erc20BalanceOf reads token's balanceOf(account) via eth_call.
+func erc20BalanceOf(ctx context.Context, client *ethclient.Client, token, account common.Address) (*big.Int, error) {
+	selector := crypto.Keccak256([]byte("balanceOf(address)"))[:4]
+	data := append(selector, common.LeftPadBytes(account.Bytes(), 32)...)
+
+	result, err := client.CallContract(ctx, ethereum.CallMsg{To: &token, Data: data}, nil)
+	if err != nil {
+		return nil, fmt.Errorf("call balanceOf: %w", err)
+	}
+	return new(big.Int).SetBytes(result), nil
+}
+
+// erc20TransferData packs calldata for token.transfer(to, amount).
+func erc20TransferData(to common.Address, amount *big.Int) []byte {
+	selector := crypto.Keccak256([]byte("transfer(address,uint256)"))[:4]
+	data := append(selector, common.LeftPadBytes(to.Bytes(), 32)...)
+	data = append(data, common.LeftPadBytes(amount.Bytes(), 32)...)
+	return data
+}
+
+// fundAccounts tops every target in targets up to its BNB and USDC
+// balance targets from the treasury account, skipping any leg that's
+// already funded. Nonces are allocated through nonce.Manager - shared with
+// onchain_send's settlement worker and prepareCondition below - so sending
+// several in a row doesn't race client.PendingNonceAt, and a stray "nonce
+// too low/high" from another process using the treasury account is
+// recovered from instead of aborting the whole run.
+func fundAccounts(chain ChainConfig, treasuryPrivateKeyHex string, targets []FundTarget) error {
+	ctx := context.Background()
+
+	client, err := ethclient.Dial(chain.RPCURL)
+	if err != nil {
+		return fmt.Errorf("dial RPC %s: %w", chain.RPCURL, err)
+	}
+	defer client.Close()
+
+	privateKey, err := crypto.HexToECDSA(strings.TrimPrefix(treasuryPrivateKeyHex, "0x"))
+	if err != nil {
+		return fmt.Errorf("parse treasury private key: %w", err)
+	}
+	treasuryAddress := crypto.PubkeyToAddress(*privateKey.Public().(*ecdsa.PublicKey))
+	usdcAddress := common.HexToAddress(chain.USDCContractAddress)
+	chainID := big.NewInt(int64(chain.ChainID))
+
+	nonces := nonce.NewManager(ethclientNonceSource{client: client})
+	gasPrice, err := client.SuggestGasPrice(ctx)
+	if err != nil {
+		return fmt.Errorf("suggest gas price: %w", err)
+	}
+
+	sendTx := func(to common.Address, value *big.Int, gasLimit uint64, data []byte) error {
+		txNonce, release, err := nonces.Acquire(ctx, treasuryAddress.Hex())
+		if err != nil {
+			return fmt.Errorf("acquire nonce: %w", err)
+		}
+		used := false
+		defer func() { release(used) }()
+
+		tx := types.NewTransaction(txNonce, to, value, gasLimit, gasPrice, data)
+		signedTx, err := types.SignTx(tx, types.NewEIP155Signer(chainID), privateKey)
+		if err != nil {
+			return fmt.Errorf("sign transaction: %w", err)
+		}
+		if sendErr := client.SendTransaction(ctx, signedTx); sendErr != nil {
+			if nonce.IsDesyncError(sendErr) {
+				if resyncErr := nonces.ReportDesync(ctx, treasuryAddress.Hex()); resyncErr != nil {
+					return fmt.Errorf("send transaction: %w (resync failed: %v)", sendErr, resyncErr)
+				}
+			}
+			return fmt.Errorf("send transaction: %w", sendErr)
+		}
+		used = true
+		return nil
+	}
+
+	for _, target := range targets {
+		bnbTargetWei := units.ToTokenUnits(target.BNBTarget, 18)
+		currentBNB, err := client.BalanceAt(ctx, target.Address, nil)
+		if err != nil {
+			return fmt.Errorf("query BNB balance for %s: %w", target.Address.Hex(), err)
+		}
+		if currentBNB.Cmp(bnbTargetWei) < 0 {
+			deficit := new(big.Int).Sub(bnbTargetWei, currentBNB)
+			if err := sendTx(target.Address, deficit, 21000, nil); err != nil {
+				return fmt.Errorf("fund BNB for %s: %w", target.Address.Hex(), err)
+			}
+			log.Printf("✅ sent %s BNB to %s", decimal.NewFromBigInt(deficit, -18).String(), target.Address.Hex())
+		}
+
+		usdcTargetUnits := units.ToTokenUnits(target.USDCTarget, chain.USDCDecimals)
+		currentUSDC, err := erc20BalanceOf(ctx, client, usdcAddress, target.Address)
+		if err != nil {
+			return fmt.Errorf("query USDC balance for %s: %w", target.Address.Hex(), err)
+		}
+		if currentUSDC.Cmp(usdcTargetUnits) < 0 {
+			deficit := new(big.Int).Sub(usdcTargetUnits, currentUSDC)
+			if err := sendTx(usdcAddress, big.NewInt(0), 100000, erc20TransferData(target.Address, deficit)); err != nil {
+				return fmt.Errorf("fund USDC for %s: %w", target.Address.Hex(), err)
+			}
+			log.Printf("✅ sent %s USDC to %s", decimal.NewFromBigInt(deficit, -chain.USDCDecimals).String(), target.Address.Hex())
+		}
+	}
+
+	return nil
+}
+
+// prepareConditionData packs calldata for
+// ConditionalTokens.prepareCondition(oracle, questionId, outcomeSlotCount).
+func prepareConditionData(oracle common.Address, questionID common.Hash, outcomeSlotCount uint64) []byte {
+	selector := crypto.Keccak256([]byte("prepareCondition(address,bytes32,uint256)"))[:4]
+	data := append(selector, common.LeftPadBytes(oracle.Bytes(), 32)...)
+	data = append(data, questionID.Bytes()...)
+	data = append(data, common.LeftPadBytes(big.NewInt(0).SetUint64(outcomeSlotCount).Bytes(), 32)...)
+	return data
+}
+
+// prepareCondition calls ConditionalTokens.prepareCondition on chain,
+// registering (oracle, questionId, outcomeSlotCount) on-chain so the ctf
+// package's offline-derived position ids resolve to real, tradable ERC1155
+// positions. This is the on-chain counterpart to ctf.ConditionID: calling it
+// with the same (oracle, questionId, outcomeSlotCount) a market's token ids
+// were derived from is what makes those token ids real.
+func prepareCondition(chain ChainConfig, senderPrivateKeyHex string, oracle common.Address, questionID common.Hash, outcomeSlotCount uint64) error {
+	if chain.ConditionalTokensAddress == "" {
+		return fmt.Errorf("chain %d has no ConditionalTokensAddress configured", chain.ChainID)
+	}
+	ctx := context.Background()
+
+	client, err := ethclient.Dial(chain.RPCURL)
+	if err != nil {
+		return fmt.Errorf("dial RPC %s: %w", chain.RPCURL, err)
+	}
+	defer client.Close()
+
+	privateKey, err := crypto.HexToECDSA(strings.TrimPrefix(senderPrivateKeyHex, "0x"))
+	if err != nil {
+		return fmt.Errorf("parse private key: %w", err)
+	}
+	senderAddress := crypto.PubkeyToAddress(*privateKey.Public().(*ecdsa.PublicKey))
+	contractAddress := common.HexToAddress(chain.ConditionalTokensAddress)
+	chainID := big.NewInt(int64(chain.ChainID))
+
+	nonces := nonce.NewManager(ethclientNonceSource{client: client})
+	txNonce, release, err := nonces.Acquire(ctx, senderAddress.Hex())
+	if err != nil {
+		return fmt.Errorf("acquire nonce: %w", err)
+	}
+	used := false
+	defer func() { release(used) }()
+
+	gasPrice, err := client.SuggestGasPrice(ctx)
+	if err != nil {
+		return fmt.Errorf("suggest gas price: %w", err)
+	}
+
+	tx := types.NewTransaction(txNonce, contractAddress, big.NewInt(0), 200000, gasPrice, prepareConditionData(oracle, questionID, outcomeSlotCount))
+	signedTx, err := types.SignTx(tx, types.NewEIP155Signer(chainID), privateKey)
+	if err != nil {
+		return fmt.Errorf("sign transaction: %w", err)
+	}
+	if sendErr := client.SendTransaction(ctx, signedTx); sendErr != nil {
+		if nonce.IsDesyncError(sendErr) {
+			if resyncErr := nonces.ReportDesync(ctx, senderAddress.Hex()); resyncErr != nil {
+				return fmt.Errorf("send transaction: %w (resync failed: %v)", sendErr, resyncErr)
+			}
+		}
+		return fmt.Errorf("send transaction: %w", sendErr)
+	}
+	used = true
+	log.Printf("✅ prepareCondition sent: oracle=%s questionId=%s outcomeSlotCount=%d tx=%s", oracle.Hex(), questionID.Hex(), outcomeSlotCount, signedTx.Hash().Hex())
+	return nil
+}
+
+// runOnchainCLI 实现 `bot_go onchain` 命令, 用法:
+//
+//	bot_go onchain fund <targets_file>
+//	bot_go onchain prepare-condition <oracle> <questionId> <outcomeSlotCount>
+//
+// fund 的 targets_file 每行一个账户: "<address> <bnbTarget> <usdcTarget>",
+// 未达到目标余额的部分由 TREASURY_PRIVATE_KEY 对应的账户转入, 已经达标的
+// 账户跳过, 不会重复打款。prepare-condition 是可选的一步, 让
+// send_event/ctf 派生出来的 conditionId 在链上真的注册过, 生成的市场才能
+// 端到端可交易; 不跑这一步不影响 send_event 照常生成 mock 事件。
+func runOnchainCLI(args []string) {
+	usage := "Usage: bot_go onchain fund <targets_file> | bot_go onchain prepare-condition <oracle> <questionId> <outcomeSlotCount>"
+	if len(args) < 1 {
+		fmt.Println(usage)
+		os.Exit(1)
+	}
+
+	switch args[0] {
+	case "fund":
+		if len(args) < 2 {
+			fmt.Println(usage)
+			os.Exit(1)
+		}
+
+		targets, err := loadFundTargets(args[1])
+		if err != nil {
+			log.Fatalf("❌ %v", err)
+		}
+
+		creds, err := loadCredentials(context.Background())
+		if err != nil {
+			log.Fatalf("❌ load credentials: %v", err)
+		}
+
+		if apiChainID, err := GetAPIChainID(); err != nil {
+			log.Printf("skip chain validation, /config not available yet: %v", err)
+		} else if err := ValidateChainAgainstAPI(apiChainID); err != nil {
+			log.Fatalf("❌ %v", err)
+		}
+
+		if err := fundAccounts(ActiveChain(), creds.TreasuryPrivateKey, targets); err != nil {
+			log.Fatalf("❌ fund accounts: %v", err)
+		}
+		fmt.Printf("funded %d account(s)\n", len(targets))
+
+	case "prepare-condition":
+		if len(args) < 4 {
+			fmt.Println(usage)
+			os.Exit(1)
+		}
+
+		outcomeSlotCount, err := strconv.ParseUint(args[3], 10, 64)
+		if err != nil {
+			log.Fatalf("❌ invalid outcomeSlotCount %q: %v", args[3], err)
+		}
+
+		creds, err := loadCredentials(context.Background())
+		if err != nil {
+			log.Fatalf("❌ load credentials: %v", err)
+		}
+
+		if apiChainID, err := GetAPIChainID(); err != nil {
+			log.Printf("skip chain validation, /config not available yet: %v", err)
+		} else if err := ValidateChainAgainstAPI(apiChainID); err != nil {
+			log.Fatalf("❌ %v", err)
+		}
+
+		oracle := common.HexToAddress(args[1])
+		questionID := common.HexToHash(args[2])
+		if err := prepareCondition(ActiveChain(), creds.TreasuryPrivateKey, oracle, questionID, outcomeSlotCount); err != nil {
+			log.Fatalf("❌ prepare condition: %v", err)
+		}
+		fmt.Println("condition prepared")
+
+	default:
+		fmt.Println(usage)
+		os.Exit(1)
+	}
+}
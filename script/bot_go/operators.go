@@ -0,0 +1,204 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"log"
+	"time"
+
+	"secrets"
+)
+
+// OperatorAccountsCredential 是保存操作员配置账户列表的凭据名, 通过
+// loadCredentials 同一套 CredentialsProvider (env/file/gcp/vault) 解析,
+// 内容是一段 JSON 数组 (见 OperatorAccountConfig), 而不是一对写死的
+// Account1/Account2。未配置这个凭据时视为没有开启这个功能, 行为退化成
+// 只用 Account1/Account2 两个账户, 不影响现有部署。
+const OperatorAccountsCredential = "OPERATOR_ACCOUNTS_JSON"
+
+// OperatorAccountConfig 描述一个由运营方配置、代表某个用户下单的账户。
+// 私钥/API Key 本身不出现在这段 JSON 里, 只放它们各自在
+// CredentialsProvider 里的凭据名, 跟 Account1PrivateKey 之类字段解析方式
+// 一致, 这段配置本身也是走同一个 provider 读出来的, 不需要在配置文件里
+// 出现任何明文密钥。
+type OperatorAccountConfig struct {
+	// AccountKey 是这个账户在 riskEngine/quoteThrottle 里的标识, 建议按
+	// "operator/<user_id>" 之类的格式命名, 避免跟 RiskAccount1/
+	// RiskAccount2 撞名。
+	AccountKey string `json:"account_key"`
+	Address    string `json:"address"`
+	// PrivateKeyCredential/FetchTokenPriKeyCredential/APIKeyCredential 是
+	// 这个账户对应私钥/Privy 登录私钥/下单 API Key 在 CredentialsProvider
+	// 里的凭据名。
+	PrivateKeyCredential       string `json:"private_key_credential"`
+	FetchTokenAddress          string `json:"fetch_token_address"`
+	FetchTokenPriKeyCredential string `json:"fetch_token_private_key_credential"`
+	APIKeyCredential           string `json:"api_key_credential"`
+}
+
+// LoadOperatorAccounts 解析 OperatorAccountsCredential 里配置的账户列表。
+// 凭据未配置 (任意 provider 返回 error, 目前四种 provider 实现里
+// "未设置"都是通过返回 error 表达, 没有单独的 IsSet 接口) 时视为功能未
+// 开启, 返回空列表而不是报错, 这样没配置这个凭据的部署行为不变。
+func LoadOperatorAccounts(ctx context.Context, provider secrets.CredentialsProvider) ([]OperatorAccountConfig, error) {
+	raw, err := provider.GetCredential(ctx, OperatorAccountsCredential)
+	if err != nil {
+		return nil, nil
+	}
+
+	var configs []OperatorAccountConfig
+	if err := json.Unmarshal([]byte(raw), &configs); err != nil {
+		return nil, fmt.Errorf("parse %s: %w", OperatorAccountsCredential, err)
+	}
+	return configs, nil
+}
+
+// SessionForOperatorAccount 解析 cfg 里引用的凭据名, 构造出这个操作员账户
+// 对应的 Session, 跟 loadCredentials/NewSession 组装 Account1/Account2
+// Session 用的是同一套流程。
+func SessionForOperatorAccount(ctx context.Context, provider secrets.CredentialsProvider, cfg OperatorAccountConfig) (*Session, error) {
+	privateKey, err := provider.GetCredential(ctx, cfg.PrivateKeyCredential)
+	if err != nil {
+		return nil, fmt.Errorf("load private key for %s: %w", cfg.AccountKey, err)
+	}
+	fetchTokenPriKey, err := provider.GetCredential(ctx, cfg.FetchTokenPriKeyCredential)
+	if err != nil {
+		return nil, fmt.Errorf("load fetch token private key for %s: %w", cfg.AccountKey, err)
+	}
+	apiKey, err := provider.GetCredential(ctx, cfg.APIKeyCredential)
+	if err != nil {
+		return nil, fmt.Errorf("load api key for %s: %w", cfg.AccountKey, err)
+	}
+
+	return NewSession(cfg.AccountKey, cfg.Address, privateKey, cfg.FetchTokenAddress, fetchTokenPriKey, apiKey)
+}
+
+// operatorPair 是一组用来互相对敲的账户 (吃单方/挂单方), 跟 RunBot 里的
+// account1/account2 角色一致, 只是身份来自配置而不是写死的两个账户。
+type operatorPair struct {
+	Taker *Session // 吃单方, 对应 RunBot 里的 account1
+	Maker *Session // 挂单方, 对应 RunBot 里的 account2
+}
+
+// PairOperatorSessions 把 sessions 两两配对成 operatorPair, 顺序即
+// configs 里出现的顺序, 前一个是 taker、后一个是 maker。sessions 数量为
+// 奇数说明配置遗漏了搭档, 直接报错而不是丢弃落单的账户。
+func PairOperatorSessions(sessions []*Session) ([]operatorPair, error) {
+	if len(sessions)%2 != 0 {
+		return nil, fmt.Errorf("operator accounts: %d sessions cannot be paired evenly, need an even count", len(sessions))
+	}
+	pairs := make([]operatorPair, 0, len(sessions)/2)
+	for i := 0; i < len(sessions); i += 2 {
+		pairs = append(pairs, operatorPair{Taker: sessions[i], Maker: sessions[i+1]})
+	}
+	return pairs, nil
+}
+
+// RunOperatorFleet 为每一对配置好的账户各跑一轮 ProcessMarket/
+// ProcessArbitrage, 复用跟 RunBot 完全相同的对敲逻辑, 只是不止一组账户。
+// 一对账户的下单失败只记录日志, 不影响其他对或者其他市场。
+func RunOperatorFleet(db *sql.DB, pairs []operatorPair) error {
+	events, err := GetActiveEvents(db)
+	if err != nil {
+		return fmt.Errorf("get events failed: %v", err)
+	}
+	log.Printf("operator fleet: found %d active events for %d account pair(s)", len(events), len(pairs))
+
+	budgets := allocateMarketBudgets(events)
+
+	assignments, err := LoadMarketAssignments(db)
+	if err != nil {
+		log.Printf("operator fleet: load market maker assignments failed, ignoring assignment table this cycle: %v", err)
+		assignments = nil
+	}
+
+	for _, pair := range pairs {
+		for _, event := range events {
+			for _, market := range event.Markets {
+				mktKey := marketKey(event.ID, market.ID)
+				if IsMarketPaused(mktKey) {
+					continue
+				}
+				if !MayQuote(assignments, mktKey, pair.Maker.AccountKey) {
+					continue
+				}
+				budgetUSDC := budgets[mktKey]
+				if budgetUSDC <= 0 {
+					budgetUSDC = QuoteProfileForTopic(event.Topic).OrderUSDC
+				}
+				if err := ProcessMarket(event, market, pair.Taker, pair.Maker, budgetUSDC); err != nil {
+					log.Printf("operator fleet: process market %s for %s/%s failed: %v",
+						mktKey, pair.Taker.AccountKey, pair.Maker.AccountKey, err)
+				}
+				if err := ProcessArbitrage(event, market, pair.Taker, pair.Maker); err != nil {
+					log.Printf("operator fleet: process arbitrage %s for %s/%s failed: %v",
+						mktKey, pair.Taker.AccountKey, pair.Maker.AccountKey, err)
+				}
+				time.Sleep(1 * time.Second)
+			}
+		}
+	}
+
+	return nil
+}
+
+// runOperatorsCLI 跑一轮 OperatorAccountsCredential 里配置的全部账户对,
+// 用法:
+//
+//	bot_go operators run
+//
+// 跟 quote-monitor/janitor 不同, 这里只跑一轮就退出, 部署方按自己需要的
+// 节奏用 cron/systemd timer 反复调用, 而不是内置一个新的 ticker 循环。
+func runOperatorsCLI(args []string) {
+	if len(args) < 1 || args[0] != "run" {
+		fmt.Println("Usage: bot_go operators run")
+		return
+	}
+
+	ctx := context.Background()
+	provider, err := newCredentialsProvider(ctx)
+	if err != nil {
+		log.Fatalf("select credentials provider: %v", err)
+	}
+
+	configs, err := LoadOperatorAccounts(ctx, provider)
+	if err != nil {
+		log.Fatalf("load operator accounts: %v", err)
+	}
+	if len(configs) == 0 {
+		log.Printf("no operator accounts configured under %s, nothing to do", OperatorAccountsCredential)
+		return
+	}
+
+	sessions := make([]*Session, 0, len(configs))
+	for _, cfg := range configs {
+		session, err := SessionForOperatorAccount(ctx, provider, cfg)
+		if err != nil {
+			log.Fatalf("build session for %s: %v", cfg.AccountKey, err)
+		}
+		sessions = append(sessions, session)
+	}
+
+	pairs, err := PairOperatorSessions(sessions)
+	if err != nil {
+		log.Fatalf("pair operator accounts: %v", err)
+	}
+
+	creds, err := loadCredentials(ctx)
+	if err != nil {
+		log.Fatalf("load credentials: %v", err)
+	}
+	connStr := fmt.Sprintf("host=%s port=%d user=%s password=%s dbname=%s sslmode=require",
+		DBHost, DBPort, DBUser, creds.DBPassword, DBName)
+	db, err := sql.Open("postgres", connStr)
+	if err != nil {
+		log.Fatalf("connect to database: %v", err)
+	}
+	defer db.Close()
+
+	if err := RunOperatorFleet(db, pairs); err != nil {
+		log.Fatalf("run operator fleet: %v", err)
+	}
+}
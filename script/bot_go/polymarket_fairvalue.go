@@ -0,0 +1,96 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/shopspring/decimal"
+)
+
+// polymarketCLOBBaseURL 是 Polymarket CLOB API 地址, 用于拉取镜像市场的
+// 中间价。
+const polymarketCLOBBaseURL = "https://clob.polymarket.com"
+
+// polymarketTokenMapping 维护本地 (eventID, marketID, tokenID) 到对应
+// Polymarket CLOB token id 的映射, key 见 fairValueKey。新增一个镜像
+// Polymarket 事件的市场时, 在这里补一条即可, 不需要改代码。目前还没有
+// 镜像市场上线, 该表暂时为空占位。
+var polymarketTokenMapping = map[string]string{}
+
+// PolymarketFairValue 镜像 Polymarket 对应市场的中间价作为公允价估计,
+// 用于我们自己开的、复制某个 Polymarket 事件的市场, 在其基础上加一个
+// 固定偏移量报价, 而不是直接照抄我们自己单薄的盘口。
+type PolymarketFairValue struct {
+	// TokenIDs 把 fairValueKey(eventID, marketID, tokenID) 映射到对应的
+	// Polymarket CLOB token id; 为 nil 时回退到包级 polymarketTokenMapping。
+	TokenIDs map[string]string
+	// Offset 加在 Polymarket 中间价上的固定偏移量, 用于补偿我们自己市场
+	// 和 Polymarket 之间的费率/流动性差异, 正值表示我们比 Polymarket 报价更高。
+	Offset decimal.Decimal
+	// FetchMidPrice 拉取给定 Polymarket CLOB token id 的中间价, 默认实现
+	// (fetchPolymarketMidPrice) 调用 CLOB 的 /midpoint 接口。
+	FetchMidPrice func(ctx context.Context, polyTokenID string) (decimal.Decimal, error)
+}
+
+// FairValue 实现 FairValueProvider。
+func (p PolymarketFairValue) FairValue(ctx context.Context, eventID int64, marketID int16, tokenID string) (decimal.Decimal, error) {
+	mapping := p.TokenIDs
+	if mapping == nil {
+		mapping = polymarketTokenMapping
+	}
+
+	key := fairValueKey(eventID, marketID, tokenID)
+	polyTokenID, ok := mapping[key]
+	if !ok {
+		return decimal.Decimal{}, fmt.Errorf("no polymarket mapping configured for %s", key)
+	}
+
+	fetch := p.FetchMidPrice
+	if fetch == nil {
+		fetch = fetchPolymarketMidPrice
+	}
+
+	mid, err := fetch(ctx, polyTokenID)
+	if err != nil {
+		return decimal.Decimal{}, fmt.Errorf("fetch polymarket mid price failed: %w", err)
+	}
+	return mid.Add(p.Offset), nil
+}
+
+// polymarketMidpointResponse 是 Polymarket CLOB /midpoint 接口的响应。
+type polymarketMidpointResponse struct {
+	Mid string `json:"mid"`
+}
+
+// fetchPolymarketMidPrice 是 PolymarketFairValue 的默认 FetchMidPrice。
+func fetchPolymarketMidPrice(ctx context.Context, polyTokenID string) (decimal.Decimal, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", polymarketCLOBBaseURL+"/midpoint?token_id="+polyTokenID, nil)
+	if err != nil {
+		return decimal.Decimal{}, err
+	}
+
+	client, err := newFairValueClient()
+	if err != nil {
+		return decimal.Decimal{}, fmt.Errorf("build fair value client: %w", err)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return decimal.Decimal{}, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return decimal.Decimal{}, err
+	}
+
+	var parsed polymarketMidpointResponse
+	if err := json.Unmarshal(body, &parsed); err != nil {
+		return decimal.Decimal{}, fmt.Errorf("parse polymarket midpoint response failed: %v, body: %s", err, string(body))
+	}
+	return decimal.NewFromString(parsed.Mid)
+}
@@ -0,0 +1,92 @@
+package main
+
+import (
+	"sync"
+	"time"
+)
+
+// QuoteProfile 把值得按 event topic 单独调的策略参数打包在一起
+// (sports/crypto/politics 的流动性和波动性差异很大), 而不是所有市场共用
+// 一套全局的 OrderUSDC 和价格带。
+type QuoteProfile struct {
+	// OrderUSDC 单笔挂单金额 (USDC), 覆盖包级 OrderUSDC 常量。
+	OrderUSDC float64
+	// SpreadTicks 是 penny-in 模式下在买1价基础上让开的 tick 数, 直接传给
+	// quotePrice/PennyInPrice。
+	SpreadTicks int64
+	// Levels 是同时维护的挂单档位数。ProcessMarket 目前只挂一档, 这里先
+	// 作为配置占位, 支持多档位挂单后按这个数字展开。
+	Levels int
+	// RefreshInterval 是该 topic 下市场的报价刷新间隔, 覆盖包级
+	// IntervalMinutes。主循环目前是单个全局 ticker, 这个值先作为配置
+	// 占位, 按 topic 拆分调度周期后再消费。
+	RefreshInterval time.Duration
+}
+
+// defaultQuoteProfile 是没有按 topic 命中时的兜底参数, 数值与现有全局
+// 常量保持一致, 保证没配置 topic 的市场行为不变。
+var defaultQuoteProfile = QuoteProfile{
+	OrderUSDC:       OrderUSDC,
+	SpreadTicks:     1,
+	Levels:          1,
+	RefreshInterval: IntervalMinutes * time.Minute,
+}
+
+// topicQuoteProfilesMu 保护 topicQuoteProfiles, 因为 ReloadDynamicConfig 会
+// 在主策略循环之外的 goroutine 里替换整张表。
+var topicQuoteProfilesMu sync.RWMutex
+
+// topicQuoteProfiles 按 event 的 topic 字段覆盖策略参数, 未命中的 topic
+// 回退到 defaultQuoteProfile。这里先预置几个已知 topic 的示例值, 后续
+// 按实际流动性数据调整; 也可以通过 bot config 文件热更新, 见 config.go。
+var topicQuoteProfiles = map[string]QuoteProfile{
+	"sports": {
+		OrderUSDC:       OrderUSDC,
+		SpreadTicks:     1,
+		Levels:          1,
+		RefreshInterval: IntervalMinutes * time.Minute,
+	},
+	"crypto": {
+		OrderUSDC:       OrderUSDC * 2.5,
+		SpreadTicks:     2,
+		Levels:          2,
+		RefreshInterval: 15 * time.Minute,
+	},
+	"politics": {
+		OrderUSDC:       OrderUSDC / 2,
+		SpreadTicks:     1,
+		Levels:          1,
+		RefreshInterval: 60 * time.Minute,
+	},
+}
+
+// QuoteProfileForTopic 返回 topic 对应的策略参数, 未配置的 topic (含空
+// 字符串) 回退到 defaultQuoteProfile。
+func QuoteProfileForTopic(topic string) QuoteProfile {
+	topicQuoteProfilesMu.RLock()
+	defer topicQuoteProfilesMu.RUnlock()
+	if profile, ok := topicQuoteProfiles[topic]; ok {
+		return profile
+	}
+	return defaultQuoteProfile
+}
+
+// SetTopicQuoteProfiles 整体替换 topicQuoteProfiles, 供 ReloadDynamicConfig
+// 热更新策略参数使用。
+func SetTopicQuoteProfiles(profiles map[string]QuoteProfile) {
+	topicQuoteProfilesMu.Lock()
+	defer topicQuoteProfilesMu.Unlock()
+	topicQuoteProfiles = profiles
+}
+
+// TopicQuoteProfilesSnapshot 返回 topicQuoteProfiles 当前内容的拷贝, 供
+// ReloadDynamicConfig 比较变更用于审计日志。
+func TopicQuoteProfilesSnapshot() map[string]QuoteProfile {
+	topicQuoteProfilesMu.RLock()
+	defer topicQuoteProfilesMu.RUnlock()
+	out := make(map[string]QuoteProfile, len(topicQuoteProfiles))
+	for k, v := range topicQuoteProfiles {
+		out[k] = v
+	}
+	return out
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"github.com/shopspring/decimal"
+)
+
+// TickSize 是价格最小变动单位, 与 ProcessMarket 现有 Truncate(4) 的价格精度
+// 保持一致。
+var TickSize = decimal.New(1, -4) // 0.0001
+
+// QuoteMode 决定 ProcessMarket 挂 token_1 买单时如何从盘口推导出价。
+type QuoteMode int
+
+const (
+	// QuoteModeCopyBest 直接照抄买1价, 是 ProcessMarket 原来的行为。
+	QuoteModeCopyBest QuoteMode = iota
+	// QuoteModePennyIn 在买1价基础上加一个 tick 抢占队列 (penny-in), 但不
+	// 超过调用方给定的价格上限, 也不会为了抢价而越过 fair value 估计;
+	// 越过时退回到跟随买1价挂单 (join best bid)。
+	QuoteModePennyIn
+)
+
+// quoteMode 控制 ProcessMarket 的挂单出价方式, 默认保持原来的行为
+// (照抄买1价), 需要试验 penny-in 时手动改成 QuoteModePennyIn。
+var quoteMode = QuoteModeCopyBest
+
+// MaxPennyInPrice 是 penny-in 模式下允许挂出的最高价, 防止盘口很薄时
+// tick-by-tick 抢价一路追到接近 1 的地方吃亏。
+var MaxPennyInPrice = decimal.NewFromFloat(0.95)
+
+// PennyInPrice 计算 penny-in/join-best-bid 模式下应挂的价格。
+//
+//   - 结果比 bestBid 高 spreadTicks 个 TickSize, 但不超过 maxPrice。
+//     spreadTicks 非正数时按 1 个 tick 处理。
+//   - fairValue 非零时, 若加价后的结果会越过 fairValue, 则不抢价, 直接
+//     跟随 bestBid 挂单 (join), 避免为了排队靠前而买在预期公允价之上。
+//   - fairValue 为零值表示调用方还没有可用的公允价估计, 跳过这项限制
+//     (目前 ProcessMarket 就是这种情况, 公允价估计见后续的 fair value
+//     provider)。
+func PennyInPrice(bestBid, maxPrice, fairValue decimal.Decimal, spreadTicks int64) decimal.Decimal {
+	if spreadTicks <= 0 {
+		spreadTicks = 1
+	}
+	pennied := bestBid.Add(TickSize.Mul(decimal.NewFromInt(spreadTicks)))
+	if pennied.GreaterThan(maxPrice) {
+		pennied = maxPrice
+	}
+	if !fairValue.IsZero() && pennied.GreaterThan(fairValue) {
+		return bestBid
+	}
+	if !pennied.GreaterThan(bestBid) {
+		return bestBid
+	}
+	return pennied
+}
+
+// quotePrice 根据 quoteMode 从买1价 bestBid 推导出 ProcessMarket 应该挂的
+// 价格。fairValue 为零值表示没有可用的公允价估计, spreadTicks 是
+// penny-in 模式下让开的 tick 数, 通常来自该市场所属 topic 的 QuoteProfile。
+func quotePrice(bestBid, fairValue decimal.Decimal, spreadTicks int64) decimal.Decimal {
+	return quotePriceWithMode(quoteMode, bestBid, fairValue, spreadTicks)
+}
+
+// quotePriceWithMode 是 quotePrice 的实现, 额外接受一个 mode 参数, 供
+// shadow.go 在不改变实际挂单行为的前提下, 用另一个 QuoteMode 重新计算一遍
+// 同样的报价用于比对。
+func quotePriceWithMode(mode QuoteMode, bestBid, fairValue decimal.Decimal, spreadTicks int64) decimal.Decimal {
+	switch mode {
+	case QuoteModePennyIn:
+		return PennyInPrice(bestBid, MaxPennyInPrice, fairValue, spreadTicks)
+	default:
+		return bestBid
+	}
+}
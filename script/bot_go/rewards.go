@@ -0,0 +1,208 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// RewardEpochMinutesEnv 覆盖每个统计窗口的时长 (分钟), 未设置时按小时
+// 分桶。交易所目前还没有公布具体的做市奖励计划, 窗口长度先按最常见的
+// "按小时结算" 假设配置, 真正的计划公布后只需要改这个环境变量。
+const RewardEpochMinutesEnv = "REWARD_EPOCH_MINUTES"
+
+// RewardEpochDuration 返回 RewardEpochMinutesEnv 配置的窗口时长, 未设置或
+// 解析失败时回退到 1 小时。
+func RewardEpochDuration() time.Duration {
+	raw := os.Getenv(RewardEpochMinutesEnv)
+	if raw == "" {
+		return time.Hour
+	}
+	minutes, err := strconv.Atoi(raw)
+	if err != nil || minutes <= 0 {
+		log.Printf("invalid %s=%q, falling back to 60 minutes: %v", RewardEpochMinutesEnv, raw, err)
+		return time.Hour
+	}
+	return time.Duration(minutes) * time.Minute
+}
+
+// epochStart 把 t 截断到所在的统计窗口起点。
+func epochStart(t time.Time) time.Time {
+	return t.Truncate(RewardEpochDuration())
+}
+
+// marketEpochReward 累计一个市场在一个统计窗口内的做市量和报价在线情况。
+// QuoteAttempts/QuoteSuccesses 是 Account2 (挂单账户) 每次尝试挂单的次数和
+// 成功次数的粗粒度代理: 目前没有独立的"两侧报价是否在盘口附近"采样循环,
+// 每轮 ProcessMarket 尝试挂 Account2 的单就算一次采样, 挂单成功就算这次
+// 采样"在线"。
+type marketEpochReward struct {
+	MakerVolumeUSDC decimal.Decimal `json:"maker_volume_usdc"`
+	QuoteAttempts   int64           `json:"quote_attempts"`
+	QuoteSuccesses  int64           `json:"quote_successes"`
+}
+
+// rewardTracker 按市场再按统计窗口聚合 marketEpochReward, 用法和
+// arbExposure/riskEngine 一样是进程内单例 + 互斥锁保护。
+type rewardTracker struct {
+	mu    sync.Mutex
+	stats map[string]map[int64]*marketEpochReward
+}
+
+var makerRewards = &rewardTracker{stats: make(map[string]map[int64]*marketEpochReward)}
+
+// RecordMakerQuoteAttempt 登记一次 Account2 挂单尝试: success 为 true 且
+// notional 非零时计入做市量, 无论成功与否都计入这个窗口的报价采样次数,
+// 用于估算报价在线率。
+func (t *rewardTracker) RecordMakerQuoteAttempt(mktKey string, notional decimal.Decimal, success bool, at time.Time) {
+	epoch := epochStart(at).Unix()
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	byEpoch, ok := t.stats[mktKey]
+	if !ok {
+		byEpoch = make(map[int64]*marketEpochReward)
+		t.stats[mktKey] = byEpoch
+	}
+	reward, ok := byEpoch[epoch]
+	if !ok {
+		reward = &marketEpochReward{}
+		byEpoch[epoch] = reward
+	}
+
+	reward.QuoteAttempts++
+	if success {
+		reward.QuoteSuccesses++
+		reward.MakerVolumeUSDC = reward.MakerVolumeUSDC.Add(notional)
+	}
+}
+
+// MarketEpochRewardEntry 是 RewardSnapshot 里的一条记录, 供报告/持久化
+// 使用。
+type MarketEpochRewardEntry struct {
+	MarketKey       string          `json:"market_key"`
+	EpochStart      time.Time       `json:"epoch_start"`
+	MakerVolumeUSDC decimal.Decimal `json:"maker_volume_usdc"`
+	QuoteAttempts   int64           `json:"quote_attempts"`
+	QuoteSuccesses  int64           `json:"quote_successes"`
+	UptimePct       float64         `json:"uptime_pct"`
+}
+
+// RewardSnapshot 返回目前累计的全部市场/窗口做市统计, 按市场 key 再按窗口
+// 起点排序, 保证输出稳定。
+func (t *rewardTracker) RewardSnapshot() []MarketEpochRewardEntry {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	var out []MarketEpochRewardEntry
+	for mktKey, byEpoch := range t.stats {
+		for epoch, reward := range byEpoch {
+			uptimePct := 0.0
+			if reward.QuoteAttempts > 0 {
+				uptimePct = float64(reward.QuoteSuccesses) / float64(reward.QuoteAttempts) * 100
+			}
+			out = append(out, MarketEpochRewardEntry{
+				MarketKey:       mktKey,
+				EpochStart:      time.Unix(epoch, 0),
+				MakerVolumeUSDC: reward.MakerVolumeUSDC,
+				QuoteAttempts:   reward.QuoteAttempts,
+				QuoteSuccesses:  reward.QuoteSuccesses,
+				UptimePct:       uptimePct,
+			})
+		}
+	}
+
+	sort.Slice(out, func(i, j int) bool {
+		if !out[i].EpochStart.Equal(out[j].EpochStart) {
+			return out[i].EpochStart.Before(out[j].EpochStart)
+		}
+		return out[i].MarketKey < out[j].MarketKey
+	})
+	return out
+}
+
+// restore 用快照替换当前累计的统计, 只在启动时的状态恢复流程里调用。
+func (t *rewardTracker) restore(entries []MarketEpochRewardEntry) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.stats = make(map[string]map[int64]*marketEpochReward, len(entries))
+	for _, e := range entries {
+		byEpoch, ok := t.stats[e.MarketKey]
+		if !ok {
+			byEpoch = make(map[int64]*marketEpochReward)
+			t.stats[e.MarketKey] = byEpoch
+		}
+		byEpoch[e.EpochStart.Unix()] = &marketEpochReward{
+			MakerVolumeUSDC: e.MakerVolumeUSDC,
+			QuoteAttempts:   e.QuoteAttempts,
+			QuoteSuccesses:  e.QuoteSuccesses,
+		}
+	}
+}
+
+// logRewardSnapshot 打印目前累计的做市量/报价在线率, RunBot 每个周期结束
+// 时和 logLatencySnapshot 一起调用, 供运营人员核对是否满足做市奖励计划
+// 的门槛, 而不用等平台单独提供报表。
+func logRewardSnapshot() {
+	snapshot := makerRewards.RewardSnapshot()
+	if len(snapshot) == 0 {
+		return
+	}
+
+	for _, e := range snapshot {
+		log.Printf("maker reward market=%s epoch=%s volume_usdc=%s uptime=%.1f%% (%d/%d)",
+			e.MarketKey, e.EpochStart.Format(time.RFC3339), e.MakerVolumeUSDC.String(), e.UptimePct, e.QuoteSuccesses, e.QuoteAttempts)
+	}
+}
+
+// rewardStateFile 是 makerRewards 快照落盘的路径, 可通过 REWARD_STATE_FILE
+// 环境变量覆盖, 未设置时落在当前工作目录下的 bot_rewards.json。
+var rewardStateFile = envOrDefault("REWARD_STATE_FILE", "bot_rewards.json")
+
+// rewardStateSnapshot 是 rewardStateFile 里保存的内容。
+type rewardStateSnapshot struct {
+	SavedAt time.Time                `json:"saved_at"`
+	Entries []MarketEpochRewardEntry `json:"entries"`
+}
+
+// SaveRewardState 把 makerRewards 当前累计的统计写入 rewardStateFile, 跟
+// SaveState 一样在 RunBot 每个周期结束时调用一次。
+func SaveRewardState() error {
+	snapshot := rewardStateSnapshot{SavedAt: time.Now(), Entries: makerRewards.RewardSnapshot()}
+	data, err := json.MarshalIndent(snapshot, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshal reward snapshot: %w", err)
+	}
+	if err := os.WriteFile(rewardStateFile, data, 0644); err != nil {
+		return fmt.Errorf("write reward snapshot: %w", err)
+	}
+	return nil
+}
+
+// LoadRewardState 读取 rewardStateFile 里的快照并恢复到 makerRewards, 文件
+// 不存在时视为空快照 (进程第一次启动)。
+func LoadRewardState() error {
+	data, err := os.ReadFile(rewardStateFile)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	var snapshot rewardStateSnapshot
+	if err := json.Unmarshal(data, &snapshot); err != nil {
+		return fmt.Errorf("parse reward snapshot: %w", err)
+	}
+	makerRewards.restore(snapshot.Entries)
+	return nil
+}
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"risk"
+)
+
+func TestRefreshExpiringOrderChecksAllow(t *testing.T) {
+	const accountKey = "test-refresh-blocked"
+	mktKey := marketKey(1, 1)
+	riskEngine.BanMarket(accountKey, mktKey)
+	defer riskEngine.UnbanMarket(accountKey, mktKey)
+
+	session := &Session{AccountKey: accountKey, PrivateKey: "deadbeef"}
+	tracked := ManagedOrder{
+		OrderID:    "order-refresh-1",
+		AccountKey: accountKey,
+		EventID:    1,
+		MarketID:   1,
+		TokenID:    "token-1",
+		Price:      "0.5",
+		Shares:     10,
+	}
+
+	if err := refreshExpiringOrder(session, tracked); err == nil {
+		t.Fatalf("expected refreshExpiringOrder to fail when Allow blocks the account/market")
+	}
+}
+
+func TestOrderManagerForgetReleasesRiskState(t *testing.T) {
+	const accountKey = "test-forget-release"
+	mktKey := marketKey(2, 1)
+	riskEngine.SetLimits(accountKey, risk.Limits{
+		MaxOpenNotional:    decimal.NewFromInt(10),
+		MaxOrdersPerMarket: 1,
+		MaxDailyLossUSDC:   decimal.NewFromInt(100),
+		BannedMarkets:      make(map[string]bool),
+	})
+
+	notional := decimal.NewFromInt(10)
+	if err := riskEngine.Allow(accountKey, mktKey, notional); err != nil {
+		t.Fatalf("first Allow: %v", err)
+	}
+	if err := riskEngine.Allow(accountKey, mktKey, decimal.NewFromInt(1)); err == nil {
+		t.Fatalf("expected second Allow to fail while the first order's notional/slot is still open")
+	}
+
+	om := NewOrderManager()
+	om.Track(ManagedOrder{
+		OrderID:    "order-forget-1",
+		AccountKey: accountKey,
+		EventID:    2,
+		MarketID:   1,
+		Price:      "10",
+		Shares:     1,
+	})
+	om.Forget("order-forget-1")
+
+	if err := riskEngine.Allow(accountKey, mktKey, notional); err != nil {
+		t.Fatalf("Allow after Forget: %v", err)
+	}
+}
@@ -0,0 +1,122 @@
+package main
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// RunLockTTL 是每个账户运行锁的存活时间, RunLock 会在到期前主动续期;
+// 持锁进程异常退出后, 最多 RunLockTTL 之后锁自动释放, 另一个部署才能
+// 接管这个账户, 不需要人工介入清理。
+const RunLockTTL = 30 * time.Second
+
+// RunLockRenewInterval 是 RunLock 续期的间隔, 明显短于 RunLockTTL, 避免
+// 一次续期因网络抖动失败就直接丢锁。
+const RunLockRenewInterval = 10 * time.Second
+
+// runLockKey 生成账户对应的 Redis key, 复用 kill switch 用的同一个
+// Redis 实例, 不为一个简单的分布式锁再引入一套基础设施。
+func runLockKey(accountKey string) string {
+	return fmt.Sprintf("bot_go:run_lock:%s", accountKey)
+}
+
+// RunLock 是账户维度的分布式互斥锁, 基于 Redis SET NX + TTL 实现, 防止
+// 同一账户的私钥/API Key 被两个 bot 部署同时使用而互相对敲/重复下单。
+type RunLock struct {
+	rdb     *redis.Client
+	key     string
+	token   string
+	cancel  context.CancelFunc
+	stopped chan struct{}
+}
+
+// renewLockScript 只有 token 仍然匹配时才续期, 避免锁过期后被别的实例
+// 抢到, 而旧实例的续期 goroutine 还在跑, 把新持锁者的锁续掉。
+var renewLockScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("PEXPIRE", KEYS[1], ARGV[2])
+else
+	return 0
+end
+`)
+
+// releaseLockScript 只有 token 仍然匹配时才删除, 避免误删已经被别的实例
+// 重新拿到的锁。
+var releaseLockScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("DEL", KEYS[1])
+else
+	return 0
+end
+`)
+
+// AcquireRunLock 尝试获取 accountKey 对应的运行锁, 拿不到时立即返回错误 -
+// 调用方应当据此退出, 而不是重试抢占, 避免和已经在跑的实例打架。拿到锁后
+// 会启动一个后台 goroutine 按 RunLockRenewInterval 续期, 直到 Release
+// 被调用或续期发现锁已经易主。
+func AcquireRunLock(rdb *redis.Client, accountKey string) (*RunLock, error) {
+	token, err := newLockToken()
+	if err != nil {
+		return nil, fmt.Errorf("generate lock token: %w", err)
+	}
+
+	key := runLockKey(accountKey)
+	ok, err := rdb.SetNX(context.Background(), key, token, RunLockTTL).Result()
+	if err != nil {
+		return nil, fmt.Errorf("acquire run lock: %w", err)
+	}
+	if !ok {
+		return nil, fmt.Errorf("run lock for %s already held by another instance", accountKey)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	lock := &RunLock{rdb: rdb, key: key, token: token, cancel: cancel, stopped: make(chan struct{})}
+	go lock.renewLoop(ctx)
+	return lock, nil
+}
+
+func newLockToken() (string, error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
+
+func (l *RunLock) renewLoop(ctx context.Context) {
+	defer close(l.stopped)
+	ticker := time.NewTicker(RunLockRenewInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			renewed, err := renewLockScript.Run(ctx, l.rdb, []string{l.key}, l.token, RunLockTTL.Milliseconds()).Int()
+			if err != nil {
+				log.Printf("run lock renew failed for %s: %v", l.key, err)
+				continue
+			}
+			if renewed == 0 {
+				log.Printf("run lock for %s was lost (renewal token mismatch)", l.key)
+				return
+			}
+		}
+	}
+}
+
+// Release 停止续期并释放锁 (仅当锁仍然是自己持有时才删除)。
+func (l *RunLock) Release() {
+	l.cancel()
+	<-l.stopped
+	if err := releaseLockScript.Run(context.Background(), l.rdb, []string{l.key}, l.token).Err(); err != nil {
+		log.Printf("run lock release failed for %s: %v", l.key, err)
+	}
+}
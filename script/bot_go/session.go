@@ -0,0 +1,118 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// Session 把一个账户的私钥/API Key/Privy 凭据、专属的 http.Client 和风控
+// 引擎里的 key 打包在一起, 构造一次后交给策略函数使用, 避免
+// ProcessMarket/ProcessArbitrage 之类的函数在参数里散落传递明文私钥字符串。
+type Session struct {
+	// AccountKey 是该账户在 riskEngine/quoteThrottle 中的标识 (RiskAccount1/
+	// RiskAccount2), Allow 用它做每账户维度的限额/节流检查。
+	AccountKey string
+	// Address 是下单/签名用的钱包地址, PrivateKey 是对应私钥。
+	Address    string
+	PrivateKey string
+	// FetchTokenAddress/FetchTokenPriKey 是登录 Privy 用的另一对地址/私钥,
+	// 与交易用的 Address/PrivateKey 是不同的密钥对。
+	FetchTokenAddress string
+	FetchTokenPriKey  string
+	// APIKey 是该账户下单/撤单接口用的 x-api-key。
+	APIKey string
+
+	httpClient *http.Client
+
+	mu         sync.Mutex
+	privyToken string
+}
+
+// NewSession 构造一个账户的 Session, 只在进程启动或每轮 RunBot 开始时调用
+// 一次, httpClient 复用同一个 marketAPIHTTPConfig 配置的 client。
+func NewSession(accountKey, address, privateKey, fetchTokenAddress, fetchTokenPriKey, apiKey string) (*Session, error) {
+	client, err := newMarketAPIClient()
+	if err != nil {
+		return nil, fmt.Errorf("build market api client: %w", err)
+	}
+	return &Session{
+		AccountKey:        accountKey,
+		Address:           address,
+		PrivateKey:        privateKey,
+		FetchTokenAddress: fetchTokenAddress,
+		FetchTokenPriKey:  fetchTokenPriKey,
+		APIKey:            apiKey,
+		httpClient:        client,
+	}, nil
+}
+
+// Authenticator 返回下单/撤单接口应使用的认证方式。
+func (s *Session) Authenticator() Authenticator {
+	return APIKeyAuth{APIKey: s.APIKey}
+}
+
+// Allow 依次检查 riskEngine 和 quoteThrottle 是否放行这次下单, 两个检查
+// 用的都是 s.AccountKey, 调用方不用再自己记得两处都要传对账户 key。
+func (s *Session) Allow(mktKey string, notional decimal.Decimal) error {
+	if err := riskEngine.Allow(s.AccountKey, mktKey, notional); err != nil {
+		return fmt.Errorf("risk engine: %w", err)
+	}
+	if err := quoteThrottle.Allow(s.AccountKey, mktKey); err != nil {
+		return fmt.Errorf("quote throttle: %w", err)
+	}
+	return nil
+}
+
+// CreateBuyOrder 用该账户的私钥/地址创建买单, 其余参数与包级 CreateBuyOrder
+// 相同。
+func (s *Session) CreateBuyOrder(tokenID string, price decimal.Decimal, shares int64, eventID int64, marketID int16, feeRateBps int64) (*PlaceOrderRequest, error) {
+	return CreateBuyOrder(s.PrivateKey, s.Address, tokenID, price, shares, eventID, marketID, feeRateBps)
+}
+
+// PlaceOrder 用该账户的 API Key 和已缓存的 httpClient 下单, 与包级 PlaceOrder
+// 效果相同, 但不必每次下单都新建一个 http.Client; 下单成功后登记进
+// orderManager, 供进程重启后的状态恢复使用, 同时把交易所分配的 order id
+// 返回给调用方, 供需要在原地等待这笔单实际挂上盘口的场景使用 (见
+// WaitForOrderResting)。
+func (s *Session) PlaceOrder(order *PlaceOrderRequest) (string, error) {
+	orderID, err := placeOrderWithClient(s.httpClient, s.Authenticator(), order)
+	if err != nil {
+		IncrementErrorCount("place_order")
+		return "", err
+	}
+
+	orderManager.Track(ManagedOrder{
+		OrderID:    orderID,
+		AccountKey: s.AccountKey,
+		EventID:    order.EventID,
+		MarketID:   order.MarketID,
+		TokenID:    order.TokenId,
+		Price:      order.Price,
+		Shares:     order.Shares,
+		PlacedAt:   time.Now(),
+		ExpiresAt:  parseOrderExpiration(order.Expiration),
+	})
+	return orderID, nil
+}
+
+// Authenticate 用 FetchTokenAddress/FetchTokenPriKey 登录 Privy 换取
+// identity token 并缓存, 重复调用只有第一次会真正发起登录请求。
+func (s *Session) Authenticate() (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.privyToken != "" {
+		return s.privyToken, nil
+	}
+
+	token, err := Authenticate(s.FetchTokenAddress, s.FetchTokenPriKey)
+	if err != nil {
+		return "", err
+	}
+	s.privyToken = token
+	return token, nil
+}
@@ -0,0 +1,121 @@
+package main
+
+import (
+	"log"
+	"os"
+	"sort"
+	"sync"
+
+	"github.com/shopspring/decimal"
+)
+
+// ShadowModeEnv 打开 shadow 策略评估, 设为 "1"/"true" 时 ProcessMarket 会
+// 额外用 shadowQuoteMode 算一遍同样输入下的报价, 只记录/打日志, 不下单,
+// 不影响 quoteMode 控制的实际挂单行为。未设置时默认关闭, 生产环境切换
+// 新策略前先开着跑一段时间看 diff 报告再决定要不要把 quoteMode 也切过去。
+const ShadowModeEnv = "BOT_SHADOW_MODE"
+
+func shadowModeEnabled() bool {
+	switch os.Getenv(ShadowModeEnv) {
+	case "1", "true", "on":
+		return true
+	default:
+		return false
+	}
+}
+
+// shadowQuoteMode 是正在评估中的候选策略, 跟 quoteMode (实际生效的策略)
+// 保持独立, 只在 EvaluateShadowQuote 里用来算 diff, 换一个候选策略时改
+// 这个变量即可, 不影响线上行为。
+var shadowQuoteMode = QuoteModePennyIn
+
+// shadowMarketStats 累计一个市场从进程启动到现在 shadow 策略跟线上策略的
+// 报价差异。
+type shadowMarketStats struct {
+	Count           int64
+	TotalAbsDiff    decimal.Decimal
+	MaxAbsDiff      decimal.Decimal
+	LastLivePrice   decimal.Decimal
+	LastShadowPrice decimal.Decimal
+}
+
+var (
+	shadowStatsMu sync.Mutex
+	shadowStats   = make(map[string]*shadowMarketStats)
+)
+
+// ShadowDiffEntry 是 ShadowSnapshot 里一个市场的汇总, 供 logShadowDiffReport
+// 打印和 admin API 后续查询。
+type ShadowDiffEntry struct {
+	MarketKey       string          `json:"market_key"`
+	Count           int64           `json:"count"`
+	AvgAbsDiff      decimal.Decimal `json:"avg_abs_diff"`
+	MaxAbsDiff      decimal.Decimal `json:"max_abs_diff"`
+	LastLivePrice   decimal.Decimal `json:"last_live_price"`
+	LastShadowPrice decimal.Decimal `json:"last_shadow_price"`
+}
+
+// EvaluateShadowQuote 在 shadow 模式打开时, 用 shadowQuoteMode 对 bestBid/
+// fairValue/spreadTicks 这组跟线上策略完全相同的输入重新算一遍报价, 记录
+// 跟 livePrice 的差异, 不做任何下单动作。shadow 模式关闭时是空操作。
+func EvaluateShadowQuote(mktKey string, bestBid, fairValue decimal.Decimal, spreadTicks int64, livePrice decimal.Decimal) {
+	if !shadowModeEnabled() {
+		return
+	}
+
+	shadowPrice := quotePriceWithMode(shadowQuoteMode, bestBid, fairValue, spreadTicks)
+	diff := shadowPrice.Sub(livePrice).Abs()
+
+	log.Printf("shadow: market=%s live=%s shadow=%s diff=%s", mktKey, livePrice.String(), shadowPrice.String(), diff.String())
+
+	shadowStatsMu.Lock()
+	defer shadowStatsMu.Unlock()
+	stats, ok := shadowStats[mktKey]
+	if !ok {
+		stats = &shadowMarketStats{}
+		shadowStats[mktKey] = stats
+	}
+	stats.Count++
+	stats.TotalAbsDiff = stats.TotalAbsDiff.Add(diff)
+	if diff.GreaterThan(stats.MaxAbsDiff) {
+		stats.MaxAbsDiff = diff
+	}
+	stats.LastLivePrice = livePrice
+	stats.LastShadowPrice = shadowPrice
+}
+
+// ShadowSnapshot 返回当前每个市场的 shadow 对比汇总, 按 market key 排序。
+func ShadowSnapshot() []ShadowDiffEntry {
+	shadowStatsMu.Lock()
+	defer shadowStatsMu.Unlock()
+
+	out := make([]ShadowDiffEntry, 0, len(shadowStats))
+	for mktKey, stats := range shadowStats {
+		avg := decimal.Zero
+		if stats.Count > 0 {
+			avg = stats.TotalAbsDiff.Div(decimal.NewFromInt(stats.Count))
+		}
+		out = append(out, ShadowDiffEntry{
+			MarketKey:       mktKey,
+			Count:           stats.Count,
+			AvgAbsDiff:      avg,
+			MaxAbsDiff:      stats.MaxAbsDiff,
+			LastLivePrice:   stats.LastLivePrice,
+			LastShadowPrice: stats.LastShadowPrice,
+		})
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].MarketKey < out[j].MarketKey })
+	return out
+}
+
+// logShadowDiffReport 打印每个市场目前为止的 shadow diff 汇总, RunBot 每
+// 个周期结束时调用一次, shadow 模式关闭或者还没有样本时不输出。
+func logShadowDiffReport() {
+	if !shadowModeEnabled() {
+		return
+	}
+	for _, entry := range ShadowSnapshot() {
+		log.Printf("shadow report: market=%s samples=%d avg_diff=%s max_diff=%s last_live=%s last_shadow=%s",
+			entry.MarketKey, entry.Count, entry.AvgAbsDiff.String(), entry.MaxAbsDiff.String(), entry.LastLivePrice.String(), entry.LastShadowPrice.String())
+	}
+}
@@ -0,0 +1,253 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// QuoteUptimeSampleInterval 是 RunQuoteUptimeMonitor 巡检一次全部活跃市场
+// 的节奏, 与 janitor 的 JanitorInterval 一样是独立于主策略循环的节奏。
+const QuoteUptimeSampleInterval = 1 * time.Minute
+
+// QuoteUptimeReportInterval 是按小时汇总/告警一次报价在线率的节奏。
+const QuoteUptimeReportInterval = time.Hour
+
+const (
+	// QuoteUptimeTargetPctEnv 覆盖达标线 (百分比), 未设置时默认 95%, 是做市
+	// 商commitment 类项目常见的门槛, 平台真正公布具体要求后再调整。
+	QuoteUptimeTargetPctEnv = "QUOTE_UPTIME_TARGET_PCT"
+	// QuoteUptimeTickToleranceEnv 覆盖判定"贴近中间价"的 tick 数, 未设置时
+	// 默认 3 个 TickSize。
+	QuoteUptimeTickToleranceEnv = "QUOTE_UPTIME_TICK_TOLERANCE"
+)
+
+func quoteUptimeTargetPct() float64 {
+	raw := os.Getenv(QuoteUptimeTargetPctEnv)
+	if raw == "" {
+		return 95.0
+	}
+	pct, err := strconv.ParseFloat(raw, 64)
+	if err != nil || pct <= 0 {
+		log.Printf("invalid %s=%q, falling back to 95: %v", QuoteUptimeTargetPctEnv, raw, err)
+		return 95.0
+	}
+	return pct
+}
+
+func quoteUptimeTickTolerance() int64 {
+	raw := os.Getenv(QuoteUptimeTickToleranceEnv)
+	if raw == "" {
+		return 3
+	}
+	ticks, err := strconv.ParseInt(raw, 10, 64)
+	if err != nil || ticks <= 0 {
+		log.Printf("invalid %s=%q, falling back to 3: %v", QuoteUptimeTickToleranceEnv, raw, err)
+		return 3
+	}
+	return ticks
+}
+
+// quoteUptimeSample 累计一个市场在一个小时窗口内的采样次数和其中判定为
+// "两侧都有报价贴在中间价附近" 的次数。
+type quoteUptimeSample struct {
+	Samples int64
+	Live    int64
+}
+
+var (
+	quoteUptimeMu    sync.Mutex
+	quoteUptimeStats = make(map[string]map[int64]*quoteUptimeSample)
+
+	// lastQuoteUptimeAlert 记录每个市场上次触发告警的窗口, 避免同一个低于
+	// 目标的窗口重复告警。
+	lastQuoteUptimeAlert = make(map[string]int64)
+)
+
+// recordQuoteUptimeSample 登记一次采样结果。
+func recordQuoteUptimeSample(mktKey string, live bool, at time.Time) {
+	epoch := epochStart(at).Unix()
+
+	quoteUptimeMu.Lock()
+	defer quoteUptimeMu.Unlock()
+
+	byEpoch, ok := quoteUptimeStats[mktKey]
+	if !ok {
+		byEpoch = make(map[int64]*quoteUptimeSample)
+		quoteUptimeStats[mktKey] = byEpoch
+	}
+	sample, ok := byEpoch[epoch]
+	if !ok {
+		sample = &quoteUptimeSample{}
+		byEpoch[epoch] = sample
+	}
+
+	sample.Samples++
+	if live {
+		sample.Live++
+	}
+}
+
+// hasLiveTwoSidedQuote 判断某个市场当前是否两个 token 都有一笔被
+// orderManager 跟踪的挂单, 且挂单价落在中间价 QuoteUptimeTickTolerance 个
+// tick 以内。中间价用 token1 (No) 盘口最优买1/卖1的均值估算; 没有足够深度
+// 时视为无法判断, 不计入"在线"。
+func hasLiveTwoSidedQuote(event Event, market Market) (bool, error) {
+	if len(market.TokenIDs) < 2 {
+		return false, nil
+	}
+	token0ID, token1ID := market.TokenIDs[0], market.TokenIDs[1]
+
+	depth, err := GetDepth(event.ID, market.ID)
+	if err != nil {
+		return false, fmt.Errorf("get depth failed: %v", err)
+	}
+	token1Depth, ok := depth.Data.Depths[token1ID]
+	if !ok || len(token1Depth.Bids) == 0 || len(token1Depth.Asks) == 0 {
+		return false, nil
+	}
+	bestBid, err := decimal.NewFromString(token1Depth.Bids[0].Price)
+	if err != nil {
+		return false, fmt.Errorf("parse bid price failed: %v", err)
+	}
+	bestAsk, err := decimal.NewFromString(token1Depth.Asks[0].Price)
+	if err != nil {
+		return false, fmt.Errorf("parse ask price failed: %v", err)
+	}
+	mid := bestBid.Add(bestAsk).Div(decimal.NewFromInt(2))
+
+	tolerance := TickSize.Mul(decimal.NewFromInt(quoteUptimeTickTolerance()))
+
+	var haveToken0, haveToken1 bool
+	for _, o := range orderManager.Snapshot() {
+		if o.EventID != event.ID || o.MarketID != market.ID {
+			continue
+		}
+		price, err := decimal.NewFromString(o.Price)
+		if err != nil {
+			continue
+		}
+		// token0 (Yes) 的隐含价格是 1 - price, 跟 feeAdjustedOppositePrice 的
+		// 假设一致。
+		var impliedMid decimal.Decimal
+		switch o.TokenID {
+		case token0ID:
+			impliedMid = decimal.NewFromInt(1).Sub(price)
+		case token1ID:
+			impliedMid = price
+		default:
+			continue
+		}
+		if impliedMid.Sub(mid).Abs().LessThanOrEqual(tolerance) {
+			if o.TokenID == token0ID {
+				haveToken0 = true
+			} else {
+				haveToken1 = true
+			}
+		}
+	}
+
+	return haveToken0 && haveToken1, nil
+}
+
+// sampleAllMarkets 巡检 events 里的每个未收盘市场, 采样一次报价在线状态。
+func sampleAllMarkets(events []Event, at time.Time) {
+	for _, event := range events {
+		for _, market := range event.Markets {
+			if market.Closed {
+				continue
+			}
+			mktKey := marketKey(event.ID, market.ID)
+			live, err := hasLiveTwoSidedQuote(event, market)
+			if err != nil {
+				log.Printf("quote uptime: sample market %s failed: %v", mktKey, err)
+				continue
+			}
+			recordQuoteUptimeSample(mktKey, live, at)
+		}
+	}
+}
+
+// reportAndAlertQuoteUptime 汇总每个市场最近一个已完整结束的窗口的在线
+// 率, 低于 quoteUptimeTargetPct 时告警 (每个窗口最多告警一次)。
+func reportAndAlertQuoteUptime(at time.Time) {
+	completedEpoch := epochStart(at.Add(-RewardEpochDuration())).Unix()
+	target := quoteUptimeTargetPct()
+
+	quoteUptimeMu.Lock()
+	defer quoteUptimeMu.Unlock()
+
+	for mktKey, byEpoch := range quoteUptimeStats {
+		sample, ok := byEpoch[completedEpoch]
+		if !ok || sample.Samples == 0 {
+			continue
+		}
+		uptimePct := float64(sample.Live) / float64(sample.Samples) * 100
+		log.Printf("quote uptime SLO market=%s epoch=%s uptime=%.1f%% (%d/%d) target=%.1f%%",
+			mktKey, time.Unix(completedEpoch, 0).Format(time.RFC3339), uptimePct, sample.Live, sample.Samples, target)
+
+		if uptimePct < target && lastQuoteUptimeAlert[mktKey] != completedEpoch {
+			lastQuoteUptimeAlert[mktKey] = completedEpoch
+			log.Printf("⚠️ quote uptime SLO breach: market=%s uptime=%.1f%% below target=%.1f%% for epoch=%s",
+				mktKey, uptimePct, target, time.Unix(completedEpoch, 0).Format(time.RFC3339))
+		}
+	}
+}
+
+// RunQuoteUptimeMonitor 每 QuoteUptimeSampleInterval 巡检一次全部活跃市场
+// 的两侧报价情况, 每 QuoteUptimeReportInterval 汇总一次前一个窗口的在线率
+// 并在低于 quoteUptimeTargetPct 时告警, 直到 stop 被关闭。跟
+// RunStaleOrderJanitor 一样独立于主策略循环运行, 不阻塞下单节奏。
+func RunQuoteUptimeMonitor(db *sql.DB, stop <-chan struct{}) {
+	sampleTicker := time.NewTicker(QuoteUptimeSampleInterval)
+	defer sampleTicker.Stop()
+	reportTicker := time.NewTicker(QuoteUptimeReportInterval)
+	defer reportTicker.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return
+		case <-sampleTicker.C:
+			events, err := GetActiveEvents(db)
+			if err != nil {
+				log.Printf("quote uptime monitor: get active events failed: %v", err)
+				continue
+			}
+			sampleAllMarkets(events, time.Now())
+		case <-reportTicker.C:
+			reportAndAlertQuoteUptime(time.Now())
+		}
+	}
+}
+
+// runQuoteMonitorCLI 作为独立进程运行 RunQuoteUptimeMonitor, 用法:
+//
+//	bot_go quote-monitor
+func runQuoteMonitorCLI(args []string) {
+	ctx := context.Background()
+
+	creds, err := loadCredentials(ctx)
+	if err != nil {
+		log.Fatalf("load credentials: %v", err)
+	}
+
+	connStr := fmt.Sprintf("host=%s port=%d user=%s password=%s dbname=%s sslmode=require",
+		DBHost, DBPort, DBUser, creds.DBPassword, DBName)
+	db, err := sql.Open("postgres", connStr)
+	if err != nil {
+		log.Fatalf("connect to database: %v", err)
+	}
+	defer db.Close()
+
+	log.Printf("Quote uptime monitor starting, sampleInterval=%s reportInterval=%s target=%.1f%%",
+		QuoteUptimeSampleInterval, QuoteUptimeReportInterval, quoteUptimeTargetPct())
+	RunQuoteUptimeMonitor(db, nil)
+}
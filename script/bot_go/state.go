@@ -0,0 +1,249 @@
+package main
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"units"
+)
+
+// ManagedOrder 是 bot 相信自己下出、还没确认成交/撤销的一笔挂单。
+type ManagedOrder struct {
+	OrderID    string    `json:"order_id"`
+	AccountKey string    `json:"account_key"`
+	EventID    int64     `json:"event_id"`
+	MarketID   int16     `json:"market_id"`
+	TokenID    string    `json:"token_id"`
+	Price      string    `json:"price"`
+	Shares     int64     `json:"shares"`
+	PlacedAt   time.Time `json:"placed_at"`
+	// ExpiresAt 是 GTD 订单的到期时间, 从 PlaceOrderRequest.Expiration 解析
+	// 得到, nil 表示这笔单没有过期时间 (GTC, 目前下单侧一直传 "0", 见
+	// expiry.go)。
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+}
+
+// OrderManager 跟踪 bot 自己下出、还没确认成交/撤销的挂单, 是 Session.
+// PlaceOrder 之外唯一的挂单状态来源: 进程重启后靠它加上交易所 open
+// orders 接口、数据库 trades 表这两个权威数据源对账, 恢复"这些挂单是我
+// 下的"这份记忆, 而不是从零开始、把之前下的单当成孤儿单处理。
+type OrderManager struct {
+	mu     sync.Mutex
+	orders map[string]ManagedOrder
+}
+
+// NewOrderManager 构造一个空的 OrderManager。
+func NewOrderManager() *OrderManager {
+	return &OrderManager{orders: make(map[string]ManagedOrder)}
+}
+
+// orderManager 是进程内全局的 OrderManager 实例, 与 riskEngine/
+// quoteThrottle 一样是进程内单例。
+var orderManager = NewOrderManager()
+
+// Track 登记一笔新下出的挂单。
+func (m *OrderManager) Track(o ManagedOrder) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.orders[o.OrderID] = o
+}
+
+// Forget 移除一笔已经确认成交/撤销的挂单, 并把它占用的 riskEngine 敞口/
+// 单市场下单次数都还回去, 否则一个账户的累计下单量或下单笔数迟早会分
+// 别超过 MaxOpenNotional/MaxOrdersPerMarket, 之后再也下不了新单, 而不
+// 仅仅是被限制敞口。
+func (m *OrderManager) Forget(orderID string) {
+	m.mu.Lock()
+	o, ok := m.orders[orderID]
+	delete(m.orders, orderID)
+	m.mu.Unlock()
+
+	if ok {
+		if price, err := decimal.NewFromString(o.Price); err == nil {
+			riskEngine.ReleaseNotional(o.AccountKey, marketKey(o.EventID, o.MarketID), units.Notional(price, o.Shares))
+		}
+	}
+	forgetVerifiedOrder(orderID)
+}
+
+// Has 报告 orderID 当前是否在跟踪中。
+func (m *OrderManager) Has(orderID string) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	_, ok := m.orders[orderID]
+	return ok
+}
+
+// HasEquivalent 报告当前是否已经有一笔跟踪中的挂单跟 (accountKey, tokenID,
+// price, shares) 这个指纹完全一致, 供下单前的重复挂单检测使用, 避免每
+// 轮策略周期在同一个价位反复叠加一模一样的单子。
+func (m *OrderManager) HasEquivalent(accountKey, tokenID, price string, shares int64) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, o := range m.orders {
+		if o.AccountKey == accountKey && o.TokenID == tokenID && o.Price == price && o.Shares == shares {
+			return true
+		}
+	}
+	return false
+}
+
+// Snapshot 返回当前跟踪的全部挂单, 用于持久化快照或按账户遍历核对。
+func (m *OrderManager) Snapshot() []ManagedOrder {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	out := make([]ManagedOrder, 0, len(m.orders))
+	for _, o := range m.orders {
+		out = append(out, o)
+	}
+	return out
+}
+
+// restore 用快照替换当前跟踪的全部挂单, 只在启动时的状态恢复流程里调用。
+func (m *OrderManager) restore(orders []ManagedOrder) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.orders = make(map[string]ManagedOrder, len(orders))
+	for _, o := range orders {
+		m.orders[o.OrderID] = o
+	}
+}
+
+// botStateFile 是 orderManager 快照落盘的路径, 可通过 BOT_STATE_FILE
+// 环境变量覆盖, 未设置时落在当前工作目录下的 bot_state.json。
+var botStateFile = envOrDefault("BOT_STATE_FILE", "bot_state.json")
+
+func envOrDefault(name, def string) string {
+	if v := os.Getenv(name); v != "" {
+		return v
+	}
+	return def
+}
+
+// botStateSnapshot 是 botStateFile 里保存的内容。
+type botStateSnapshot struct {
+	SavedAt time.Time      `json:"saved_at"`
+	Orders  []ManagedOrder `json:"orders"`
+}
+
+// SaveState 把 orderManager 当前的挂单快照写入 botStateFile。RunBot 每个
+// 周期结束时调用一次, 保证进程崩溃后重启能看到最近一次已知状态, 而不是
+// 完全丢失"哪些挂单是自己下的"这份记忆。
+func SaveState() error {
+	snapshot := botStateSnapshot{SavedAt: time.Now(), Orders: orderManager.Snapshot()}
+	data, err := json.MarshalIndent(snapshot, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshal state snapshot: %w", err)
+	}
+	if err := os.WriteFile(botStateFile, data, 0644); err != nil {
+		return fmt.Errorf("write state snapshot: %w", err)
+	}
+	return nil
+}
+
+// loadStateFile 读取 botStateFile 里的快照, 文件不存在时视为空快照
+// (进程第一次启动, 或者是全新部署的场景)。
+func loadStateFile() ([]ManagedOrder, error) {
+	data, err := os.ReadFile(botStateFile)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var snapshot botStateSnapshot
+	if err := json.Unmarshal(data, &snapshot); err != nil {
+		return nil, fmt.Errorf("parse state snapshot: %w", err)
+	}
+	return snapshot.Orders, nil
+}
+
+// orderHasTrade 报告 orderID 在 trades 表里是否已经有成交记录。
+func orderHasTrade(db *sql.DB, orderID string) (bool, error) {
+	var exists bool
+	err := db.QueryRow(`SELECT EXISTS(SELECT 1 FROM trades WHERE order_id = $1)`, orderID).Scan(&exists)
+	if err != nil {
+		return false, fmt.Errorf("query trades for order %s: %w", orderID, err)
+	}
+	return exists, nil
+}
+
+// LoadAndReconcileState 从 botStateFile 加载上一次的挂单快照, 然后用
+// 交易所 open orders 接口和数据库 trades 表这两个权威数据源核对:
+//
+//   - 快照记录的订单如果在交易所已经不再是 open 状态, 检查 trades 表:
+//     有成交记录就当作已成交, 没有就当作已撤销/过期, 两种情况都不再
+//     跟踪。
+//   - 交易所 open orders 里存在、但快照没有记录的订单 (例如上一次快照
+//     写入之后、进程崩溃之前刚下出的单), 补进 orderManager, 避免后续
+//     被孤儿单清理逻辑之外的假设遗漏。
+//
+// 调用方通常是 start_bot 在进入主循环之前调用一次。某个账户的 open
+// orders 接口调用失败时, 保留该账户在快照里的记录原样不变, 不放大成
+// 整体启动失败。
+func LoadAndReconcileState(db *sql.DB, sessions []*Session) error {
+	snapshot, err := loadStateFile()
+	if err != nil {
+		return fmt.Errorf("load state snapshot: %w", err)
+	}
+	orderManager.restore(snapshot)
+
+	for _, session := range sessions {
+		openOrders, err := GetOpenOrders(session.Authenticator())
+		if err != nil {
+			log.Printf("reconcile: get open orders for %s failed, keeping snapshot as-is: %v", session.AccountKey, err)
+			continue
+		}
+
+		openByID := make(map[string]OpenOrder, len(openOrders))
+		for _, o := range openOrders {
+			openByID[o.OrderID] = o
+		}
+
+		for _, tracked := range orderManager.Snapshot() {
+			if tracked.AccountKey != session.AccountKey {
+				continue
+			}
+			if _, stillOpen := openByID[tracked.OrderID]; stillOpen {
+				continue
+			}
+
+			matched, err := orderHasTrade(db, tracked.OrderID)
+			if err != nil {
+				log.Printf("reconcile: %v", err)
+				continue
+			}
+			if matched {
+				log.Printf("reconcile: order %s matched while bot was down, dropping from tracking", tracked.OrderID)
+			} else {
+				log.Printf("reconcile: order %s no longer open and has no trade, treating as cancelled/expired", tracked.OrderID)
+			}
+			orderManager.Forget(tracked.OrderID)
+		}
+
+		for orderID, open := range openByID {
+			if orderManager.Has(orderID) {
+				continue
+			}
+			log.Printf("reconcile: found untracked open order %s for %s, adopting it", orderID, session.AccountKey)
+			orderManager.Track(ManagedOrder{
+				OrderID:    orderID,
+				AccountKey: session.AccountKey,
+				EventID:    open.EventID,
+				MarketID:   open.MarketID,
+				TokenID:    open.TokenID,
+				PlacedAt:   time.Unix(open.CreatedAt, 0),
+			})
+		}
+	}
+
+	return SaveState()
+}
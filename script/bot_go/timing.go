@@ -0,0 +1,105 @@
+package main
+
+import (
+	"log"
+	"math/rand"
+	"os"
+	"strconv"
+	"time"
+)
+
+// MakerTakerDelayMinSecondsEnv/MakerTakerDelayMaxSecondsEnv 覆盖 ProcessMarket
+// 里账户2挂出做市单和账户1吃单之间的等待时间, 未设置时都退回到原来写死
+// 的 6 秒, min==max 时就是固定延迟, min<max 时每次从区间里随机取一个值,
+// 避免固定节奏被针对性地观察出来。
+const (
+	MakerTakerDelayMinSecondsEnv = "MAKER_TAKER_DELAY_MIN_SECONDS"
+	MakerTakerDelayMaxSecondsEnv = "MAKER_TAKER_DELAY_MAX_SECONDS"
+)
+
+const defaultMakerTakerDelay = 6 * time.Second
+
+func envSeconds(name string, def time.Duration) time.Duration {
+	raw := os.Getenv(name)
+	if raw == "" {
+		return def
+	}
+	seconds, err := strconv.ParseFloat(raw, 64)
+	if err != nil || seconds < 0 {
+		log.Printf("invalid %s=%q, falling back to %s: %v", name, raw, def, err)
+		return def
+	}
+	return time.Duration(seconds * float64(time.Second))
+}
+
+// MakerTakerDelay 返回这一轮账户2/账户1之间应该等待的时长, 在配置的
+// [min, max] 区间内随机取值; min>max 时按 min 处理。
+func MakerTakerDelay() time.Duration {
+	min := envSeconds(MakerTakerDelayMinSecondsEnv, defaultMakerTakerDelay)
+	max := envSeconds(MakerTakerDelayMaxSecondsEnv, defaultMakerTakerDelay)
+	if max <= min {
+		return min
+	}
+	return min + time.Duration(rand.Int63n(int64(max-min)))
+}
+
+// MakerRestingConfirmEnv 打开后, ProcessMarket 在等待延迟之外还会通过
+// open-orders 接口确认账户2的做市单确实已经挂到盘口上再让账户1吃单,
+// 而不是只靠固定/随机延迟去猜。未设置时保持原来的行为 (只等延迟)。
+const MakerRestingConfirmEnv = "MAKER_TAKER_CONFIRM_RESTING"
+
+// MakerRestingConfirmTimeout 是等待做市单出现在 open orders 里的最长时间,
+// 超时后照常继续吃单, 不无限期卡住主循环。
+const MakerRestingConfirmTimeout = 10 * time.Second
+
+// MakerRestingPollInterval 是轮询 open orders 确认挂单是否出现的间隔。
+const MakerRestingPollInterval = 500 * time.Millisecond
+
+func makerRestingConfirmEnabled() bool {
+	switch os.Getenv(MakerRestingConfirmEnv) {
+	case "1", "true", "on":
+		return true
+	default:
+		return false
+	}
+}
+
+// WaitForOrderResting 轮询 auth 对应账户的 open orders, 直到 orderID 出现
+// 或者超过 timeout, 返回值表示是否在超时前确认到挂单已经在盘口上。
+func WaitForOrderResting(auth Authenticator, orderID string, timeout time.Duration) bool {
+	deadline := time.Now().Add(timeout)
+	for {
+		orders, err := GetOpenOrders(auth)
+		if err != nil {
+			log.Printf("wait for order resting: get open orders failed: %v", err)
+		} else {
+			for _, o := range orders {
+				if o.OrderID == orderID {
+					return true
+				}
+			}
+		}
+		if time.Now().After(deadline) {
+			return false
+		}
+		time.Sleep(MakerRestingPollInterval)
+	}
+}
+
+// WaitBeforeCrossing 是 ProcessMarket 在账户2挂单和账户1吃单之间调用的
+// 等待逻辑: 先按 MakerTakerDelay 等一段可配置/可随机的时间; 如果打开了
+// MakerRestingConfirmEnv 并且拿到了做市单的 order id, 额外用 open orders
+// 接口确认这笔单真的挂上了盘口, 确认不到也不阻塞太久, 超时后照常继续。
+func WaitBeforeCrossing(auth Authenticator, makerOrderID string) {
+	delay := MakerTakerDelay()
+	log.Printf("Waiting %s before crossing...", delay.Round(time.Millisecond))
+	time.Sleep(delay)
+
+	if !makerRestingConfirmEnabled() || makerOrderID == "" {
+		return
+	}
+
+	if !WaitForOrderResting(auth, makerOrderID, MakerRestingConfirmTimeout) {
+		log.Printf("maker order %s not confirmed resting within %s, crossing anyway", makerOrderID, MakerRestingConfirmTimeout)
+	}
+}
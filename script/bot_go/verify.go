@@ -0,0 +1,116 @@
+package main
+
+import (
+	"database/sql"
+	"log"
+	"sync"
+	"time"
+)
+
+// OrderVerifyDelay 是下单后等待多久再去核对订单是否真的在盘口挂着 (或者
+// 已经成交), 太快检查会因为交易所处理延迟而产生大量假阳性。
+const OrderVerifyDelay = 15 * time.Second
+
+// OrderVerifyInterval 是 RunOrderVerificationLoop 两次巡检之间的间隔, 跟
+// StaleOrderJanitor 一样独立于主策略循环运行。
+const OrderVerifyInterval = 30 * time.Second
+
+// verifiedOrderMu/verifiedOrderIDs 记录已经核对过的订单 id, 避免同一笔单
+// 每次巡检都重复核对/重复告警。
+var (
+	verifiedOrderMu  sync.Mutex
+	verifiedOrderIDs = make(map[string]bool)
+)
+
+func markOrderVerified(orderID string) {
+	verifiedOrderMu.Lock()
+	defer verifiedOrderMu.Unlock()
+	verifiedOrderIDs[orderID] = true
+}
+
+func isOrderVerified(orderID string) bool {
+	verifiedOrderMu.Lock()
+	defer verifiedOrderMu.Unlock()
+	return verifiedOrderIDs[orderID]
+}
+
+// forgetVerifiedOrder 在订单被 orderManager 遗忘 (成交/撤销/核对完成) 后
+// 一并清理, 避免 verifiedOrderIDs 无限增长。
+func forgetVerifiedOrder(orderID string) {
+	verifiedOrderMu.Lock()
+	defer verifiedOrderMu.Unlock()
+	delete(verifiedOrderIDs, orderID)
+}
+
+// verifyTrackedOrder 核对一笔下单超过 OrderVerifyDelay 的挂单当前状态:
+// 还在 openByID 里就当作正常挂着; 不在 openByID 里但 trades 表有记录就当作
+// 已成交; 两边都没有就是"交易所应答成功、但订单实际上不存在"的异常,
+// 只打日志告警, 不自动撤单/重下, 交给人或者上层策略决定怎么处理。
+func verifyTrackedOrder(db *sql.DB, tracked ManagedOrder, openByID map[string]OpenOrder) {
+	defer markOrderVerified(tracked.OrderID)
+
+	if _, stillOpen := openByID[tracked.OrderID]; stillOpen {
+		return
+	}
+
+	filled, err := orderHasTrade(db, tracked.OrderID)
+	if err != nil {
+		log.Printf("order verify: check trades for %s failed: %v", tracked.OrderID, err)
+		return
+	}
+	if filled {
+		return
+	}
+
+	log.Printf("⚠️ order verify: order %s (account=%s event=%d market=%d token=%s) was accepted but is neither open nor filled %s after placement",
+		tracked.OrderID, tracked.AccountKey, tracked.EventID, tracked.MarketID, tracked.TokenID, time.Since(tracked.PlacedAt).Round(time.Second))
+}
+
+// sweepOrderVerification 核对 sessions 里每个账户、下单时间超过
+// OrderVerifyDelay 且还没核对过的挂单。单个账户拉取 open orders 失败只记录
+// 日志, 不影响其他账户的核对。
+func sweepOrderVerification(db *sql.DB, sessions []*Session) {
+	now := time.Now()
+
+	for _, session := range sessions {
+		openOrders, err := GetOpenOrders(session.Authenticator())
+		if err != nil {
+			log.Printf("order verify: get open orders for %s failed: %v", session.AccountKey, err)
+			continue
+		}
+		openByID := make(map[string]OpenOrder, len(openOrders))
+		for _, o := range openOrders {
+			openByID[o.OrderID] = o
+		}
+
+		for _, tracked := range orderManager.Snapshot() {
+			if tracked.AccountKey != session.AccountKey {
+				continue
+			}
+			if isOrderVerified(tracked.OrderID) {
+				continue
+			}
+			if now.Sub(tracked.PlacedAt) < OrderVerifyDelay {
+				continue
+			}
+			verifyTrackedOrder(db, tracked, openByID)
+		}
+	}
+}
+
+// RunOrderVerificationLoop 每 OrderVerifyInterval 巡检一次 sessions 的挂单,
+// 确认 PlaceOrder 拿到的 order id 在 OrderVerifyDelay 之后确实在盘口挂着或
+// 者已经成交, 直到 stop 被关闭。
+func RunOrderVerificationLoop(db *sql.DB, sessions []*Session, stop <-chan struct{}) {
+	ticker := time.NewTicker(OrderVerifyInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			sweepOrderVerification(db, sessions)
+		}
+	}
+}
@@ -0,0 +1,134 @@
+package main
+
+import (
+	"log"
+	"math"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// DailyVolumeTargetUSDCEnv 配置没有按市场单独设置时使用的全局每日名义
+// 成交量目标 (USDC)。VolumeTargetMultiplier 按 makerRewards 里过去 24
+// 小时累计的做市量估算离目标还差多少, 用来伸缩下一轮的挂单金额/是否
+// 跳过这一轮, 而不是死守 OrderUSDC 每 IntervalMinutes 分钟一次的固定节奏。
+// 未设置时换算出跟原来固定节奏等价的目标, 保证没配置时行为不变。
+const DailyVolumeTargetUSDCEnv = "BOT_DAILY_VOLUME_TARGET_USDC"
+
+// MinVolumeTargetMultiplier/MaxVolumeTargetMultiplier 限制单轮挂单金额的
+// 伸缩范围, 避免某个窗口做市量刚好是 0 或者刚好远超目标时把这一轮的挂单
+// 金额放大/缩小到不合理的程度。
+const (
+	MinVolumeTargetMultiplier = 0.25
+	MaxVolumeTargetMultiplier = 4.0
+)
+
+// SkipCycleVolumeTargetRatio 是 target/realized 低于这个值 (也就是过去
+// 24 小时的做市量已经远超目标) 时, 干脆跳过这个市场这一轮的挂单, 而不是
+// 继续挂一个金额已经缩到 MinVolumeTargetMultiplier 的单。单靠缩小挂单
+// 金额没法降低报价频率, 跳过整轮才对应请求里"调整周期频率"的那一半。
+const SkipCycleVolumeTargetRatio = 0.15
+
+var (
+	marketDailyVolumeTargetsMu sync.RWMutex
+	marketDailyVolumeTargets   = make(map[string]float64)
+)
+
+// SetMarketDailyVolumeTargets 整体替换按市场配置的每日成交量目标, 供
+// ReloadDynamicConfig/admin API 热更新使用, 跟 topicQuoteProfiles 一样。
+func SetMarketDailyVolumeTargets(targets map[string]float64) {
+	marketDailyVolumeTargetsMu.Lock()
+	defer marketDailyVolumeTargetsMu.Unlock()
+	marketDailyVolumeTargets = targets
+}
+
+// MarketDailyVolumeTargetsSnapshot 返回当前按市场配置的每日成交量目标的
+// 拷贝, 供审计日志/admin API 查看用。
+func MarketDailyVolumeTargetsSnapshot() map[string]float64 {
+	marketDailyVolumeTargetsMu.RLock()
+	defer marketDailyVolumeTargetsMu.RUnlock()
+	out := make(map[string]float64, len(marketDailyVolumeTargets))
+	for k, v := range marketDailyVolumeTargets {
+		out[k] = v
+	}
+	return out
+}
+
+// defaultDailyVolumeTargetUSDC 是没有按市场单独配置时使用的全局目标,
+// 优先读 DailyVolumeTargetUSDCEnv, 未设置或解析失败时换算出跟原来固定
+// 节奏 (每 IntervalMinutes 分钟一轮、每轮 OrderUSDC) 等价的值。
+func defaultDailyVolumeTargetUSDC() float64 {
+	if raw := os.Getenv(DailyVolumeTargetUSDCEnv); raw != "" {
+		if v, err := strconv.ParseFloat(raw, 64); err == nil && v > 0 {
+			return v
+		}
+		log.Printf("invalid %s=%q, falling back to the legacy fixed-cadence equivalent", DailyVolumeTargetUSDCEnv, raw)
+	}
+	cyclesPerDay := (24 * time.Hour) / (IntervalMinutes * time.Minute)
+	return OrderUSDC * float64(cyclesPerDay)
+}
+
+// dailyVolumeTargetForMarket 返回 mktKey 的每日成交量目标, 未按市场配置时
+// 回退到 defaultDailyVolumeTargetUSDC。
+func dailyVolumeTargetForMarket(mktKey string) float64 {
+	marketDailyVolumeTargetsMu.RLock()
+	target, ok := marketDailyVolumeTargets[mktKey]
+	marketDailyVolumeTargetsMu.RUnlock()
+	if ok && target > 0 {
+		return target
+	}
+	return defaultDailyVolumeTargetUSDC()
+}
+
+// realizedVolumeLast24h 把 makerRewards 里 mktKey 过去 24 小时各个统计窗口
+// 的做市量加总, 作为"已实现自成交量"的估计: 两个账户互相挂单吃单基本都是
+// 即时成交, Account2 挂单成功的名义金额已经是这份 24 小时滚动成交量足够
+// 好的代理, 不需要为了这个再单独查一遍 trades 表。
+func realizedVolumeLast24h(mktKey string) decimal.Decimal {
+	cutoff := time.Now().Add(-24 * time.Hour)
+	total := decimal.Zero
+	for _, e := range makerRewards.RewardSnapshot() {
+		if e.MarketKey != mktKey || e.EpochStart.Before(cutoff) {
+			continue
+		}
+		total = total.Add(e.MakerVolumeUSDC)
+	}
+	return total
+}
+
+// volumeTargetRatio 是目标除以过去 24 小时已实现成交量, 没有 clamp 过的
+// 原始值: 大于 1 说明落后于目标, 小于 1 说明领先。realized 恰好是 0 时用
+// 目标的 1% 当分母, 避免除零把比值顶到无穷大。
+func volumeTargetRatio(mktKey string) float64 {
+	target := dailyVolumeTargetForMarket(mktKey)
+	if target <= 0 {
+		return 1
+	}
+	realizedF, _ := realizedVolumeLast24h(mktKey).Float64()
+	return target / math.Max(realizedF, target*0.01)
+}
+
+// ShouldSkipCycleForVolumeTarget 报告 mktKey 过去 24 小时的做市量是否已经
+// 远超目标 (ratio 低于 SkipCycleVolumeTargetRatio), 这种情况下这一轮直接
+// 跳过挂单, 而不是继续挂一个金额已经缩到 MinVolumeTargetMultiplier 的单。
+func ShouldSkipCycleForVolumeTarget(mktKey string) bool {
+	return volumeTargetRatio(mktKey) < SkipCycleVolumeTargetRatio
+}
+
+// VolumeTargetMultiplier 返回这一轮应该按目标伸缩 budgetUSDC 的倍数: 过去
+// 24 小时做市量落后于目标就放大挂单金额追赶, 领先就缩小, clamp 到
+// [MinVolumeTargetMultiplier, MaxVolumeTargetMultiplier] 避免单轮伸缩
+// 过猛。调用前应该先用 ShouldSkipCycleForVolumeTarget 判断要不要整轮跳过。
+func VolumeTargetMultiplier(mktKey string) float64 {
+	ratio := volumeTargetRatio(mktKey)
+	if ratio < MinVolumeTargetMultiplier {
+		return MinVolumeTargetMultiplier
+	}
+	if ratio > MaxVolumeTargetMultiplier {
+		return MaxVolumeTargetMultiplier
+	}
+	return ratio
+}
@@ -0,0 +1,335 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"html"
+	"log"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/shopspring/decimal"
+
+	"strategy"
+)
+
+// WebDashboardAddrEnv overrides where `bot_go web-dashboard` listens.
+// Unlike the admin API this server is read-only and meant to be shared with
+// non-terminal users on the team, so it isn't forced to loopback - it's
+// still loopback by default until an operator opts into wider exposure.
+const WebDashboardAddrEnv = "BOT_WEB_DASHBOARD_ADDR"
+
+func webDashboardAddr() string {
+	if addr := os.Getenv(WebDashboardAddrEnv); addr != "" {
+		return addr
+	}
+	return "127.0.0.1:9192"
+}
+
+// webSampleInterval is how often the web dashboard samples volume/PnL
+// history for its charts - coarser than dashboardRefreshInterval since the
+// charts are meant to show trend over the run, not a live tick.
+const webSampleInterval = 30 * time.Second
+
+// webHistoryLimit bounds how many samples webHistory keeps, so a
+// long-running dashboard process doesn't grow its chart history forever.
+// At webSampleInterval that's an hour of history.
+const webHistoryLimit = 120
+
+// webSample is one point in the volume/PnL history charted on the web
+// dashboard. PositionValue is a mark-to-market proxy (net position * mid
+// price, summed across both accounts and every token in the market) rather
+// than true realized PnL - nothing in this repo tracks cost basis yet, so
+// this is the closest honest approximation available from existing data.
+type webSample struct {
+	Timestamp     time.Time
+	VolumeUSDC    float64
+	PositionValue float64
+}
+
+// webHistory accumulates webSamples for the charts, bounded to
+// webHistoryLimit.
+type webHistory struct {
+	mu      sync.Mutex
+	samples []webSample
+}
+
+func (h *webHistory) add(s webSample) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.samples = append(h.samples, s)
+	if len(h.samples) > webHistoryLimit {
+		h.samples = h.samples[len(h.samples)-webHistoryLimit:]
+	}
+}
+
+func (h *webHistory) snapshot() []webSample {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	out := make([]webSample, len(h.samples))
+	copy(out, h.samples)
+	return out
+}
+
+// midPrice returns book's mid price and whether one could be computed -
+// both sides need at least one level.
+func midPrice(book dashboardTokenBook) (decimal.Decimal, bool) {
+	if len(book.Bids) == 0 || len(book.Asks) == 0 {
+		return decimal.Zero, false
+	}
+	bid, err := decimal.NewFromString(book.Bids[0].Price)
+	if err != nil {
+		return decimal.Zero, false
+	}
+	ask, err := decimal.NewFromString(book.Asks[0].Price)
+	if err != nil {
+		return decimal.Zero, false
+	}
+	return bid.Add(ask).Div(decimal.NewFromInt(2)), true
+}
+
+// sampleWebHistory takes one volume/position-value reading for market and
+// appends it to history. Failures on either half (analytics API down, no
+// depth yet) just leave that half at zero for this sample rather than
+// skipping the whole point - a flat spot in one series shouldn't blank out
+// the other.
+func sampleWebHistory(auth Authenticator, eventID int64, market *Market, tracker *strategy.PositionTracker, books *dashboardBookState, history *webHistory) {
+	var volume float64
+	if analytics, err := GetMarketAnalytics(auth, eventID, market.ID); err != nil {
+		log.Printf("web dashboard: get market analytics failed: %v", err)
+	} else if v, err := strconv.ParseFloat(analytics.VolumeUSDC, 64); err == nil {
+		volume = v
+	}
+
+	positionValue := decimal.Zero
+	snapshot := books.snapshot()
+	for _, tokenID := range market.TokenIDs {
+		book, ok := snapshot[tokenID]
+		if !ok {
+			continue
+		}
+		mid, ok := midPrice(book)
+		if !ok {
+			continue
+		}
+		for _, userID := range []int64{Account1UserID, Account2UserID} {
+			pos, err := tracker.NetPosition(userID, tokenID)
+			if err != nil {
+				log.Printf("web dashboard: net position lookup failed for user=%d token=%s: %v", userID, tokenID, err)
+				continue
+			}
+			positionValue = positionValue.Add(pos.Mul(mid))
+		}
+	}
+
+	positionValueFloat, _ := positionValue.Float64()
+	history.add(webSample{Timestamp: time.Now(), VolumeUSDC: volume, PositionValue: positionValueFloat})
+}
+
+// renderSVGChart renders values as a simple inline line chart, no
+// JavaScript charting library needed - matching the plain-terminal
+// dashboard's choice to not pull in a dependency the offline module cache
+// doesn't have.
+func renderSVGChart(values []float64, width, height int, color string) string {
+	if len(values) == 0 {
+		return fmt.Sprintf(`<svg width="%d" height="%d"></svg>`, width, height)
+	}
+
+	minV, maxV := values[0], values[0]
+	for _, v := range values {
+		if v < minV {
+			minV = v
+		}
+		if v > maxV {
+			maxV = v
+		}
+	}
+	if maxV == minV {
+		maxV = minV + 1
+	}
+
+	var points strings.Builder
+	for i, v := range values {
+		x := 0.0
+		if len(values) > 1 {
+			x = float64(i) / float64(len(values)-1) * float64(width)
+		}
+		y := float64(height) - (v-minV)/(maxV-minV)*float64(height)
+		if i > 0 {
+			points.WriteByte(' ')
+		}
+		fmt.Fprintf(&points, "%.1f,%.1f", x, y)
+	}
+
+	return fmt.Sprintf(`<svg width="%d" height="%d" viewBox="0 0 %d %d"><polyline fill="none" stroke="%s" stroke-width="2" points="%s"/></svg>`,
+		width, height, width, height, color, points.String())
+}
+
+// webDashboardHandler serves the read-only HTML page: the same panels as
+// `bot_go dashboard` (top of book, resting orders, positions, recent fills,
+// error counters) plus volume/PnL-proxy charts built from history.
+func webDashboardHandler(market *Market, eventID int64, marketID int16, auth Authenticator, tracker *strategy.PositionTracker, books *dashboardBookState, history *webHistory) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+
+		samples := history.snapshot()
+		volumeSeries := make([]float64, len(samples))
+		positionSeries := make([]float64, len(samples))
+		for i, s := range samples {
+			volumeSeries[i] = s.VolumeUSDC
+			positionSeries[i] = s.PositionValue
+		}
+
+		fmt.Fprintf(w, `<!DOCTYPE html><html><head><meta http-equiv="refresh" content="%d"><title>bot_go dashboard</title>
+<style>body{font-family:monospace;margin:2em} table{border-collapse:collapse;margin-bottom:1.5em} td,th{border:1px solid #ccc;padding:2px 8px;text-align:left} h2{margin-top:1.5em}</style>
+</head><body>`, int(dashboardRefreshInterval.Seconds()))
+		fmt.Fprintf(w, "<h1>bot_go dashboard - %s</h1>\n", html.EscapeString(time.Now().Format(time.RFC3339)))
+		fmt.Fprintf(w, "<p>Watching: %s (event_id=%d, market_id=%d)</p>\n", html.EscapeString(market.Title), eventID, marketID)
+
+		fmt.Fprintln(w, "<h2>Top of book</h2><table><tr><th>Outcome</th><th>Token</th><th>Bid</th><th>Ask</th><th>Last trade</th></tr>")
+		snapshot := books.snapshot()
+		for i, tokenID := range market.TokenIDs {
+			outcome := tokenID
+			if i < len(market.Outcomes) {
+				outcome = market.Outcomes[i]
+			}
+			book, ok := snapshot[tokenID]
+			bestBid, bestAsk, lastTrade := "-", "-", "-"
+			if ok {
+				if len(book.Bids) > 0 {
+					bestBid = book.Bids[0].Price
+				}
+				if len(book.Asks) > 0 {
+					bestAsk = book.Asks[0].Price
+				}
+				if book.LatestTradePrice != "" {
+					lastTrade = book.LatestTradePrice
+				}
+			}
+			fmt.Fprintf(w, "<tr><td>%s</td><td>%s</td><td>%s</td><td>%s</td><td>%s</td></tr>\n",
+				html.EscapeString(outcome), html.EscapeString(shortTokenID(tokenID)), html.EscapeString(bestBid), html.EscapeString(bestAsk), html.EscapeString(lastTrade))
+		}
+		fmt.Fprintln(w, "</table>")
+
+		var orders []ManagedOrder
+		if err := fetchAdminJSON("/admin/orders", &orders); err != nil {
+			log.Printf("web dashboard: fetch orders failed: %v", err)
+		}
+		fmt.Fprintln(w, "<h2>Resting orders</h2><table><tr><th>Account</th><th>Order ID</th><th>Token</th><th>Price</th><th>Shares</th><th>Placed at</th></tr>")
+		for _, o := range orders {
+			fmt.Fprintf(w, "<tr><td>%s</td><td>%s</td><td>%s</td><td>%s</td><td>%d</td><td>%s</td></tr>\n",
+				html.EscapeString(o.AccountKey), html.EscapeString(o.OrderID), html.EscapeString(shortTokenID(o.TokenID)), html.EscapeString(o.Price), o.Shares, html.EscapeString(o.PlacedAt.Format(time.RFC3339)))
+		}
+		fmt.Fprintln(w, "</table>")
+
+		fmt.Fprintln(w, "<h2>Net positions</h2><table><tr><th>Outcome</th><th>Token</th><th>Account1</th><th>Account2</th></tr>")
+		for i, tokenID := range market.TokenIDs {
+			outcome := tokenID
+			if i < len(market.Outcomes) {
+				outcome = market.Outcomes[i]
+			}
+			fmt.Fprintf(w, "<tr><td>%s</td><td>%s</td><td>%s</td><td>%s</td></tr>\n",
+				html.EscapeString(outcome), html.EscapeString(shortTokenID(tokenID)),
+				html.EscapeString(dashboardPosition(tracker, Account1UserID, tokenID)), html.EscapeString(dashboardPosition(tracker, Account2UserID, tokenID)))
+		}
+		fmt.Fprintln(w, "</table>")
+
+		fills := dashboardFills(auth, eventID, marketID, 10)
+		fmt.Fprintln(w, "<h2>Recent fills</h2><table><tr><th>Trade ID</th><th>Order ID</th><th>Token</th><th>Price</th><th>Shares</th></tr>")
+		for _, f := range fills {
+			fmt.Fprintf(w, "<tr><td>%s</td><td>%s</td><td>%s</td><td>%s</td><td>%s</td></tr>\n",
+				html.EscapeString(f.TradeID), html.EscapeString(f.OrderID), html.EscapeString(shortTokenID(f.TokenID)), html.EscapeString(f.Price), html.EscapeString(f.Shares))
+		}
+		fmt.Fprintln(w, "</table>")
+
+		errorCounts := make(map[string]int)
+		if err := fetchAdminJSON("/admin/errors", &errorCounts); err != nil {
+			log.Printf("web dashboard: fetch error counts failed: %v", err)
+		}
+		fmt.Fprintln(w, "<h2>Error counters</h2><table><tr><th>Category</th><th>Count</th></tr>")
+		for category, count := range errorCounts {
+			fmt.Fprintf(w, "<tr><td>%s</td><td>%d</td></tr>\n", html.EscapeString(category), count)
+		}
+		fmt.Fprintln(w, "</table>")
+
+		fmt.Fprintln(w, "<h2>Volume (USDC)</h2>")
+		fmt.Fprintln(w, renderSVGChart(volumeSeries, 600, 120, "#2a6"))
+		fmt.Fprintln(w, "<h2>Position value proxy (net position &times; mid price, not realized PnL)</h2>")
+		fmt.Fprintln(w, renderSVGChart(positionSeries, 600, 120, "#26a"))
+
+		fmt.Fprintln(w, "</body></html>")
+	}
+}
+
+// runWebDashboardCLI implements `bot_go web-dashboard`, usage:
+//
+//	bot_go web-dashboard <event_id> <market_id>
+//
+// Serves the same panels as `bot_go dashboard` as a read-only web page,
+// plus volume and position-value-proxy charts sampled over the run, so
+// non-terminal teammates can watch a market without SSHing in.
+func runWebDashboardCLI(args []string) {
+	if len(args) < 2 {
+		fmt.Println("Usage: bot_go web-dashboard <event_id> <market_id>")
+		os.Exit(1)
+	}
+
+	eventID, err := strconv.ParseInt(args[0], 10, 64)
+	if err != nil {
+		fmt.Printf("invalid event_id: %v\n", err)
+		os.Exit(1)
+	}
+	marketIDInt, err := strconv.ParseInt(args[1], 10, 16)
+	if err != nil {
+		fmt.Printf("invalid market_id: %v\n", err)
+		os.Exit(1)
+	}
+	marketID := int16(marketIDInt)
+
+	ctx := context.Background()
+	creds, err := loadCredentials(ctx)
+	if err != nil {
+		log.Fatalf("load credentials: %v", err)
+	}
+	auth := APIKeyAuth{APIKey: creds.Account1ApiKey}
+
+	connStr := fmt.Sprintf("host=%s port=%d user=%s password=%s dbname=%s sslmode=require",
+		DBHost, DBPort, DBUser, creds.DBPassword, DBName)
+	db, err := sql.Open("postgres", connStr)
+	if err != nil {
+		log.Fatalf("connect to database: %v", err)
+	}
+	defer db.Close()
+	tracker := strategy.NewPositionTracker(db)
+
+	market, err := getMarket(db, eventID, marketID)
+	if err != nil {
+		log.Fatalf("look up market: %v", err)
+	}
+
+	books := &dashboardBookState{}
+	go dialDashboardDepthWS(eventID, marketID, books)
+
+	history := &webHistory{}
+	go func() {
+		ticker := time.NewTicker(webSampleInterval)
+		defer ticker.Stop()
+		for range ticker.C {
+			sampleWebHistory(auth, eventID, market, tracker, books, history)
+		}
+	}()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", webDashboardHandler(market, eventID, marketID, auth, tracker, books, history))
+
+	addr := webDashboardAddr()
+	log.Printf("web dashboard listening on http://%s", addr)
+	if err := http.ListenAndServe(addr, mux); err != nil {
+		log.Fatalf("web dashboard server failed: %v", err)
+	}
+}
@@ -0,0 +1,205 @@
+package main
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"tracing"
+)
+
+// ErrOrderChannelUnavailable is returned by PlaceOrderWS when no order
+// entry websocket is available, so callers fall back to the REST
+// PlaceOrder instead of failing the order outright.
+var ErrOrderChannelUnavailable = errors.New("order entry websocket unavailable")
+
+// OrderEntryRequest is one order submitted over the order entry websocket
+// channel, tagged with a CorrelationID so its response can be matched back
+// to the caller out of order.
+type OrderEntryRequest struct {
+	CorrelationID string             `json:"correlation_id"`
+	Order         *PlaceOrderRequest `json:"order"`
+	// TraceParent carries the same W3C traceparent value PlaceOrder sets
+	// as an HTTP header, so an order's whole lifecycle traces the same way
+	// whether it went over REST or this channel.
+	TraceParent string `json:"traceparent,omitempty"`
+}
+
+// OrderEntryResponse is the server's reply to an OrderEntryRequest,
+// matched back to the caller by CorrelationID. Its Code/Msg/Data mirror
+// PlaceOrderResponse so callers can treat WS and REST results the same way.
+type OrderEntryResponse struct {
+	CorrelationID string `json:"correlation_id"`
+	Code          int    `json:"code"`
+	Msg           string `json:"msg"`
+	Data          string `json:"data"`
+}
+
+// orderChannelResponseTimeout bounds how long PlaceOrderWS waits for a
+// matching response before giving up and letting the caller fall back to
+// REST, so one wedged correlation ID can't block a strategy indefinitely.
+const orderChannelResponseTimeout = 5 * time.Second
+
+// OrderChannel is a persistent, authenticated websocket connection for
+// submitting orders with request/response correlation, so the taker
+// strategy can avoid a fresh HTTP handshake and TLS setup per order. The
+// exchange does not expose an order entry websocket as of this writing;
+// OrderChannel exists so PlaceOrderWS has somewhere to plug in once it
+// does. Every caller must be prepared to fall back to the REST PlaceOrder
+// when DialOrderChannel or PlaceOrderWS returns ErrOrderChannelUnavailable.
+type OrderChannel struct {
+	conn *websocket.Conn
+
+	mu      sync.Mutex
+	pending map[string]chan OrderEntryResponse
+	closed  bool
+}
+
+// DialOrderChannel opens an order entry websocket at wsURL, authenticated
+// the same way an HTTP request would be, and starts reading responses in
+// the background. Callers should Close the returned channel when done.
+func DialOrderChannel(wsURL string, auth Authenticator) (*OrderChannel, error) {
+	req, err := http.NewRequest("GET", wsURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("build order channel handshake request: %w", err)
+	}
+	auth.Authenticate(req)
+
+	dialer, err := marketAPIHTTPConfig.WebsocketDialer()
+	if err != nil {
+		return nil, fmt.Errorf("build order channel dialer: %w", err)
+	}
+
+	conn, _, err := dialer.Dial(wsURL, req.Header)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrOrderChannelUnavailable, err)
+	}
+
+	oc := &OrderChannel{
+		conn:    conn,
+		pending: make(map[string]chan OrderEntryResponse),
+	}
+	go oc.readLoop()
+	return oc, nil
+}
+
+func (oc *OrderChannel) readLoop() {
+	for {
+		_, data, err := oc.conn.ReadMessage()
+		if err != nil {
+			oc.failAllPending(err)
+			return
+		}
+
+		var resp OrderEntryResponse
+		if err := json.Unmarshal(data, &resp); err != nil {
+			continue
+		}
+
+		oc.mu.Lock()
+		ch, ok := oc.pending[resp.CorrelationID]
+		if ok {
+			delete(oc.pending, resp.CorrelationID)
+		}
+		oc.mu.Unlock()
+
+		if ok {
+			ch <- resp
+		}
+	}
+}
+
+func (oc *OrderChannel) failAllPending(err error) {
+	oc.mu.Lock()
+	defer oc.mu.Unlock()
+	oc.closed = true
+	for id, ch := range oc.pending {
+		close(ch)
+		delete(oc.pending, id)
+	}
+	_ = err
+}
+
+func newCorrelationID() (string, error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("generate correlation id: %w", err)
+	}
+	return hex.EncodeToString(buf), nil
+}
+
+// PlaceOrderWS submits order over the order entry channel and waits for
+// its matching response. Callers should retry via the REST PlaceOrder if
+// this returns an error - the channel may have dropped, or the response
+// may simply not have arrived within orderChannelResponseTimeout.
+func (oc *OrderChannel) PlaceOrderWS(order *PlaceOrderRequest) (*OrderEntryResponse, error) {
+	correlationID, err := newCorrelationID()
+	if err != nil {
+		return nil, err
+	}
+
+	respCh := make(chan OrderEntryResponse, 1)
+	oc.mu.Lock()
+	if oc.closed {
+		oc.mu.Unlock()
+		return nil, ErrOrderChannelUnavailable
+	}
+	oc.pending[correlationID] = respCh
+	oc.mu.Unlock()
+
+	var traceParent string
+	if tc, err := tracing.New(); err != nil {
+		log.Printf("generate trace context failed: %v", err)
+	} else {
+		traceParent = tc.String()
+	}
+
+	jsonData, err := json.Marshal(OrderEntryRequest{CorrelationID: correlationID, Order: order, TraceParent: traceParent})
+	if err != nil {
+		oc.mu.Lock()
+		delete(oc.pending, correlationID)
+		oc.mu.Unlock()
+		return nil, err
+	}
+
+	start := time.Now()
+	if err := oc.conn.WriteMessage(websocket.TextMessage, jsonData); err != nil {
+		oc.mu.Lock()
+		delete(oc.pending, correlationID)
+		oc.mu.Unlock()
+		recordDebugEntry("place_order_ws", jsonData, nil, err, time.Since(start))
+		return nil, err
+	}
+
+	select {
+	case resp, ok := <-respCh:
+		if !ok {
+			return nil, ErrOrderChannelUnavailable
+		}
+		respData, _ := json.Marshal(resp)
+		recordDebugEntry("place_order_ws", jsonData, respData, nil, time.Since(start))
+		if resp.Code != 0 {
+			return &resp, fmt.Errorf("place order over ws failed: %s", resp.Msg)
+		}
+		return &resp, nil
+	case <-time.After(orderChannelResponseTimeout):
+		oc.mu.Lock()
+		delete(oc.pending, correlationID)
+		oc.mu.Unlock()
+		timeoutErr := fmt.Errorf("place order over ws: %w: no response for correlation id %s", ErrOrderChannelUnavailable, correlationID)
+		recordDebugEntry("place_order_ws", jsonData, nil, timeoutErr, time.Since(start))
+		return nil, timeoutErr
+	}
+}
+
+// Close closes the underlying websocket connection.
+func (oc *OrderChannel) Close() error {
+	return oc.conn.Close()
+}
@@ -0,0 +1,77 @@
+// Package ctf derives Gnosis Conditional Tokens Framework (CTF) identifiers
+// (conditionId/collectionId/positionId) offline, using the same
+// abi.encodePacked + keccak256 formulas as ConditionalTokens.sol (the
+// contract Polymarket's exchange is built on). Deriving these client-side
+// lets send_event mint token IDs for synthetic test markets that the
+// contracts will actually recognize once prepareCondition is called
+// on-chain for the same (oracle, questionId, outcomeSlotCount).
+package ctf
+
+import (
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// ConditionID mirrors ConditionalTokens.getConditionId: conditionId =
+// keccak256(oracle ++ questionId ++ outcomeSlotCount), the same value
+// prepareCondition(oracle, questionId, outcomeSlotCount) would register
+// on-chain.
+func ConditionID(oracle common.Address, questionID common.Hash, outcomeSlotCount uint64) common.Hash {
+	packed := make([]byte, 0, common.AddressLength+common.HashLength+32)
+	packed = append(packed, oracle.Bytes()...)
+	packed = append(packed, questionID.Bytes()...)
+	packed = append(packed, common.LeftPadBytes(new(big.Int).SetUint64(outcomeSlotCount).Bytes(), 32)...)
+	return crypto.Keccak256Hash(packed)
+}
+
+// CollectionID mirrors ConditionalTokens.getCollectionId: collectionId =
+// keccak256(parentCollectionId ++ conditionId ++ indexSet). parentCollectionId
+// is the zero hash for a root-level collection (the common case for a
+// market that isn't itself a combination of other conditions).
+func CollectionID(parentCollectionID, conditionID common.Hash, indexSet *big.Int) common.Hash {
+	packed := make([]byte, 0, common.HashLength*2+32)
+	packed = append(packed, parentCollectionID.Bytes()...)
+	packed = append(packed, conditionID.Bytes()...)
+	packed = append(packed, common.LeftPadBytes(indexSet.Bytes(), 32)...)
+	return crypto.Keccak256Hash(packed)
+}
+
+// PositionID mirrors ConditionalTokens.getPositionId: positionId =
+// uint256(keccak256(collateralToken ++ collectionId)). This is the ERC1155
+// token ID the exchange trades.
+func PositionID(collateralToken common.Address, collectionID common.Hash) *big.Int {
+	packed := make([]byte, 0, common.AddressLength+common.HashLength)
+	packed = append(packed, collateralToken.Bytes()...)
+	packed = append(packed, collectionID.Bytes()...)
+	return new(big.Int).SetBytes(crypto.Keccak256(packed))
+}
+
+// IndexSetForOutcome returns the index set (bitmask) for a single outcome in
+// a simple, non-combined partition: outcome i occupies bit i.
+func IndexSetForOutcome(outcomeIndex uint) *big.Int {
+	return new(big.Int).Lsh(big.NewInt(1), outcomeIndex)
+}
+
+// RootParentCollectionID is the parentCollectionId used for a market that
+// isn't nested inside another condition's collection, i.e. every market
+// send_event deals with today.
+var RootParentCollectionID common.Hash
+
+// DerivePositionID is the convenience entry point send_event's generators
+// use: given a condition and an outcome index, it derives the positionId
+// (token ID) for that outcome under the root collection.
+func DerivePositionID(conditionID common.Hash, collateralToken common.Address, outcomeIndex uint) *big.Int {
+	collectionID := CollectionID(RootParentCollectionID, conditionID, IndexSetForOutcome(outcomeIndex))
+	return PositionID(collateralToken, collectionID)
+}
+
+// QuestionIDFromSlug deterministically derives a questionId from a market
+// slug for synthetic test markets that don't have a real oracle-assigned
+// questionId yet: questionId = keccak256(slug). Real markets sourced from a
+// live oracle (e.g. imported from Polymarket) should use the oracle's actual
+// questionId instead, when known.
+func QuestionIDFromSlug(slug string) common.Hash {
+	return crypto.Keccak256Hash([]byte(slug))
+}
@@ -0,0 +1,226 @@
+// Package depth diffs two order book snapshots from the prediction market
+// depth API, so the bot can tell whether it actually needs to requote and
+// monitoring can alert on crossed or locked books without both
+// reimplementing the same price-level comparison.
+package depth
+
+import (
+	"fmt"
+
+	"github.com/shopspring/decimal"
+)
+
+// PriceLevel is one price/quantity pair, matching the depth API's wire
+// format (both are strings so callers can round-trip without float
+// precision loss).
+type PriceLevel struct {
+	Price    string `json:"price"`
+	Quantity string `json:"quantity"`
+}
+
+// Book is one token's order book.
+type Book struct {
+	LatestTradePrice string       `json:"latest_trade_price"`
+	Bids             []PriceLevel `json:"bids"`
+	Asks             []PriceLevel `json:"asks"`
+}
+
+// Snapshot is a full depth response covering every token in a market,
+// keyed by token ID.
+type Snapshot struct {
+	UpdateID  uint64          `json:"update_id"`
+	Timestamp int64           `json:"timestamp"`
+	Depths    map[string]Book `json:"depths"`
+}
+
+// BestBid returns the highest bid, or nil if the book has no bids. Levels
+// are assumed sorted best-first, matching the depth API's response order.
+func (b Book) BestBid() *PriceLevel {
+	if len(b.Bids) == 0 {
+		return nil
+	}
+	return &b.Bids[0]
+}
+
+// BestAsk returns the lowest ask, or nil if the book has no asks.
+func (b Book) BestAsk() *PriceLevel {
+	if len(b.Asks) == 0 {
+		return nil
+	}
+	return &b.Asks[0]
+}
+
+// IsCrossed reports whether the best bid is priced at or above the best
+// ask, which should never happen in a healthy book.
+func (b Book) IsCrossed() (bool, error) {
+	bid, ask := b.BestBid(), b.BestAsk()
+	if bid == nil || ask == nil {
+		return false, nil
+	}
+	bidPrice, askPrice, err := parsePair(bid.Price, ask.Price)
+	if err != nil {
+		return false, err
+	}
+	return bidPrice.GreaterThan(askPrice), nil
+}
+
+// IsLocked reports whether the best bid and best ask sit at the same price.
+func (b Book) IsLocked() (bool, error) {
+	bid, ask := b.BestBid(), b.BestAsk()
+	if bid == nil || ask == nil {
+		return false, nil
+	}
+	bidPrice, askPrice, err := parsePair(bid.Price, ask.Price)
+	if err != nil {
+		return false, err
+	}
+	return bidPrice.Equal(askPrice), nil
+}
+
+// LevelChange is a price level whose quantity moved between two snapshots.
+type LevelChange struct {
+	Price       string
+	OldQuantity string
+	NewQuantity string
+}
+
+// BookDiff is the result of comparing two Book snapshots for one token.
+type BookDiff struct {
+	AddedBids   []PriceLevel
+	RemovedBids []PriceLevel
+	ChangedBids []LevelChange
+
+	AddedAsks   []PriceLevel
+	RemovedAsks []PriceLevel
+	ChangedAsks []LevelChange
+
+	TopOfBookChanged bool
+	PrevBestBid      *PriceLevel
+	PrevBestAsk      *PriceLevel
+	BestBid          *PriceLevel
+	BestAsk          *PriceLevel
+}
+
+// HasChanges reports whether anything at all moved between the two books.
+func (d BookDiff) HasChanges() bool {
+	return len(d.AddedBids) > 0 || len(d.RemovedBids) > 0 || len(d.ChangedBids) > 0 ||
+		len(d.AddedAsks) > 0 || len(d.RemovedAsks) > 0 || len(d.ChangedAsks) > 0
+}
+
+// DiffBooks compares prev and next and reports what changed.
+func DiffBooks(prev, next Book) (BookDiff, error) {
+	bidsAdded, bidsRemoved, bidsChanged, err := diffSide(prev.Bids, next.Bids)
+	if err != nil {
+		return BookDiff{}, fmt.Errorf("diff bids: %w", err)
+	}
+	asksAdded, asksRemoved, asksChanged, err := diffSide(prev.Asks, next.Asks)
+	if err != nil {
+		return BookDiff{}, fmt.Errorf("diff asks: %w", err)
+	}
+
+	diff := BookDiff{
+		AddedBids:   bidsAdded,
+		RemovedBids: bidsRemoved,
+		ChangedBids: bidsChanged,
+		AddedAsks:   asksAdded,
+		RemovedAsks: asksRemoved,
+		ChangedAsks: asksChanged,
+		PrevBestBid: prev.BestBid(),
+		PrevBestAsk: prev.BestAsk(),
+		BestBid:     next.BestBid(),
+		BestAsk:     next.BestAsk(),
+	}
+	diff.TopOfBookChanged = !levelEqual(diff.PrevBestBid, diff.BestBid) || !levelEqual(diff.PrevBestAsk, diff.BestAsk)
+
+	return diff, nil
+}
+
+// DiffSnapshots compares every token present in either snapshot. A token
+// missing from prev is treated as an all-added book; a token missing from
+// next is treated as an all-removed book.
+func DiffSnapshots(prev, next Snapshot) (map[string]BookDiff, error) {
+	diffs := make(map[string]BookDiff)
+
+	tokens := make(map[string]struct{}, len(prev.Depths)+len(next.Depths))
+	for tokenID := range prev.Depths {
+		tokens[tokenID] = struct{}{}
+	}
+	for tokenID := range next.Depths {
+		tokens[tokenID] = struct{}{}
+	}
+
+	for tokenID := range tokens {
+		diff, err := DiffBooks(prev.Depths[tokenID], next.Depths[tokenID])
+		if err != nil {
+			return nil, fmt.Errorf("diff token %s: %w", tokenID, err)
+		}
+		diffs[tokenID] = diff
+	}
+
+	return diffs, nil
+}
+
+func diffSide(prev, next []PriceLevel) (added, removed []PriceLevel, changed []LevelChange, err error) {
+	prevByPrice := make(map[string]string, len(prev))
+	for _, level := range prev {
+		prevByPrice[level.Price] = level.Quantity
+	}
+
+	nextByPrice := make(map[string]string, len(next))
+	for _, level := range next {
+		nextByPrice[level.Price] = level.Quantity
+	}
+
+	for _, level := range next {
+		oldQty, existed := prevByPrice[level.Price]
+		if !existed {
+			added = append(added, level)
+			continue
+		}
+		if oldQty == level.Quantity {
+			continue
+		}
+		same, err := equalDecimal(oldQty, level.Quantity)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		if !same {
+			changed = append(changed, LevelChange{Price: level.Price, OldQuantity: oldQty, NewQuantity: level.Quantity})
+		}
+	}
+
+	for _, level := range prev {
+		if _, stillThere := nextByPrice[level.Price]; !stillThere {
+			removed = append(removed, level)
+		}
+	}
+
+	return added, removed, changed, nil
+}
+
+func levelEqual(a, b *PriceLevel) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Price == b.Price && a.Quantity == b.Quantity
+}
+
+func equalDecimal(a, b string) (bool, error) {
+	da, db, err := parsePair(a, b)
+	if err != nil {
+		return false, err
+	}
+	return da.Equal(db), nil
+}
+
+func parsePair(a, b string) (decimal.Decimal, decimal.Decimal, error) {
+	da, err := decimal.NewFromString(a)
+	if err != nil {
+		return decimal.Decimal{}, decimal.Decimal{}, fmt.Errorf("parse %q: %w", a, err)
+	}
+	db, err := decimal.NewFromString(b)
+	if err != nil {
+		return decimal.Decimal{}, decimal.Decimal{}, fmt.Errorf("parse %q: %w", b, err)
+	}
+	return da, db, nil
+}
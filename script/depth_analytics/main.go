@@ -0,0 +1,452 @@
+// Command depth_analytics records periodic order book snapshots into the
+// depth_samples table (see script/migrations for the schema) and reports
+// time-weighted spread, depth-at-1%/5%-from-mid, and quote presence over a
+// period from what's been recorded - used to evaluate whether our quoting
+// improves market quality.
+//
+// Usage:
+//
+//	go run . record <config.yaml>
+//	go run . report <event_id> <market_id> <start_rfc3339> <end_rfc3339>
+package main
+
+import (
+	"database/sql"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"sort"
+	"time"
+
+	_ "github.com/lib/pq"
+	"github.com/shopspring/decimal"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	DBHost = "34.146.110.159"
+	DBPort = 5432
+	DBUser = "postgres"
+	DBName = "deepsense"
+)
+
+// APIBaseURL is the REST API this recorder polls, matching bot_go's own.
+const APIBaseURL = "https://predictionmarket-api-290128242879.asia-northeast1.run.app/api"
+
+// RecorderConfig describes the markets to sample and how often.
+type RecorderConfig struct {
+	Markets        []MarketConfig `yaml:"markets"`
+	PollIntervalMs int            `yaml:"poll_interval_ms"`
+}
+
+// MarketConfig identifies one market to sample.
+type MarketConfig struct {
+	EventID  int64 `yaml:"event_id"`
+	MarketID int16 `yaml:"market_id"`
+}
+
+// loadRecorderConfig reads and validates the recorder's YAML config file.
+func loadRecorderConfig(path string) (*RecorderConfig, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read recorder config: %w", err)
+	}
+
+	var cfg RecorderConfig
+	if err := yaml.Unmarshal(data, &cfg); err != nil {
+		return nil, fmt.Errorf("parse recorder config: %w", err)
+	}
+	if len(cfg.Markets) == 0 {
+		return nil, fmt.Errorf("recorder config has no markets")
+	}
+	if cfg.PollIntervalMs <= 0 {
+		cfg.PollIntervalMs = 5000
+	}
+	return &cfg, nil
+}
+
+// priceLevel is one price/quantity rung of a book, matching the REST API's
+// field names directly so it can be decoded without a conversion step.
+type priceLevel struct {
+	Price    string `json:"price"`
+	Quantity string `json:"quantity"`
+}
+
+// restDepthResponse mirrors bot_go's DepthResponse - this recorder keeps its
+// own copy rather than importing bot_go, the same way depth_checker keeps
+// its own copy instead of importing bot_go.
+type restDepthResponse struct {
+	Code int    `json:"code"`
+	Msg  string `json:"msg"`
+	Data struct {
+		Timestamp int64                    `json:"timestamp"`
+		Depths    map[string]restDepthBook `json:"depths"`
+	} `json:"data"`
+}
+
+// restDepthBook is one token's book as the REST API encodes it.
+type restDepthBook struct {
+	Bids []priceLevel `json:"bids"`
+	Asks []priceLevel `json:"asks"`
+}
+
+// fetchRESTDepth polls the REST /depth endpoint for one market.
+func fetchRESTDepth(eventID int64, marketID int16) (map[string]restDepthBook, error) {
+	url := fmt.Sprintf("%s/depth?event_id=%d&market_id=%d", APIBaseURL, eventID, marketID)
+
+	resp, err := http.Get(url)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != 200 {
+		return nil, fmt.Errorf("HTTP %d: %s", resp.StatusCode, string(body))
+	}
+
+	var depthResp restDepthResponse
+	if err := json.Unmarshal(body, &depthResp); err != nil {
+		return nil, err
+	}
+	if depthResp.Code != 0 {
+		return nil, fmt.Errorf("depth API error: %s", depthResp.Msg)
+	}
+	return depthResp.Data.Depths, nil
+}
+
+// insertDepthSample writes one token's book as a depth_samples row.
+func insertDepthSample(db *sql.DB, eventID int64, marketID int16, tokenID string, sampledAt time.Time, book restDepthBook) error {
+	var bestBid, bestAsk *string
+	if len(book.Bids) > 0 {
+		bestBid = &book.Bids[0].Price
+	}
+	if len(book.Asks) > 0 {
+		bestAsk = &book.Asks[0].Price
+	}
+
+	bids, err := json.Marshal(book.Bids)
+	if err != nil {
+		return fmt.Errorf("marshal bids: %w", err)
+	}
+	asks, err := json.Marshal(book.Asks)
+	if err != nil {
+		return fmt.Errorf("marshal asks: %w", err)
+	}
+
+	_, err = db.Exec(`
+		INSERT INTO depth_samples (event_id, market_id, token_id, sampled_at, best_bid, best_ask, bids, asks)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+	`, eventID, marketID, tokenID, sampledAt, bestBid, bestAsk, bids, asks)
+	if err != nil {
+		return fmt.Errorf("insert depth sample event_id=%d market_id=%d token_id=%s: %w", eventID, marketID, tokenID, err)
+	}
+	return nil
+}
+
+// sampleMarket polls one market's book and records one row per token.
+func sampleMarket(db *sql.DB, market MarketConfig) error {
+	depths, err := fetchRESTDepth(market.EventID, market.MarketID)
+	if err != nil {
+		return fmt.Errorf("fetch depth: %w", err)
+	}
+
+	sampledAt := time.Now().UTC()
+	for tokenID, book := range depths {
+		if err := insertDepthSample(db, market.EventID, market.MarketID, tokenID, sampledAt, book); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func connectDB() (*sql.DB, error) {
+	dbPassword := os.Getenv("DEPTH_ANALYTICS_DB_PASSWORD")
+	if dbPassword == "" {
+		return nil, fmt.Errorf("DEPTH_ANALYTICS_DB_PASSWORD environment variable is not set")
+	}
+
+	connStr := fmt.Sprintf("host=%s port=%d user=%s password=%s dbname=%s sslmode=require",
+		DBHost, DBPort, DBUser, dbPassword, DBName)
+	db, err := sql.Open("postgres", connStr)
+	if err != nil {
+		return nil, fmt.Errorf("open database: %w", err)
+	}
+	if err := db.Ping(); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("ping database: %w", err)
+	}
+	return db, nil
+}
+
+func runRecord(configPath string) {
+	cfg, err := loadRecorderConfig(configPath)
+	if err != nil {
+		log.Fatalf("❌ %v", err)
+	}
+
+	db, err := connectDB()
+	if err != nil {
+		log.Fatalf("❌ %v", err)
+	}
+	defer db.Close()
+	log.Println("Connected to database")
+
+	pollInterval := time.Duration(cfg.PollIntervalMs) * time.Millisecond
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+
+	log.Printf("🚀 depth_analytics recorder started, sampling %d market(s) every %s", len(cfg.Markets), pollInterval)
+	for {
+		for _, market := range cfg.Markets {
+			if err := sampleMarket(db, market); err != nil {
+				log.Printf("⚠️  [%d/%d] sample failed: %v", market.EventID, market.MarketID, err)
+				continue
+			}
+		}
+		<-ticker.C
+	}
+}
+
+// sample is one recorded depth_samples row, decoded for report computation.
+type sample struct {
+	TokenID   string
+	SampledAt time.Time
+	BestBid   sql.NullString
+	BestAsk   sql.NullString
+	Bids      []priceLevel
+	Asks      []priceLevel
+}
+
+func fetchSamples(db *sql.DB, eventID int64, marketID int16, start, end time.Time) ([]sample, error) {
+	rows, err := db.Query(`
+		SELECT token_id, sampled_at, best_bid, best_ask, bids, asks
+		FROM depth_samples
+		WHERE event_id = $1 AND market_id = $2 AND sampled_at >= $3 AND sampled_at <= $4
+		ORDER BY token_id, sampled_at
+	`, eventID, marketID, start, end)
+	if err != nil {
+		return nil, fmt.Errorf("query depth samples: %w", err)
+	}
+	defer rows.Close()
+
+	var samples []sample
+	for rows.Next() {
+		var s sample
+		var bidsRaw, asksRaw []byte
+		if err := rows.Scan(&s.TokenID, &s.SampledAt, &s.BestBid, &s.BestAsk, &bidsRaw, &asksRaw); err != nil {
+			return nil, fmt.Errorf("scan depth sample: %w", err)
+		}
+		if err := json.Unmarshal(bidsRaw, &s.Bids); err != nil {
+			return nil, fmt.Errorf("unmarshal bids: %w", err)
+		}
+		if err := json.Unmarshal(asksRaw, &s.Asks); err != nil {
+			return nil, fmt.Errorf("unmarshal asks: %w", err)
+		}
+		samples = append(samples, s)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate depth samples: %w", err)
+	}
+	return samples, nil
+}
+
+// tokenStats is one token's computed metrics over the requested period.
+type tokenStats struct {
+	TokenID            string
+	TimeWeightedSpread decimal.Decimal
+	QuotePresence      decimal.Decimal
+	BidDepth1Pct       decimal.Decimal
+	AskDepth1Pct       decimal.Decimal
+	BidDepth5Pct       decimal.Decimal
+	AskDepth5Pct       decimal.Decimal
+	SampleCount        int
+}
+
+// depthWithinPct sums the notional (price * quantity) of levels within pct
+// of mid, e.g. pct=0.01 for depth at 1% from mid.
+func depthWithinPct(levels []priceLevel, mid decimal.Decimal, pct decimal.Decimal) decimal.Decimal {
+	bound := mid.Mul(pct)
+	total := decimal.Zero
+	for _, lvl := range levels {
+		price, err := decimal.NewFromString(lvl.Price)
+		if err != nil {
+			continue
+		}
+		qty, err := decimal.NewFromString(lvl.Quantity)
+		if err != nil {
+			continue
+		}
+		if price.Sub(mid).Abs().GreaterThan(bound) {
+			continue
+		}
+		total = total.Add(price.Mul(qty))
+	}
+	return total
+}
+
+// computeTokenStats folds one token's ordered samples into its period
+// metrics. Spread is weighted by the duration each sample's book was live
+// (i.e. until the next sample, or until the period end for the last one),
+// so a period with uneven sampling still reflects wall-clock time correctly
+// rather than treating every sample as equally representative.
+func computeTokenStats(tokenID string, samples []sample, periodEnd time.Time) tokenStats {
+	stats := tokenStats{TokenID: tokenID, SampleCount: len(samples)}
+	if len(samples) == 0 {
+		return stats
+	}
+
+	weightedSpread := decimal.Zero
+	totalWeight := decimal.Zero
+	quotedWeight := decimal.Zero
+
+	var bidDepth1, askDepth1, bidDepth5, askDepth5, depthWeight decimal.Decimal
+
+	for i, s := range samples {
+		until := periodEnd
+		if i+1 < len(samples) {
+			until = samples[i+1].SampledAt
+		}
+		weight := decimal.NewFromFloat(until.Sub(s.SampledAt).Seconds())
+		if weight.IsNegative() {
+			weight = decimal.Zero
+		}
+		totalWeight = totalWeight.Add(weight)
+
+		if !s.BestBid.Valid || !s.BestAsk.Valid {
+			continue
+		}
+		bestBid, errB := decimal.NewFromString(s.BestBid.String)
+		bestAsk, errA := decimal.NewFromString(s.BestAsk.String)
+		if errB != nil || errA != nil {
+			continue
+		}
+
+		quotedWeight = quotedWeight.Add(weight)
+		spread := bestAsk.Sub(bestBid)
+		weightedSpread = weightedSpread.Add(spread.Mul(weight))
+
+		mid := bestBid.Add(bestAsk).Div(decimal.NewFromInt(2))
+		bidDepth1 = bidDepth1.Add(depthWithinPct(s.Bids, mid, decimal.NewFromFloat(0.01)).Mul(weight))
+		askDepth1 = askDepth1.Add(depthWithinPct(s.Asks, mid, decimal.NewFromFloat(0.01)).Mul(weight))
+		bidDepth5 = bidDepth5.Add(depthWithinPct(s.Bids, mid, decimal.NewFromFloat(0.05)).Mul(weight))
+		askDepth5 = askDepth5.Add(depthWithinPct(s.Asks, mid, decimal.NewFromFloat(0.05)).Mul(weight))
+		depthWeight = depthWeight.Add(weight)
+	}
+
+	if totalWeight.IsPositive() {
+		stats.QuotePresence = quotedWeight.Div(totalWeight)
+	}
+	if quotedWeight.IsPositive() {
+		stats.TimeWeightedSpread = weightedSpread.Div(quotedWeight)
+	}
+	if depthWeight.IsPositive() {
+		stats.BidDepth1Pct = bidDepth1.Div(depthWeight)
+		stats.AskDepth1Pct = askDepth1.Div(depthWeight)
+		stats.BidDepth5Pct = bidDepth5.Div(depthWeight)
+		stats.AskDepth5Pct = askDepth5.Div(depthWeight)
+	}
+	return stats
+}
+
+func runReport(eventID int64, marketID int16, start, end time.Time) {
+	db, err := connectDB()
+	if err != nil {
+		log.Fatalf("❌ %v", err)
+	}
+	defer db.Close()
+
+	samples, err := fetchSamples(db, eventID, marketID, start, end)
+	if err != nil {
+		log.Fatalf("❌ %v", err)
+	}
+	if len(samples) == 0 {
+		log.Fatalf("❌ no depth samples found for event_id=%d market_id=%d in [%s, %s]", eventID, marketID, start.Format(time.RFC3339), end.Format(time.RFC3339))
+	}
+
+	byToken := make(map[string][]sample)
+	for _, s := range samples {
+		byToken[s.TokenID] = append(byToken[s.TokenID], s)
+	}
+
+	tokenIDs := make([]string, 0, len(byToken))
+	for tokenID := range byToken {
+		tokenIDs = append(tokenIDs, tokenID)
+	}
+	sort.Strings(tokenIDs)
+
+	w := csv.NewWriter(os.Stdout)
+	defer w.Flush()
+
+	w.Write([]string{"token_id", "sample_count", "time_weighted_spread", "quote_presence", "bid_depth_1pct", "ask_depth_1pct", "bid_depth_5pct", "ask_depth_5pct"})
+	for _, tokenID := range tokenIDs {
+		stats := computeTokenStats(tokenID, byToken[tokenID], end)
+		w.Write([]string{
+			stats.TokenID,
+			fmt.Sprintf("%d", stats.SampleCount),
+			stats.TimeWeightedSpread.String(),
+			stats.QuotePresence.String(),
+			stats.BidDepth1Pct.String(),
+			stats.AskDepth1Pct.String(),
+			stats.BidDepth5Pct.String(),
+			stats.AskDepth5Pct.String(),
+		})
+	}
+}
+
+func printUsage() {
+	fmt.Println("Usage:")
+	fmt.Println("  go run . record <config.yaml>                                    - poll depth and record samples")
+	fmt.Println("  go run . report <event_id> <market_id> <start_rfc3339> <end_rfc3339> - print CSV spread/depth/quote-presence report")
+}
+
+func main() {
+	if len(os.Args) < 2 {
+		printUsage()
+		os.Exit(1)
+	}
+
+	switch os.Args[1] {
+	case "record":
+		if len(os.Args) < 3 {
+			printUsage()
+			os.Exit(1)
+		}
+		runRecord(os.Args[2])
+
+	case "report":
+		if len(os.Args) < 6 {
+			printUsage()
+			os.Exit(1)
+		}
+		var eventID int64
+		if _, err := fmt.Sscanf(os.Args[2], "%d", &eventID); err != nil {
+			log.Fatalf("❌ invalid event_id: %v", err)
+		}
+		var marketIDInt int
+		if _, err := fmt.Sscanf(os.Args[3], "%d", &marketIDInt); err != nil {
+			log.Fatalf("❌ invalid market_id: %v", err)
+		}
+		start, err := time.Parse(time.RFC3339, os.Args[4])
+		if err != nil {
+			log.Fatalf("❌ invalid start time: %v", err)
+		}
+		end, err := time.Parse(time.RFC3339, os.Args[5])
+		if err != nil {
+			log.Fatalf("❌ invalid end time: %v", err)
+		}
+		runReport(eventID, int16(marketIDInt), start, end)
+
+	default:
+		fmt.Printf("Error: unknown subcommand %q\n", os.Args[1])
+		printUsage()
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,65 @@
+package eip712
+
+import (
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+)
+
+// domainCacheKey identifies a distinct EIP-712 domain separator. It mirrors
+// exactly the fields Domain.Map() feeds into the EIP712Domain struct hash,
+// so two Domains that would hash identically always share a cache entry.
+type domainCacheKey struct {
+	name              string
+	version           string
+	chainID           string
+	verifyingContract common.Address
+	salt              [32]byte
+}
+
+func domainCacheKeyFor(domain Domain) domainCacheKey {
+	key := domainCacheKey{
+		name:              domain.Name,
+		version:           domain.Version,
+		verifyingContract: domain.VerifyingContract,
+		salt:              domain.Salt,
+	}
+	if domain.ChainID != nil {
+		key.chainID = domain.ChainID.String()
+	}
+	return key
+}
+
+var (
+	domainSeparatorCacheMu sync.RWMutex
+	domainSeparatorCache   = make(map[domainCacheKey]hexutil.Bytes)
+)
+
+// domainSeparatorHash returns the keccak256 struct hash of typedData's
+// EIP712Domain, from domainSeparatorCache when this exact domain has
+// already been hashed once. Signing many orders against the same exchange
+// domain (the common case) then pays the domain-hashing cost once instead
+// of once per order.
+func domainSeparatorHash(typedData apitypes.TypedData, domain Domain) (hexutil.Bytes, error) {
+	key := domainCacheKeyFor(domain)
+
+	domainSeparatorCacheMu.RLock()
+	hash, ok := domainSeparatorCache[key]
+	domainSeparatorCacheMu.RUnlock()
+	if ok {
+		return hash, nil
+	}
+
+	hash, err := typedData.HashStruct("EIP712Domain", typedData.Domain.Map())
+	if err != nil {
+		return nil, err
+	}
+
+	domainSeparatorCacheMu.Lock()
+	domainSeparatorCache[key] = hash
+	domainSeparatorCacheMu.Unlock()
+
+	return hash, nil
+}
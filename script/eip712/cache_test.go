@@ -0,0 +1,11 @@
+package eip712
+
+import "github.com/ethereum/go-ethereum/common/hexutil"
+
+// resetDomainSeparatorCache clears the package-wide domain separator cache
+// so benchmarks can measure a cold cache deliberately.
+func resetDomainSeparatorCache() {
+	domainSeparatorCacheMu.Lock()
+	domainSeparatorCache = make(map[domainCacheKey]hexutil.Bytes)
+	domainSeparatorCacheMu.Unlock()
+}
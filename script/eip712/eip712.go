@@ -16,7 +16,13 @@ import (
 	"github.com/ethereum/go-ethereum/signer/core/apitypes"
 )
 
-// Signer provides a simple interface for EIP-712 signing
+// Signer provides a simple interface for EIP-712 signing. A *Signer is
+// safe for concurrent use: its fields are set once in NewSigner and never
+// mutated afterward, and SignTypedData only reads them, so the same Signer
+// can be shared across goroutines signing many orders in parallel. The
+// domain separator hash it computes along the way is additionally cached
+// package-wide (see domainSeparatorHash), since batch signing thousands of
+// orders against the same domain would otherwise re-hash it every time.
 type Signer struct {
 	privateKey *ecdsa.PrivateKey
 	address    common.Address
@@ -91,7 +97,7 @@ func (s *Signer) SignTypedData(domain Domain, types map[string][]Type, primaryTy
 	typedData := apitypes.TypedData{
 		Types:       make(apitypes.Types),
 		PrimaryType: primaryType,
-		Domain:      s.domainToAPITypes(domain),
+		Domain:      domainToAPITypes(domain),
 		Message:     apitypes.TypedDataMessage(message),
 	}
 
@@ -108,14 +114,21 @@ func (s *Signer) SignTypedData(domain Domain, types map[string][]Type, primaryTy
 
 	// Add EIP712Domain type if not present
 	if _, ok := typedData.Types["EIP712Domain"]; !ok {
-		typedData.Types["EIP712Domain"] = s.buildDomainTypes(domain)
+		typedData.Types["EIP712Domain"] = buildDomainTypes(domain)
 	}
 
-	// Hash the typed data
-	hash, _, err := apitypes.TypedDataAndHash(typedData)
+	// Hash the typed data. This mirrors apitypes.TypedDataAndHash, except
+	// the domain separator half is served from domainSeparatorCache instead
+	// of being recomputed on every call.
+	domainSeparator, err := domainSeparatorHash(typedData, domain)
+	if err != nil {
+		return nil, fmt.Errorf("failed to hash domain: %w", err)
+	}
+	typedDataHash, err := typedData.HashStruct(typedData.PrimaryType, typedData.Message)
 	if err != nil {
 		return nil, fmt.Errorf("failed to hash typed data: %w", err)
 	}
+	hash := crypto.Keccak256([]byte(fmt.Sprintf("\x19\x01%s%s", string(domainSeparator), string(typedDataHash))))
 
 	// Sign the hash
 	signature, err := crypto.Sign(hash, s.privateKey)
@@ -135,7 +148,18 @@ func (s *Signer) SignTypedData(domain Domain, types map[string][]Type, primaryTy
 	}, nil
 }
 
-func (s *Signer) domainToAPITypes(domain Domain) apitypes.TypedDataDomain {
+// SignTypedDataAuto signs message like SignTypedData, but infers the type
+// map (including nested structs and arrays) via InferTypes instead of
+// requiring the caller to hand-write it. Prefer SignTypedData with an
+// explicit type map for anything contract-facing where the exact field
+// order and type names matter, such as Order; use this for one-off or
+// exploratory signing where inference is good enough.
+func (s *Signer) SignTypedDataAuto(domain Domain, rootTypeName string, message Message) (*Signature, error) {
+	types := InferTypes(rootTypeName, message)
+	return s.SignTypedData(domain, types, rootTypeName, message)
+}
+
+func domainToAPITypes(domain Domain) apitypes.TypedDataDomain {
 	d := apitypes.TypedDataDomain{
 		Name:    domain.Name,
 		Version: domain.Version,
@@ -156,7 +180,7 @@ func (s *Signer) domainToAPITypes(domain Domain) apitypes.TypedDataDomain {
 	return d
 }
 
-func (s *Signer) buildDomainTypes(domain Domain) []apitypes.Type {
+func buildDomainTypes(domain Domain) []apitypes.Type {
 	types := []apitypes.Type{
 		{Name: "name", Type: "string"},
 		{Name: "version", Type: "string"},
@@ -177,39 +201,42 @@ func (s *Signer) buildDomainTypes(domain Domain) []apitypes.Type {
 	return types
 }
 
-// inferTypes attempts to infer EIP-712 types from a message
+// inferPrimitiveType infers the EIP-712 type name for a leaf (non-struct,
+// non-array) message value.
+func inferPrimitiveType(value any) string {
+	switch v := value.(type) {
+	case string:
+		if common.IsHexAddress(v) {
+			return "address"
+		} else if _, ok := new(big.Int).SetString(v, 10); ok {
+			return "uint256"
+		}
+		return "string"
+	case *big.Int:
+		return "uint256"
+	case int, int8, int16, int32, int64:
+		return "uint256"
+	case uint, uint8, uint16, uint32, uint64:
+		return "uint256"
+	case bool:
+		return "bool"
+	case []byte:
+		return fmt.Sprintf("bytes%d", len(v))
+	default:
+		return "string"
+	}
+}
+
+// inferTypes infers EIP-712 field types for a flat message, one level deep.
+// Nested struct or array values are the caller's responsibility to type via
+// InferTypes.
 func inferTypes(message map[string]any) []Type {
 	types := make([]Type, 0, len(message))
 
 	for name, value := range message {
-		var fieldType string
-
-		switch v := value.(type) {
-		case string:
-			if common.IsHexAddress(v) {
-				fieldType = "address"
-			} else if _, ok := new(big.Int).SetString(v, 10); ok {
-				fieldType = "uint256"
-			} else {
-				fieldType = "string"
-			}
-		case *big.Int:
-			fieldType = "uint256"
-		case int, int8, int16, int32, int64:
-			fieldType = "uint256"
-		case uint, uint8, uint16, uint32, uint64:
-			fieldType = "uint256"
-		case bool:
-			fieldType = "bool"
-		case []byte:
-			fieldType = fmt.Sprintf("bytes%d", len(v))
-		default:
-			fieldType = "string"
-		}
-
 		types = append(types, Type{
 			Name: name,
-			Type: fieldType,
+			Type: inferPrimitiveType(value),
 		})
 	}
 
@@ -220,6 +247,79 @@ func inferTypes(message map[string]any) []Type {
 	return types
 }
 
+// structTypeName derives the EIP-712 custom type name for a nested struct
+// field from its own field name, e.g. "makerData" -> "MakerData". EIP-712
+// type names are conventionally capitalized, independent of how the field
+// referencing them is cased.
+func structTypeName(fieldName string) string {
+	if fieldName == "" {
+		return fieldName
+	}
+	return strings.ToUpper(fieldName[:1]) + fieldName[1:]
+}
+
+// InferTypes walks message and builds the full EIP-712 type map rooted at
+// rootTypeName, recursively registering a custom type for every nested
+// struct value (map[string]any or Message) and every array of structs, so
+// callers with nested typed data (batch actions, permit-style structs)
+// don't have to hand-write Types for every level. Arrays of primitives are
+// typed as "<elemType>[]"; arrays of structs are typed as "<StructName>[]"
+// and their element type is inferred from the array's first entry, since
+// EIP-712 arrays are homogeneous.
+func InferTypes(rootTypeName string, message map[string]any) map[string][]Type {
+	types := make(map[string][]Type)
+	inferStructTypes(rootTypeName, message, types)
+	return types
+}
+
+func inferStructTypes(typeName string, message map[string]any, types map[string][]Type) {
+	fields := make([]Type, 0, len(message))
+
+	for name, value := range message {
+		fields = append(fields, Type{
+			Name: name,
+			Type: inferFieldType(structTypeName(name), value, types),
+		})
+	}
+
+	sort.Slice(fields, func(i, j int) bool {
+		return fields[i].Name < fields[j].Name
+	})
+	types[typeName] = fields
+}
+
+// inferFieldType returns the EIP-712 type string for value, registering
+// nested struct types into types (keyed by nestedTypeName) as needed.
+func inferFieldType(nestedTypeName string, value any, types map[string][]Type) string {
+	switch v := value.(type) {
+	case map[string]any:
+		inferStructTypes(nestedTypeName, v, types)
+		return nestedTypeName
+	case Message:
+		inferStructTypes(nestedTypeName, v, types)
+		return nestedTypeName
+	case []map[string]any:
+		if len(v) == 0 {
+			return nestedTypeName + "[]"
+		}
+		inferStructTypes(nestedTypeName, v[0], types)
+		return nestedTypeName + "[]"
+	case []Message:
+		if len(v) == 0 {
+			return nestedTypeName + "[]"
+		}
+		inferStructTypes(nestedTypeName, v[0], types)
+		return nestedTypeName + "[]"
+	case []any:
+		if len(v) == 0 {
+			return "string[]"
+		}
+		return inferFieldType(nestedTypeName, v[0], types) + "[]"
+	default:
+		return inferPrimitiveType(value)
+	}
+}
+
 // validateNoCycles checks for cyclic references in type definitions
 func validateNoCycles(types map[string][]Type) error {
 	visited := make(map[string]bool)
@@ -0,0 +1,119 @@
+package eip712
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// Golden order-hashing test vectors.
+//
+// These pin a fixed test private key and fixed order fields to a known
+// struct hash and signature, so a change to CTFExchangeDomain, OrderTypes
+// or the underlying SignTypedData plumbing that silently alters what gets
+// signed shows up here first, instead of as orders the on-chain exchange
+// contract rejects.
+//
+// The expected values below were captured from this package's own output
+// (there is no network access to the Polymarket/CTF reference
+// implementation in this environment to cross-check against); treat them
+// as a change-detector for this codebase, not as independently verified
+// against the reference contract.
+const (
+	goldenPrivateKey = "4c0883a69102937d6231471b5dbb6204fe5129617082792ae468d01a3f362318"
+	goldenChainID    = EVMTestnetChainID
+)
+
+func goldenVerifyingContract(t *testing.T) common.Address {
+	t.Helper()
+	addr, err := GetCTFExchangeAddress(goldenChainID)
+	if err != nil {
+		t.Fatalf("GetCTFExchangeAddress: %v", err)
+	}
+	return addr
+}
+
+func goldenOrder() *Order {
+	return &Order{
+		Salt:          big.NewInt(1),
+		Maker:         common.HexToAddress("0x62924ea9188Ad1228eEa76931B595c781b72b664"),
+		Signer:        common.HexToAddress("0x62924ea9188Ad1228eEa76931B595c781b72b664"),
+		Taker:         common.Address{},
+		TokenId:       big.NewInt(12345),
+		MakerAmount:   big.NewInt(1000000),
+		TakerAmount:   big.NewInt(2000000),
+		Expiration:    big.NewInt(0),
+		Nonce:         big.NewInt(0),
+		FeeRateBps:    big.NewInt(0),
+		Side:          0,
+		SignatureType: 0,
+	}
+}
+
+func TestSignOrder_GoldenVector(t *testing.T) {
+	sig, err := SignOrder(goldenPrivateKey, goldenChainID, goldenVerifyingContract(t), goldenOrder())
+	if err != nil {
+		t.Fatalf("SignOrder: %v", err)
+	}
+
+	const (
+		wantHash = "0x926954cd1ee40929878aefa2b282c2bf7816c94f0eee8b18469ffc5c95527deb"
+		wantR    = "0x1dc04220633e43e62306d07e37a08d0ed8d1eb00cbd2fae8f03b4913342fc25c"
+		wantS    = "0x09083920b98302248d9f2d62e1a18d974d70f189a0047f233c18b3c04d36f448"
+		wantV    = uint8(27)
+	)
+
+	if sig.Hash != wantHash {
+		t.Errorf("struct hash = %s, want %s", sig.Hash, wantHash)
+	}
+	if sig.R != wantR {
+		t.Errorf("R = %s, want %s", sig.R, wantR)
+	}
+	if sig.S != wantS {
+		t.Errorf("S = %s, want %s", sig.S, wantS)
+	}
+	if sig.V != wantV {
+		t.Errorf("V = %d, want %d", sig.V, wantV)
+	}
+}
+
+func TestSignOrder_DomainChangeBreaksHash(t *testing.T) {
+	// A regression test in its own right: changing any domain or type field
+	// must change the struct hash, since that's exactly the class of bug
+	// these golden vectors exist to catch.
+	order := goldenOrder()
+	verifyingContract := goldenVerifyingContract(t)
+
+	base, err := SignOrder(goldenPrivateKey, goldenChainID, verifyingContract, order)
+	if err != nil {
+		t.Fatalf("SignOrder (base): %v", err)
+	}
+
+	mainnet, err := SignOrder(goldenPrivateKey, EVMChainID, verifyingContract, order)
+	if err != nil {
+		t.Fatalf("SignOrder (mainnet chain id): %v", err)
+	}
+
+	if base.Hash == mainnet.Hash {
+		t.Errorf("struct hash unchanged after chain ID change: both %s", base.Hash)
+	}
+}
+
+func TestSignOrder_DeterministicAcrossRuns(t *testing.T) {
+	order := goldenOrder()
+	verifyingContract := goldenVerifyingContract(t)
+
+	first, err := SignOrder(goldenPrivateKey, goldenChainID, verifyingContract, order)
+	if err != nil {
+		t.Fatalf("SignOrder (first): %v", err)
+	}
+	second, err := SignOrder(goldenPrivateKey, goldenChainID, verifyingContract, order)
+	if err != nil {
+		t.Fatalf("SignOrder (second): %v", err)
+	}
+
+	if first.Hash != second.Hash || first.Bytes != second.Bytes {
+		t.Errorf("signing the same order twice produced different output: %+v vs %+v", first, second)
+	}
+}
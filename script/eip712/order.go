@@ -13,6 +13,12 @@ const (
 	EVMTestnetCTFExchangeAddress = "0x65a2085833D2658f2B0ee2216F50A6CD2CE99C93"
 	EVMChainID                   = 56
 	EVMTestnetChainID            = 97
+
+	// NegRiskAdapter 处理同一分类事件下多个互斥结果 (categorical market) 组成
+	// 的一篮子头寸的结算, 跟普通两结果市场用的 CTFExchange 是不同的合约, 签名
+	// 用的 verifyingContract/domain name 也要相应换成这个。
+	EVMNegRiskAdapterAddress        = "0xC5d563A36AE78145C45a50134d48A1215220f80"
+	EVMTestnetNegRiskAdapterAddress = "0xC5d563A36AE78145C45a50134d48A1215220f80"
 )
 
 // Order represents a prediction market order
@@ -180,6 +186,30 @@ func GetCTFExchangeAddress(chainID int) (common.Address, error) {
 	}
 }
 
+// GetNegRiskAdapterAddress returns the NegRiskAdapter contract address for
+// the given chain ID, used to sign/settle basket orders across the mutually
+// exclusive outcome tokens of a categorical event instead of GetCTFExchangeAddress.
+func GetNegRiskAdapterAddress(chainID int) (common.Address, error) {
+	switch chainID {
+	case EVMChainID:
+		return common.HexToAddress(EVMNegRiskAdapterAddress), nil
+	case EVMTestnetChainID:
+		return common.HexToAddress(EVMTestnetNegRiskAdapterAddress), nil
+	default:
+		return common.Address{}, fmt.Errorf("unsupported chain_id: %d", chainID)
+	}
+}
+
+// NegRiskAdapterDomain returns the EIP-712 domain for the NegRiskAdapter.
+func NegRiskAdapterDomain(chainID int64, verifyingContract common.Address) Domain {
+	return Domain{
+		Name:              "Sidekick Predict NegRisk Adapter",
+		Version:           "1",
+		ChainID:           big.NewInt(chainID),
+		VerifyingContract: verifyingContract,
+	}
+}
+
 // SignOrder signs a prediction market order
 func SignOrder(privateKeyHex string, chainID int64, verifyingContract common.Address, order *Order) (*Signature, error) {
 	signer, err := NewSigner(privateKeyHex, chainID)
@@ -194,6 +224,44 @@ func SignOrder(privateKeyHex string, chainID int64, verifyingContract common.Add
 	return signer.SignTypedData(domain, types, "Order", message)
 }
 
+// SignNegRiskOrder signs a basket order against the NegRiskAdapter domain
+// instead of the plain CTF Exchange domain, for orders that settle a set of
+// mutually exclusive outcome tokens across a categorical event.
+func SignNegRiskOrder(privateKeyHex string, chainID int64, verifyingContract common.Address, order *Order) (*Signature, error) {
+	signer, err := NewSigner(privateKeyHex, chainID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create signer: %w", err)
+	}
+
+	domain := NegRiskAdapterDomain(chainID, verifyingContract)
+	types := OrderTypes()
+	message := OrderToMessage(order)
+
+	return signer.SignTypedData(domain, types, "Order", message)
+}
+
+// VerifyOrderSignature recovers the address that produced signatureHex over
+// order under the CTF Exchange domain, the verification counterpart to
+// SignOrder.
+func VerifyOrderSignature(chainID int64, verifyingContract common.Address, order *Order, signatureHex string) (common.Address, error) {
+	domain := CTFExchangeDomain(chainID, verifyingContract)
+	types := OrderTypes()
+	message := OrderToMessage(order)
+
+	return RecoverTypedDataSigner(domain, types, "Order", message, signatureHex)
+}
+
+// VerifyNegRiskOrderSignature is the NegRiskAdapter counterpart of
+// VerifyOrderSignature, for basket orders against a categorical event's
+// mutually exclusive outcome tokens.
+func VerifyNegRiskOrderSignature(chainID int64, verifyingContract common.Address, order *Order, signatureHex string) (common.Address, error) {
+	domain := NegRiskAdapterDomain(chainID, verifyingContract)
+	types := OrderTypes()
+	message := OrderToMessage(order)
+
+	return RecoverTypedDataSigner(domain, types, "Order", message, signatureHex)
+}
+
 // SignOrderInput is a convenience function that takes OrderInput and returns the signature
 func SignOrderInput(privateKeyHex string, chainID int, input *OrderInput) (string, error) {
 	// Get verifying contract address
@@ -216,3 +284,58 @@ func SignOrderInput(privateKeyHex string, chainID int, input *OrderInput) (strin
 
 	return signature.Bytes, nil
 }
+
+// SignNegRiskOrderInput is the NegRiskAdapter counterpart of SignOrderInput,
+// for basket orders against a categorical event's mutually exclusive outcome
+// tokens.
+func SignNegRiskOrderInput(privateKeyHex string, chainID int, input *OrderInput) (string, error) {
+	verifyingContract, err := GetNegRiskAdapterAddress(chainID)
+	if err != nil {
+		return "", err
+	}
+
+	order, err := OrderInputToOrder(input)
+	if err != nil {
+		return "", err
+	}
+
+	signature, err := SignNegRiskOrder(privateKeyHex, int64(chainID), verifyingContract, order)
+	if err != nil {
+		return "", err
+	}
+
+	return signature.Bytes, nil
+}
+
+// VerifyOrderInputSignature is the string-based convenience counterpart of
+// VerifyOrderSignature, mirroring SignOrderInput.
+func VerifyOrderInputSignature(chainID int, input *OrderInput, signatureHex string) (common.Address, error) {
+	verifyingContract, err := GetCTFExchangeAddress(chainID)
+	if err != nil {
+		return common.Address{}, err
+	}
+
+	order, err := OrderInputToOrder(input)
+	if err != nil {
+		return common.Address{}, err
+	}
+
+	return VerifyOrderSignature(int64(chainID), verifyingContract, order, signatureHex)
+}
+
+// VerifyNegRiskOrderInputSignature is the string-based convenience
+// counterpart of VerifyNegRiskOrderSignature, mirroring
+// SignNegRiskOrderInput.
+func VerifyNegRiskOrderInputSignature(chainID int, input *OrderInput, signatureHex string) (common.Address, error) {
+	verifyingContract, err := GetNegRiskAdapterAddress(chainID)
+	if err != nil {
+		return common.Address{}, err
+	}
+
+	order, err := OrderInputToOrder(input)
+	if err != nil {
+		return common.Address{}, err
+	}
+
+	return VerifyNegRiskOrderSignature(int64(chainID), verifyingContract, order, signatureHex)
+}
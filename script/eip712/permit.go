@@ -0,0 +1,135 @@
+package eip712
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// Permit represents an EIP-2612 permit (as used by USDC): a gasless
+// approval that lets spender pull up to value of owner's tokens without a
+// separate on-chain approve transaction.
+type Permit struct {
+	Owner    common.Address
+	Spender  common.Address
+	Value    *big.Int
+	Nonce    *big.Int
+	Deadline *big.Int
+}
+
+// PermitDomain returns the EIP-712 domain for an EIP-2612 permit. name and
+// version must match the token contract's own domain separator exactly
+// (USDC uses name "USD Coin", version "2"), or the signature will be
+// rejected by the token even though it verifies locally.
+func PermitDomain(name, version string, chainID int64, tokenAddress common.Address) Domain {
+	return Domain{
+		Name:              name,
+		Version:           version,
+		ChainID:           big.NewInt(chainID),
+		VerifyingContract: tokenAddress,
+	}
+}
+
+// PermitTypes returns the EIP-712 type definition for EIP-2612 Permit.
+func PermitTypes() map[string][]Type {
+	return map[string][]Type{
+		"Permit": {
+			{Name: "owner", Type: "address"},
+			{Name: "spender", Type: "address"},
+			{Name: "value", Type: "uint256"},
+			{Name: "nonce", Type: "uint256"},
+			{Name: "deadline", Type: "uint256"},
+		},
+	}
+}
+
+func permitToMessage(permit *Permit) Message {
+	return Message{
+		"owner":    permit.Owner.Hex(),
+		"spender":  permit.Spender.Hex(),
+		"value":    permit.Value.String(),
+		"nonce":    permit.Nonce.String(),
+		"deadline": permit.Deadline.String(),
+	}
+}
+
+// SignPermit signs an EIP-2612 permit for the token identified by
+// tokenName/tokenVersion/tokenAddress, granting spender allowance up to
+// permit.Value. tokenName and tokenVersion must be the token contract's
+// own EIP-712 domain values, not an arbitrary label.
+func SignPermit(privateKeyHex string, chainID int64, tokenName, tokenVersion string, tokenAddress common.Address, permit *Permit) (*Signature, error) {
+	signer, err := NewSigner(privateKeyHex, chainID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create signer: %w", err)
+	}
+
+	domain := PermitDomain(tokenName, tokenVersion, chainID, tokenAddress)
+	return signer.SignTypedData(domain, PermitTypes(), "Permit", permitToMessage(permit))
+}
+
+// SignUSDCPermit signs an EIP-2612 permit against USDC's own domain
+// separator (name "USD Coin", version "2"), the common case for granting
+// the exchange or an operator wallet an allowance without a separate
+// approval transaction.
+func SignUSDCPermit(privateKeyHex string, chainID int64, usdcAddress common.Address, permit *Permit) (*Signature, error) {
+	return SignPermit(privateKeyHex, chainID, "USD Coin", "2", usdcAddress, permit)
+}
+
+// DaiPermit represents the DAI-style permit used by DAI and a handful of
+// other older tokens: instead of an exact value, it grants or revokes
+// unlimited allowance (allowed) and uses an incrementing nonce plus an
+// absolute expiry instead of value/deadline.
+type DaiPermit struct {
+	Holder  common.Address
+	Spender common.Address
+	Nonce   *big.Int
+	Expiry  *big.Int
+	Allowed bool
+}
+
+// DaiPermitDomain returns the EIP-712 domain for a DAI-style permit.
+func DaiPermitDomain(name, version string, chainID int64, tokenAddress common.Address) Domain {
+	return Domain{
+		Name:              name,
+		Version:           version,
+		ChainID:           big.NewInt(chainID),
+		VerifyingContract: tokenAddress,
+	}
+}
+
+// DaiPermitTypes returns the EIP-712 type definition for the DAI-style
+// Permit (fields named "holder"/"allowed" rather than "owner"/"value").
+func DaiPermitTypes() map[string][]Type {
+	return map[string][]Type{
+		"Permit": {
+			{Name: "holder", Type: "address"},
+			{Name: "spender", Type: "address"},
+			{Name: "nonce", Type: "uint256"},
+			{Name: "expiry", Type: "uint256"},
+			{Name: "allowed", Type: "bool"},
+		},
+	}
+}
+
+func daiPermitToMessage(permit *DaiPermit) Message {
+	return Message{
+		"holder":  permit.Holder.Hex(),
+		"spender": permit.Spender.Hex(),
+		"nonce":   permit.Nonce.String(),
+		"expiry":  permit.Expiry.String(),
+		"allowed": permit.Allowed,
+	}
+}
+
+// SignDaiPermit signs a DAI-style permit for the token identified by
+// tokenName/tokenVersion/tokenAddress.
+func SignDaiPermit(privateKeyHex string, chainID int64, tokenName, tokenVersion string, tokenAddress common.Address, permit *DaiPermit) (*Signature, error) {
+	signer, err := NewSigner(privateKeyHex, chainID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create signer: %w", err)
+	}
+
+	domain := DaiPermitDomain(tokenName, tokenVersion, chainID, tokenAddress)
+	return signer.SignTypedData(domain, DaiPermitTypes(), "Permit", daiPermitToMessage(permit))
+}
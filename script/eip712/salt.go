@@ -0,0 +1,43 @@
+package eip712
+
+import (
+	"crypto/rand"
+	"fmt"
+	"math/big"
+	"time"
+)
+
+// maxSalt bounds generated salts to fit safely inside a signed 64-bit
+// integer, since callers commonly carry a salt through an int64 API field
+// (e.g. bot_go's PlaceOrderRequest.Salt) alongside the on-chain uint256
+// order.
+var maxSalt = new(big.Int).Lsh(big.NewInt(1), 62)
+
+// GenerateSalt returns a cryptographically random order salt.
+// time.Now().Unix() is not a safe salt source: it only has one-second
+// resolution, so two orders built within the same second (e.g. the
+// Account1 and Account2 legs of the same market) collide and one gets
+// signed with a salt the exchange has already seen.
+func GenerateSalt() (*big.Int, error) {
+	salt, err := rand.Int(rand.Reader, maxSalt)
+	if err != nil {
+		return nil, fmt.Errorf("generate salt: %w", err)
+	}
+	return salt, nil
+}
+
+// DefaultClockSkewMargin is added on top of an order's requested TTL so a
+// short-lived order doesn't expire in transit because this process's clock
+// runs a little ahead of the exchange's.
+const DefaultClockSkewMargin = 30 * time.Second
+
+// ExpirationAt returns the unix timestamp ttl from now plus
+// DefaultClockSkewMargin, as a *big.Int ready to drop into Order.Expiration
+// or OrderInput.Expiration. A ttl <= 0 returns zero, matching this
+// exchange's convention that expiration "0" means the order never expires.
+func ExpirationAt(ttl time.Duration) *big.Int {
+	if ttl <= 0 {
+		return big.NewInt(0)
+	}
+	return big.NewInt(time.Now().Add(ttl).Add(DefaultClockSkewMargin).Unix())
+}
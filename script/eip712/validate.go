@@ -0,0 +1,99 @@
+package eip712
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// SignatureType values the CTF Exchange accepts, mirroring
+// OrderInput.SignatureType/Order.SignatureType.
+const (
+	SignatureTypeEOA            = 0
+	SignatureTypePolyProxy      = 1
+	SignatureTypePolyGnosisSafe = 2
+	maxSignatureType            = SignatureTypePolyGnosisSafe
+)
+
+// ValidateOrderInput checks that input's numeric string fields are valid
+// base-10 non-negative integers, its address fields are checksum-valid
+// addresses, and its side/signatureType fall within the values the CTF
+// Exchange accepts. It does not check the signature itself - pair it with
+// RecoverTypedDataSigner/VerifyOrderInputSignature for that - this only
+// checks the input is well-formed enough to sign or verify in the first
+// place, the same shape check bot_go's order construction and mock_go's
+// order_verifier both need to agree on.
+func ValidateOrderInput(input *OrderInput) error {
+	numericFields := map[string]string{
+		"salt":        input.Salt,
+		"tokenId":     input.TokenId,
+		"makerAmount": input.MakerAmount,
+		"takerAmount": input.TakerAmount,
+		"expiration":  input.Expiration,
+		"nonce":       input.Nonce,
+		"feeRateBps":  input.FeeRateBps,
+	}
+	for name, value := range numericFields {
+		if err := validateNonNegativeInteger(name, value); err != nil {
+			return err
+		}
+	}
+
+	addressFields := map[string]string{
+		"maker":  input.Maker,
+		"signer": input.Signer,
+		"taker":  input.Taker,
+	}
+	for name, value := range addressFields {
+		if err := validateChecksumAddress(name, value); err != nil {
+			return err
+		}
+	}
+
+	if input.Side != 0 && input.Side != 1 {
+		return fmt.Errorf("invalid side: %d (must be 0=buy or 1=sell)", input.Side)
+	}
+
+	if input.SignatureType < SignatureTypeEOA || input.SignatureType > maxSignatureType {
+		return fmt.Errorf("invalid signatureType: %d", input.SignatureType)
+	}
+
+	return nil
+}
+
+func validateNonNegativeInteger(name, value string) error {
+	n, ok := new(big.Int).SetString(value, 10)
+	if !ok {
+		return fmt.Errorf("invalid %s: %q is not a base-10 integer", name, value)
+	}
+	if n.Sign() < 0 {
+		return fmt.Errorf("invalid %s: %q is negative", name, value)
+	}
+	return nil
+}
+
+func validateChecksumAddress(name, value string) error {
+	if !common.IsHexAddress(value) {
+		return fmt.Errorf("invalid %s: %q is not a valid address", name, value)
+	}
+	if common.HexToAddress(value).Hex() != value {
+		return fmt.Errorf("invalid %s: %q is not checksum-encoded (want %s)", name, value, common.HexToAddress(value).Hex())
+	}
+	return nil
+}
+
+// ParseSide maps the wire-level "buy"/"sell" side string (as used in
+// bot_go's PlaceOrderRequest.Side) to the numeric OrderInput.Side/Order.Side
+// convention (0=buy, 1=sell), rejecting anything else instead of silently
+// defaulting to buy.
+func ParseSide(side string) (uint8, error) {
+	switch side {
+	case "buy":
+		return 0, nil
+	case "sell":
+		return 1, nil
+	default:
+		return 0, fmt.Errorf("invalid side: %q (must be \"buy\" or \"sell\")", side)
+	}
+}
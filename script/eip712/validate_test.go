@@ -0,0 +1,64 @@
+package eip712
+
+import "testing"
+
+func validOrderInput() *OrderInput {
+	return &OrderInput{
+		Salt:          "1",
+		Maker:         "0x62924ea9188Ad1228eEa76931B595c781b72b664",
+		Signer:        "0x62924ea9188Ad1228eEa76931B595c781b72b664",
+		Taker:         "0x0000000000000000000000000000000000000000",
+		TokenId:       "12345",
+		MakerAmount:   "1000000",
+		TakerAmount:   "2000000",
+		Expiration:    "0",
+		Nonce:         "0",
+		FeeRateBps:    "0",
+		Side:          0,
+		SignatureType: 0,
+	}
+}
+
+func TestValidateOrderInput_Valid(t *testing.T) {
+	if err := ValidateOrderInput(validOrderInput()); err != nil {
+		t.Errorf("ValidateOrderInput(valid) = %v, want nil", err)
+	}
+}
+
+func TestValidateOrderInput_RejectsMalformedInput(t *testing.T) {
+	cases := map[string]func(*OrderInput){
+		"non-numeric salt":           func(i *OrderInput) { i.Salt = "not-a-number" },
+		"negative makerAmount":       func(i *OrderInput) { i.MakerAmount = "-1" },
+		"non-checksummed maker":      func(i *OrderInput) { i.Maker = "0x62924ea9188ad1228eea76931b595c781b72b664" },
+		"invalid maker address":      func(i *OrderInput) { i.Maker = "not-an-address" },
+		"side out of range":          func(i *OrderInput) { i.Side = 2 },
+		"negative side":              func(i *OrderInput) { i.Side = -1 },
+		"signatureType out of range": func(i *OrderInput) { i.SignatureType = 99 },
+		"overlong numeric string":    func(i *OrderInput) { i.TokenId = "1" + string(make([]byte, 512)) },
+	}
+
+	for name, mutate := range cases {
+		t.Run(name, func(t *testing.T) {
+			input := validOrderInput()
+			mutate(input)
+			if err := ValidateOrderInput(input); err == nil {
+				t.Errorf("ValidateOrderInput(%s) = nil, want error", name)
+			}
+		})
+	}
+}
+
+func TestParseSide(t *testing.T) {
+	if side, err := ParseSide("buy"); err != nil || side != 0 {
+		t.Errorf("ParseSide(buy) = (%d, %v), want (0, nil)", side, err)
+	}
+	if side, err := ParseSide("sell"); err != nil || side != 1 {
+		t.Errorf("ParseSide(sell) = (%d, %v), want (1, nil)", side, err)
+	}
+	if _, err := ParseSide("BUY"); err == nil {
+		t.Errorf("ParseSide(BUY) = nil error, want error (case-sensitive)")
+	}
+	if _, err := ParseSide(""); err == nil {
+		t.Errorf("ParseSide(\"\") = nil error, want error")
+	}
+}
@@ -0,0 +1,101 @@
+package eip712
+
+import (
+	"testing"
+
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+func TestVerifyOrderSignature_RoundTrip(t *testing.T) {
+	order := goldenOrder()
+	verifyingContract := goldenVerifyingContract(t)
+
+	sig, err := SignOrder(goldenPrivateKey, goldenChainID, verifyingContract, order)
+	if err != nil {
+		t.Fatalf("SignOrder: %v", err)
+	}
+
+	recovered, err := VerifyOrderSignature(goldenChainID, verifyingContract, order, sig.Bytes)
+	if err != nil {
+		t.Fatalf("VerifyOrderSignature: %v", err)
+	}
+
+	privateKey, err := crypto.HexToECDSA(goldenPrivateKey)
+	if err != nil {
+		t.Fatalf("HexToECDSA: %v", err)
+	}
+	wantSigner := crypto.PubkeyToAddress(privateKey.PublicKey)
+
+	if recovered != wantSigner {
+		t.Errorf("recovered signer = %s, want %s", recovered.Hex(), wantSigner.Hex())
+	}
+}
+
+func TestVerifyOrderSignature_TamperedOrderFailsToMatch(t *testing.T) {
+	order := goldenOrder()
+	verifyingContract := goldenVerifyingContract(t)
+
+	sig, err := SignOrder(goldenPrivateKey, goldenChainID, verifyingContract, order)
+	if err != nil {
+		t.Fatalf("SignOrder: %v", err)
+	}
+
+	privateKey, err := crypto.HexToECDSA(goldenPrivateKey)
+	if err != nil {
+		t.Fatalf("HexToECDSA: %v", err)
+	}
+	wantSigner := crypto.PubkeyToAddress(privateKey.PublicKey)
+
+	tampered := goldenOrder()
+	tampered.MakerAmount = tampered.MakerAmount.Add(tampered.MakerAmount, tampered.MakerAmount)
+
+	recovered, err := VerifyOrderSignature(goldenChainID, verifyingContract, tampered, sig.Bytes)
+	if err != nil {
+		t.Fatalf("VerifyOrderSignature: %v", err)
+	}
+
+	if recovered == wantSigner {
+		t.Errorf("recovered signer matched the maker after tampering with makerAmount, want mismatch")
+	}
+}
+
+func TestVerifyOrderInputSignature_RoundTrip(t *testing.T) {
+	input := &OrderInput{
+		Salt:          "1",
+		Maker:         "0x62924ea9188Ad1228eEa76931B595c781b72b664",
+		Signer:        "0x62924ea9188Ad1228eEa76931B595c781b72b664",
+		Taker:         "0x0000000000000000000000000000000000000000",
+		TokenId:       "12345",
+		MakerAmount:   "1000000",
+		TakerAmount:   "2000000",
+		Expiration:    "0",
+		Nonce:         "0",
+		FeeRateBps:    "0",
+		Side:          0,
+		SignatureType: 0,
+	}
+
+	signature, err := SignOrderInput(goldenPrivateKey, goldenChainID, input)
+	if err != nil {
+		t.Fatalf("SignOrderInput: %v", err)
+	}
+
+	recovered, err := VerifyOrderInputSignature(goldenChainID, input, signature)
+	if err != nil {
+		t.Fatalf("VerifyOrderInputSignature: %v", err)
+	}
+
+	privateKey, err := crypto.HexToECDSA(goldenPrivateKey)
+	if err != nil {
+		t.Fatalf("HexToECDSA: %v", err)
+	}
+	wantSigner := crypto.PubkeyToAddress(privateKey.PublicKey)
+
+	// The recovered address is whoever actually holds goldenPrivateKey, not
+	// necessarily input.Maker/Signer - callers are expected to compare the
+	// recovered address against the claimed maker/signer themselves, the
+	// same check the order_verifier command performs.
+	if recovered != wantSigner {
+		t.Errorf("recovered signer = %s, want %s", recovered.Hex(), wantSigner.Hex())
+	}
+}
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+	"os"
+
+	_ "github.com/lib/pq"
+)
+
+const (
+	DBHost = "34.146.110.159"
+	DBPort = 5432
+	DBUser = "postgres"
+	DBName = "deepsense"
+)
+
+func connectDB() (*sql.DB, error) {
+	password := os.Getenv("MIGRATIONS_DB_PASSWORD")
+	connStr := fmt.Sprintf("host=%s port=%d user=%s password=%s dbname=%s sslmode=require",
+		DBHost, DBPort, DBUser, password, DBName)
+	db, err := sql.Open("postgres", connStr)
+	if err != nil {
+		return nil, fmt.Errorf("connect to database: %w", err)
+	}
+	if err := db.Ping(); err != nil {
+		return nil, fmt.Errorf("ping database: %w", err)
+	}
+	return db, nil
+}
+
+func printUsage() {
+	fmt.Println("Usage: migrations up|down")
+}
+
+func main() {
+	if len(os.Args) < 2 {
+		printUsage()
+		os.Exit(1)
+	}
+
+	db, err := connectDB()
+	if err != nil {
+		log.Fatalf("%v", err)
+	}
+	defer db.Close()
+
+	switch os.Args[1] {
+	case "up":
+		if err := Up(db); err != nil {
+			log.Fatalf("migrate up: %v", err)
+		}
+		fmt.Println("migrations applied")
+	case "down":
+		if err := Down(db); err != nil {
+			log.Fatalf("migrate down: %v", err)
+		}
+		fmt.Println("last migration reverted")
+	default:
+		printUsage()
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,220 @@
+// migrations embeds the SQL schema the scripts in this repo depend on
+// (events, orders, trades, candles, ...) as an ordered set of versioned
+// up/down files, so a test environment (see testenv) or a fresh deployment
+// can be brought to exactly the schema production runs, instead of drifting
+// from the ad-hoc .sql files that used to be applied by hand.
+package main
+
+import (
+	"database/sql"
+	"embed"
+	"fmt"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+//go:embed sql/*.sql
+var sqlFiles embed.FS
+
+// Migration is one versioned schema change, identified by an integer
+// version that also fixes its apply order.
+type Migration struct {
+	Version int
+	Name    string
+	UpSQL   string
+	DownSQL string
+}
+
+// Load reads and pairs up the embedded sql/NNNN_name.{up,down}.sql files,
+// sorted by version ascending.
+func Load() ([]Migration, error) {
+	entries, err := sqlFiles.ReadDir("sql")
+	if err != nil {
+		return nil, fmt.Errorf("read sql dir: %w", err)
+	}
+
+	byVersion := make(map[int]*Migration)
+	for _, entry := range entries {
+		name := entry.Name()
+		version, base, direction, err := parseFilename(name)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+
+		data, err := sqlFiles.ReadFile(path.Join("sql", name))
+		if err != nil {
+			return nil, fmt.Errorf("read %s: %w", name, err)
+		}
+
+		m, ok := byVersion[version]
+		if !ok {
+			m = &Migration{Version: version, Name: base}
+			byVersion[version] = m
+		}
+		switch direction {
+		case "up":
+			m.UpSQL = string(data)
+		case "down":
+			m.DownSQL = string(data)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, m := range byVersion {
+		if m.UpSQL == "" {
+			return nil, fmt.Errorf("migration %04d_%s has no .up.sql file", m.Version, m.Name)
+		}
+		if m.DownSQL == "" {
+			return nil, fmt.Errorf("migration %04d_%s has no .down.sql file", m.Version, m.Name)
+		}
+		migrations = append(migrations, *m)
+	}
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].Version < migrations[j].Version })
+
+	return migrations, nil
+}
+
+// parseFilename splits "0002_create_candles.up.sql" into version 2, name
+// "create_candles", direction "up".
+func parseFilename(name string) (version int, base string, direction string, err error) {
+	trimmed := strings.TrimSuffix(name, ".sql")
+	switch {
+	case strings.HasSuffix(trimmed, ".up"):
+		direction = "up"
+		trimmed = strings.TrimSuffix(trimmed, ".up")
+	case strings.HasSuffix(trimmed, ".down"):
+		direction = "down"
+		trimmed = strings.TrimSuffix(trimmed, ".down")
+	default:
+		return 0, "", "", fmt.Errorf("filename must end in .up.sql or .down.sql")
+	}
+
+	parts := strings.SplitN(trimmed, "_", 2)
+	if len(parts) != 2 {
+		return 0, "", "", fmt.Errorf("filename must be VERSION_name%s.sql", "."+direction)
+	}
+	version, err = strconv.Atoi(parts[0])
+	if err != nil {
+		return 0, "", "", fmt.Errorf("invalid version prefix: %w", err)
+	}
+	return version, parts[1], direction, nil
+}
+
+// schemaMigrationsTable tracks which migration versions have already been
+// applied, so Up/Down only run what's needed.
+const schemaMigrationsTable = `
+CREATE TABLE IF NOT EXISTS schema_migrations (
+	version BIGINT PRIMARY KEY,
+	name TEXT NOT NULL,
+	applied_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+)`
+
+func ensureVersionTable(db *sql.DB) error {
+	_, err := db.Exec(schemaMigrationsTable)
+	return err
+}
+
+func appliedVersions(db *sql.DB) (map[int]bool, error) {
+	rows, err := db.Query(`SELECT version FROM schema_migrations`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	applied := make(map[int]bool)
+	for rows.Next() {
+		var v int
+		if err := rows.Scan(&v); err != nil {
+			return nil, err
+		}
+		applied[v] = true
+	}
+	return applied, rows.Err()
+}
+
+// Up applies every migration with a version greater than the highest
+// already-applied version, in order, each in its own transaction.
+func Up(db *sql.DB) error {
+	if err := ensureVersionTable(db); err != nil {
+		return fmt.Errorf("ensure schema_migrations: %w", err)
+	}
+	migrations, err := Load()
+	if err != nil {
+		return err
+	}
+	applied, err := appliedVersions(db)
+	if err != nil {
+		return fmt.Errorf("read applied versions: %w", err)
+	}
+
+	for _, m := range migrations {
+		if applied[m.Version] {
+			continue
+		}
+		if err := applyOne(db, m.Version, m.Name, m.UpSQL); err != nil {
+			return fmt.Errorf("apply %04d_%s: %w", m.Version, m.Name, err)
+		}
+	}
+	return nil
+}
+
+// Down reverts the single most recently applied migration.
+func Down(db *sql.DB) error {
+	if err := ensureVersionTable(db); err != nil {
+		return fmt.Errorf("ensure schema_migrations: %w", err)
+	}
+	migrations, err := Load()
+	if err != nil {
+		return err
+	}
+	applied, err := appliedVersions(db)
+	if err != nil {
+		return fmt.Errorf("read applied versions: %w", err)
+	}
+
+	var latest *Migration
+	for i := range migrations {
+		if applied[migrations[i].Version] {
+			latest = &migrations[i]
+		}
+	}
+	if latest == nil {
+		return nil
+	}
+
+	return revertOne(db, latest.Version, latest.Name, latest.DownSQL)
+}
+
+func applyOne(db *sql.DB, version int, name, upSQL string) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(upSQL); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(`INSERT INTO schema_migrations (version, name) VALUES ($1, $2)`, version, name); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func revertOne(db *sql.DB, version int, name, downSQL string) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(downSQL); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(`DELETE FROM schema_migrations WHERE version = $1`, version); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
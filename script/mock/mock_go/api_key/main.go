@@ -2,12 +2,13 @@ package main
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 	"log"
 	"os"
 
-	"github.com/redis/go-redis/v9"
+	"mock_go/chaos"
+	"mock_go/streamtypes"
+	"streams"
 )
 
 const (
@@ -21,42 +22,18 @@ const (
 	REDIS_DB       = 0 // engine_input_mq 使用 DB 6
 )
 
-// ApiKeyEventAdd 添加 API Key 事件
-type ApiKeyEventAdd struct {
-	Action  string `json:"action"`
-	ApiKey  string `json:"api_key"`
-	PrivyID string `json:"privy_id"`
-}
-
-// ApiKeyEventRemove 移除 API Key 事件
-type ApiKeyEventRemove struct {
-	Action string `json:"action"`
-	ApiKey string `json:"api_key"`
-}
-
 // sendAddApiKey 发送添加 API Key 消息
-func sendAddApiKey(ctx context.Context, rdb *redis.Client, apiKey, privyID string) error {
-	event := ApiKeyEventAdd{
+func sendAddApiKey(ctx context.Context, producer *streams.Producer, apiKey, privyID string) error {
+	event := streamtypes.ApiKeyEventAdd{
+		Version: streamtypes.CurrentVersion,
 		Action:  "add",
 		ApiKey:  apiKey,
 		PrivyID: privyID,
 	}
 
-	msgBytes, err := json.Marshal(event)
-	if err != nil {
-		return fmt.Errorf("failed to marshal add event: %w", err)
-	}
-
-	log.Printf("Sending Add API Key: %s", string(msgBytes))
+	log.Printf("Sending Add API Key: %+v", event)
 
-	err = rdb.XAdd(ctx, &redis.XAddArgs{
-		Stream: API_KEY_STREAM,
-		Values: map[string]interface{}{
-			API_KEY_MSG_KEY: string(msgBytes),
-		},
-	}).Err()
-
-	if err != nil {
+	if _, err := producer.Send(ctx, event); err != nil {
 		return fmt.Errorf("failed to publish add event: %w", err)
 	}
 
@@ -65,27 +42,16 @@ func sendAddApiKey(ctx context.Context, rdb *redis.Client, apiKey, privyID strin
 }
 
 // sendRemoveApiKey 发送移除 API Key 消息
-func sendRemoveApiKey(ctx context.Context, rdb *redis.Client, apiKey string) error {
-	event := ApiKeyEventRemove{
-		Action: "remove",
-		ApiKey: apiKey,
-	}
-
-	msgBytes, err := json.Marshal(event)
-	if err != nil {
-		return fmt.Errorf("failed to marshal remove event: %w", err)
+func sendRemoveApiKey(ctx context.Context, producer *streams.Producer, apiKey string) error {
+	event := streamtypes.ApiKeyEventRemove{
+		Version: streamtypes.CurrentVersion,
+		Action:  "remove",
+		ApiKey:  apiKey,
 	}
 
-	log.Printf("Sending Remove API Key: %s", string(msgBytes))
-
-	err = rdb.XAdd(ctx, &redis.XAddArgs{
-		Stream: API_KEY_STREAM,
-		Values: map[string]interface{}{
-			API_KEY_MSG_KEY: string(msgBytes),
-		},
-	}).Err()
+	log.Printf("Sending Remove API Key: %+v", event)
 
-	if err != nil {
+	if _, err := producer.Send(ctx, event); err != nil {
 		return fmt.Errorf("failed to publish remove event: %w", err)
 	}
 
@@ -114,11 +80,11 @@ func main() {
 	ctx := context.Background()
 
 	// 连接 Redis
-	rdb := redis.NewClient(&redis.Options{
-		Addr:     REDIS_HOST,
+	rdb := chaos.Attach(streams.NewRedisClient(streams.RedisConfig{
+		Addrs:    []string{REDIS_HOST},
 		Password: REDIS_PASSWORD,
 		DB:       REDIS_DB,
-	})
+	}.OverrideFromEnv()), chaos.ConfigFromEnv())
 	defer rdb.Close()
 
 	// 测试 Redis 连接
@@ -127,6 +93,8 @@ func main() {
 	}
 	log.Println("Connected to Redis")
 
+	producer := streams.NewProducer(rdb, API_KEY_STREAM, API_KEY_MSG_KEY)
+
 	switch action {
 	case "add":
 		if len(os.Args) < 4 {
@@ -136,7 +104,7 @@ func main() {
 		}
 		apiKey := os.Args[2]
 		privyID := os.Args[3]
-		if err := sendAddApiKey(ctx, rdb, apiKey, privyID); err != nil {
+		if err := sendAddApiKey(ctx, producer, apiKey, privyID); err != nil {
 			log.Fatalf("Failed to add API Key: %v", err)
 		}
 
@@ -147,7 +115,7 @@ func main() {
 			os.Exit(1)
 		}
 		apiKey := os.Args[2]
-		if err := sendRemoveApiKey(ctx, rdb, apiKey); err != nil {
+		if err := sendRemoveApiKey(ctx, producer, apiKey); err != nil {
 			log.Fatalf("Failed to remove API Key: %v", err)
 		}
 
@@ -0,0 +1,138 @@
+// Package chaos wraps a *redis.Client with a go-redis Hook that randomly
+// injects connection drops, timeouts and slow responses, so the mock
+// tools (and whatever consumer they're standing in for during a test) can
+// be exercised against a flaky Redis without needing an actual flaky
+// Redis. It's off by default: Attach only installs the hook once one of
+// the CHAOS_* rates below is set, so normal runs of the mock tools are
+// unaffected.
+package chaos
+
+import (
+	"context"
+	"errors"
+	"log"
+	"math/rand"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// ErrSimulatedDrop is returned in place of the real command error when the
+// drop chaos rate fires.
+var ErrSimulatedDrop = errors.New("chaos: simulated connection drop")
+
+// ErrSimulatedTimeout is returned in place of the real command error when
+// the timeout chaos rate fires.
+var ErrSimulatedTimeout = errors.New("chaos: simulated command timeout")
+
+// Config holds the injection rates, each a probability in [0, 1] applied
+// independently per command.
+type Config struct {
+	DropRate    float64
+	TimeoutRate float64
+	SlowRate    float64
+	SlowDelay   time.Duration
+}
+
+// Enabled reports whether any rate is set; a zero Config is a no-op.
+func (c Config) Enabled() bool {
+	return c.DropRate > 0 || c.TimeoutRate > 0 || c.SlowRate > 0
+}
+
+// ConfigFromEnv reads CHAOS_DROP_RATE, CHAOS_TIMEOUT_RATE, CHAOS_SLOW_RATE
+// (floats in [0, 1], default 0) and CHAOS_SLOW_DELAY_MS (default 200) from
+// the environment. Unset or unparseable values default to 0 (disabled) so
+// this is safe to call unconditionally.
+func ConfigFromEnv() Config {
+	return Config{
+		DropRate:    envFloat("CHAOS_DROP_RATE"),
+		TimeoutRate: envFloat("CHAOS_TIMEOUT_RATE"),
+		SlowRate:    envFloat("CHAOS_SLOW_RATE"),
+		SlowDelay:   envMillis("CHAOS_SLOW_DELAY_MS", 200*time.Millisecond),
+	}
+}
+
+func envFloat(name string) float64 {
+	v, err := strconv.ParseFloat(os.Getenv(name), 64)
+	if err != nil {
+		return 0
+	}
+	return v
+}
+
+func envMillis(name string, fallback time.Duration) time.Duration {
+	ms, err := strconv.Atoi(os.Getenv(name))
+	if err != nil {
+		return fallback
+	}
+	return time.Duration(ms) * time.Millisecond
+}
+
+// Attach installs a Hook built from cfg onto rdb and returns rdb, so
+// callers can chain it onto redis.NewClient or streams.NewRedisClient
+// (chaos targets the Cmdable surface every topology shares, so this works
+// the same for a single-node, Sentinel, or Cluster client). If cfg is not
+// Enabled, rdb is returned untouched.
+func Attach(rdb redis.UniversalClient, cfg Config) redis.UniversalClient {
+	if !cfg.Enabled() {
+		return rdb
+	}
+	log.Printf("⚡ chaos mode enabled: drop=%.2f timeout=%.2f slow=%.2f (delay=%s)",
+		cfg.DropRate, cfg.TimeoutRate, cfg.SlowRate, cfg.SlowDelay)
+	rdb.AddHook(NewHook(cfg))
+	return rdb
+}
+
+// Hook implements redis.Hook, injecting failures/delays into ProcessHook
+// before letting the real command through.
+type Hook struct {
+	cfg  Config
+	rand *rand.Rand
+}
+
+// NewHook returns a Hook that rolls its own random source, seeded from the
+// current time.
+func NewHook(cfg Config) *Hook {
+	return &Hook{cfg: cfg, rand: rand.New(rand.NewSource(time.Now().UnixNano()))}
+}
+
+func (h *Hook) roll(rate float64) bool {
+	if rate <= 0 {
+		return false
+	}
+	return h.rand.Float64() < rate
+}
+
+// DialHook passes dialing through unmodified; chaos only targets commands
+// on an already-established connection.
+func (h *Hook) DialHook(next redis.DialHook) redis.DialHook {
+	return next
+}
+
+// ProcessHook injects a simulated drop, a simulated timeout, or an
+// artificial delay before delegating to next, in that priority order (a
+// dropped or timed-out command never actually reaches Redis).
+func (h *Hook) ProcessHook(next redis.ProcessHook) redis.ProcessHook {
+	return func(ctx context.Context, cmd redis.Cmder) error {
+		if h.roll(h.cfg.DropRate) {
+			cmd.SetErr(ErrSimulatedDrop)
+			return ErrSimulatedDrop
+		}
+		if h.roll(h.cfg.TimeoutRate) {
+			cmd.SetErr(ErrSimulatedTimeout)
+			return ErrSimulatedTimeout
+		}
+		if h.roll(h.cfg.SlowRate) {
+			time.Sleep(h.cfg.SlowDelay)
+		}
+		return next(ctx, cmd)
+	}
+}
+
+// ProcessPipelineHook passes pipelined commands through unmodified; the
+// mock tools never pipeline, so there's nothing worth injecting here yet.
+func (h *Hook) ProcessPipelineHook(next redis.ProcessPipelineHook) redis.ProcessPipelineHook {
+	return next
+}
@@ -0,0 +1,99 @@
+package chaos
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+func TestConfig_Enabled(t *testing.T) {
+	if (Config{}).Enabled() {
+		t.Errorf("Enabled() on zero Config = true, want false")
+	}
+	if !(Config{DropRate: 0.1}).Enabled() {
+		t.Errorf("Enabled() with DropRate set = false, want true")
+	}
+}
+
+func TestHook_ProcessHook_DropAlwaysFails(t *testing.T) {
+	h := NewHook(Config{DropRate: 1})
+	called := false
+	next := func(ctx context.Context, cmd redis.Cmder) error {
+		called = true
+		return nil
+	}
+
+	cmd := redis.NewStatusCmd(context.Background())
+	err := h.ProcessHook(next)(context.Background(), cmd)
+
+	if err != ErrSimulatedDrop {
+		t.Errorf("err = %v, want ErrSimulatedDrop", err)
+	}
+	if called {
+		t.Errorf("next was called, want the dropped command to never reach it")
+	}
+	if cmd.Err() != ErrSimulatedDrop {
+		t.Errorf("cmd.Err() = %v, want ErrSimulatedDrop", cmd.Err())
+	}
+}
+
+func TestHook_ProcessHook_TimeoutAlwaysFails(t *testing.T) {
+	h := NewHook(Config{TimeoutRate: 1})
+	next := func(ctx context.Context, cmd redis.Cmder) error { return nil }
+
+	err := h.ProcessHook(next)(context.Background(), redis.NewStatusCmd(context.Background()))
+	if err != ErrSimulatedTimeout {
+		t.Errorf("err = %v, want ErrSimulatedTimeout", err)
+	}
+}
+
+func TestHook_ProcessHook_SlowDelaysThenCallsNext(t *testing.T) {
+	h := NewHook(Config{SlowRate: 1, SlowDelay: 10 * time.Millisecond})
+	called := false
+	next := func(ctx context.Context, cmd redis.Cmder) error {
+		called = true
+		return nil
+	}
+
+	start := time.Now()
+	err := h.ProcessHook(next)(context.Background(), redis.NewStatusCmd(context.Background()))
+	elapsed := time.Since(start)
+
+	if err != nil {
+		t.Errorf("err = %v, want nil", err)
+	}
+	if !called {
+		t.Errorf("next was not called, want the slowed command to still go through")
+	}
+	if elapsed < 10*time.Millisecond {
+		t.Errorf("elapsed = %s, want at least SlowDelay", elapsed)
+	}
+}
+
+func TestHook_ProcessHook_DisabledPassesThrough(t *testing.T) {
+	h := NewHook(Config{})
+	called := false
+	next := func(ctx context.Context, cmd redis.Cmder) error {
+		called = true
+		return nil
+	}
+
+	if err := h.ProcessHook(next)(context.Background(), redis.NewStatusCmd(context.Background())); err != nil {
+		t.Errorf("err = %v, want nil", err)
+	}
+	if !called {
+		t.Errorf("next was not called, want a disabled Hook to always pass through")
+	}
+}
+
+func TestAttach_NoopWhenDisabled(t *testing.T) {
+	rdb := redis.NewClient(&redis.Options{Addr: "127.0.0.1:0"})
+	defer rdb.Close()
+
+	got := Attach(rdb, Config{})
+	if got != rdb {
+		t.Errorf("Attach with disabled config returned a different client")
+	}
+}
@@ -0,0 +1,305 @@
+// Command depth_checker cross-checks the websocket depth feed against the
+// REST /depth endpoint for a configured set of markets: it reconstructs
+// each market's book from the websocket snapshot/change stream, polls REST
+// on an interval, and whenever both sides land on the same update_id it
+// diffs the two books with the depth package and reports any divergence -
+// we've seen the two disagree in production with no tooling to say how
+// often or how badly.
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"gopkg.in/yaml.v3"
+
+	"depth"
+	"mock_go/wsclient"
+	"mock_go/wsdepth"
+)
+
+// WSHost is the depth websocket server this checker subscribes to, the
+// same server monitor.go watches.
+const WSHost = "predictionmarket-websocket-depth-290128242879.asia-northeast1.run.app"
+
+// APIBaseURL is the REST API this checker polls, matching bot_go's own.
+const APIBaseURL = "https://predictionmarket-api-290128242879.asia-northeast1.run.app/api"
+
+// CheckerConfig describes the markets to cross-check and how often to poll
+// the REST side.
+type CheckerConfig struct {
+	Markets        []MarketConfig `yaml:"markets"`
+	PollIntervalMs int            `yaml:"poll_interval_ms"`
+}
+
+// MarketConfig identifies one market to check.
+type MarketConfig struct {
+	EventID  int64 `yaml:"event_id"`
+	MarketID int16 `yaml:"market_id"`
+}
+
+// loadCheckerConfig reads and validates the checker's YAML config file.
+func loadCheckerConfig(path string) (*CheckerConfig, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read checker config: %w", err)
+	}
+
+	var cfg CheckerConfig
+	if err := yaml.Unmarshal(data, &cfg); err != nil {
+		return nil, fmt.Errorf("parse checker config: %w", err)
+	}
+	if len(cfg.Markets) == 0 {
+		return nil, fmt.Errorf("checker config has no markets")
+	}
+	if cfg.PollIntervalMs <= 0 {
+		cfg.PollIntervalMs = 5000
+	}
+	return &cfg, nil
+}
+
+func marketKey(eventID int64, marketID int16) string {
+	return fmt.Sprintf("%d/%d", eventID, marketID)
+}
+
+// restDepthResponse mirrors bot_go's DepthResponse - this checker keeps its
+// own copy rather than importing bot_go, the same way onchain_send keeps
+// its own ChainConfig instead of importing bot_go's.
+type restDepthResponse struct {
+	Code int    `json:"code"`
+	Msg  string `json:"msg"`
+	Data struct {
+		UpdateID  uint64                   `json:"update_id"`
+		Timestamp int64                    `json:"timestamp"`
+		Depths    map[string]restDepthBook `json:"depths"`
+	} `json:"data"`
+}
+
+// restDepthBook is one token's book as the REST API encodes it - its price
+// levels already match depth.PriceLevel's field names, so no conversion is
+// needed beyond reshaping into depth.Book.
+type restDepthBook struct {
+	LatestTradePrice string             `json:"latest_trade_price"`
+	Bids             []depth.PriceLevel `json:"bids"`
+	Asks             []depth.PriceLevel `json:"asks"`
+}
+
+func (b restDepthBook) toDepthBook() depth.Book {
+	return depth.Book{LatestTradePrice: b.LatestTradePrice, Bids: b.Bids, Asks: b.Asks}
+}
+
+// fetchRESTDepth polls the REST /depth endpoint the same way bot_go's
+// GetDepth does, decoding straight into a depth.Snapshot.
+func fetchRESTDepth(eventID int64, marketID int16) (depth.Snapshot, error) {
+	url := fmt.Sprintf("%s/depth?event_id=%d&market_id=%d", APIBaseURL, eventID, marketID)
+
+	resp, err := http.Get(url)
+	if err != nil {
+		return depth.Snapshot{}, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return depth.Snapshot{}, err
+	}
+	if resp.StatusCode != 200 {
+		return depth.Snapshot{}, fmt.Errorf("HTTP %d: %s", resp.StatusCode, string(body))
+	}
+
+	var depthResp restDepthResponse
+	if err := json.Unmarshal(body, &depthResp); err != nil {
+		return depth.Snapshot{}, err
+	}
+	if depthResp.Code != 0 {
+		return depth.Snapshot{}, fmt.Errorf("depth API error: %s", depthResp.Msg)
+	}
+
+	depths := make(map[string]depth.Book, len(depthResp.Data.Depths))
+	for tokenID, book := range depthResp.Data.Depths {
+		depths[tokenID] = book.toDepthBook()
+	}
+	return depth.Snapshot{UpdateID: depthResp.Data.UpdateID, Timestamp: depthResp.Data.Timestamp, Depths: depths}, nil
+}
+
+// checkStats counts how many comparisons a run has made across all
+// markets, so it can log a periodic summary alongside the per-comparison
+// lines.
+type checkStats struct {
+	mu         sync.Mutex
+	compared   int
+	mismatched int
+	skipped    int
+}
+
+func (s *checkStats) recordCompared(mismatch bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.compared++
+	if mismatch {
+		s.mismatched++
+	}
+}
+
+func (s *checkStats) recordSkipped() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.skipped++
+}
+
+func (s *checkStats) snapshot() (compared, mismatched, skipped int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.compared, s.mismatched, s.skipped
+}
+
+// pollMarket polls REST for market on interval, diffing against the
+// websocket state whenever both sides report the same update_id. It never
+// diffs across two different update_ids - the two feeds are legitimately a
+// moment apart on every update, and only a same-update_id mismatch means
+// the two actually disagree about the book.
+func pollMarket(market MarketConfig, wsState *wsdepth.BookState, stats *checkStats, interval time.Duration, stop <-chan struct{}) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	var lastCheckedUpdateID uint64
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			restSnapshot, err := fetchRESTDepth(market.EventID, market.MarketID)
+			if err != nil {
+				log.Printf("⚠️  [%d/%d] REST /depth failed: %v", market.EventID, market.MarketID, err)
+				continue
+			}
+
+			wsSnapshot, ok := wsState.Snapshot()
+			if !ok {
+				continue
+			}
+
+			if restSnapshot.UpdateID != wsSnapshot.UpdateID {
+				stats.recordSkipped()
+				continue
+			}
+			if restSnapshot.UpdateID == lastCheckedUpdateID {
+				continue
+			}
+			lastCheckedUpdateID = restSnapshot.UpdateID
+
+			diffs, err := depth.DiffSnapshots(wsSnapshot, restSnapshot)
+			if err != nil {
+				log.Printf("⚠️  [%d/%d] could not diff update_id=%d: %v", market.EventID, market.MarketID, restSnapshot.UpdateID, err)
+				continue
+			}
+
+			mismatch := false
+			for tokenID, diff := range diffs {
+				if !diff.HasChanges() {
+					continue
+				}
+				mismatch = true
+				log.Printf("🚨 [%d/%d %s] REST/websocket MISMATCH at update_id=%d: added_bids=%d removed_bids=%d changed_bids=%d added_asks=%d removed_asks=%d changed_asks=%d",
+					market.EventID, market.MarketID, tokenID, restSnapshot.UpdateID,
+					len(diff.AddedBids), len(diff.RemovedBids), len(diff.ChangedBids),
+					len(diff.AddedAsks), len(diff.RemovedAsks), len(diff.ChangedAsks))
+			}
+			stats.recordCompared(mismatch)
+			if !mismatch {
+				log.Printf("✅ [%d/%d] REST/websocket agree at update_id=%d", market.EventID, market.MarketID, restSnapshot.UpdateID)
+			}
+		}
+	}
+}
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Println("Usage: go run . <depth_checker.yaml>")
+		os.Exit(1)
+	}
+
+	cfg, err := loadCheckerConfig(os.Args[1])
+	if err != nil {
+		log.Fatalf("❌ %v", err)
+	}
+	pollInterval := time.Duration(cfg.PollIntervalMs) * time.Millisecond
+
+	interrupt := make(chan os.Signal, 1)
+	signal.Notify(interrupt, os.Interrupt)
+
+	states := make(map[string]*wsdepth.BookState, len(cfg.Markets))
+	for _, market := range cfg.Markets {
+		states[marketKey(market.EventID, market.MarketID)] = &wsdepth.BookState{}
+	}
+
+	log.Printf("🔗 Connecting to wss://%s/depth", WSHost)
+
+	c, err := wsclient.Dial(wsclient.Config{
+		Scheme:            "wss",
+		Host:              WSHost,
+		Path:              "/depth",
+		HeartbeatInterval: 20 * time.Second,
+		OnMessage: func(messageType int, message []byte) {
+			var msg wsdepth.Message
+			if err := json.Unmarshal(message, &msg); err != nil {
+				log.Printf("⚠️  could not parse depth push: %v", err)
+				return
+			}
+
+			state, ok := states[marketKey(msg.EventID, msg.MarketID)]
+			if !ok {
+				return
+			}
+			state.Apply(msg)
+		},
+		OnClose: func(err error) {
+			if err != nil {
+				log.Println("read error:", err)
+			}
+		},
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer c.Close()
+
+	log.Println("✅ Connected to WebSocket Depth Server")
+
+	stats := &checkStats{}
+	stop := make(chan struct{})
+
+	for _, market := range cfg.Markets {
+		subscribeData, _ := json.Marshal(wsdepth.SubscribeMessage{
+			Action:   wsdepth.ActionSubscribe,
+			EventID:  market.EventID,
+			MarketID: market.MarketID,
+		})
+		if err := c.Send(websocket.TextMessage, subscribeData); err != nil {
+			log.Fatalf("❌ subscribe %d/%d failed: %v", market.EventID, market.MarketID, err)
+		}
+		log.Printf("📨 Subscribed to depth: event_id=%d, market_id=%d", market.EventID, market.MarketID)
+
+		go pollMarket(market, states[marketKey(market.EventID, market.MarketID)], stats, pollInterval, stop)
+	}
+
+	go func() {
+		ticker := time.NewTicker(time.Minute)
+		defer ticker.Stop()
+		for range ticker.C {
+			compared, mismatched, skipped := stats.snapshot()
+			log.Printf("📊 checked=%d mismatched=%d skipped(update_id not yet aligned)=%d", compared, mismatched, skipped)
+		}
+	}()
+
+	c.Run(interrupt)
+	close(stop)
+}
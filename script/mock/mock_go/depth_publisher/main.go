@@ -0,0 +1,353 @@
+// Command depth_publisher runs a standalone WebSocket server that speaks
+// websocket_depth's subscribe/unsubscribe protocol and pushes synthetic,
+// configurable depth updates (random-walk mid price, configurable level
+// counts and update rate) instead of real matching engine output, so
+// monitor/websocket_depth and other clients can be exercised without a
+// running engine.
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"math/rand"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/shopspring/decimal"
+	"gopkg.in/yaml.v3"
+
+	"depth"
+)
+
+// PublisherConfig describes the markets to simulate and how fast/deep to
+// simulate them.
+type PublisherConfig struct {
+	ListenAddr       string         `yaml:"listen_addr"`
+	Markets          []MarketConfig `yaml:"markets"`
+	LevelCount       int            `yaml:"level_count"`
+	UpdateIntervalMs int            `yaml:"update_interval_ms"`
+	StartPrice       string         `yaml:"start_price"`
+	StepSize         string         `yaml:"step_size"`
+	TickSize         string         `yaml:"tick_size"`
+}
+
+// MarketConfig is one market to simulate, with its yes/no token IDs.
+type MarketConfig struct {
+	EventID  int64    `yaml:"event_id"`
+	MarketID int16    `yaml:"market_id"`
+	TokenIDs []string `yaml:"token_ids"`
+}
+
+func loadConfig(path string) (*PublisherConfig, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read depth_publisher config: %w", err)
+	}
+
+	var cfg PublisherConfig
+	if err := yaml.Unmarshal(data, &cfg); err != nil {
+		return nil, fmt.Errorf("parse depth_publisher config: %w", err)
+	}
+
+	if cfg.ListenAddr == "" {
+		cfg.ListenAddr = "127.0.0.1:8085"
+	}
+	if len(cfg.Markets) == 0 {
+		return nil, fmt.Errorf("depth_publisher config has no markets")
+	}
+	if cfg.LevelCount <= 0 {
+		cfg.LevelCount = 5
+	}
+	if cfg.UpdateIntervalMs <= 0 {
+		cfg.UpdateIntervalMs = 500
+	}
+	if cfg.StartPrice == "" {
+		cfg.StartPrice = "0.5"
+	}
+	if cfg.StepSize == "" {
+		cfg.StepSize = "0.01"
+	}
+	if cfg.TickSize == "" {
+		cfg.TickSize = "0.01"
+	}
+
+	return &cfg, nil
+}
+
+// depthSubscribeMessage matches websocket_depth's subscribe/unsubscribe
+// protocol (see mock_go/websocket_depth and mock_go/monitor).
+type depthSubscribeMessage struct {
+	Action   string `json:"action"`
+	EventID  int64  `json:"event_id"`
+	MarketID int16  `json:"market_id"`
+}
+
+// depthPush is the shape of a pushed depth update for one market.
+type depthPush struct {
+	EventID  int64                 `json:"event_id"`
+	MarketID int16                 `json:"market_id"`
+	Depths   map[string]depth.Book `json:"depths"`
+}
+
+func marketKey(eventID int64, marketID int16) string {
+	return fmt.Sprintf("%d/%d", eventID, marketID)
+}
+
+// client is one connected WebSocket subscriber.
+type client struct {
+	conn *websocket.Conn
+	send chan []byte
+
+	mu         sync.Mutex
+	subscribed map[string]bool
+}
+
+func newClient(conn *websocket.Conn) *client {
+	return &client{conn: conn, send: make(chan []byte, 16), subscribed: make(map[string]bool)}
+}
+
+func (c *client) isSubscribed(key string) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.subscribed[key]
+}
+
+func (c *client) setSubscribed(key string, subscribed bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if subscribed {
+		c.subscribed[key] = true
+	} else {
+		delete(c.subscribed, key)
+	}
+}
+
+func (c *client) writePump() {
+	for payload := range c.send {
+		if err := c.conn.WriteMessage(websocket.TextMessage, payload); err != nil {
+			return
+		}
+	}
+}
+
+func (c *client) readPump(unregister func(*client)) {
+	defer unregister(c)
+	defer c.conn.Close()
+
+	for {
+		_, message, err := c.conn.ReadMessage()
+		if err != nil {
+			return
+		}
+
+		var msg depthSubscribeMessage
+		if err := json.Unmarshal(message, &msg); err != nil {
+			log.Printf("⚠️  ignoring invalid message: %v", err)
+			continue
+		}
+
+		key := marketKey(msg.EventID, msg.MarketID)
+		switch msg.Action {
+		case "subscribe":
+			c.setSubscribed(key, true)
+			log.Printf("📨 client subscribed to event_id=%d market_id=%d", msg.EventID, msg.MarketID)
+		case "unsubscribe":
+			c.setSubscribed(key, false)
+			log.Printf("📨 client unsubscribed from event_id=%d market_id=%d", msg.EventID, msg.MarketID)
+		default:
+			log.Printf("⚠️  ignoring unknown action %q", msg.Action)
+		}
+	}
+}
+
+// hub tracks connected clients and fans a market's update out to whoever
+// is currently subscribed to it.
+type hub struct {
+	mu      sync.Mutex
+	clients map[*client]struct{}
+}
+
+func newHub() *hub {
+	return &hub{clients: make(map[*client]struct{})}
+}
+
+func (h *hub) register(c *client) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.clients[c] = struct{}{}
+}
+
+func (h *hub) unregister(c *client) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if _, ok := h.clients[c]; ok {
+		delete(h.clients, c)
+		close(c.send)
+	}
+}
+
+func (h *hub) broadcast(key string, payload []byte) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for c := range h.clients {
+		if !c.isSubscribed(key) {
+			continue
+		}
+		select {
+		case c.send <- payload:
+		default:
+			log.Printf("⚠️  client send buffer full, dropping update for %s", key)
+		}
+	}
+}
+
+// marketSimulator random-walks a market's mid price and derives a
+// symmetric yes/no order book from it (token 0's mid is p, token 1's is
+// 1-p, matching a binary prediction market's complementary pricing).
+type marketSimulator struct {
+	market MarketConfig
+	mid    decimal.Decimal
+	step   decimal.Decimal
+	tick   decimal.Decimal
+	levels int
+}
+
+func newMarketSimulator(market MarketConfig, startPrice, step, tick decimal.Decimal, levels int) *marketSimulator {
+	return &marketSimulator{market: market, mid: startPrice, step: step, tick: tick, levels: levels}
+}
+
+func (s *marketSimulator) tickOnce(rng *rand.Rand) depthPush {
+	direction := decimal.NewFromInt(1)
+	if rng.Float64() < 0.5 {
+		direction = decimal.NewFromInt(-1)
+	}
+	s.mid = s.mid.Add(s.step.Mul(direction).Mul(decimal.NewFromFloat(rng.Float64())))
+	s.mid = clamp(s.mid, decimal.NewFromFloat(0.01), decimal.NewFromFloat(0.99))
+
+	depths := make(map[string]depth.Book, len(s.market.TokenIDs))
+	for i, tokenID := range s.market.TokenIDs {
+		mid := s.mid
+		if i%2 == 1 {
+			mid = decimal.NewFromInt(1).Sub(s.mid)
+		}
+		depths[tokenID] = generateBook(mid, s.tick, s.levels, rng)
+	}
+
+	return depthPush{EventID: s.market.EventID, MarketID: s.market.MarketID, Depths: depths}
+}
+
+func clamp(v, min, max decimal.Decimal) decimal.Decimal {
+	if v.LessThan(min) {
+		return min
+	}
+	if v.GreaterThan(max) {
+		return max
+	}
+	return v
+}
+
+// generateBook builds a synthetic book of levels bids below mid and
+// levels asks above mid, spaced by tick, with random quantities.
+func generateBook(mid, tick decimal.Decimal, levels int, rng *rand.Rand) depth.Book {
+	book := depth.Book{LatestTradePrice: mid.StringFixed(4)}
+
+	for i := 1; i <= levels; i++ {
+		offset := tick.Mul(decimal.NewFromInt(int64(i)))
+
+		bidPrice := clamp(mid.Sub(offset), decimal.NewFromFloat(0.01), decimal.NewFromFloat(0.99))
+		book.Bids = append(book.Bids, depth.PriceLevel{
+			Price:    bidPrice.StringFixed(4),
+			Quantity: randomQuantity(rng),
+		})
+
+		askPrice := clamp(mid.Add(offset), decimal.NewFromFloat(0.01), decimal.NewFromFloat(0.99))
+		book.Asks = append(book.Asks, depth.PriceLevel{
+			Price:    askPrice.StringFixed(4),
+			Quantity: randomQuantity(rng),
+		})
+	}
+
+	return book
+}
+
+func randomQuantity(rng *rand.Rand) string {
+	qty := 10 + rng.Intn(490) // 10..500 shares
+	return fmt.Sprintf("%d", qty)
+}
+
+func runSimulators(cfg *PublisherConfig, h *hub) {
+	startPrice, err := decimal.NewFromString(cfg.StartPrice)
+	if err != nil {
+		log.Fatalf("❌ invalid start_price: %v", err)
+	}
+	step, err := decimal.NewFromString(cfg.StepSize)
+	if err != nil {
+		log.Fatalf("❌ invalid step_size: %v", err)
+	}
+	tick, err := decimal.NewFromString(cfg.TickSize)
+	if err != nil {
+		log.Fatalf("❌ invalid tick_size: %v", err)
+	}
+
+	for i, market := range cfg.Markets {
+		sim := newMarketSimulator(market, startPrice, step, tick, cfg.LevelCount)
+		rng := rand.New(rand.NewSource(int64(i) + 1))
+		key := marketKey(market.EventID, market.MarketID)
+
+		go func(sim *marketSimulator, rng *rand.Rand, key string) {
+			ticker := time.NewTicker(time.Duration(cfg.UpdateIntervalMs) * time.Millisecond)
+			defer ticker.Stop()
+
+			for range ticker.C {
+				push := sim.tickOnce(rng)
+				payload, err := json.Marshal(push)
+				if err != nil {
+					log.Printf("⚠️  marshal depth push failed: %v", err)
+					continue
+				}
+				h.broadcast(key, payload)
+			}
+		}(sim, rng, key)
+	}
+}
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Println("Usage: go run . <depth_publisher.yaml>")
+		os.Exit(1)
+	}
+
+	cfg, err := loadConfig(os.Args[1])
+	if err != nil {
+		log.Fatalf("❌ %v", err)
+	}
+
+	h := newHub()
+	runSimulators(cfg, h)
+
+	upgrader := websocket.Upgrader{
+		CheckOrigin: func(r *http.Request) bool { return true },
+	}
+
+	http.HandleFunc("/depth", func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			log.Printf("⚠️  upgrade failed: %v", err)
+			return
+		}
+
+		c := newClient(conn)
+		h.register(c)
+		go c.writePump()
+		c.readPump(h.unregister)
+	})
+
+	log.Printf("🚀 Depth publisher listening on ws://%s/depth, simulating %d market(s)", cfg.ListenAddr, len(cfg.Markets))
+	if err := http.ListenAndServe(cfg.ListenAddr, nil); err != nil {
+		log.Fatalf("❌ %v", err)
+	}
+}
@@ -0,0 +1,176 @@
+// Command engine_input_consumer 模拟 match_engine 消费 event_input_stream
+// 的行为: 校验并美化打印 AddOneEvent/RemoveOneEvent 消息, 同时维护一份
+// "引擎当前应该知道哪些事件" 的内存视图, 这样 send_event 的改动不需要真的
+// 跑起 match_engine 就能验证消息是否符合预期。
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"sort"
+	"time"
+
+	"mock_go/chaos"
+	"mock_go/streamtypes"
+	"streams"
+)
+
+const (
+	// Redis Stream 配置, 与 send_event 保持一致
+	EventInputStream = "event_input_stream"
+	EventInputMsgKey = "event_input_key"
+
+	// Redis 配置 (common_mq)
+	RedisHost     = "35.200.1.149:6379"
+	RedisPassword = "mZDUu0M43KmvMo1ehuiz"
+	RedisDB       = 0
+
+	ConsumerGroup = "mock_engine_input_consumer"
+	ConsumerName  = "mock_consumer_1"
+)
+
+// rawMessage 只用来读出 types/version 字段, 决定消息要反序列化成哪个具体
+// 类型、以及这个 mock consumer 认不认识发送方打的版本号。消息里其余字段
+// 一律交给 json.Unmarshal 按目标 struct 解析, 未知字段本来就会被忽略,
+// 所以升级 match_engine 侧的 payload 不会让这里 panic 或报错。
+type rawMessage struct {
+	Types   string `json:"types"`
+	Version int    `json:"version"`
+}
+
+// knownEvent 是内存视图里的一条记录: 引擎当前认为该事件长这样。
+type knownEvent struct {
+	Markets map[string]streamtypes.EngineMQEventMarket
+	EndDate *time.Time
+}
+
+// engineView 维护 "引擎当前应该知道哪些事件" 的内存快照, 单线程消费循环内
+// 使用, 不需要加锁。
+type engineView struct {
+	events map[int64]knownEvent
+}
+
+func newEngineView() *engineView {
+	return &engineView{events: make(map[int64]knownEvent)}
+}
+
+func (v *engineView) applyAdd(msg streamtypes.EventInputMessageCreate) error {
+	if msg.EventID == 0 {
+		return fmt.Errorf("AddOneEvent missing event_id")
+	}
+	if len(msg.Markets) == 0 {
+		return fmt.Errorf("AddOneEvent event_id=%d has no markets", msg.EventID)
+	}
+	for key, market := range msg.Markets {
+		if len(market.Outcomes) != len(market.TokenIDs) {
+			return fmt.Errorf("AddOneEvent event_id=%d market %s: %d outcomes but %d token_ids",
+				msg.EventID, key, len(market.Outcomes), len(market.TokenIDs))
+		}
+	}
+	v.events[msg.EventID] = knownEvent{Markets: msg.Markets, EndDate: msg.EndDate}
+	return nil
+}
+
+func (v *engineView) applyRemove(msg streamtypes.EventInputMessageClose) error {
+	if msg.EventID == 0 {
+		return fmt.Errorf("RemoveOneEvent missing event_id")
+	}
+	if _, ok := v.events[msg.EventID]; !ok {
+		return fmt.Errorf("RemoveOneEvent event_id=%d: engine does not currently know this event", msg.EventID)
+	}
+	delete(v.events, msg.EventID)
+	return nil
+}
+
+func (v *engineView) print() {
+	if len(v.events) == 0 {
+		log.Printf("📋 Engine view: no events known")
+		return
+	}
+
+	ids := make([]int64, 0, len(v.events))
+	for id := range v.events {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+
+	log.Printf("📋 Engine view: %d event(s) known", len(ids))
+	for _, id := range ids {
+		event := v.events[id]
+		log.Printf("   event_id=%d markets=%d end_date=%v", id, len(event.Markets), event.EndDate)
+	}
+}
+
+func handleMessage(view *engineView) streams.Handler {
+	return func(ctx context.Context, raw []byte) error {
+		var header rawMessage
+		if err := json.Unmarshal(raw, &header); err != nil {
+			return fmt.Errorf("invalid message JSON: %w", err)
+		}
+
+		if !streamtypes.SupportedVersion(header.Version) {
+			log.Printf("⚠️  %s message has version %d, newer than this consumer's %d - decoding best-effort with the fields we know",
+				header.Types, header.Version, streamtypes.CurrentVersion)
+		}
+
+		switch header.Types {
+		case "AddOneEvent":
+			var msg streamtypes.EventInputMessageCreate
+			if err := json.Unmarshal(raw, &msg); err != nil {
+				return fmt.Errorf("invalid AddOneEvent: %w", err)
+			}
+			if err := view.applyAdd(msg); err != nil {
+				return err
+			}
+			log.Printf("✅ AddOneEvent event_id=%d markets=%d end_date=%v", msg.EventID, len(msg.Markets), msg.EndDate)
+
+		case "RemoveOneEvent":
+			var msg streamtypes.EventInputMessageClose
+			if err := json.Unmarshal(raw, &msg); err != nil {
+				return fmt.Errorf("invalid RemoveOneEvent: %w", err)
+			}
+			if err := view.applyRemove(msg); err != nil {
+				return err
+			}
+			log.Printf("✅ RemoveOneEvent event_id=%d", msg.EventID)
+
+		default:
+			return fmt.Errorf("unknown message type %q", header.Types)
+		}
+
+		view.print()
+		return nil
+	}
+}
+
+func main() {
+	ctx := context.Background()
+
+	rdb := chaos.Attach(streams.NewRedisClient(streams.RedisConfig{
+		Addrs:    []string{RedisHost},
+		Password: RedisPassword,
+		DB:       RedisDB,
+	}.OverrideFromEnv()), chaos.ConfigFromEnv())
+	defer rdb.Close()
+
+	if err := rdb.Ping(ctx).Err(); err != nil {
+		log.Fatalf("Failed to connect to Redis: %v", err)
+	}
+	log.Println("✅ Connected to Redis")
+
+	consumer := streams.NewConsumer(rdb, streams.ConsumerConfig{
+		Stream:   EventInputStream,
+		MsgKey:   EventInputMsgKey,
+		Group:    ConsumerGroup,
+		Consumer: ConsumerName,
+	})
+
+	view := newEngineView()
+
+	log.Printf("🚀 Engine Input Consumer started, listening on stream: %s", EventInputStream)
+	if err := consumer.Run(ctx, handleMessage(view)); err != nil {
+		log.Fatalf("Engine Input Consumer stopped: %v", err)
+	}
+}
@@ -0,0 +1,685 @@
+// Command load_gen spins up N simulated accounts, registers their API
+// keys, and submits randomized EIP-712-signed orders across a configured
+// set of markets at a target rate, to stress-test the matching engine and
+// API with realistic signed traffic instead of hand-crafted single
+// requests.
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"math/big"
+	"math/rand"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	gethmath "github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+	"github.com/redis/go-redis/v9"
+	"github.com/shopspring/decimal"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	APIBaseURL = "https://predictionmarket-api-290128242879.asia-northeast1.run.app/api"
+
+	CTFExchangeAddress = "0x65a2085833D2658f2B0ee2216F50A6CD2CE99C93"
+
+	// api_key_stream 与 mock_go/api_key 保持一致, 用于给模拟账户注册可用
+	// 的 API Key。streams 包整合前, 各工具各自维护这份常量。
+	apiKeyStream        = "api_key_stream"
+	apiKeyMsgKey        = "api_key_key"
+	apiKeyRedisHost     = "35.200.1.149:6379"
+	apiKeyRedisPassword = "mZDUu0M43KmvMo1ehuiz"
+	apiKeyRedisDB       = 0
+)
+
+// LoadGenConfig describes the simulated account pool, target markets, and
+// the rate/volume of orders to generate.
+type LoadGenConfig struct {
+	Seed            string         `yaml:"seed"`
+	AccountCount    int            `yaml:"account_count"`
+	Markets         []MarketTarget `yaml:"markets"`
+	OrdersPerSecond float64        `yaml:"orders_per_second"`
+	TotalOrders     int            `yaml:"total_orders"`
+	MinPrice        string         `yaml:"min_price"`
+	MaxPrice        string         `yaml:"max_price"`
+	MinShares       int64          `yaml:"min_shares"`
+	MaxShares       int64          `yaml:"max_shares"`
+	ChainID         int64          `yaml:"chain_id"`
+	RecordFile      string         `yaml:"record_file"`
+}
+
+// MarketTarget is one market (and its tokens) load_gen may submit orders
+// against.
+type MarketTarget struct {
+	EventID  int64    `yaml:"event_id"`
+	MarketID int16    `yaml:"market_id"`
+	TokenIDs []string `yaml:"token_ids"`
+}
+
+func loadConfig(path string) (*LoadGenConfig, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read load_gen config: %w", err)
+	}
+
+	var cfg LoadGenConfig
+	if err := yaml.Unmarshal(data, &cfg); err != nil {
+		return nil, fmt.Errorf("parse load_gen config: %w", err)
+	}
+
+	if cfg.AccountCount <= 0 {
+		return nil, fmt.Errorf("account_count must be > 0")
+	}
+	if len(cfg.Markets) == 0 {
+		return nil, fmt.Errorf("load_gen config has no markets")
+	}
+	if cfg.OrdersPerSecond <= 0 {
+		cfg.OrdersPerSecond = 1
+	}
+	if cfg.TotalOrders <= 0 {
+		cfg.TotalOrders = 100
+	}
+	if cfg.MinPrice == "" {
+		cfg.MinPrice = "0.1"
+	}
+	if cfg.MaxPrice == "" {
+		cfg.MaxPrice = "0.9"
+	}
+	if cfg.MinShares <= 0 {
+		cfg.MinShares = 1
+	}
+	if cfg.MaxShares < cfg.MinShares {
+		cfg.MaxShares = cfg.MinShares
+	}
+	if cfg.ChainID == 0 {
+		cfg.ChainID = 97
+	}
+
+	return &cfg, nil
+}
+
+// simulatedAccount is one load-test identity: a deterministically derived
+// key plus the API key registered for it.
+type simulatedAccount struct {
+	Index      int
+	PrivateKey *ecdsa.PrivateKey
+	Address    string
+	APIKey     string
+}
+
+// deriveAccounts derives count accounts from seed, so a run can be
+// reproduced (or extended) by reusing the same seed.
+func deriveAccounts(seed string, count int) ([]*simulatedAccount, error) {
+	accounts := make([]*simulatedAccount, 0, count)
+	for i := 0; i < count; i++ {
+		privateKey, err := deriveKey(seed, i)
+		if err != nil {
+			return nil, fmt.Errorf("derive account %d: %w", i, err)
+		}
+		address := crypto.PubkeyToAddress(privateKey.PublicKey).Hex()
+		accounts = append(accounts, &simulatedAccount{
+			Index:      i,
+			PrivateKey: privateKey,
+			Address:    address,
+			APIKey:     fmt.Sprintf("loadgen-%x-%d", crypto.Keccak256([]byte(seed))[:4], i),
+		})
+	}
+	return accounts, nil
+}
+
+// deriveKey turns seed+index into a private key via keccak256. A tiny
+// nonce loop handles the near-impossible case where the hash isn't a
+// valid secp256k1 scalar.
+func deriveKey(seed string, index int) (*ecdsa.PrivateKey, error) {
+	for nonce := 0; nonce < 16; nonce++ {
+		material := fmt.Sprintf("%s:%d:%d", seed, index, nonce)
+		hash := crypto.Keccak256([]byte(material))
+		if privateKey, err := crypto.ToECDSA(hash); err == nil {
+			return privateKey, nil
+		}
+	}
+	return nil, fmt.Errorf("could not derive a valid key for index %d after 16 attempts", index)
+}
+
+// apiKeyEventAdd matches the api_key_stream message shape produced by
+// mock_go/api_key.
+type apiKeyEventAdd struct {
+	Action  string `json:"action"`
+	ApiKey  string `json:"api_key"`
+	PrivyID string `json:"privy_id"`
+}
+
+// registerAPIKeys publishes an "add" event per account onto the
+// api_key_stream, the same path a real Privy login would trigger.
+func registerAPIKeys(ctx context.Context, accounts []*simulatedAccount) error {
+	rdb := redis.NewClient(&redis.Options{
+		Addr:     apiKeyRedisHost,
+		Password: apiKeyRedisPassword,
+		DB:       apiKeyRedisDB,
+	})
+	defer rdb.Close()
+
+	for _, account := range accounts {
+		event := apiKeyEventAdd{Action: "add", ApiKey: account.APIKey, PrivyID: account.Address}
+		payload, err := json.Marshal(event)
+		if err != nil {
+			return fmt.Errorf("marshal api key event: %w", err)
+		}
+		if err := rdb.XAdd(ctx, &redis.XAddArgs{
+			Stream: apiKeyStream,
+			Values: map[string]interface{}{apiKeyMsgKey: string(payload)},
+		}).Err(); err != nil {
+			return fmt.Errorf("register api key for account %d: %w", account.Index, err)
+		}
+	}
+	return nil
+}
+
+// authenticateAccount confirms a freshly registered API key is already
+// accepted by the API before load_gen starts hammering it with orders.
+func authenticateAccount(account *simulatedAccount) error {
+	req, err := http.NewRequest("POST", APIBaseURL+"/cancel_all_orders", nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("x-api-key", account.APIKey)
+
+	client := &http.Client{Timeout: 10 * time.Second}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 500 {
+		return fmt.Errorf("account %d auth check failed: status %d", account.Index, resp.StatusCode)
+	}
+	return nil
+}
+
+// orderInput is the string-encoded EIP-712 order payload, mirroring
+// eip712.OrderInput.
+type orderInput struct {
+	Salt          string
+	Maker         string
+	Signer        string
+	Taker         string
+	TokenId       string
+	MakerAmount   string
+	TakerAmount   string
+	Expiration    string
+	Nonce         string
+	FeeRateBps    string
+	Side          int
+	SignatureType int
+}
+
+var orderTypedDataTypes = apitypes.Types{
+	"EIP712Domain": {
+		{Name: "name", Type: "string"},
+		{Name: "version", Type: "string"},
+		{Name: "chainId", Type: "uint256"},
+	},
+	"Order": {
+		{Name: "salt", Type: "uint256"},
+		{Name: "maker", Type: "address"},
+		{Name: "signer", Type: "address"},
+		{Name: "taker", Type: "address"},
+		{Name: "tokenId", Type: "uint256"},
+		{Name: "makerAmount", Type: "uint256"},
+		{Name: "takerAmount", Type: "uint256"},
+		{Name: "expiration", Type: "uint256"},
+		{Name: "nonce", Type: "uint256"},
+		{Name: "feeRateBps", Type: "uint256"},
+		{Name: "side", Type: "uint8"},
+		{Name: "signatureType", Type: "uint8"},
+	},
+}
+
+// signOrder signs input under the CTF Exchange's EIP-712 domain.
+func signOrder(privateKey *ecdsa.PrivateKey, chainID int64, input orderInput) (string, error) {
+	typedData := apitypes.TypedData{
+		Types:       orderTypedDataTypes,
+		PrimaryType: "Order",
+		Domain: apitypes.TypedDataDomain{
+			Name:              "Sidekick Predict CTF Exchange",
+			Version:           "1",
+			ChainId:           (*gethmath.HexOrDecimal256)(big.NewInt(chainID)),
+			VerifyingContract: CTFExchangeAddress,
+		},
+		Message: apitypes.TypedDataMessage{
+			"salt":          input.Salt,
+			"maker":         input.Maker,
+			"signer":        input.Signer,
+			"taker":         input.Taker,
+			"tokenId":       input.TokenId,
+			"makerAmount":   input.MakerAmount,
+			"takerAmount":   input.TakerAmount,
+			"expiration":    input.Expiration,
+			"nonce":         input.Nonce,
+			"feeRateBps":    input.FeeRateBps,
+			"side":          fmt.Sprintf("%d", input.Side),
+			"signatureType": fmt.Sprintf("%d", input.SignatureType),
+		},
+	}
+
+	hash, _, err := apitypes.TypedDataAndHash(typedData)
+	if err != nil {
+		return "", fmt.Errorf("hash typed data: %w", err)
+	}
+
+	signature, err := crypto.Sign(hash, privateKey)
+	if err != nil {
+		return "", fmt.Errorf("sign order: %w", err)
+	}
+	signature[64] += 27 // v: 0/1 -> 27/28 per Ethereum convention
+
+	return hexutil.Encode(signature), nil
+}
+
+// placeOrderRequest mirrors bot_go's PlaceOrderRequest wire format.
+type placeOrderRequest struct {
+	Expiration    string `json:"expiration"`
+	FeeRateBps    string `json:"feeRateBps"`
+	Maker         string `json:"maker"`
+	MakerAmount   string `json:"makerAmount"`
+	Nonce         string `json:"nonce"`
+	Salt          int64  `json:"salt"`
+	Side          string `json:"side"`
+	Signature     string `json:"signature"`
+	SignatureType int    `json:"signatureType"`
+	Signer        string `json:"signer"`
+	Taker         string `json:"taker"`
+	TakerAmount   string `json:"takerAmount"`
+	TokenId       string `json:"tokenId"`
+	EventID       int64  `json:"event_id"`
+	MarketID      int16  `json:"market_id"`
+	Price         string `json:"price"`
+	OrderType     string `json:"order_type"`
+}
+
+type placeOrderResponse struct {
+	Code int    `json:"code"`
+	Msg  string `json:"msg"`
+	Data string `json:"data"`
+}
+
+// recordedEntry is one line of a load_gen recording: enough of the
+// logical order to rebuild and re-sign it later via replay, plus the
+// outcome observed at record time.
+type recordedEntry struct {
+	Timestamp    string `json:"timestamp"`
+	AccountIndex int    `json:"account_index"`
+	EventID      int64  `json:"event_id"`
+	MarketID     int16  `json:"market_id"`
+	TokenID      string `json:"token_id"`
+	Price        string `json:"price"`
+	Shares       int64  `json:"shares"`
+	LatencyMs    int64  `json:"latency_ms"`
+	Response     string `json:"response,omitempty"`
+	Error        string `json:"error,omitempty"`
+}
+
+// recorder appends recordedEntry lines to a load_gen recording file. A
+// nil recorder is valid and simply drops entries, so recording stays
+// opt-in via LoadGenConfig.RecordFile.
+type recorder struct {
+	file *os.File
+}
+
+func newRecorder(path string) (*recorder, error) {
+	if path == "" {
+		return nil, nil
+	}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("open record file: %w", err)
+	}
+	return &recorder{file: f}, nil
+}
+
+func (r *recorder) record(entry recordedEntry) {
+	if r == nil {
+		return
+	}
+	line, err := json.Marshal(entry)
+	if err != nil {
+		log.Printf("⚠️  encode recorded entry: %v", err)
+		return
+	}
+	if _, err := r.file.Write(append(line, '\n')); err != nil {
+		log.Printf("⚠️  write recorded entry: %v", err)
+	}
+}
+
+func (r *recorder) Close() {
+	if r == nil {
+		return
+	}
+	r.file.Close()
+}
+
+// buildOrder signs a fresh order for account against market/tokenID at
+// price/shares, minting a new salt each call. Both randomOrder and
+// replay funnel through this so a replayed order always carries a valid,
+// non-reused signature instead of the one captured at record time.
+func buildOrder(cfg *LoadGenConfig, account *simulatedAccount, market MarketTarget, tokenID string, price decimal.Decimal, shares int64) (*placeOrderRequest, error) {
+	unit := decimal.NewFromInt(10).Pow(decimal.NewFromInt(18))
+	takerAmount := decimal.NewFromInt(shares).Mul(unit).String()
+	makerAmount := decimal.NewFromInt(shares).Mul(price).Mul(unit).String()
+	salt := time.Now().UnixNano()
+
+	input := orderInput{
+		Salt:          fmt.Sprintf("%d", salt),
+		Maker:         account.Address,
+		Signer:        account.Address,
+		Taker:         "0x0000000000000000000000000000000000000000",
+		TokenId:       tokenID,
+		MakerAmount:   makerAmount,
+		TakerAmount:   takerAmount,
+		Expiration:    "0",
+		Nonce:         "0",
+		FeeRateBps:    "0",
+		Side:          0, // buy
+		SignatureType: 0,
+	}
+
+	signature, err := signOrder(account.PrivateKey, cfg.ChainID, input)
+	if err != nil {
+		return nil, err
+	}
+
+	return &placeOrderRequest{
+		Expiration:    "0",
+		FeeRateBps:    "0",
+		Maker:         account.Address,
+		MakerAmount:   makerAmount,
+		Nonce:         "0",
+		Salt:          salt,
+		Side:          "buy",
+		Signature:     signature,
+		SignatureType: 0,
+		Signer:        account.Address,
+		Taker:         "0x0000000000000000000000000000000000000000",
+		TakerAmount:   takerAmount,
+		TokenId:       tokenID,
+		EventID:       market.EventID,
+		MarketID:      market.MarketID,
+		Price:         price.String(),
+		OrderType:     "limit",
+	}, nil
+}
+
+// randomOrder builds one randomized, fully signed buy order for account
+// against a randomly chosen market/token from cfg, returning the market
+// and share count picked alongside the order so callers can record them.
+func randomOrder(cfg *LoadGenConfig, account *simulatedAccount, rng *rand.Rand) (*placeOrderRequest, MarketTarget, string, int64, error) {
+	market := cfg.Markets[rng.Intn(len(cfg.Markets))]
+	tokenID := market.TokenIDs[rng.Intn(len(market.TokenIDs))]
+
+	minPrice, err := decimal.NewFromString(cfg.MinPrice)
+	if err != nil {
+		return nil, market, tokenID, 0, fmt.Errorf("invalid min_price: %w", err)
+	}
+	maxPrice, err := decimal.NewFromString(cfg.MaxPrice)
+	if err != nil {
+		return nil, market, tokenID, 0, fmt.Errorf("invalid max_price: %w", err)
+	}
+	price := minPrice.Add(maxPrice.Sub(minPrice).Mul(decimal.NewFromFloat(rng.Float64()))).Truncate(4)
+
+	shares := cfg.MinShares
+	if cfg.MaxShares > cfg.MinShares {
+		shares += rng.Int63n(cfg.MaxShares - cfg.MinShares + 1)
+	}
+
+	order, err := buildOrder(cfg, account, market, tokenID, price, shares)
+	return order, market, tokenID, shares, err
+}
+
+// submitOrder places order under account and returns the raw response
+// body (for recording) plus an error if the API rejected it.
+func submitOrder(account *simulatedAccount, order *placeOrderRequest) (string, error) {
+	body, err := json.Marshal(order)
+	if err != nil {
+		return "", err
+	}
+
+	req, err := http.NewRequest("POST", APIBaseURL+"/place_order", bytes.NewReader(body))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("x-api-key", account.APIKey)
+
+	client := &http.Client{Timeout: 15 * time.Second}
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", err
+	}
+
+	var result placeOrderResponse
+	if err := json.Unmarshal(respBody, &result); err != nil {
+		return string(respBody), fmt.Errorf("parse response failed: %v, body: %s", err, string(respBody))
+	}
+	if result.Code != 0 {
+		return string(respBody), fmt.Errorf("place order failed: %s", result.Msg)
+	}
+	return string(respBody), nil
+}
+
+// setup loads cfg, derives accounts, registers their API keys, and warms
+// up auth - the common prelude shared by run and replay.
+func setup(configPath string) (*LoadGenConfig, []*simulatedAccount, error) {
+	cfg, err := loadConfig(configPath)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	accounts, err := deriveAccounts(cfg.Seed, cfg.AccountCount)
+	if err != nil {
+		return nil, nil, fmt.Errorf("derive accounts: %w", err)
+	}
+	log.Printf("Derived %d simulated accounts from seed", len(accounts))
+
+	ctx := context.Background()
+	if err := registerAPIKeys(ctx, accounts); err != nil {
+		return nil, nil, fmt.Errorf("register api keys: %w", err)
+	}
+	log.Printf("Registered %d API keys", len(accounts))
+
+	for _, account := range accounts {
+		if err := authenticateAccount(account); err != nil {
+			log.Printf("⚠️  account %d not yet authenticated: %v", account.Index, err)
+		}
+	}
+
+	return cfg, accounts, nil
+}
+
+// runLoadGen submits randomized orders at cfg's configured rate/volume,
+// recording each attempt if cfg.RecordFile is set.
+func runLoadGen(cfg *LoadGenConfig, accounts []*simulatedAccount) {
+	rec, err := newRecorder(cfg.RecordFile)
+	if err != nil {
+		log.Fatalf("❌ %v", err)
+	}
+	defer rec.Close()
+
+	rng := rand.New(rand.NewSource(int64(len(cfg.Seed))))
+	interval := time.Duration(float64(time.Second) / cfg.OrdersPerSecond)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	log.Printf("Submitting %d orders at %.2f orders/sec across %d markets", cfg.TotalOrders, cfg.OrdersPerSecond, len(cfg.Markets))
+
+	placed, failed := 0, 0
+	for i := 0; i < cfg.TotalOrders; i++ {
+		<-ticker.C
+
+		account := accounts[rng.Intn(len(accounts))]
+
+		order, market, tokenID, shares, err := randomOrder(cfg, account, rng)
+		if err != nil {
+			log.Printf("⚠️  build order failed: %v", err)
+			failed++
+			continue
+		}
+
+		start := time.Now()
+		respBody, err := submitOrder(account, order)
+		latency := time.Since(start)
+
+		entry := recordedEntry{
+			Timestamp:    start.Format(time.RFC3339Nano),
+			AccountIndex: account.Index,
+			EventID:      market.EventID,
+			MarketID:     market.MarketID,
+			TokenID:      tokenID,
+			Price:        order.Price,
+			Shares:       shares,
+			LatencyMs:    latency.Milliseconds(),
+			Response:     respBody,
+		}
+		if err != nil {
+			entry.Error = err.Error()
+			log.Printf("⚠️  account %d order failed: %v", account.Index, err)
+			failed++
+			rec.record(entry)
+			continue
+		}
+		rec.record(entry)
+		placed++
+	}
+
+	log.Printf("Done: placed=%d failed=%d", placed, failed)
+}
+
+// runReplay re-submits every entry from a prior recording against cfg,
+// with a fresh salt and signature per order, for regression-testing
+// engine changes against a captured flow.
+func runReplay(cfg *LoadGenConfig, accounts []*simulatedAccount, recordingPath string) {
+	data, err := os.ReadFile(recordingPath)
+	if err != nil {
+		log.Fatalf("❌ read recording: %v", err)
+	}
+
+	rec, err := newRecorder(cfg.RecordFile)
+	if err != nil {
+		log.Fatalf("❌ %v", err)
+	}
+	defer rec.Close()
+
+	accountByIndex := make(map[int]*simulatedAccount, len(accounts))
+	for _, account := range accounts {
+		accountByIndex[account.Index] = account
+	}
+
+	lines := bytes.Split(bytes.TrimSpace(data), []byte("\n"))
+	log.Printf("Replaying %d recorded orders", len(lines))
+
+	replayed, failed := 0, 0
+	for _, line := range lines {
+		if len(bytes.TrimSpace(line)) == 0 {
+			continue
+		}
+
+		var original recordedEntry
+		if err := json.Unmarshal(line, &original); err != nil {
+			log.Printf("⚠️  skip malformed recording line: %v", err)
+			failed++
+			continue
+		}
+
+		account, ok := accountByIndex[original.AccountIndex]
+		if !ok {
+			log.Printf("⚠️  skip entry for unknown account index %d", original.AccountIndex)
+			failed++
+			continue
+		}
+
+		price, err := decimal.NewFromString(original.Price)
+		if err != nil {
+			log.Printf("⚠️  skip entry with bad price %q: %v", original.Price, err)
+			failed++
+			continue
+		}
+		market := MarketTarget{EventID: original.EventID, MarketID: original.MarketID, TokenIDs: []string{original.TokenID}}
+
+		order, err := buildOrder(cfg, account, market, original.TokenID, price, original.Shares)
+		if err != nil {
+			log.Printf("⚠️  rebuild order failed: %v", err)
+			failed++
+			continue
+		}
+
+		start := time.Now()
+		respBody, err := submitOrder(account, order)
+		latency := time.Since(start)
+
+		entry := recordedEntry{
+			Timestamp:    start.Format(time.RFC3339Nano),
+			AccountIndex: account.Index,
+			EventID:      market.EventID,
+			MarketID:     market.MarketID,
+			TokenID:      original.TokenID,
+			Price:        order.Price,
+			Shares:       original.Shares,
+			LatencyMs:    latency.Milliseconds(),
+			Response:     respBody,
+		}
+		if err != nil {
+			entry.Error = err.Error()
+			log.Printf("⚠️  replay of account %d order failed: %v", account.Index, err)
+			failed++
+			rec.record(entry)
+			continue
+		}
+		rec.record(entry)
+		replayed++
+	}
+
+	log.Printf("Replay done: replayed=%d failed=%d", replayed, failed)
+}
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Println("Usage: go run . <load_gen.yaml>")
+		fmt.Println("       go run . replay <recording.jsonl> <load_gen.yaml>")
+		os.Exit(1)
+	}
+
+	if os.Args[1] == "replay" {
+		if len(os.Args) < 4 {
+			fmt.Println("Usage: go run . replay <recording.jsonl> <load_gen.yaml>")
+			os.Exit(1)
+		}
+		cfg, accounts, err := setup(os.Args[3])
+		if err != nil {
+			log.Fatalf("❌ %v", err)
+		}
+		runReplay(cfg, accounts, os.Args[2])
+		return
+	}
+
+	cfg, accounts, err := setup(os.Args[1])
+	if err != nil {
+		log.Fatalf("❌ %v", err)
+	}
+	runLoadGen(cfg, accounts)
+}
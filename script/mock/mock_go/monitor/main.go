@@ -0,0 +1,281 @@
+// Command monitor subscribes to the depth websocket for a configured set
+// of markets and logs an alert when a book crosses/locks, when a yes/no
+// pair prices an arbitrage opportunity, or when a book goes stale. It's
+// meant to catch engine bugs and surface arb the bot should be taking,
+// not to place any orders itself.
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/shopspring/decimal"
+	"gopkg.in/yaml.v3"
+
+	"depth"
+	"mock_go/wsclient"
+)
+
+// WSHost is the depth websocket server the monitor subscribes to.
+const WSHost = "predictionmarket-websocket-depth-290128242879.asia-northeast1.run.app"
+
+// MonitorConfig describes the markets to watch and the alert thresholds.
+type MonitorConfig struct {
+	Markets           []MarketConfig `yaml:"markets"`
+	ArbThreshold      string         `yaml:"arb_threshold"`
+	StaleAfterMinutes int            `yaml:"stale_after_minutes"`
+}
+
+// MarketConfig identifies one market and its yes/no token IDs, so the
+// monitor can check the cross-token arb condition.
+type MarketConfig struct {
+	EventID    int64  `yaml:"event_id"`
+	MarketID   int16  `yaml:"market_id"`
+	YesTokenID string `yaml:"yes_token_id"`
+	NoTokenID  string `yaml:"no_token_id"`
+}
+
+// depthSubscribeMessage matches websocket_depth's subscribe protocol.
+type depthSubscribeMessage struct {
+	Action   string `json:"action"`
+	EventID  int64  `json:"event_id"`
+	MarketID int16  `json:"market_id"`
+}
+
+// depthPush is the shape of a pushed depth update for one market.
+type depthPush struct {
+	EventID  int64                 `json:"event_id"`
+	MarketID int16                 `json:"market_id"`
+	Depths   map[string]depth.Book `json:"depths"`
+}
+
+// marketState tracks when a market's book was last seen updated, so
+// staleness can be detected independently of whatever it last alerted on.
+type marketState struct {
+	mu         sync.Mutex
+	lastUpdate time.Time
+}
+
+func (s *marketState) touch() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.lastUpdate = time.Now()
+}
+
+func (s *marketState) sinceLastUpdate() time.Duration {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.lastUpdate.IsZero() {
+		return 0
+	}
+	return time.Since(s.lastUpdate)
+}
+
+// loadMonitorConfig reads and validates the monitor's YAML config file.
+func loadMonitorConfig(path string) (*MonitorConfig, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read monitor config: %w", err)
+	}
+
+	var cfg MonitorConfig
+	if err := yaml.Unmarshal(data, &cfg); err != nil {
+		return nil, fmt.Errorf("parse monitor config: %w", err)
+	}
+	if len(cfg.Markets) == 0 {
+		return nil, fmt.Errorf("monitor config has no markets")
+	}
+	if cfg.ArbThreshold == "" {
+		cfg.ArbThreshold = "0.02"
+	}
+	if cfg.StaleAfterMinutes <= 0 {
+		cfg.StaleAfterMinutes = 5
+	}
+
+	return &cfg, nil
+}
+
+func marketKey(eventID int64, marketID int16) string {
+	return fmt.Sprintf("%d/%d", eventID, marketID)
+}
+
+// checkMarket inspects a freshly pushed book for the three alert
+// conditions: a crossed/locked book, and (once both sides are known) a
+// yes+no best-bid sum past the arb threshold.
+func checkMarket(market MarketConfig, book depth.Book, side string, arbThreshold decimal.Decimal, yesBid, noBid *decimal.Decimal) {
+	crossed, err := book.IsCrossed()
+	if err != nil {
+		log.Printf("⚠️  [%d/%d %s] could not evaluate crossed book: %v", market.EventID, market.MarketID, side, err)
+	} else if crossed {
+		log.Printf("🚨 [%d/%d %s] CROSSED book: best bid >= best ask", market.EventID, market.MarketID, side)
+	}
+
+	locked, err := book.IsLocked()
+	if err != nil {
+		log.Printf("⚠️  [%d/%d %s] could not evaluate locked book: %v", market.EventID, market.MarketID, side, err)
+	} else if locked {
+		log.Printf("🚨 [%d/%d %s] LOCKED book: best bid == best ask", market.EventID, market.MarketID, side)
+	}
+
+	if yesBid == nil || noBid == nil {
+		return
+	}
+
+	sum := yesBid.Add(*noBid)
+	over := sum.Sub(decimal.NewFromInt(1))
+	if over.GreaterThan(arbThreshold) {
+		log.Printf("💰 [%d/%d] ARB: yes_bid+no_bid=%s exceeds 1 by %s (threshold %s)",
+			market.EventID, market.MarketID, sum.String(), over.String(), arbThreshold.String())
+	}
+}
+
+// runMarketWatch subscribes to a market's depth and dispatches every push
+// to checkMarket, tracking the best bid on each side so the arb check can
+// run once both the yes and no books have reported.
+func runMarketWatch(market MarketConfig, state *marketState, arbThreshold decimal.Decimal) func(depthPush) {
+	var mu sync.Mutex
+	var yesBid, noBid *decimal.Decimal
+
+	return func(push depthPush) {
+		state.touch()
+
+		for tokenID, book := range push.Depths {
+			var side string
+			switch tokenID {
+			case market.YesTokenID:
+				side = "yes"
+			case market.NoTokenID:
+				side = "no"
+			default:
+				continue
+			}
+
+			bid, err := bestBidDecimal(book)
+			if err != nil {
+				log.Printf("⚠️  [%d/%d %s] could not parse best bid: %v", market.EventID, market.MarketID, side, err)
+				continue
+			}
+
+			mu.Lock()
+			if side == "yes" {
+				yesBid = bid
+			} else {
+				noBid = bid
+			}
+			currentYes, currentNo := yesBid, noBid
+			mu.Unlock()
+
+			checkMarket(market, book, side, arbThreshold, currentYes, currentNo)
+		}
+	}
+}
+
+func bestBidDecimal(book depth.Book) (*decimal.Decimal, error) {
+	best := book.BestBid()
+	if best == nil {
+		return nil, nil
+	}
+	price, err := decimal.NewFromString(best.Price)
+	if err != nil {
+		return nil, fmt.Errorf("parse price %q: %w", best.Price, err)
+	}
+	return &price, nil
+}
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Println("Usage: go run . <monitor.yaml>")
+		os.Exit(1)
+	}
+
+	cfg, err := loadMonitorConfig(os.Args[1])
+	if err != nil {
+		log.Fatalf("❌ %v", err)
+	}
+
+	arbThreshold, err := decimal.NewFromString(cfg.ArbThreshold)
+	if err != nil {
+		log.Fatalf("❌ invalid arb_threshold %q: %v", cfg.ArbThreshold, err)
+	}
+
+	interrupt := make(chan os.Signal, 1)
+	signal.Notify(interrupt, os.Interrupt)
+
+	states := make(map[string]*marketState, len(cfg.Markets))
+	handlers := make(map[string]func(depthPush))
+	for _, market := range cfg.Markets {
+		key := marketKey(market.EventID, market.MarketID)
+		state := &marketState{}
+		states[key] = state
+	}
+
+	log.Printf("🔗 Connecting to wss://%s/depth", WSHost)
+
+	c, err := wsclient.Dial(wsclient.Config{
+		Scheme:            "wss",
+		Host:              WSHost,
+		Path:              "/depth",
+		HeartbeatInterval: 20 * time.Second,
+		OnMessage: func(messageType int, message []byte) {
+			var push depthPush
+			if err := json.Unmarshal(message, &push); err != nil {
+				log.Printf("⚠️  could not parse depth push: %v", err)
+				return
+			}
+
+			handler, ok := handlers[marketKey(push.EventID, push.MarketID)]
+			if !ok {
+				return
+			}
+			handler(push)
+		},
+		OnClose: func(err error) {
+			if err != nil {
+				log.Println("read error:", err)
+			}
+		},
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer c.Close()
+
+	log.Println("✅ Connected to WebSocket Depth Server")
+
+	for _, market := range cfg.Markets {
+		key := marketKey(market.EventID, market.MarketID)
+		handlers[key] = runMarketWatch(market, states[key], arbThreshold)
+
+		subscribeData, _ := json.Marshal(depthSubscribeMessage{
+			Action:   "subscribe",
+			EventID:  market.EventID,
+			MarketID: market.MarketID,
+		})
+		if err := c.Send(websocket.TextMessage, subscribeData); err != nil {
+			log.Fatalf("❌ subscribe %d/%d failed: %v", market.EventID, market.MarketID, err)
+		}
+		log.Printf("📨 Subscribed to depth: event_id=%d, market_id=%d", market.EventID, market.MarketID)
+	}
+
+	go func() {
+		staleAfter := time.Duration(cfg.StaleAfterMinutes) * time.Minute
+		ticker := time.NewTicker(time.Minute)
+		defer ticker.Stop()
+		for range ticker.C {
+			for _, market := range cfg.Markets {
+				state := states[marketKey(market.EventID, market.MarketID)]
+				if age := state.sinceLastUpdate(); age > staleAfter {
+					log.Printf("🚨 [%d/%d] STALE: no depth update for %s", market.EventID, market.MarketID, age.Round(time.Second))
+				}
+			}
+		}
+	}()
+
+	c.Run(interrupt)
+}
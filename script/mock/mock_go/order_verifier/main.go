@@ -0,0 +1,211 @@
+// order_verifier 是给 QA 用的独立命令行工具: 读入一份第三方 bot 提交的
+// PlaceOrderRequest JSON, 用 eip712 包的签名恢复 API 检查签名是否确实对应
+// maker/signer 地址, 并检查 makerAmount/takerAmount/price/side 这些字段
+// 相互之间是否自洽, 而不是真的把订单发给撮合引擎。
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"os"
+
+	"eip712"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/shopspring/decimal"
+)
+
+// placeOrderRequest mirrors bot_go's PlaceOrderRequest wire format; it's
+// redefined here (rather than imported) because bot_go is a package main in
+// a separate module, the same reason load_gen keeps its own orderInput
+// mirror of eip712.OrderInput.
+type placeOrderRequest struct {
+	Expiration    string `json:"expiration"`
+	FeeRateBps    string `json:"feeRateBps"`
+	Maker         string `json:"maker"`
+	MakerAmount   string `json:"makerAmount"`
+	Nonce         string `json:"nonce"`
+	Salt          int64  `json:"salt"`
+	Side          string `json:"side"`
+	Signature     string `json:"signature"`
+	SignatureType int    `json:"signatureType"`
+	Signer        string `json:"signer"`
+	Taker         string `json:"taker"`
+	TakerAmount   string `json:"takerAmount"`
+	TokenId       string `json:"tokenId"`
+	Price         string `json:"price"`
+	OrderType     string `json:"order_type"`
+}
+
+// amountTolerance 是 makerAmount/takerAmount 换算回 price 之后允许跟请求
+// 里的 price 字段相差的误差, 用来吸收 ToTokenUnits 在链上精度下取整产生
+// 的截断误差, 不是签名/篡改检测的容差。
+var amountTolerance = decimal.NewFromFloat(0.0001)
+
+// checkResult 记录 verifyOrder 里每一项独立检查的结果, 方便命令行按顺序
+// 打印, 而不是第一个失败就退出、看不到其余字段的检查状态。
+type checkResult struct {
+	Name   string
+	OK     bool
+	Detail string
+}
+
+func toOrderInput(req *placeOrderRequest) (*eip712.OrderInput, error) {
+	side, err := eip712.ParseSide(req.Side)
+	if err != nil {
+		return nil, err
+	}
+
+	return &eip712.OrderInput{
+		Salt:          fmt.Sprintf("%d", req.Salt),
+		Maker:         req.Maker,
+		Signer:        req.Signer,
+		Taker:         req.Taker,
+		TokenId:       req.TokenId,
+		MakerAmount:   req.MakerAmount,
+		TakerAmount:   req.TakerAmount,
+		Expiration:    req.Expiration,
+		Nonce:         req.Nonce,
+		FeeRateBps:    req.FeeRateBps,
+		Side:          int(side),
+		SignatureType: req.SignatureType,
+	}, nil
+}
+
+// verifySignature checks the order input is well-formed, then recovers the
+// signer that actually produced req.Signature and checks it against the
+// maker/signer addresses the request claims.
+func verifySignature(req *placeOrderRequest, chainID int) []checkResult {
+	var results []checkResult
+
+	input, err := toOrderInput(req)
+	if err != nil {
+		return []checkResult{{Name: "well-formed", OK: false, Detail: err.Error()}}
+	}
+	if err := eip712.ValidateOrderInput(input); err != nil {
+		results = append(results, checkResult{Name: "well-formed", OK: false, Detail: err.Error()})
+	} else {
+		results = append(results, checkResult{Name: "well-formed", OK: true, Detail: "numeric fields and addresses are well-formed"})
+	}
+
+	recovered, err := eip712.VerifyOrderInputSignature(chainID, input, req.Signature)
+	if err != nil {
+		return append(results, checkResult{Name: "signature", OK: false, Detail: fmt.Sprintf("failed to recover signer: %v", err)})
+	}
+
+	results = append(results, checkResult{Name: "signature", OK: true, Detail: fmt.Sprintf("recovered %s", recovered.Hex())})
+
+	if !common.IsHexAddress(req.Maker) {
+		results = append(results, checkResult{Name: "maker matches signature", OK: false, Detail: fmt.Sprintf("maker %q is not a valid address", req.Maker)})
+	} else {
+		matches := recovered == common.HexToAddress(req.Maker)
+		results = append(results, checkResult{Name: "maker matches signature", OK: matches, Detail: fmt.Sprintf("maker=%s recovered=%s", common.HexToAddress(req.Maker).Hex(), recovered.Hex())})
+	}
+
+	if !common.IsHexAddress(req.Signer) {
+		results = append(results, checkResult{Name: "signer matches signature", OK: false, Detail: fmt.Sprintf("signer %q is not a valid address", req.Signer)})
+	} else {
+		matches := recovered == common.HexToAddress(req.Signer)
+		results = append(results, checkResult{Name: "signer matches signature", OK: matches, Detail: fmt.Sprintf("signer=%s recovered=%s", common.HexToAddress(req.Signer).Hex(), recovered.Hex())})
+	}
+
+	return results
+}
+
+// verifyAmounts checks that makerAmount/takerAmount, both raw 18-decimal
+// token units (see units.MakerAmountUnits/TakerAmountUnits in bot_go),
+// divide back out to the price the request separately claims, and that
+// side is one of the two values the bot ever produces.
+func verifyAmounts(req *placeOrderRequest) []checkResult {
+	var results []checkResult
+
+	if req.Side != "buy" && req.Side != "sell" {
+		results = append(results, checkResult{Name: "side", OK: false, Detail: fmt.Sprintf("side %q is neither \"buy\" nor \"sell\"", req.Side)})
+	} else {
+		results = append(results, checkResult{Name: "side", OK: true, Detail: req.Side})
+	}
+
+	makerAmount, makerOK := new(big.Int).SetString(req.MakerAmount, 10)
+	takerAmount, takerOK := new(big.Int).SetString(req.TakerAmount, 10)
+	price, priceErr := decimal.NewFromString(req.Price)
+
+	if !makerOK || !takerOK || priceErr != nil {
+		results = append(results, checkResult{Name: "makerAmount/takerAmount/price consistent", OK: false, Detail: "one or more of makerAmount, takerAmount, price is not a valid number"})
+		return results
+	}
+	if takerAmount.Sign() == 0 {
+		results = append(results, checkResult{Name: "makerAmount/takerAmount/price consistent", OK: false, Detail: "takerAmount is zero"})
+		return results
+	}
+
+	// makerAmount and takerAmount are both scaled by 10^18, so the scaling
+	// cancels out of the ratio and it can be compared directly to price.
+	impliedPrice := decimal.NewFromBigInt(makerAmount, 0).Div(decimal.NewFromBigInt(takerAmount, 0))
+	diff := impliedPrice.Sub(price).Abs()
+
+	ok := diff.LessThanOrEqual(amountTolerance)
+	results = append(results, checkResult{
+		Name:   "makerAmount/takerAmount/price consistent",
+		OK:     ok,
+		Detail: fmt.Sprintf("price=%s implied=%s diff=%s", price.String(), impliedPrice.String(), diff.String()),
+	})
+
+	return results
+}
+
+func verifyOrder(req *placeOrderRequest, chainID int) []checkResult {
+	results := verifySignature(req, chainID)
+	results = append(results, verifyAmounts(req)...)
+	return results
+}
+
+func printUsage() {
+	fmt.Println("Usage:")
+	fmt.Println("  go run . <order.json> [chain_id]  - Verify a PlaceOrderRequest JSON file")
+	fmt.Println()
+	fmt.Println("chain_id defaults to eip712.EVMChainID (56) if omitted.")
+}
+
+func main() {
+	if len(os.Args) < 2 {
+		printUsage()
+		os.Exit(1)
+	}
+
+	data, err := os.ReadFile(os.Args[1])
+	if err != nil {
+		fmt.Printf("Error: failed to read %s: %v\n", os.Args[1], err)
+		os.Exit(1)
+	}
+
+	var req placeOrderRequest
+	if err := json.Unmarshal(data, &req); err != nil {
+		fmt.Printf("Error: failed to parse order JSON: %v\n", err)
+		os.Exit(1)
+	}
+
+	chainID := eip712.EVMChainID
+	if len(os.Args) >= 3 {
+		if _, err := fmt.Sscanf(os.Args[2], "%d", &chainID); err != nil {
+			fmt.Printf("Error: invalid chain_id %q: %v\n", os.Args[2], err)
+			os.Exit(1)
+		}
+	}
+
+	results := verifyOrder(&req, chainID)
+
+	allOK := true
+	for _, r := range results {
+		status := "OK"
+		if !r.OK {
+			status = "FAIL"
+			allOK = false
+		}
+		fmt.Printf("[%s] %s: %s\n", status, r.Name, r.Detail)
+	}
+
+	if !allOK {
+		os.Exit(1)
+	}
+}
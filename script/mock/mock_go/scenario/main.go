@@ -0,0 +1,100 @@
+// Command scenario orchestrates an end-to-end flow across the mock tools
+// (send_event, api_key, trade_responder, websocket_user, ...) and the bot,
+// so reproducing a full maker/taker/settlement cycle doesn't require
+// manually running five different tools in the right order.
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"os/exec"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ScenarioFile describes an ordered sequence of steps to run against a
+// chosen environment.
+type ScenarioFile struct {
+	Environment string `yaml:"environment"`
+	Steps       []Step `yaml:"steps"`
+}
+
+// Step runs a single command as part of the scenario. Dir is relative to
+// the repository root (the directory containing `go.mod` for that tool),
+// and the command itself is invoked as `go run . <args...>`.
+type Step struct {
+	Name           string   `yaml:"name"`
+	Dir            string   `yaml:"dir"`
+	Args           []string `yaml:"args"`
+	TimeoutSeconds int      `yaml:"timeout_seconds"`
+}
+
+// loadScenarioFile reads and parses the scenario YAML file.
+func loadScenarioFile(path string) (*ScenarioFile, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read scenario file: %w", err)
+	}
+
+	var sf ScenarioFile
+	if err := yaml.Unmarshal(data, &sf); err != nil {
+		return nil, fmt.Errorf("parse scenario file: %w", err)
+	}
+	if len(sf.Steps) == 0 {
+		return nil, fmt.Errorf("scenario has no steps")
+	}
+
+	return &sf, nil
+}
+
+// runStep executes a single step via `go run .` in its directory, streaming
+// output to the parent process and enforcing a per-step timeout.
+func runStep(step Step) error {
+	timeout := time.Duration(step.TimeoutSeconds) * time.Second
+	if timeout <= 0 {
+		timeout = 60 * time.Second
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	args := append([]string{"run", "."}, step.Args...)
+	cmd := exec.CommandContext(ctx, "go", args...)
+	cmd.Dir = step.Dir
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+
+	log.Printf("▶️  [%s] running in %s: go %v", step.Name, step.Dir, args)
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("step %q failed: %w", step.Name, err)
+	}
+
+	log.Printf("✅ [%s] completed", step.Name)
+	return nil
+}
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Println("Usage: go run . <scenario.yaml>")
+		os.Exit(1)
+	}
+
+	scenarioPath := os.Args[1]
+	sf, err := loadScenarioFile(scenarioPath)
+	if err != nil {
+		log.Fatalf("❌ %v", err)
+	}
+
+	log.Printf("🚀 Running scenario against environment=%q (%d steps)", sf.Environment, len(sf.Steps))
+
+	for i, step := range sf.Steps {
+		if err := runStep(step); err != nil {
+			log.Fatalf("❌ scenario aborted at step %d/%d: %v", i+1, len(sf.Steps), err)
+		}
+	}
+
+	log.Println("🎉 Scenario completed successfully")
+}
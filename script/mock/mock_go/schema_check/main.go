@@ -0,0 +1,58 @@
+// schema_check 是给 QA/迁移用的独立命令行工具: 读入一份从生产环境抓下来的
+// stream 消息 JSON 样本, 按 -type 指定的消息类型跑 streamtypes/schema 里
+// 登记的 JSON Schema 校验, 用来在改 streamtypes 之前确认线上真实消息没有
+// 悄悄漂移出当前的 schema。
+package main
+
+import (
+	"fmt"
+	"os"
+	"sort"
+
+	"mock_go/streamtypes/schema"
+)
+
+func printUsage() {
+	fmt.Println("Usage:")
+	fmt.Println("  go run . -type <message_type> <sample.json>  - Validate a sample message against its schema")
+	fmt.Println()
+	fmt.Println("Known message types:")
+	types := make([]string, 0, len(schema.ByMessageType))
+	for t := range schema.ByMessageType {
+		types = append(types, t)
+	}
+	sort.Strings(types)
+	for _, t := range types {
+		fmt.Printf("  %s\n", t)
+	}
+}
+
+func main() {
+	if len(os.Args) != 4 || os.Args[1] != "-type" {
+		printUsage()
+		os.Exit(1)
+	}
+
+	messageType := os.Args[2]
+	path := os.Args[3]
+
+	s, ok := schema.ByMessageType[messageType]
+	if !ok {
+		fmt.Printf("Error: unknown message type %q\n", messageType)
+		printUsage()
+		os.Exit(1)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		fmt.Printf("Error: failed to read %s: %v\n", path, err)
+		os.Exit(1)
+	}
+
+	if err := schema.Validate(s, data); err != nil {
+		fmt.Printf("[FAIL] %s does not conform to %s schema: %v\n", path, messageType, err)
+		os.Exit(1)
+	}
+
+	fmt.Printf("[OK] %s conforms to %s schema\n", path, messageType)
+}
@@ -0,0 +1,267 @@
+package main
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"ctf"
+	"github.com/jackc/pgx/v4/pgxpool"
+	"gopkg.in/yaml.v3"
+)
+
+// MarketDefinition 是 `create --file` 里一个市场的手写定义, 不需要填
+// condition_id/parent_collection_id/token_ids 之类链上字段, 这些都由
+// buildEventFromDefinition 生成。
+type MarketDefinition struct {
+	Slug     string   `yaml:"slug" csv:"market_slug"`
+	Question string   `yaml:"question" csv:"question"`
+	Image    string   `yaml:"image,omitempty" csv:"image"`
+	Outcomes []string `yaml:"outcomes" csv:"-"`
+}
+
+// EventDefinition 是 `create --file` 里一个事件的手写定义, 字段跟
+// events.go 的 Event 一一对应, 但 slug 之外的标识符 (event_identifier、
+// market_identifier、token_ids) 都由工具生成, 不需要非工程同学自己编。
+type EventDefinition struct {
+	Slug        string             `yaml:"slug"`
+	Title       string             `yaml:"title"`
+	Description string             `yaml:"description,omitempty"`
+	Image       string             `yaml:"image,omitempty"`
+	EndDate     string             `yaml:"end_date,omitempty"`
+	Topic       string             `yaml:"topic,omitempty"`
+	Markets     []MarketDefinition `yaml:"markets"`
+}
+
+// eventDefinitionsFile 是 YAML 文件的顶层结构: 一份文件里可以定义多个
+// 事件, 内容团队一次提交批量生效。
+type eventDefinitionsFile struct {
+	Events []EventDefinition `yaml:"events"`
+}
+
+// LoadEventDefinitions 按文件扩展名选择 YAML 还是 CSV 解析, 是
+// `send_event create --file <path>` 的入口。
+func LoadEventDefinitions(path string) ([]EventDefinition, error) {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		return loadEventDefinitionsYAML(path)
+	case ".csv":
+		return loadEventDefinitionsCSV(path)
+	default:
+		return nil, fmt.Errorf("unsupported event definition file extension %q, expected .yaml/.yml/.csv", filepath.Ext(path))
+	}
+}
+
+func loadEventDefinitionsYAML(path string) ([]EventDefinition, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read %s: %w", path, err)
+	}
+
+	var file eventDefinitionsFile
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("parse %s as YAML: %w", path, err)
+	}
+	return file.Events, nil
+}
+
+// loadEventDefinitionsCSV 解析一份扁平的 CSV: 每行是一个市场的一个
+// outcome, 表头为 event_slug,event_title,end_date,market_slug,question,outcome。
+// 同一个 event_slug/market_slug 的多行会被合并成一个市场的多个 outcomes,
+// 这是给只需要简单 Yes/No 或几个选项的事件用的, 复杂字段 (description、
+// image、topic) 用 YAML 格式填。
+func loadEventDefinitionsCSV(path string) ([]EventDefinition, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("open %s: %w", path, err)
+	}
+	defer f.Close()
+
+	reader := csv.NewReader(f)
+	rows, err := reader.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("parse %s as CSV: %w", path, err)
+	}
+	if len(rows) < 2 {
+		return nil, fmt.Errorf("%s has no data rows", path)
+	}
+
+	header := rows[0]
+	col := make(map[string]int, len(header))
+	for i, name := range header {
+		col[strings.TrimSpace(name)] = i
+	}
+	required := []string{"event_slug", "event_title", "market_slug", "question", "outcome"}
+	for _, name := range required {
+		if _, ok := col[name]; !ok {
+			return nil, fmt.Errorf("%s is missing required column %q", path, name)
+		}
+	}
+	get := func(row []string, name string) string {
+		if i, ok := col[name]; ok && i < len(row) {
+			return strings.TrimSpace(row[i])
+		}
+		return ""
+	}
+
+	eventOrder := []string{}
+	events := make(map[string]*EventDefinition)
+	marketOrder := make(map[string][]string)
+	markets := make(map[string]*MarketDefinition)
+
+	for _, row := range rows[1:] {
+		eventSlug := get(row, "event_slug")
+		marketSlug := get(row, "market_slug")
+		if eventSlug == "" || marketSlug == "" {
+			continue
+		}
+
+		if _, ok := events[eventSlug]; !ok {
+			events[eventSlug] = &EventDefinition{
+				Slug:    eventSlug,
+				Title:   get(row, "event_title"),
+				EndDate: get(row, "end_date"),
+			}
+			eventOrder = append(eventOrder, eventSlug)
+		}
+
+		marketKey := eventSlug + "/" + marketSlug
+		if _, ok := markets[marketKey]; !ok {
+			markets[marketKey] = &MarketDefinition{Slug: marketSlug, Question: get(row, "question")}
+			marketOrder[eventSlug] = append(marketOrder[eventSlug], marketKey)
+		}
+		markets[marketKey].Outcomes = append(markets[marketKey].Outcomes, get(row, "outcome"))
+	}
+
+	definitions := make([]EventDefinition, 0, len(eventOrder))
+	for _, eventSlug := range eventOrder {
+		event := events[eventSlug]
+		for _, marketKey := range marketOrder[eventSlug] {
+			event.Markets = append(event.Markets, *markets[marketKey])
+		}
+		definitions = append(definitions, *event)
+	}
+	return definitions, nil
+}
+
+// buildEventFromDefinition 把手写定义转换成我们自己的 Event/EventMarket
+// schema, event identifier 前缀标出来源 (这里是 "manual-" 而不是
+// "polymarket-"), 方便事后区分一个事件是导入的还是手写的。这类市场还没有
+// 一个真实 oracle 报告过的 questionId, 所以 conditionId 用
+// ctf.QuestionIDFromSlug(marketSlug) 派生的合成 questionId 算出来 (跟
+// oracleAddress 绑定); 一旦真的对着这个 oracle/questionId/outcomeSlotCount
+// 调用过 prepareCondition, 这里生成的 token_id 就是合约认得的那个, 跟
+// mapGammaEvent 对已有 conditionId 的市场走的是同一套 ctf 派生逻辑。
+func buildEventFromDefinition(def EventDefinition) (Event, error) {
+	if def.Slug == "" {
+		return Event{}, fmt.Errorf("event definition is missing slug")
+	}
+	if len(def.Markets) == 0 {
+		return Event{}, fmt.Errorf("event %q has no markets", def.Slug)
+	}
+
+	eventIdentifier := "manual-" + def.Slug
+
+	var endDate *time.Time
+	if def.EndDate != "" {
+		parsed, err := parseDefinitionEndDate(def.EndDate)
+		if err != nil {
+			return Event{}, fmt.Errorf("event %q: %w", def.Slug, err)
+		}
+		endDate = parsed
+	}
+
+	topic := def.Topic
+	if topic == "" {
+		topic = "manual-import"
+	}
+
+	markets := make(map[string]EventMarket, len(def.Markets))
+	for i, m := range def.Markets {
+		if len(m.Outcomes) == 0 {
+			return Event{}, fmt.Errorf("event %q market %q has no outcomes", def.Slug, m.Slug)
+		}
+
+		questionID := ctf.QuestionIDFromSlug(eventIdentifier + "-" + m.Slug)
+		conditionID := ctf.ConditionID(oracleAddress, questionID, uint64(len(m.Outcomes)))
+
+		tokenIDs := make([]string, len(m.Outcomes))
+		for j := range m.Outcomes {
+			tokenIDs[j] = ctf.DerivePositionID(conditionID, collateralTokenAddress, uint(j)).String()
+		}
+
+		marketID := int16(i)
+		markets[fmt.Sprintf("%d", marketID)] = EventMarket{
+			ConditionID:      conditionID.Hex(),
+			ID:               marketID,
+			MarketIdentifier: eventIdentifier + "-" + m.Slug,
+			Question:         m.Question,
+			Slug:             m.Slug,
+			Title:            m.Question,
+			Image:            m.Image,
+			Outcomes:         m.Outcomes,
+			TokenIDs:         tokenIDs,
+		}
+	}
+
+	return Event{
+		EventIdentifier: eventIdentifier,
+		Slug:            def.Slug,
+		Title:           def.Title,
+		Description:     def.Description,
+		Image:           def.Image,
+		EndDate:         endDate,
+		Topic:           topic,
+		Markets:         markets,
+	}, nil
+}
+
+// parseDefinitionEndDate 接受 RFC3339 或纯日期 (YYYY-MM-DD), 后者按当天
+// UTC 零点算, 让非工程同学不用记 RFC3339 的写法。
+func parseDefinitionEndDate(s string) (*time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return &t, nil
+	}
+	if t, err := time.Parse("2006-01-02", s); err == nil {
+		return &t, nil
+	}
+	return nil, fmt.Errorf("invalid end_date %q, expected RFC3339 or YYYY-MM-DD", s)
+}
+
+// CreateEventsFromFile 解析 path 里的事件定义, 逐个插入并发布, 一个事件
+// 失败不影响文件里其它事件, 返回成功插入的事件列表和遇到的错误汇总。
+func CreateEventsFromFile(ctx context.Context, pgPool *pgxpool.Pool, publish func(Event) error, path string) ([]Event, []error) {
+	definitions, err := LoadEventDefinitions(path)
+	if err != nil {
+		return nil, []error{err}
+	}
+
+	var created []Event
+	var errs []error
+	for _, def := range definitions {
+		event, err := buildEventFromDefinition(def)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+
+		id, err := upsertEvent(ctx, pgPool, event)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("event %q: %w", def.Slug, err))
+			continue
+		}
+		event.ID = id
+
+		if err := publish(event); err != nil {
+			errs = append(errs, fmt.Errorf("event %q: publish: %w", def.Slug, err))
+			continue
+		}
+
+		created = append(created, event)
+	}
+	return created, errs
+}
@@ -5,12 +5,16 @@ import (
 	"encoding/json"
 	"fmt"
 	"log"
+	"os"
 	"sort"
 	"strings"
 	"time"
 
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/jackc/pgx/v4/pgxpool"
-	"github.com/redis/go-redis/v9"
+	"mock_go/chaos"
+	"mock_go/streamtypes"
+	"streams"
 )
 
 const (
@@ -42,6 +46,18 @@ const (
 	REDIS_DB       = 0 // engine_input_mq 使用 DB 0
 )
 
+// collateralTokenAddress 是 generateTokenID 派生 positionId 时用的
+// collateral token 地址, 跟 bot_go/chain.go 里测试网 ChainConfig 的
+// USDCContractAddress 保持一致, 这样 send_event 生成的 token_id 和 bot_go
+// 实际下单用的 collateral 是同一个合约算出来的。
+var collateralTokenAddress = common.HexToAddress("0x8AC76a51cc950d9822D68b83fE1Ad97B32Cd580D")
+
+// oracleAddress 是 create.go 给手写/合成的测试市场派生 conditionId 时假定
+// 的报告地址 (ConditionalTokens.prepareCondition 的 oracle 参数), 目前还
+// 没有一个专门用于合成市场的报告地址, 这里先用跟 collateralTokenAddress
+// 不同的占位地址, 上线前需要换成真正负责这些测试市场的 oracle 账户。
+var oracleAddress = common.HexToAddress("0x000000000000000000000000000000000000dEaD")
+
 // Event 数据库表结构
 type Event struct {
 	ID              int64                  `json:"id"`
@@ -71,34 +87,6 @@ type EventMarket struct {
 	TokenIDs           []string `json:"token_ids"`
 }
 
-// EngineMQEventCreate 用于发送给 match_engine
-type EngineMQEventCreate struct {
-	EventID int64                          `json:"event_id"`
-	Markets map[string]EngineMQEventMarket `json:"markets"`
-	EndDate *time.Time                     `json:"end_date,omitempty"`
-}
-
-// EngineMQEventMarket match_engine 需要的市场结构
-type EngineMQEventMarket struct {
-	MarketID int16    `json:"market_id"`
-	Outcomes []string `json:"outcomes"`
-	TokenIDs []string `json:"token_ids"`
-}
-
-// EventInputMessageCreate 用于 AddOneEvent
-type EventInputMessageCreate struct {
-	Types   string                         `json:"types"`
-	EventID int64                          `json:"event_id"`
-	Markets map[string]EngineMQEventMarket `json:"markets"`
-	EndDate *time.Time                     `json:"end_date,omitempty"`
-}
-
-// EventInputMessageClose 用于 RemoveOneEvent
-type EventInputMessageClose struct {
-	Types   string `json:"types"`
-	EventID int64  `json:"event_id"`
-}
-
 // sortOutcomesAndTokenIDs 排序 outcomes 和 token_ids
 // 如果是 Yes/No，Yes 在前，No 在后；否则按字典序排序
 func sortOutcomesAndTokenIDs(outcomes []string, tokenIDs []string) ([]string, []string) {
@@ -163,8 +151,23 @@ func sortOutcomesAndTokenIDs(outcomes []string, tokenIDs []string) ([]string, []
 	return sortedOutcomes, sortedTokenIDs
 }
 
-// sendEventCreate 发送未关闭且未过期的事件创建消息到 match_engine
-func sendEventCreate(ctx context.Context, pgPool *pgxpool.Pool, rdb *redis.Client) error {
+// sendEventCreate 发送未关闭且未过期的事件创建消息到 match_engine。发布前
+// 先跑一遍 ValidateEvents, 有问题的事件 (slug 冲突、outcomes 为空/重复、
+// 标题超长、图片链接打不开) 会被跳过并写进 validationReportFile, 而不是
+// 带着问题发布出去。
+func sendEventCreate(ctx context.Context, pgPool *pgxpool.Pool, producer *streams.Producer) error {
+	report, err := ValidateEvents(ctx, pgPool)
+	if err != nil {
+		return fmt.Errorf("validate events before publish: %w", err)
+	}
+	if err := WriteValidationReport(report); err != nil {
+		log.Printf("Failed to write validation report: %v", err)
+	}
+	if !report.OK() {
+		log.Printf("⚠️ validation found %d issue(s) across %d event(s), see %s; affected events will be skipped",
+			len(report.Issues), len(report.InvalidIDs), validationReportFile)
+	}
+
 	// 查询 closed=false 且未过期的事件
 	query := `SELECT id, event_identifier, slug, title, description, image, end_date, topic, markets, closed, created_at
 	          FROM events WHERE closed = false AND (end_date IS NULL OR end_date > NOW()) ORDER BY id`
@@ -197,50 +200,18 @@ func sendEventCreate(ctx context.Context, pgPool *pgxpool.Pool, rdb *redis.Clien
 			continue
 		}
 
-		// 解析 markets JSON
-		if err := json.Unmarshal(marketsJSON, &event.Markets); err != nil {
-			log.Printf("Failed to unmarshal markets for event %d: %v", event.ID, err)
+		if _, invalid := report.InvalidIDs[event.ID]; invalid {
+			log.Printf("Skipping event %d (%s): failed validation, see %s", event.ID, event.EventIdentifier, validationReportFile)
 			continue
 		}
 
-		// 构建 EngineMQEventCreate
-		engineMarkets := make(map[string]EngineMQEventMarket)
-		for marketIDStr, market := range event.Markets {
-			// 排序 outcomes 和 token_ids
-			sortedOutcomes, sortedTokenIDs := sortOutcomesAndTokenIDs(market.Outcomes, market.TokenIDs)
-
-			engineMarket := EngineMQEventMarket{
-				MarketID: market.ID,
-				Outcomes: sortedOutcomes,
-				TokenIDs: sortedTokenIDs,
-			}
-			engineMarkets[marketIDStr] = engineMarket
-		}
-
-		// 构建 EventInputMessageCreate (展平结构)
-		eventMsg := EventInputMessageCreate{
-			Types:   "AddOneEvent",
-			EventID: event.ID,
-			Markets: engineMarkets,
-			EndDate: event.EndDate,
-		}
-
-		// 序列化为 JSON
-		msgBytes, err := json.Marshal(eventMsg)
-		if err != nil {
-			log.Printf("Failed to marshal event message for event %d: %v", event.ID, err)
+		// 解析 markets JSON
+		if err := json.Unmarshal(marketsJSON, &event.Markets); err != nil {
+			log.Printf("Failed to unmarshal markets for event %d: %v", event.ID, err)
 			continue
 		}
 
-		// 发送到 Redis Stream
-		err = rdb.XAdd(ctx, &redis.XAddArgs{
-			Stream: EVENT_INPUT_STREAM,
-			Values: map[string]interface{}{
-				EVENT_INPUT_MSG_KEY: string(msgBytes),
-			},
-		}).Err()
-
-		if err != nil {
+		if err := publishEventCreate(ctx, producer, event); err != nil {
 			log.Printf("Failed to publish event %d to Redis: %v", event.ID, err)
 			continue
 		}
@@ -257,32 +228,47 @@ func sendEventCreate(ctx context.Context, pgPool *pgxpool.Pool, rdb *redis.Clien
 	return nil
 }
 
+// publishEventCreate 把单个事件的 AddOneEvent 消息发到 match_engine, 从
+// sendEventCreate 的批量循环里抽出来, import-polymarket/create 之类只插入
+// 单个事件的模式也复用同一份发布逻辑。
+func publishEventCreate(ctx context.Context, producer *streams.Producer, event Event) error {
+	engineMarkets := make(map[string]streamtypes.EngineMQEventMarket)
+	for marketIDStr, market := range event.Markets {
+		// 排序 outcomes 和 token_ids
+		sortedOutcomes, sortedTokenIDs := sortOutcomesAndTokenIDs(market.Outcomes, market.TokenIDs)
+
+		engineMarkets[marketIDStr] = streamtypes.EngineMQEventMarket{
+			MarketID: market.ID,
+			Outcomes: sortedOutcomes,
+			TokenIDs: sortedTokenIDs,
+		}
+	}
+
+	eventMsg := streamtypes.EventInputMessageCreate{
+		Version: streamtypes.CurrentVersion,
+		Types:   "AddOneEvent",
+		EventID: event.ID,
+		Markets: engineMarkets,
+		EndDate: event.EndDate,
+	}
+
+	_, err := producer.Send(ctx, eventMsg)
+	return err
+}
+
 // sendEventClose 发送指定事件的关闭消息到 match_engine
-func sendEventClose(ctx context.Context, rdb *redis.Client, eventID int64) error {
+func sendEventClose(ctx context.Context, producer *streams.Producer, eventID int64) error {
 	// 构建 EventInputMessageClose (展平结构)
-	eventMsg := EventInputMessageClose{
+	eventMsg := streamtypes.EventInputMessageClose{
+		Version: streamtypes.CurrentVersion,
 		Types:   "RemoveOneEvent",
 		EventID: eventID,
 	}
 
-	// 序列化为 JSON
-	msgBytes, err := json.Marshal(eventMsg)
-	if err != nil {
-		return fmt.Errorf("failed to marshal close message for event %d: %w", eventID, err)
-	}
-
-	// 打印 JSON 用于调试
-	log.Printf("RemoveOneEvent JSON: %s", string(msgBytes))
+	log.Printf("Sending RemoveOneEvent: %+v", eventMsg)
 
 	// 发送到 Redis Stream
-	err = rdb.XAdd(ctx, &redis.XAddArgs{
-		Stream: EVENT_INPUT_STREAM,
-		Values: map[string]interface{}{
-			EVENT_INPUT_MSG_KEY: string(msgBytes),
-		},
-	}).Err()
-
-	if err != nil {
+	if _, err := producer.Send(ctx, eventMsg); err != nil {
 		return fmt.Errorf("failed to publish close event %d to Redis: %w", eventID, err)
 	}
 
@@ -293,10 +279,22 @@ func sendEventClose(ctx context.Context, rdb *redis.Client, eventID int64) error
 func main() {
 	ctx := context.Background()
 
-	// 连接 PostgreSQL
-	//dsn := fmt.Sprintf("postgres://%s:%s@%s:%d/%s",
+	// local 模式: 不连共享库, 而是对着本地 Postgres 建 events 表、插入
+	// 几条示例事件, 让没有共享库访问权限的开发者也能跑通整条流水线。
+	// 用法: send_event local [dsn], 不传 dsn 时用 LocalPostgresDSN。
+	local := len(os.Args) > 1 && os.Args[1] == "local"
+
 	dsn := fmt.Sprintf("postgres://%s:%s@%s:%d/%s?sslmode=require",
 		POSTGRES_USER, POSTGRES_PASSWORD, POSTGRES_HOST, POSTGRES_PORT, POSTGRES_DATABASE)
+	if local {
+		dsn = LocalPostgresDSN
+		if len(os.Args) > 2 {
+			dsn = os.Args[2]
+		}
+	}
+
+	// 连接 PostgreSQL
+	//dsn := fmt.Sprintf("postgres://%s:%s@%s:%d/%s",
 	pgPool, err := pgxpool.Connect(ctx, dsn)
 	if err != nil {
 		log.Fatalf("Failed to connect to PostgreSQL: %v", err)
@@ -304,12 +302,43 @@ func main() {
 	defer pgPool.Close()
 	log.Println("Connected to PostgreSQL")
 
+	// validate 模式: 只跑 ValidateEvents 生成报告, 不连 Redis、不发布任何
+	// 消息, 内容团队/CI 在正式发布前先跑一遍。用法: send_event validate [dsn]。
+	if len(os.Args) > 1 && os.Args[1] == "validate" {
+		report, err := ValidateEvents(ctx, pgPool)
+		if err != nil {
+			log.Fatalf("Failed to validate events: %v", err)
+		}
+		if err := WriteValidationReport(report); err != nil {
+			log.Fatalf("Failed to write validation report: %v", err)
+		}
+		if report.OK() {
+			log.Printf("✅ validated %d event(s), no issues found (report: %s)", report.EventsCheck, validationReportFile)
+			return
+		}
+		log.Printf("⚠️ validated %d event(s), found %d issue(s) across %d event(s), see %s",
+			report.EventsCheck, len(report.Issues), len(report.InvalidIDs), validationReportFile)
+		os.Exit(1)
+	}
+
+	if local {
+		if err := applyLocalSchema(ctx, pgPool); err != nil {
+			log.Fatalf("Failed to apply local schema: %v", err)
+		}
+		log.Println("Applied local events schema")
+
+		if err := seedLocalEvents(ctx, pgPool); err != nil {
+			log.Fatalf("Failed to seed local events: %v", err)
+		}
+		log.Println("Seeded local events")
+	}
+
 	// 连接 Redis
-	rdb := redis.NewClient(&redis.Options{
-		Addr:     REDIS_HOST,
+	rdb := chaos.Attach(streams.NewRedisClient(streams.RedisConfig{
+		Addrs:    []string{REDIS_HOST},
 		Password: REDIS_PASSWORD,
 		DB:       REDIS_DB,
-	})
+	}.OverrideFromEnv()), chaos.ConfigFromEnv())
 	defer rdb.Close()
 
 	// 测试 Redis 连接
@@ -318,15 +347,64 @@ func main() {
 	// }
 	// log.Println("Connected to Redis")
 
+	producer := streams.NewProducer(rdb, EVENT_INPUT_STREAM, EVENT_INPUT_MSG_KEY)
+
+	// import-polymarket 模式: 从 Polymarket 的 Gamma API 拉一个事件的元数据,
+	// 映射成我们自己的 events schema (按我们的规则生成 token_ids, 不直接
+	// 沿用 Polymarket 的 clobTokenIds), 插入 Postgres 并发布 AddOneEvent。
+	// 用法: send_event import-polymarket --slug <polymarket-event-slug>
+	if len(os.Args) > 1 && os.Args[1] == "import-polymarket" {
+		slug, err := parseSlugFlag(os.Args[2:])
+		if err != nil {
+			log.Fatalf("%v\nUsage: send_event import-polymarket --slug <polymarket-event-slug>", err)
+		}
+
+		event, err := ImportPolymarketEvent(ctx, pgPool, slug)
+		if err != nil {
+			log.Fatalf("Failed to import Polymarket event %q: %v", slug, err)
+		}
+
+		if err := publishEventCreate(ctx, producer, *event); err != nil {
+			log.Fatalf("Failed to publish imported event %d to Redis: %v", event.ID, err)
+		}
+
+		log.Printf("✅ Imported and published Polymarket event %q as event_id=%d (%s)", slug, event.ID, event.EventIdentifier)
+		return
+	}
+
+	// create 模式: 从一份 YAML/CSV 文件里读事件/市场/outcomes/end_date 定义,
+	// 生成标识符和 token_ids 后插入并发布, 替代手写 SQL insert。
+	// 用法: send_event create --file events.yaml
+	if len(os.Args) > 1 && os.Args[1] == "create" {
+		path, err := parseFileFlag(os.Args[2:])
+		if err != nil {
+			log.Fatalf("%v\nUsage: send_event create --file <events.yaml|events.csv>", err)
+		}
+
+		created, errs := CreateEventsFromFile(ctx, pgPool, func(event Event) error {
+			return publishEventCreate(ctx, producer, event)
+		}, path)
+		for _, err := range errs {
+			log.Printf("⚠️ %v", err)
+		}
+		for _, event := range created {
+			log.Printf("✅ Created and published event_id=%d (%s)", event.ID, event.EventIdentifier)
+		}
+		if len(errs) > 0 && len(created) == 0 {
+			log.Fatalf("Failed to create any events from %s", path)
+		}
+		return
+	}
+
 	// 发送事件创建消息
 	log.Println("\n=== Sending Event Create Messages ===")
-	if err := sendEventCreate(ctx, pgPool, rdb); err != nil {
+	if err := sendEventCreate(ctx, pgPool, producer); err != nil {
 		log.Fatalf("Failed to send event create messages: %v", err)
 	}
 
 	log.Println("\n✅ Event create messages sent successfully")
-	// log.Println("\n💡 To close an event, call: sendEventClose(ctx, rdb, event_id)")
-	// sendEventClose(ctx, rdb, 1)
+	// log.Println("\n💡 To close an event, call: sendEventClose(ctx, producer, event_id)")
+	// sendEventClose(ctx, producer, 1)
 	// if err != nil {
 	// 	log.Fatalf("Failed to send event close messages: %v", err)
 	// }
@@ -0,0 +1,239 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"ctf"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/jackc/pgx/v4/pgxpool"
+)
+
+// gammaAPIBaseURL 是 Polymarket 公开的 Gamma API 地址, 只读拉取事件/市场
+// 元数据用, 不涉及下单/鉴权。
+const gammaAPIBaseURL = "https://gamma-api.polymarket.com"
+
+// gammaHTTPTimeout 是访问 Gamma API 单次请求的超时时间。
+const gammaHTTPTimeout = 10 * time.Second
+
+// gammaEvent/gammaMarket 是 Gamma API `/events?slug=...` 返回的事件/市场
+// 形状 (只取我们用得到的字段)。outcomes/clobTokenIds 在 Gamma API 里是
+// JSON 编码成字符串的数组 (比如 `"[\"Yes\",\"No\"]"`), 不是原生数组。
+type gammaEvent struct {
+	Slug        string        `json:"slug"`
+	Title       string        `json:"title"`
+	Description string        `json:"description"`
+	Image       string        `json:"image"`
+	EndDate     string        `json:"endDate"`
+	Markets     []gammaMarket `json:"markets"`
+}
+
+type gammaMarket struct {
+	ConditionID        string `json:"conditionId"`
+	ParentCollectionID string `json:"parentCollectionId"`
+	Slug               string `json:"slug"`
+	Question           string `json:"question"`
+	Image              string `json:"image"`
+	Outcomes           string `json:"outcomes"`
+	ClobTokenIDs       string `json:"clobTokenIds"`
+}
+
+// fetchGammaEvent 按 slug 从 Gamma API 拉一个事件, 找不到时返回 error。
+func fetchGammaEvent(ctx context.Context, slug string) (*gammaEvent, error) {
+	url := fmt.Sprintf("%s/events?slug=%s", gammaAPIBaseURL, slug)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	client := &http.Client{Timeout: gammaHTTPTimeout}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("request Gamma API: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("read Gamma API response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("Gamma API returned status %d: %s", resp.StatusCode, string(body))
+	}
+
+	var events []gammaEvent
+	if err := json.Unmarshal(body, &events); err != nil {
+		return nil, fmt.Errorf("parse Gamma API response: %w", err)
+	}
+	if len(events) == 0 {
+		return nil, fmt.Errorf("no Polymarket event found for slug %q", slug)
+	}
+	return &events[0], nil
+}
+
+// generateTokenID 给一个市场的某个 outcome 生成 token_id, 按 ctf 包实现的
+// Gnosis CTF 公式从 conditionId 派生 positionId (十进制字符串, 跟
+// eip712.Order.TokenId/OrderInput.TokenId 的编码一致), 而不是直接沿用
+// Polymarket 的 clobTokenIds (那是它自己 CTFExchange 部署用的 collateral
+// token 算出来的, 跟我们自己的撮合引擎认的 collateral token 不是一回事)。
+// 同一个 conditionId + outcome index 任何时候重新派生都得到同一个
+// token_id, 一旦真的对着 collateralTokenAddress 对应的 ConditionalTokens
+// 合约 prepareCondition 过这个 conditionId, 这里生成的 token_id 就是合约
+// 认得的那个。
+func generateTokenID(conditionID string, outcomeIndex int) (string, error) {
+	if conditionID == "" {
+		return "", fmt.Errorf("missing conditionId, cannot derive a CTF-recognized token id")
+	}
+	positionID := ctf.DerivePositionID(common.HexToHash(conditionID), collateralTokenAddress, uint(outcomeIndex))
+	return positionID.String(), nil
+}
+
+// mapGammaEvent 把 Gamma API 返回的事件映射成我们自己的 Event/EventMarket
+// schema, 市场 ID 按出现顺序从 0 开始编号, token_ids 用 generateTokenID
+// 重新生成。
+func mapGammaEvent(src *gammaEvent) (Event, error) {
+	eventIdentifier := "polymarket-" + src.Slug
+
+	var endDate *time.Time
+	if src.EndDate != "" {
+		if t, err := time.Parse(time.RFC3339, src.EndDate); err == nil {
+			endDate = &t
+		}
+	}
+
+	markets := make(map[string]EventMarket, len(src.Markets))
+	for i, m := range src.Markets {
+		var outcomes []string
+		if m.Outcomes != "" {
+			if err := json.Unmarshal([]byte(m.Outcomes), &outcomes); err != nil {
+				return Event{}, fmt.Errorf("parse outcomes for market %q: %w", m.Slug, err)
+			}
+		}
+
+		tokenIDs := make([]string, len(outcomes))
+		for j := range outcomes {
+			tokenID, err := generateTokenID(m.ConditionID, j)
+			if err != nil {
+				return Event{}, fmt.Errorf("derive token id for market %q: %w", m.Slug, err)
+			}
+			tokenIDs[j] = tokenID
+		}
+
+		marketID := int16(i)
+		markets[fmt.Sprintf("%d", marketID)] = EventMarket{
+			ParentCollectionID: m.ParentCollectionID,
+			ConditionID:        m.ConditionID,
+			ID:                 marketID,
+			MarketIdentifier:   eventIdentifier + "-" + m.Slug,
+			Question:           m.Question,
+			Slug:               m.Slug,
+			Title:              m.Question,
+			Image:              m.Image,
+			Outcomes:           outcomes,
+			TokenIDs:           tokenIDs,
+		}
+	}
+
+	return Event{
+		EventIdentifier: eventIdentifier,
+		Slug:            src.Slug,
+		Title:           src.Title,
+		Description:     src.Description,
+		Image:           src.Image,
+		EndDate:         endDate,
+		Topic:           "polymarket-import",
+		Markets:         markets,
+	}, nil
+}
+
+// upsertEvent 插入或更新一个事件 (按 event_identifier 去重), 重新导入同一
+// 个 slug 会刷新已有那一行而不是报重复键错误, 返回数据库分配的 id。
+func upsertEvent(ctx context.Context, pgPool *pgxpool.Pool, event Event) (int64, error) {
+	marketsJSON, err := json.Marshal(event.Markets)
+	if err != nil {
+		return 0, fmt.Errorf("marshal markets: %w", err)
+	}
+
+	var id int64
+	err = pgPool.QueryRow(ctx, `
+		INSERT INTO events (event_identifier, slug, title, description, image, end_date, topic, markets, closed, resolved)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, false, false)
+		ON CONFLICT (event_identifier) DO UPDATE SET
+			slug = EXCLUDED.slug,
+			title = EXCLUDED.title,
+			description = EXCLUDED.description,
+			image = EXCLUDED.image,
+			end_date = EXCLUDED.end_date,
+			markets = EXCLUDED.markets
+		RETURNING id
+	`, event.EventIdentifier, event.Slug, event.Title, event.Description, event.Image, event.EndDate, event.Topic, marketsJSON).Scan(&id)
+	if err != nil {
+		return 0, fmt.Errorf("upsert event %s: %w", event.EventIdentifier, err)
+	}
+	return id, nil
+}
+
+// ImportPolymarketEvent 拉取、映射、插入一个 Polymarket 事件, 返回插入后
+// 带有数据库 id 的 Event, 供调用方紧接着发布 AddOneEvent。
+func ImportPolymarketEvent(ctx context.Context, pgPool *pgxpool.Pool, slug string) (*Event, error) {
+	src, err := fetchGammaEvent(ctx, slug)
+	if err != nil {
+		return nil, err
+	}
+
+	event, err := mapGammaEvent(src)
+	if err != nil {
+		return nil, err
+	}
+
+	id, err := upsertEvent(ctx, pgPool, event)
+	if err != nil {
+		return nil, err
+	}
+	event.ID = id
+
+	return &event, nil
+}
+
+// parseSlugFlag 从 import-polymarket 的子参数里取 --slug 的值, 支持
+// `--slug foo` 和 `--slug=foo` 两种写法。
+func parseSlugFlag(args []string) (string, error) {
+	value, ok := parseNamedFlag(args, "--slug")
+	if !ok {
+		return "", fmt.Errorf("missing required --slug flag")
+	}
+	return value, nil
+}
+
+// parseFileFlag 从 create 的子参数里取 --file 的值, 支持 `--file foo` 和
+// `--file=foo` 两种写法。
+func parseFileFlag(args []string) (string, error) {
+	value, ok := parseNamedFlag(args, "--file")
+	if !ok {
+		return "", fmt.Errorf("missing required --file flag")
+	}
+	return value, nil
+}
+
+// parseNamedFlag 是 parseSlugFlag/parseFileFlag 共用的简单 flag 解析, 这个
+// 工具里没有引入 "flag" 包, 跟 local/validate 模式手动解析 os.Args 的风格
+// 保持一致。
+func parseNamedFlag(args []string, name string) (string, bool) {
+	for i, arg := range args {
+		if arg == name {
+			if i+1 >= len(args) {
+				return "", false
+			}
+			return args[i+1], true
+		}
+		if strings.HasPrefix(arg, name+"=") {
+			return strings.TrimPrefix(arg, name+"="), true
+		}
+	}
+	return "", false
+}
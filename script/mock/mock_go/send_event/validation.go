@@ -0,0 +1,186 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/jackc/pgx/v4/pgxpool"
+)
+
+// maxTitleLength 是事件/市场标题允许的最长字符数, 跟前端列表页的展示宽度
+// 对齐, 超过这个长度的标题会被截断显示, 所以在发布前就该拦下来。
+const maxTitleLength = 140
+
+// imageCheckTimeout 是校验图片 URL 时单次 HTTP 请求的超时时间, 内容团队
+// 一次性校验的事件数量可能不小, 单个请求不该卡太久。
+const imageCheckTimeout = 5 * time.Second
+
+// ValidationIssue 是校验报告里的一条问题, EventIdentifier/MarketID 定位到
+// 具体是哪个事件/市场出的问题, 方便内容团队按 event_identifier 去改。
+type ValidationIssue struct {
+	EventID         int64  `json:"event_id"`
+	EventIdentifier string `json:"event_identifier"`
+	MarketID        *int16 `json:"market_id,omitempty"`
+	Field           string `json:"field"`
+	Message         string `json:"message"`
+}
+
+// ValidationReport 是 `send_event validate` 生成的机读报告, 内容团队或者
+// CI 都可以直接读 JSON 里的 Issues 字段。
+type ValidationReport struct {
+	GeneratedAt time.Time          `json:"generated_at"`
+	EventsCheck int                `json:"events_checked"`
+	Issues      []ValidationIssue  `json:"issues"`
+	InvalidIDs  map[int64]struct{} `json:"-"`
+}
+
+// OK 报告没有发现任何问题。
+func (r *ValidationReport) OK() bool {
+	return len(r.Issues) == 0
+}
+
+// validationReportFile 是 `send_event validate`/sendEventCreate 写机读报告
+// 的路径, 未设置 VALIDATION_REPORT_FILE 时用这个默认值。
+var validationReportFile = envOrDefault("VALIDATION_REPORT_FILE", "send_event_validation_report.json")
+
+func envOrDefault(name, fallback string) string {
+	if v := os.Getenv(name); v != "" {
+		return v
+	}
+	return fallback
+}
+
+// ValidateEvents 对 pgPool 里全部事件跑校验规则, 返回一份机读报告:
+//   - slug 必须在全部事件里唯一 (发布之后前端按 slug 路由, 重复会互相覆盖)
+//   - 每个市场的 outcomes 不能为空、不能有重复项
+//   - 标题长度不能超过 maxTitleLength
+//   - image 非空时必须能用 HTTP 请求解析 (2xx/3xx), 避免内容团队填了失效链接
+func ValidateEvents(ctx context.Context, pgPool *pgxpool.Pool) (*ValidationReport, error) {
+	rows, err := pgPool.Query(ctx, `
+		SELECT id, event_identifier, slug, title, image, markets
+		FROM events
+		ORDER BY id
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("query events for validation: %w", err)
+	}
+	defer rows.Close()
+
+	report := &ValidationReport{GeneratedAt: time.Now(), InvalidIDs: make(map[int64]struct{})}
+	slugOwners := make(map[string]int64)
+
+	type row struct {
+		id         int64
+		identifier string
+		slug       string
+		title      string
+		image      string
+		marketsRaw []byte
+	}
+	var all []row
+	for rows.Next() {
+		var r row
+		if err := rows.Scan(&r.id, &r.identifier, &r.slug, &r.title, &r.image, &r.marketsRaw); err != nil {
+			return nil, fmt.Errorf("scan event for validation: %w", err)
+		}
+		all = append(all, r)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate events for validation: %w", err)
+	}
+
+	httpClient := &http.Client{Timeout: imageCheckTimeout}
+
+	for _, r := range all {
+		report.EventsCheck++
+		addIssue := func(field, message string) {
+			report.Issues = append(report.Issues, ValidationIssue{
+				EventID: r.id, EventIdentifier: r.identifier, Field: field, Message: message,
+			})
+			report.InvalidIDs[r.id] = struct{}{}
+		}
+
+		if owner, ok := slugOwners[r.slug]; ok {
+			addIssue("slug", fmt.Sprintf("slug %q also used by event %d", r.slug, owner))
+		} else {
+			slugOwners[r.slug] = r.id
+		}
+
+		if len(r.title) > maxTitleLength {
+			addIssue("title", fmt.Sprintf("title is %d characters, exceeds limit of %d", len(r.title), maxTitleLength))
+		}
+
+		if r.image != "" && !imageURLResolves(httpClient, r.image) {
+			addIssue("image", fmt.Sprintf("image URL %q does not resolve", r.image))
+		}
+
+		var markets map[string]EventMarket
+		if err := json.Unmarshal(r.marketsRaw, &markets); err != nil {
+			addIssue("markets", fmt.Sprintf("markets is not valid JSON: %v", err))
+			continue
+		}
+		for _, market := range markets {
+			marketID := market.ID
+			addMarketIssue := func(field, message string) {
+				report.Issues = append(report.Issues, ValidationIssue{
+					EventID: r.id, EventIdentifier: r.identifier, MarketID: &marketID, Field: field, Message: message,
+				})
+				report.InvalidIDs[r.id] = struct{}{}
+			}
+
+			if len(market.Outcomes) == 0 {
+				addMarketIssue("outcomes", "market has no outcomes")
+				continue
+			}
+			seen := make(map[string]bool, len(market.Outcomes))
+			for _, outcome := range market.Outcomes {
+				if seen[outcome] {
+					addMarketIssue("outcomes", fmt.Sprintf("duplicate outcome %q", outcome))
+				}
+				seen[outcome] = true
+			}
+			if len(market.Title) > maxTitleLength {
+				addMarketIssue("title", fmt.Sprintf("title is %d characters, exceeds limit of %d", len(market.Title), maxTitleLength))
+			}
+		}
+	}
+
+	return report, nil
+}
+
+// imageURLResolves 报告 url 能否用 HTTP 请求打开 (2xx/3xx 都算通过, 3xx 常
+// 见于 CDN 重定向)。先尝试 HEAD, 部分图床不支持 HEAD 时退化成 GET。
+func imageURLResolves(client *http.Client, url string) bool {
+	for _, method := range []string{http.MethodHead, http.MethodGet} {
+		req, err := http.NewRequest(method, url, nil)
+		if err != nil {
+			continue
+		}
+		resp, err := client.Do(req)
+		if err != nil {
+			continue
+		}
+		resp.Body.Close()
+		if resp.StatusCode < 400 {
+			return true
+		}
+	}
+	return false
+}
+
+// WriteValidationReport 把报告写到 validationReportFile, 供内容团队/CI 事后
+// 读取, 不需要重新跑一遍校验。
+func WriteValidationReport(report *ValidationReport) error {
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshal validation report: %w", err)
+	}
+	if err := os.WriteFile(validationReportFile, data, 0644); err != nil {
+		return fmt.Errorf("write validation report to %s: %w", validationReportFile, err)
+	}
+	return nil
+}
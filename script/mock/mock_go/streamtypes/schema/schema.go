@@ -0,0 +1,166 @@
+// Package schema holds versioned JSON Schema documents for the messages
+// defined in streamtypes, plus a small validator to check arbitrary sample
+// messages (e.g. captured from production) against them. There's no
+// protoc/buf toolchain wired into this repo, so rather than bolt one on for
+// a single mock module we generate nothing: the JSON Schema files here are
+// the checked-in source of truth, and streamtypes.go is kept in sync with
+// them by hand, the same way validate.go in eip712 is kept in sync with
+// OrderInput by hand.
+package schema
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+)
+
+//go:embed event_input_message_create.schema.json
+var eventInputMessageCreateSchema string
+
+//go:embed event_input_message_close.schema.json
+var eventInputMessageCloseSchema string
+
+//go:embed api_key_event_add.schema.json
+var apiKeyEventAddSchema string
+
+//go:embed api_key_event_remove.schema.json
+var apiKeyEventRemoveSchema string
+
+//go:embed trade_onchain_send_request.schema.json
+var tradeOnchainSendRequestSchema string
+
+//go:embed trade_onchain_send_response.schema.json
+var tradeOnchainSendResponseSchema string
+
+// Schema is the subset of JSON Schema (draft-07) this package understands:
+// object/array/string/number/integer/boolean types, "properties",
+// "required" and "items". That's enough to describe every message in
+// streamtypes without pulling in a full JSON Schema library for one mock
+// module.
+type Schema struct {
+	Type       string             `json:"type"`
+	Properties map[string]*Schema `json:"properties,omitempty"`
+	Required   []string           `json:"required,omitempty"`
+	Items      *Schema            `json:"items,omitempty"`
+}
+
+// ByMessageType maps the "types"/"action" discriminator value producers put
+// on each stream message to the schema that describes it.
+var ByMessageType = map[string]*Schema{
+	"AddOneEvent":              mustParse(eventInputMessageCreateSchema),
+	"RemoveOneEvent":           mustParse(eventInputMessageCloseSchema),
+	"add":                      mustParse(apiKeyEventAddSchema),
+	"remove":                   mustParse(apiKeyEventRemoveSchema),
+	"TradeOnchainSendRequest":  mustParse(tradeOnchainSendRequestSchema),
+	"TradeOnchainSendResponse": mustParse(tradeOnchainSendResponseSchema),
+}
+
+func mustParse(raw string) *Schema {
+	var s Schema
+	if err := json.Unmarshal([]byte(raw), &s); err != nil {
+		panic(fmt.Sprintf("schema: invalid embedded schema: %v", err))
+	}
+	return &s
+}
+
+// Validate checks that data (a single JSON message) conforms to s: every
+// required property is present and every present property has the right
+// JSON type. It does not attempt to validate string formats, numeric
+// ranges, or additionalProperties - just enough structural drift detection
+// to catch a producer/consumer schema going out of sync.
+func Validate(s *Schema, data []byte) error {
+	var v interface{}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return fmt.Errorf("invalid JSON: %w", err)
+	}
+	return validateValue(s, v, "$")
+}
+
+func validateValue(s *Schema, v interface{}, path string) error {
+	if s == nil {
+		return nil
+	}
+	if !typeMatches(s.Type, v) {
+		return fmt.Errorf("%s: want type %q, got %s", path, s.Type, jsonTypeName(v))
+	}
+
+	switch s.Type {
+	case "object":
+		obj, _ := v.(map[string]interface{})
+		for _, name := range s.Required {
+			if _, ok := obj[name]; !ok {
+				return fmt.Errorf("%s: missing required property %q", path, name)
+			}
+		}
+		for name, propSchema := range s.Properties {
+			propVal, ok := obj[name]
+			if !ok {
+				continue
+			}
+			if propVal == nil {
+				continue
+			}
+			if err := validateValue(propSchema, propVal, path+"."+name); err != nil {
+				return err
+			}
+		}
+
+	case "array":
+		arr, _ := v.([]interface{})
+		if s.Items != nil {
+			for i, elem := range arr {
+				if err := validateValue(s.Items, elem, fmt.Sprintf("%s[%d]", path, i)); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+func typeMatches(want string, v interface{}) bool {
+	switch want {
+	case "", "any":
+		return true
+	case "object":
+		_, ok := v.(map[string]interface{})
+		return ok
+	case "array":
+		_, ok := v.([]interface{})
+		return ok
+	case "string":
+		_, ok := v.(string)
+		return ok
+	case "boolean":
+		_, ok := v.(bool)
+		return ok
+	case "number":
+		_, ok := v.(float64)
+		return ok
+	case "integer":
+		f, ok := v.(float64)
+		return ok && f == float64(int64(f))
+	default:
+		return true
+	}
+}
+
+func jsonTypeName(v interface{}) string {
+	switch v.(type) {
+	case nil:
+		return "null"
+	case map[string]interface{}:
+		return "object"
+	case []interface{}:
+		return "array"
+	case string:
+		return "string"
+	case bool:
+		return "boolean"
+	case float64:
+		return "number"
+	default:
+		return fmt.Sprintf("%T", v)
+	}
+}
@@ -0,0 +1,66 @@
+package schema
+
+import "testing"
+
+func TestValidate_EventInputMessageCreate(t *testing.T) {
+	s := ByMessageType["AddOneEvent"]
+	valid := []byte(`{"types":"AddOneEvent","event_id":42,"markets":{"1":{"market_id":1,"outcomes":["Yes","No"],"token_ids":["111","222"]}}}`)
+	if err := Validate(s, valid); err != nil {
+		t.Errorf("Validate(valid) = %v, want nil", err)
+	}
+
+	missingField := []byte(`{"types":"AddOneEvent","markets":{}}`)
+	if err := Validate(s, missingField); err == nil {
+		t.Errorf("Validate(missing event_id) = nil, want error")
+	}
+
+	wrongType := []byte(`{"types":"AddOneEvent","event_id":"not-a-number","markets":{}}`)
+	if err := Validate(s, wrongType); err == nil {
+		t.Errorf("Validate(event_id as string) = nil, want error")
+	}
+}
+
+func TestValidate_EventInputMessageClose(t *testing.T) {
+	s := ByMessageType["RemoveOneEvent"]
+	if err := Validate(s, []byte(`{"types":"RemoveOneEvent","event_id":7}`)); err != nil {
+		t.Errorf("Validate(valid) = %v, want nil", err)
+	}
+	if err := Validate(s, []byte(`{"types":"RemoveOneEvent"}`)); err == nil {
+		t.Errorf("Validate(missing event_id) = nil, want error")
+	}
+}
+
+func TestValidate_ApiKeyEvents(t *testing.T) {
+	add := ByMessageType["add"]
+	if err := Validate(add, []byte(`{"action":"add","api_key":"k","privy_id":"p"}`)); err != nil {
+		t.Errorf("Validate(add valid) = %v, want nil", err)
+	}
+	if err := Validate(add, []byte(`{"action":"add","api_key":"k"}`)); err == nil {
+		t.Errorf("Validate(add missing privy_id) = nil, want error")
+	}
+
+	remove := ByMessageType["remove"]
+	if err := Validate(remove, []byte(`{"action":"remove","api_key":"k"}`)); err != nil {
+		t.Errorf("Validate(remove valid) = %v, want nil", err)
+	}
+}
+
+func TestValidate_TradeOnchainMessages(t *testing.T) {
+	req := ByMessageType["TradeOnchainSendRequest"]
+	validReq := []byte(`{"match_info":{},"trade_id":"t1","event_id":1,"market_id":2,"taker_trade_info":{},"maker_trade_infos":[]}`)
+	if err := Validate(req, validReq); err != nil {
+		t.Errorf("Validate(request valid) = %v, want nil", err)
+	}
+	if err := Validate(req, []byte(`{"trade_id":"t1"}`)); err == nil {
+		t.Errorf("Validate(request missing fields) = nil, want error")
+	}
+
+	resp := ByMessageType["TradeOnchainSendResponse"]
+	validResp := []byte(`{"trade_id":"t1","event_id":1,"market_id":2,"taker_trade_info":{},"maker_trade_infos":[],"tx_hash":"0xabc","success":true}`)
+	if err := Validate(resp, validResp); err != nil {
+		t.Errorf("Validate(response valid) = %v, want nil", err)
+	}
+	if err := Validate(resp, []byte(`{"trade_id":"t1","success":"yes"}`)); err == nil {
+		t.Errorf("Validate(response success as string) = nil, want error")
+	}
+}
@@ -0,0 +1,158 @@
+// Package streamtypes holds the canonical Go structs for the Redis Stream
+// messages the mock tools produce and consume (event_input_stream, the
+// onchain trade send/response streams, and api_key_stream). Before this
+// package existed, each tool redefined its own copy of these shapes
+// (send_event's EngineMQEventMarket vs. engine_input_consumer's
+// engineMQMarket, for example) and they could silently drift apart; every
+// producer and consumer in mock_go should import these instead of
+// hand-rolling its own.
+package streamtypes
+
+import "time"
+
+// CurrentVersion is the version every producer in this repo stamps onto the
+// envelopes below. Messages from before this field existed (or from a
+// producer that hasn't been updated yet) arrive with Version == 0, which
+// SupportedVersion treats as equivalent to version 1 rather than rejecting.
+const CurrentVersion = 1
+
+// SupportedVersion reports whether a consumer built against this package
+// knows how to decode a message stamped with version v. 0 is accepted as
+// the implicit pre-versioning version 1, so old producers and old captured
+// samples keep working; consumers should treat any version this returns
+// false for as "log and skip, don't crash" rather than a hard error, so a
+// version bump on the producer side never takes mock/test tooling down.
+func SupportedVersion(v int) bool {
+	return v == 0 || v == CurrentVersion
+}
+
+// EngineMQEventMarket is the per-market shape match_engine expects inside
+// EventInputMessageCreate.Markets.
+type EngineMQEventMarket struct {
+	MarketID int16    `json:"market_id"`
+	Outcomes []string `json:"outcomes"`
+	TokenIDs []string `json:"token_ids"`
+}
+
+// EventInputMessageCreate is published to event_input_stream to tell
+// match_engine about a newly added event.
+type EventInputMessageCreate struct {
+	Version int                            `json:"version,omitempty"`
+	Types   string                         `json:"types"`
+	EventID int64                          `json:"event_id"`
+	Markets map[string]EngineMQEventMarket `json:"markets"`
+	EndDate *time.Time                     `json:"end_date,omitempty"`
+}
+
+// EventInputMessageClose is published to event_input_stream to tell
+// match_engine an event has closed and should be removed from its view.
+type EventInputMessageClose struct {
+	Version int    `json:"version,omitempty"`
+	Types   string `json:"types"`
+	EventID int64  `json:"event_id"`
+}
+
+// ApiKeyEventAdd is published to api_key_stream to register a new API key.
+type ApiKeyEventAdd struct {
+	Version int    `json:"version,omitempty"`
+	Action  string `json:"action"`
+	ApiKey  string `json:"api_key"`
+	PrivyID string `json:"privy_id"`
+}
+
+// ApiKeyEventRemove is published to api_key_stream to revoke an API key.
+type ApiKeyEventRemove struct {
+	Version int    `json:"version,omitempty"`
+	Action  string `json:"action"`
+	ApiKey  string `json:"api_key"`
+}
+
+// SignatureOrderMsg is the wire shape of one signed order leg inside a
+// MatchOrderInfo, mirroring bot_go's PlaceOrderRequest field-for-field
+// under snake_case JSON tags (the onchain send service's own convention,
+// distinct from the engine API's camelCase convention PlaceOrderRequest
+// uses).
+type SignatureOrderMsg struct {
+	Expiration    string `json:"expiration"`
+	FeeRateBps    string `json:"fee_rate_bps"`
+	Maker         string `json:"maker"`
+	MakerAmount   string `json:"maker_amount"`
+	Nonce         string `json:"nonce"`
+	Salt          int64  `json:"salt"`
+	Side          string `json:"side"`
+	Signature     string `json:"signature"`
+	SignatureType int32  `json:"signature_type"`
+	Signer        string `json:"signer"`
+	Taker         string `json:"taker"`
+	TakerAmount   string `json:"taker_amount"`
+	TokenID       string `json:"token_id"`
+}
+
+// MatchOrderInfo bundles a matched taker order against the maker order(s)
+// it filled, ready to submit onchain.
+type MatchOrderInfo struct {
+	TakerOrder         SignatureOrderMsg   `json:"taker_order"`
+	MakerOrder         []SignatureOrderMsg `json:"maker_order"`
+	TakerFillAmount    string              `json:"taker_fill_amount"`
+	TakerReceiveAmount string              `json:"taker_receive_amount"`
+	MakerFillAmount    []string            `json:"maker_fill_amount"`
+}
+
+// TakerTradeInfo carries the bookkeeping fields the onchain send service
+// needs for the taker side of a trade (balance debits/credits, IDs for the
+// resulting fill).
+type TakerTradeInfo struct {
+	TakerSide            string `json:"taker_side"`
+	TakerUserID          int64  `json:"taker_user_id"`
+	TakerUsdcAmount      string `json:"taker_usdc_amount"`
+	TakerTokenAmount     string `json:"taker_token_amount"`
+	TakerTokenID         string `json:"taker_token_id"`
+	TakerOrderID         string `json:"taker_order_id"`
+	TakerUnfreezeAmount  string `json:"taker_unfreeze_amount"`
+	RealTakerUsdcAmount  string `json:"real_taker_usdc_amount"`
+	RealTakerTokenAmount string `json:"real_taker_token_amount"`
+	TakerPrivyUserID     string `json:"taker_privy_user_id"`
+	TakerOutcomeName     string `json:"taker_outcome_name"`
+}
+
+// MakerTradeInfo is the TakerTradeInfo counterpart for one maker leg of a
+// trade.
+type MakerTradeInfo struct {
+	MakerSide            string `json:"maker_side"`
+	MakerUserID          int64  `json:"maker_user_id"`
+	MakerUsdcAmount      string `json:"maker_usdc_amount"`
+	MakerTokenAmount     string `json:"maker_token_amount"`
+	MakerTokenID         string `json:"maker_token_id"`
+	MakerOrderID         string `json:"maker_order_id"`
+	MakerPrice           string `json:"maker_price"`
+	RealMakerUsdcAmount  string `json:"real_maker_usdc_amount"`
+	RealMakerTokenAmount string `json:"real_maker_token_amount"`
+	MakerPrivyUserID     string `json:"maker_privy_user_id"`
+	MakerOutcomeName     string `json:"maker_outcome_name"`
+}
+
+// TradeOnchainSendRequest is published to the onchain send-request stream
+// once match_engine has matched a trade and it's ready to settle onchain.
+type TradeOnchainSendRequest struct {
+	Version         int              `json:"version,omitempty"`
+	MatchInfo       MatchOrderInfo   `json:"match_info"`
+	TradeID         string           `json:"trade_id"`
+	EventID         int64            `json:"event_id"`
+	MarketID        int32            `json:"market_id"`
+	TakerTradeInfo  TakerTradeInfo   `json:"taker_trade_info"`
+	MakerTradeInfos []MakerTradeInfo `json:"maker_trade_infos"`
+}
+
+// TradeOnchainSendResponse is the onchain send service's reply, dropping
+// MatchInfo (already spent once the transaction lands) and adding the
+// transaction hash and outcome.
+type TradeOnchainSendResponse struct {
+	Version         int              `json:"version,omitempty"`
+	TradeID         string           `json:"trade_id"`
+	EventID         int64            `json:"event_id"`
+	MarketID        int32            `json:"market_id"`
+	TakerTradeInfo  TakerTradeInfo   `json:"taker_trade_info"`
+	MakerTradeInfos []MakerTradeInfo `json:"maker_trade_infos"`
+	TxHash          string           `json:"tx_hash"`
+	Success         bool             `json:"success"`
+}
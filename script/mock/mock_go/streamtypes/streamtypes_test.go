@@ -0,0 +1,168 @@
+package streamtypes
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestSupportedVersion(t *testing.T) {
+	if !SupportedVersion(0) {
+		t.Errorf("SupportedVersion(0) = false, want true (pre-versioning producers)")
+	}
+	if !SupportedVersion(CurrentVersion) {
+		t.Errorf("SupportedVersion(CurrentVersion) = false, want true")
+	}
+	if SupportedVersion(CurrentVersion + 1) {
+		t.Errorf("SupportedVersion(CurrentVersion+1) = true, want false")
+	}
+}
+
+func TestEventInputMessageCreate_RoundTrip(t *testing.T) {
+	endDate := time.Unix(1700000000, 0).UTC()
+	want := EventInputMessageCreate{
+		Types:   "add",
+		EventID: 42,
+		Markets: map[string]EngineMQEventMarket{
+			"m1": {MarketID: 1, Outcomes: []string{"Yes", "No"}, TokenIDs: []string{"111", "222"}},
+		},
+		EndDate: &endDate,
+	}
+
+	data, err := json.Marshal(want)
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+
+	var got EventInputMessageCreate
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+
+	if got.EventID != want.EventID || got.Types != want.Types {
+		t.Errorf("round trip mismatch: got %+v, want %+v", got, want)
+	}
+	if !got.EndDate.Equal(*want.EndDate) {
+		t.Errorf("EndDate round trip mismatch: got %v, want %v", got.EndDate, want.EndDate)
+	}
+	gotMarket, ok := got.Markets["m1"]
+	wantMarket := want.Markets["m1"]
+	if !ok || gotMarket.MarketID != wantMarket.MarketID ||
+		!reflect.DeepEqual(gotMarket.Outcomes, wantMarket.Outcomes) ||
+		!reflect.DeepEqual(gotMarket.TokenIDs, wantMarket.TokenIDs) {
+		t.Errorf("Markets round trip mismatch: got %+v, want %+v", got.Markets, want.Markets)
+	}
+}
+
+func TestEventInputMessageClose_RoundTrip(t *testing.T) {
+	want := EventInputMessageClose{Types: "remove", EventID: 7}
+
+	data, err := json.Marshal(want)
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+
+	var got EventInputMessageClose
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+
+	if got != want {
+		t.Errorf("round trip mismatch: got %+v, want %+v", got, want)
+	}
+}
+
+func TestApiKeyEvents_RoundTrip(t *testing.T) {
+	wantAdd := ApiKeyEventAdd{Action: "add", ApiKey: "key-1", PrivyID: "did:privy:abc"}
+	data, err := json.Marshal(wantAdd)
+	if err != nil {
+		t.Fatalf("Marshal add: %v", err)
+	}
+	var gotAdd ApiKeyEventAdd
+	if err := json.Unmarshal(data, &gotAdd); err != nil {
+		t.Fatalf("Unmarshal add: %v", err)
+	}
+	if gotAdd != wantAdd {
+		t.Errorf("add round trip mismatch: got %+v, want %+v", gotAdd, wantAdd)
+	}
+
+	wantRemove := ApiKeyEventRemove{Action: "remove", ApiKey: "key-1"}
+	data, err = json.Marshal(wantRemove)
+	if err != nil {
+		t.Fatalf("Marshal remove: %v", err)
+	}
+	var gotRemove ApiKeyEventRemove
+	if err := json.Unmarshal(data, &gotRemove); err != nil {
+		t.Fatalf("Unmarshal remove: %v", err)
+	}
+	if gotRemove != wantRemove {
+		t.Errorf("remove round trip mismatch: got %+v, want %+v", gotRemove, wantRemove)
+	}
+}
+
+func TestTradeOnchainSendRequest_RoundTrip(t *testing.T) {
+	want := TradeOnchainSendRequest{
+		MatchInfo: MatchOrderInfo{
+			TakerOrder:         SignatureOrderMsg{Maker: "0xabc", Salt: 1, Side: "buy", SignatureType: 0},
+			MakerOrder:         []SignatureOrderMsg{{Maker: "0xdef", Salt: 2, Side: "sell", SignatureType: 0}},
+			TakerFillAmount:    "100",
+			TakerReceiveAmount: "50",
+			MakerFillAmount:    []string{"50"},
+		},
+		TradeID:  "trade-1",
+		EventID:  1,
+		MarketID: 2,
+		TakerTradeInfo: TakerTradeInfo{
+			TakerSide:   "buy",
+			TakerUserID: 10,
+		},
+		MakerTradeInfos: []MakerTradeInfo{
+			{MakerSide: "sell", MakerUserID: 20},
+		},
+	}
+
+	data, err := json.Marshal(want)
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+
+	var got TradeOnchainSendRequest
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+
+	if got.TradeID != want.TradeID || got.EventID != want.EventID || got.MarketID != want.MarketID {
+		t.Errorf("round trip mismatch: got %+v, want %+v", got, want)
+	}
+	if got.MatchInfo.TakerOrder != want.MatchInfo.TakerOrder {
+		t.Errorf("TakerOrder round trip mismatch: got %+v, want %+v", got.MatchInfo.TakerOrder, want.MatchInfo.TakerOrder)
+	}
+	if len(got.MakerTradeInfos) != 1 || got.MakerTradeInfos[0] != want.MakerTradeInfos[0] {
+		t.Errorf("MakerTradeInfos round trip mismatch: got %+v, want %+v", got.MakerTradeInfos, want.MakerTradeInfos)
+	}
+}
+
+func TestTradeOnchainSendResponse_RoundTrip(t *testing.T) {
+	want := TradeOnchainSendResponse{
+		TradeID:  "trade-1",
+		EventID:  1,
+		MarketID: 2,
+		TxHash:   "0x1234",
+		Success:  true,
+	}
+
+	data, err := json.Marshal(want)
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+
+	var got TradeOnchainSendResponse
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+
+	if got.TradeID != want.TradeID || got.TxHash != want.TxHash || got.Success != want.Success {
+		t.Errorf("round trip mismatch: got %+v, want %+v", got, want)
+	}
+}
@@ -0,0 +1,245 @@
+// Command ticker consumes the depth websocket feed and maintains a compact
+// best-bid/best-ask/mid/last-trade-price summary per token, publishing
+// each update to both a Redis hash (for a dashboard to read on demand)
+// and a Redis stream (for anything that wants to react as it happens) -
+// so a lightweight consumer doesn't need to hold and diff full order
+// books just to know where the market currently is.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/redis/go-redis/v9"
+	"github.com/shopspring/decimal"
+	"gopkg.in/yaml.v3"
+
+	"depth"
+	"mock_go/wsclient"
+	"mock_go/wsdepth"
+	"streams"
+)
+
+// Redis 配置, 跟 trade_responder 一样指向 mock 环境的 COMMON_MQ。
+const (
+	RedisAddr     = "127.0.0.1:8889"
+	RedisPassword = "123456"
+	RedisDB       = 0
+
+	TickerStream = "deepsense:depth:service:ticker_update"
+	TickerKey    = "ticker_update"
+
+	// TickerHashPrefix keys each token's ticker hash as
+	// "<prefix>:<event_id>:<market_id>:<token_id>", matching the
+	// stream/key naming other mock_go services already use.
+	TickerHashPrefix = "deepsense:ticker"
+)
+
+// WSHost is the depth websocket server this service subscribes to, the
+// same server monitor.go and depth_checker watch.
+const WSHost = "predictionmarket-websocket-depth-290128242879.asia-northeast1.run.app"
+
+// TickerConfig describes the markets to publish a ticker for.
+type TickerConfig struct {
+	Markets []MarketConfig `yaml:"markets"`
+}
+
+// MarketConfig identifies one market to track.
+type MarketConfig struct {
+	EventID  int64 `yaml:"event_id"`
+	MarketID int16 `yaml:"market_id"`
+}
+
+// loadTickerConfig reads and validates the service's YAML config file.
+func loadTickerConfig(path string) (*TickerConfig, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read ticker config: %w", err)
+	}
+
+	var cfg TickerConfig
+	if err := yaml.Unmarshal(data, &cfg); err != nil {
+		return nil, fmt.Errorf("parse ticker config: %w", err)
+	}
+	if len(cfg.Markets) == 0 {
+		return nil, fmt.Errorf("ticker config has no markets")
+	}
+	return &cfg, nil
+}
+
+func marketKey(eventID int64, marketID int16) string {
+	return fmt.Sprintf("%d/%d", eventID, marketID)
+}
+
+// Ticker is the compact per-token summary this service publishes. BestBid,
+// BestAsk and Mid are left empty (rather than "0") when the corresponding
+// side of the book is empty, so a consumer can tell "no bid" apart from
+// "bid at zero".
+type Ticker struct {
+	EventID          int64  `json:"event_id"`
+	MarketID         int16  `json:"market_id"`
+	TokenID          string `json:"token_id"`
+	BestBid          string `json:"best_bid,omitempty"`
+	BestAsk          string `json:"best_ask,omitempty"`
+	Mid              string `json:"mid,omitempty"`
+	LatestTradePrice string `json:"last_trade_price,omitempty"`
+	UpdateID         uint64 `json:"update_id"`
+	Timestamp        int64  `json:"timestamp"`
+}
+
+// tickerFromBook builds a Ticker for one token's book. Every depth push
+// already carries that token's full book (see wsdepth.Message), so no
+// separate book reconstruction is needed to derive it.
+func tickerFromBook(market MarketConfig, tokenID string, book depth.Book, updateID uint64, timestamp int64) (Ticker, error) {
+	t := Ticker{
+		EventID:          market.EventID,
+		MarketID:         market.MarketID,
+		TokenID:          tokenID,
+		LatestTradePrice: book.LatestTradePrice,
+		UpdateID:         updateID,
+		Timestamp:        timestamp,
+	}
+
+	bid, ask := book.BestBid(), book.BestAsk()
+	if bid != nil {
+		t.BestBid = bid.Price
+	}
+	if ask != nil {
+		t.BestAsk = ask.Price
+	}
+	if bid == nil || ask == nil {
+		return t, nil
+	}
+
+	bidPrice, err := decimal.NewFromString(bid.Price)
+	if err != nil {
+		return Ticker{}, fmt.Errorf("parse best bid %q: %w", bid.Price, err)
+	}
+	askPrice, err := decimal.NewFromString(ask.Price)
+	if err != nil {
+		return Ticker{}, fmt.Errorf("parse best ask %q: %w", ask.Price, err)
+	}
+	t.Mid = bidPrice.Add(askPrice).Div(decimal.NewFromInt(2)).String()
+	return t, nil
+}
+
+func tickerHashKey(market MarketConfig, tokenID string) string {
+	return fmt.Sprintf("%s:%d:%d:%s", TickerHashPrefix, market.EventID, market.MarketID, tokenID)
+}
+
+// publish writes t to both its Redis hash and the ticker stream. A failure
+// on either side is logged and otherwise ignored - a stale/missing ticker
+// for one update is recoverable on the next push, unlike a trade or
+// settlement message that can't just be dropped.
+func publish(ctx context.Context, rdb redis.UniversalClient, producer *streams.Producer, t Ticker) {
+	fields := map[string]interface{}{
+		"best_bid":         t.BestBid,
+		"best_ask":         t.BestAsk,
+		"mid":              t.Mid,
+		"last_trade_price": t.LatestTradePrice,
+		"update_id":        t.UpdateID,
+		"timestamp":        t.Timestamp,
+	}
+	if err := rdb.HSet(ctx, tickerHashKey(MarketConfig{EventID: t.EventID, MarketID: t.MarketID}, t.TokenID), fields).Err(); err != nil {
+		log.Printf("⚠️  [%d/%d %s] HSET ticker failed: %v", t.EventID, t.MarketID, t.TokenID, err)
+	}
+
+	if _, err := producer.Send(ctx, t); err != nil {
+		log.Printf("⚠️  [%d/%d %s] publish ticker_update failed: %v", t.EventID, t.MarketID, t.TokenID, err)
+	}
+}
+
+func main() {
+	ctx := context.Background()
+
+	if len(os.Args) < 2 {
+		fmt.Println("Usage: go run . <ticker.yaml>")
+		os.Exit(1)
+	}
+
+	cfg, err := loadTickerConfig(os.Args[1])
+	if err != nil {
+		log.Fatalf("❌ %v", err)
+	}
+
+	rdb := streams.NewRedisClient(streams.RedisConfig{
+		Addrs:    []string{RedisAddr},
+		Password: RedisPassword,
+		DB:       RedisDB,
+	}.OverrideFromEnv())
+	if err := rdb.Ping(ctx).Err(); err != nil {
+		log.Fatalf("Failed to connect to Redis: %v", err)
+	}
+	log.Println("✅ Connected to Redis")
+
+	producer := streams.NewProducer(rdb, TickerStream, TickerKey)
+
+	interrupt := make(chan os.Signal, 1)
+	signal.Notify(interrupt, os.Interrupt)
+
+	markets := make(map[string]MarketConfig, len(cfg.Markets))
+	for _, market := range cfg.Markets {
+		markets[marketKey(market.EventID, market.MarketID)] = market
+	}
+
+	log.Printf("🔗 Connecting to wss://%s/depth", WSHost)
+
+	c, err := wsclient.Dial(wsclient.Config{
+		Scheme:            "wss",
+		Host:              WSHost,
+		Path:              "/depth",
+		HeartbeatInterval: 20 * time.Second,
+		OnMessage: func(messageType int, message []byte) {
+			var msg wsdepth.Message
+			if err := json.Unmarshal(message, &msg); err != nil {
+				log.Printf("⚠️  could not parse depth push: %v", err)
+				return
+			}
+
+			market, ok := markets[marketKey(msg.EventID, msg.MarketID)]
+			if !ok {
+				return
+			}
+
+			for tokenID, book := range msg.Updates() {
+				t, err := tickerFromBook(market, tokenID, book.ToDepthBook(), msg.UpdateID, msg.Timestamp)
+				if err != nil {
+					log.Printf("⚠️  [%d/%d %s] could not build ticker: %v", market.EventID, market.MarketID, tokenID, err)
+					continue
+				}
+				publish(ctx, rdb, producer, t)
+			}
+		},
+		OnClose: func(err error) {
+			if err != nil {
+				log.Println("read error:", err)
+			}
+		},
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer c.Close()
+
+	log.Println("✅ Connected to WebSocket Depth Server")
+
+	for _, market := range cfg.Markets {
+		subscribeData, _ := json.Marshal(wsdepth.SubscribeMessage{
+			Action:   wsdepth.ActionSubscribe,
+			EventID:  market.EventID,
+			MarketID: market.MarketID,
+		})
+		if err := c.Send(websocket.TextMessage, subscribeData); err != nil {
+			log.Fatalf("❌ subscribe %d/%d failed: %v", market.EventID, market.MarketID, err)
+		}
+		log.Printf("📨 Subscribed to depth: event_id=%d, market_id=%d", market.EventID, market.MarketID)
+	}
+
+	c.Run(interrupt)
+}
@@ -5,9 +5,15 @@ import (
 	"encoding/json"
 	"fmt"
 	"log"
+	"math/rand"
+	"os"
+	"strconv"
 	"time"
 
-	"github.com/redis/go-redis/v9"
+	"mock_go/chaos"
+	"mock_go/streamtypes"
+	"streams"
+	"tracing"
 )
 
 // Redis 配置
@@ -23,88 +29,43 @@ const (
 	ConsumerName    = "mock_consumer_1"
 )
 
-// TradeOnchainSendRequest 发送请求
-type TradeOnchainSendRequest struct {
-	MatchInfo       MatchOrderInfo   `json:"match_info"`
-	TradeID         string           `json:"trade_id"`
-	EventID         int64            `json:"event_id"`
-	MarketID        int32            `json:"market_id"`
-	TakerTradeInfo  TakerTradeInfo   `json:"taker_trade_info"`
-	MakerTradeInfos []MakerTradeInfo `json:"maker_trade_infos"`
-}
-
-// TradeOnchainSendResponse 发送响应（去掉 match_info，加上 tx_hash 和 success）
-type TradeOnchainSendResponse struct {
-	TradeID         string           `json:"trade_id"`
-	EventID         int64            `json:"event_id"`
-	MarketID        int32            `json:"market_id"`
-	TakerTradeInfo  TakerTradeInfo   `json:"taker_trade_info"`
-	MakerTradeInfos []MakerTradeInfo `json:"maker_trade_infos"`
-	TxHash          string           `json:"tx_hash"`
-	Success         bool             `json:"success"`
-}
-
-type MatchOrderInfo struct {
-	TakerOrder         SignatureOrderMsg   `json:"taker_order"`
-	MakerOrder         []SignatureOrderMsg `json:"maker_order"`
-	TakerFillAmount    string              `json:"taker_fill_amount"`
-	TakerReceiveAmount string              `json:"taker_receive_amount"`
-	MakerFillAmount    []string            `json:"maker_fill_amount"`
-}
-
-type SignatureOrderMsg struct {
-	Expiration    string `json:"expiration"`
-	FeeRateBps    string `json:"fee_rate_bps"`
-	Maker         string `json:"maker"`
-	MakerAmount   string `json:"maker_amount"`
-	Nonce         string `json:"nonce"`
-	Salt          int64  `json:"salt"`
-	Side          string `json:"side"`
-	Signature     string `json:"signature"`
-	SignatureType int32  `json:"signature_type"`
-	Signer        string `json:"signer"`
-	Taker         string `json:"taker"`
-	TakerAmount   string `json:"taker_amount"`
-	TokenID       string `json:"token_id"`
-}
+// ReorgRateEnv/ReorgDelayMsEnv let a test simulate a settled trade getting
+// reorged out: like chaos's CHAOS_*_RATE knobs, a probability in [0, 1]
+// applied per trade, off (0) by default so normal runs are unaffected.
+// Unlike chaos, this isn't Redis-layer flakiness - it's the responder
+// itself changing its mind about a trade after already reporting success,
+// which is a distinct failure mode downstream accounting needs to handle.
+const (
+	ReorgRateEnv      = "MOCK_REORG_RATE"
+	ReorgDelayMsEnv   = "MOCK_REORG_DELAY_MS"
+	defaultReorgDelay = 3 * time.Second
+)
 
-type TakerTradeInfo struct {
-	TakerSide            string `json:"taker_side"`
-	TakerUserID          int64  `json:"taker_user_id"`
-	TakerUsdcAmount      string `json:"taker_usdc_amount"`
-	TakerTokenAmount     string `json:"taker_token_amount"`
-	TakerTokenID         string `json:"taker_token_id"`
-	TakerOrderID         string `json:"taker_order_id"`
-	TakerUnfreezeAmount  string `json:"taker_unfreeze_amount"`
-	RealTakerUsdcAmount  string `json:"real_taker_usdc_amount"`
-	RealTakerTokenAmount string `json:"real_taker_token_amount"`
-	TakerPrivyUserID     string `json:"taker_privy_user_id"`
-	TakerOutcomeName     string `json:"taker_outcome_name"`
+func reorgRateFromEnv() float64 {
+	v, err := strconv.ParseFloat(os.Getenv(ReorgRateEnv), 64)
+	if err != nil {
+		return 0
+	}
+	return v
 }
 
-type MakerTradeInfo struct {
-	MakerSide            string `json:"maker_side"`
-	MakerUserID          int64  `json:"maker_user_id"`
-	MakerUsdcAmount      string `json:"maker_usdc_amount"`
-	MakerTokenAmount     string `json:"maker_token_amount"`
-	MakerTokenID         string `json:"maker_token_id"`
-	MakerOrderID         string `json:"maker_order_id"`
-	MakerPrice           string `json:"maker_price"`
-	RealMakerUsdcAmount  string `json:"real_maker_usdc_amount"`
-	RealMakerTokenAmount string `json:"real_maker_token_amount"`
-	MakerPrivyUserID     string `json:"maker_privy_user_id"`
-	MakerOutcomeName     string `json:"maker_outcome_name"`
+func reorgDelayFromEnv() time.Duration {
+	ms, err := strconv.Atoi(os.Getenv(ReorgDelayMsEnv))
+	if err != nil {
+		return defaultReorgDelay
+	}
+	return time.Duration(ms) * time.Millisecond
 }
 
 func main() {
 	ctx := context.Background()
 
 	// 连接 Redis
-	rdb := redis.NewClient(&redis.Options{
-		Addr:     RedisAddr,
+	rdb := chaos.Attach(streams.NewRedisClient(streams.RedisConfig{
+		Addrs:    []string{RedisAddr},
 		Password: RedisPassword,
 		DB:       RedisDB,
-	})
+	}.OverrideFromEnv()), chaos.ConfigFromEnv())
 
 	// 测试连接
 	if err := rdb.Ping(ctx).Err(); err != nil {
@@ -112,63 +73,65 @@ func main() {
 	}
 	log.Println("✅ Connected to Redis")
 
-	// 创建消费者组（如果不存在）
-	err := rdb.XGroupCreateMkStream(ctx, TradeSendStream, ConsumerGroup, "0").Err()
-	if err != nil && err.Error() != "BUSYGROUP Consumer Group name already exists" {
-		log.Printf("Warning: Failed to create consumer group: %v", err)
+	producer := streams.NewProducer(rdb, TradeRespStream, TradeRespKey)
+	consumer := streams.NewConsumer(rdb, streams.ConsumerConfig{
+		Stream:   TradeSendStream,
+		MsgKey:   TradeSendKey,
+		Group:    ConsumerGroup,
+		Consumer: ConsumerName,
+	})
+
+	reorgRate := reorgRateFromEnv()
+	reorgDelay := reorgDelayFromEnv()
+	if reorgRate > 0 {
+		log.Printf("♻️  reorg simulation enabled: rate=%.2f delay=%s", reorgRate, reorgDelay)
 	}
+	reorgSource := rand.New(rand.NewSource(time.Now().UnixNano()))
 
 	log.Printf("🚀 Trade Responder started, listening on stream: %s", TradeSendStream)
 
-	// 消费消息
-	for {
-		streams, err := rdb.XReadGroup(ctx, &redis.XReadGroupArgs{
-			Group:    ConsumerGroup,
-			Consumer: ConsumerName,
-			Streams:  []string{TradeSendStream, ">"},
-			Count:    10,
-			Block:    2 * time.Second,
-		}).Result()
-
-		if err != nil {
-			if err == redis.Nil {
-				// 没有新消息
-				continue
-			}
-			log.Printf("Error reading from stream: %v", err)
-			time.Sleep(1 * time.Second)
-			continue
-		}
-
-		for _, stream := range streams {
-			for _, message := range stream.Messages {
-				handleMessage(ctx, rdb, message)
-			}
-		}
+	if err := consumer.Run(ctx, func(ctx context.Context, raw []byte) error {
+		return handleMessage(ctx, producer, raw, reorgRate, reorgDelay, reorgSource)
+	}); err != nil {
+		log.Fatalf("Trade Responder stopped: %v", err)
 	}
 }
 
-func handleMessage(ctx context.Context, rdb *redis.Client, message redis.XMessage) {
-	// 提取消息内容
-	data, ok := message.Values[TradeSendKey].(string)
-	if !ok {
-		log.Printf("❌ Invalid message format: %v", message.Values)
-		return
+func handleMessage(ctx context.Context, producer *streams.Producer, raw []byte, reorgRate float64, reorgDelay time.Duration, reorgSource *rand.Rand) error {
+	// 反序列化请求。未知字段本来就会被 json.Unmarshal 忽略, 所以
+	// match_engine 侧加字段不需要这里跟着改; 只有 version 号超出这个
+	// mock 认识的范围时才提醒一下, 仍然按已知字段尽力处理。
+	var req streamtypes.TradeOnchainSendRequest
+	if err := json.Unmarshal(raw, &req); err != nil {
+		return fmt.Errorf("unmarshal request: %w", err)
+	}
+	if !streamtypes.SupportedVersion(req.Version) {
+		log.Printf("⚠️  trade request %s has version %d, newer than this responder's %d - decoding best-effort with the fields we know",
+			req.TradeID, req.Version, streamtypes.CurrentVersion)
 	}
 
-	// 反序列化请求
-	var req TradeOnchainSendRequest
-	if err := json.Unmarshal([]byte(data), &req); err != nil {
-		log.Printf("❌ Failed to unmarshal request: %v", err)
-		return
+	// 从请求消息里取出的 trace context 一路带到响应消息, 这样 bot 下单
+	// 到 trade_responder 处理再到响应回来的整条链路可以用同一个 trace_id
+	// 串起来。请求方还没接入 tracing 时这里也起一个新 trace, 保证响应流
+	// 至少总是带 trace_context, 不用等所有生产者都升级。
+	tc, ok := tracing.FromContext(ctx)
+	if ok {
+		if child, err := tc.NewChild(); err == nil {
+			tc = child
+		}
+	} else if newTC, err := tracing.New(); err != nil {
+		log.Printf("generate trace context failed: %v", err)
+	} else {
+		tc = newTC
 	}
 
-	log.Printf("📨 Received trade request: trade_id=%s, event_id=%d, market_id=%d",
-		req.TradeID, req.EventID, req.MarketID)
+	log.Printf("📨 Received trade request: trade_id=%s, event_id=%d, market_id=%d, trace_id=%s",
+		req.TradeID, req.EventID, req.MarketID, tc.TraceID)
 	log.Printf("📨 Received trade full request info: %+v\n", req)
 
 	// 构建响应（模拟成功）
-	resp := TradeOnchainSendResponse{
+	resp := streamtypes.TradeOnchainSendResponse{
+		Version:         streamtypes.CurrentVersion,
 		TradeID:         req.TradeID,
 		EventID:         req.EventID,
 		MarketID:        req.MarketID,
@@ -178,31 +141,37 @@ func handleMessage(ctx context.Context, rdb *redis.Client, message redis.XMessag
 		Success:         true,
 	}
 
-	// 序列化响应
-	respData, err := json.Marshal(resp)
-	if err != nil {
-		log.Printf("❌ Failed to marshal response: %v", err)
-		return
-	}
-
-	// 推送响应到 TRADE_RESPONSE_STREAM
-	_, err = rdb.XAdd(ctx, &redis.XAddArgs{
-		Stream: TradeRespStream,
-		Values: map[string]interface{}{
-			TradeRespKey: string(respData),
-		},
-	}).Result()
-
-	if err != nil {
-		log.Printf("❌ Failed to send response: %v", err)
-		return
+	// 推送响应到 TRADE_RESPONSE_STREAM, 带上 trace context
+	if _, err := producer.SendWithTrace(ctx, resp, tc); err != nil {
+		return fmt.Errorf("send response: %w", err)
 	}
 
 	log.Printf("✅ Sent trade response: trade_id=%s, tx_hash=%s, success=%v",
 		resp.TradeID, resp.TxHash, resp.Success)
 
-	// ACK 消息
-	if err := rdb.XAck(ctx, TradeSendStream, ConsumerGroup, message.ID).Err(); err != nil {
-		log.Printf("⚠️  Failed to ACK message: %v", err)
+	if reorgRate > 0 && reorgSource.Float64() < reorgRate {
+		go simulateReorg(producer, resp, tc, reorgDelay)
+	}
+	return nil
+}
+
+// simulateReorg sends a second response for the same trade_id after delay,
+// flipping Success to false to mimic the settlement getting reorged out
+// after this responder already reported it final - downstream accounting
+// needs to be able to reverse a trade it already booked as successful. It
+// runs detached from the request that triggered it (its own background
+// context, own goroutine), since the reorg is only "discovered" well after
+// handleMessage has already returned.
+func simulateReorg(producer *streams.Producer, original streamtypes.TradeOnchainSendResponse, tc tracing.TraceContext, delay time.Duration) {
+	time.Sleep(delay)
+
+	correction := original
+	correction.Success = false
+
+	if _, err := producer.SendWithTrace(context.Background(), correction, tc); err != nil {
+		log.Printf("❌ Failed to send simulated reorg correction for trade_id=%s: %v", original.TradeID, err)
+		return
 	}
+	log.Printf("♻️  Simulated reorg: trade_id=%s tx_hash=%s now reported success=false",
+		original.TradeID, original.TxHash)
 }
@@ -3,12 +3,13 @@ package main
 import (
 	"encoding/json"
 	"log"
-	"net/url"
 	"os"
 	"os/signal"
 	"time"
 
 	"github.com/gorilla/websocket"
+
+	"mock_go/wsclient"
 )
 
 // WebSocket 配置
@@ -35,29 +36,14 @@ func main() {
 	interrupt := make(chan os.Signal, 1)
 	signal.Notify(interrupt, os.Interrupt)
 
-	u := url.URL{Scheme: "wss", Host: WSHost, Path: "/depth"}
-	log.Printf("🔗 Connecting to %s", u.String())
-
-	c, _, err := websocket.DefaultDialer.Dial(u.String(), nil)
-	if err != nil {
-		log.Fatal("dial:", err)
-	}
-	defer c.Close()
-
-	log.Println("✅ Connected to WebSocket Depth Server")
-
-	done := make(chan struct{})
-
-	// 读取消息协程
-	go func() {
-		defer close(done)
-		for {
-			_, message, err := c.ReadMessage()
-			if err != nil {
-				log.Println("read error:", err)
-				return
-			}
+	log.Printf("🔗 Connecting to wss://%s/depth", WSHost)
 
+	c, err := wsclient.Dial(wsclient.Config{
+		Scheme:            "wss",
+		Host:              WSHost,
+		Path:              "/depth",
+		HeartbeatInterval: 20 * time.Second,
+		OnMessage: func(messageType int, message []byte) {
 			// 解析并美化输出
 			var data interface{}
 			if err := json.Unmarshal(message, &data); err == nil {
@@ -66,8 +52,31 @@ func main() {
 			} else {
 				log.Printf("📊 Received: %s", message)
 			}
-		}
-	}()
+		},
+		OnClose: func(err error) {
+			if err != nil {
+				log.Println("read error:", err)
+			}
+		},
+		OnInterrupt: func(c *wsclient.Client) {
+			log.Println("🛑 Interrupt received, closing connection...")
+
+			// 取消订阅
+			unsubscribe := DepthUnsubscribeMessage{
+				Action:   "unsubscribe",
+				EventID:  1,
+				MarketID: 1,
+			}
+			unsubscribeData, _ := json.Marshal(unsubscribe)
+			c.Send(websocket.TextMessage, unsubscribeData)
+		},
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer c.Close()
+
+	log.Println("✅ Connected to WebSocket Depth Server")
 
 	// 订阅深度数据
 	subscribe := DepthSubscribeMessage{
@@ -77,49 +86,11 @@ func main() {
 	}
 
 	subscribeData, _ := json.Marshal(subscribe)
-	if err := c.WriteMessage(websocket.TextMessage, subscribeData); err != nil {
+	if err := c.Send(websocket.TextMessage, subscribeData); err != nil {
 		log.Println("write error:", err)
 		return
 	}
 	log.Printf("📨 Subscribed to depth: event_id=1, market_id=1")
 
-	// 等待中断信号
-	ticker := time.NewTicker(20 * time.Second)
-	defer ticker.Stop()
-
-	for {
-		select {
-		case <-done:
-			return
-		case <-ticker.C:
-			// 定期发送心跳 - 发送文本消息"ping"
-			if err := c.WriteMessage(websocket.TextMessage, []byte("ping")); err != nil {
-				log.Println("ping error:", err)
-				return
-			}
-		case <-interrupt:
-			log.Println("🛑 Interrupt received, closing connection...")
-
-			// 取消订阅
-			unsubscribe := DepthUnsubscribeMessage{
-				Action:   "unsubscribe",
-				EventID:  1,
-				MarketID: 1,
-			}
-			unsubscribeData, _ := json.Marshal(unsubscribe)
-			c.WriteMessage(websocket.TextMessage, unsubscribeData)
-
-			// 正常关闭连接
-			err := c.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""))
-			if err != nil {
-				log.Println("write close:", err)
-				return
-			}
-			select {
-			case <-done:
-			case <-time.After(time.Second):
-			}
-			return
-		}
-	}
+	c.Run(interrupt)
 }
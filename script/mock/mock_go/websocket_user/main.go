@@ -2,13 +2,18 @@ package main
 
 import (
 	"encoding/json"
+	"fmt"
 	"log"
 	"net/url"
 	"os"
 	"os/signal"
+	"sync"
 	"time"
 
 	"github.com/gorilla/websocket"
+	"gopkg.in/yaml.v3"
+
+	"mock_go/wsclient"
 )
 
 // WebSocket 配置
@@ -25,34 +30,291 @@ type AuthMessage struct {
 	Auth string `json:"auth"`
 }
 
-func main() {
-	interrupt := make(chan os.Signal, 1)
-	signal.Notify(interrupt, os.Interrupt)
+// Scenario 描述断言模式下期望收到的用户事件序列
+type Scenario struct {
+	TimeoutSeconds int             `yaml:"timeout_seconds"`
+	Expect         []ExpectedEvent `yaml:"expect"`
+}
+
+// ExpectedEvent 是一条待匹配的事件，Fields 中列出的键值都必须出现在实际消息中
+type ExpectedEvent struct {
+	Fields map[string]any `yaml:",inline"`
+}
+
+// loadScenario 从 YAML 文件加载断言场景
+func loadScenario(path string) (*Scenario, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read scenario file: %w", err)
+	}
+
+	var scenario Scenario
+	if err := yaml.Unmarshal(data, &scenario); err != nil {
+		return nil, fmt.Errorf("parse scenario file: %w", err)
+	}
+
+	if scenario.TimeoutSeconds <= 0 {
+		scenario.TimeoutSeconds = 30
+	}
+	if len(scenario.Expect) == 0 {
+		return nil, fmt.Errorf("scenario has no expected events")
+	}
+
+	return &scenario, nil
+}
+
+// matchesEvent 判断 got 是否满足 want 中列出的所有字段
+func matchesEvent(want map[string]any, got map[string]any) bool {
+	for key, wantVal := range want {
+		gotVal, ok := got[key]
+		if !ok {
+			return false
+		}
+		if fmt.Sprintf("%v", gotVal) != fmt.Sprintf("%v", wantVal) {
+			return false
+		}
+	}
+	return true
+}
+
+// runAssert 连接、鉴权，然后等待收到的消息依次满足场景中的每个期望事件
+func runAssert(scenarioPath string) error {
+	scenario, err := loadScenario(scenarioPath)
+	if err != nil {
+		return err
+	}
 
-	// u := url.URL{Scheme: "ws", Host: WSHost, Path: "/user"}
 	u := url.URL{Scheme: "wss", Host: WSHost, Path: "/user"}
 	log.Printf("🔗 Connecting to %s", u.String())
 
 	c, _, err := websocket.DefaultDialer.Dial(u.String(), nil)
 	if err != nil {
-		log.Fatal("dial:", err)
+		return fmt.Errorf("dial: %w", err)
 	}
 	defer c.Close()
 
 	log.Println("✅ Connected to WebSocket User Server")
 
-	done := make(chan struct{})
+	authData, _ := json.Marshal(AuthMessage{Auth: PrivyToken})
+	if err := c.WriteMessage(websocket.TextMessage, authData); err != nil {
+		return fmt.Errorf("auth write error: %w", err)
+	}
+	log.Printf("🔐 Sent authentication with Privy token")
+
+	messages := make(chan map[string]any)
+	errs := make(chan error, 1)
 
-	// 读取消息协程
 	go func() {
-		defer close(done)
 		for {
-			_, message, err := c.ReadMessage()
+			_, raw, err := c.ReadMessage()
 			if err != nil {
-				log.Println("read error:", err)
+				errs <- fmt.Errorf("read error: %w", err)
 				return
 			}
 
+			var data map[string]any
+			if err := json.Unmarshal(raw, &data); err != nil {
+				log.Printf("⚠️  Skipping non-JSON message: %s", raw)
+				continue
+			}
+			messages <- data
+		}
+	}()
+
+	timeout := time.After(time.Duration(scenario.TimeoutSeconds) * time.Second)
+	step := 0
+
+	for step < len(scenario.Expect) {
+		select {
+		case data := <-messages:
+			want := scenario.Expect[step].Fields
+			if matchesEvent(want, data) {
+				log.Printf("✅ Matched step %d/%d: %v", step+1, len(scenario.Expect), want)
+				step++
+			} else {
+				log.Printf("👤 Received (no match): %v", data)
+			}
+		case err := <-errs:
+			return fmt.Errorf("scenario failed at step %d/%d: %w", step+1, len(scenario.Expect), err)
+		case <-timeout:
+			return fmt.Errorf("scenario timed out waiting for step %d/%d: %v", step+1, len(scenario.Expect), scenario.Expect[step].Fields)
+		}
+	}
+
+	log.Println("✅ Scenario completed: all expected events observed")
+	return nil
+}
+
+// MultiConfig 描述多账户会话复用器的配置
+type MultiConfig struct {
+	Accounts []AccountConfig `yaml:"accounts"`
+}
+
+// AccountConfig 是单个账户的鉴权信息
+type AccountConfig struct {
+	Name  string `yaml:"name"`
+	Token string `yaml:"token"`
+}
+
+// accountSession 跟踪单个账户会话的连接状态，用于健康上报
+type accountSession struct {
+	name        string
+	connected   bool
+	lastMessage time.Time
+	lastError   error
+	mu          sync.Mutex
+}
+
+func (s *accountSession) setConnected(connected bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.connected = connected
+}
+
+func (s *accountSession) touch() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.lastMessage = time.Now()
+}
+
+func (s *accountSession) setError(err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.lastError = err
+}
+
+func (s *accountSession) snapshot() (bool, time.Time, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.connected, s.lastMessage, s.lastError
+}
+
+// loadMultiConfig 从 YAML 文件加载多账户配置
+func loadMultiConfig(path string) (*MultiConfig, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read multi-account config: %w", err)
+	}
+
+	var cfg MultiConfig
+	if err := yaml.Unmarshal(data, &cfg); err != nil {
+		return nil, fmt.Errorf("parse multi-account config: %w", err)
+	}
+	if len(cfg.Accounts) == 0 {
+		return nil, fmt.Errorf("multi-account config has no accounts")
+	}
+
+	return &cfg, nil
+}
+
+// runAccountSession 为单个账户建立连接、鉴权并持续打印带标签的消息，直到收到中断信号
+func runAccountSession(interrupt <-chan os.Signal, account AccountConfig, session *accountSession) {
+	tag := account.Name
+
+	c, err := wsclient.Dial(wsclient.Config{
+		Scheme:            "wss",
+		Host:              WSHost,
+		Path:              "/user",
+		HeartbeatInterval: 30 * time.Second,
+		OnMessage: func(messageType int, message []byte) {
+			session.touch()
+			log.Printf("👤 [%s] Received: %s", tag, message)
+		},
+		OnClose: func(err error) {
+			if err != nil {
+				session.setError(fmt.Errorf("read error: %w", err))
+				log.Printf("❌ [%s] read error: %v", tag, err)
+			}
+		},
+	})
+	if err != nil {
+		session.setError(err)
+		log.Printf("❌ [%s] dial failed: %v", tag, err)
+		return
+	}
+	defer c.Close()
+
+	session.setConnected(true)
+	defer session.setConnected(false)
+	log.Printf("✅ [%s] Connected to WebSocket User Server", tag)
+
+	authData, _ := json.Marshal(AuthMessage{Auth: account.Token})
+	if err := c.Send(websocket.TextMessage, authData); err != nil {
+		session.setError(fmt.Errorf("auth write error: %w", err))
+		log.Printf("❌ [%s] auth write error: %v", tag, err)
+		return
+	}
+	log.Printf("🔐 [%s] Sent authentication with Privy token", tag)
+
+	c.Run(interrupt)
+}
+
+// runMulti 为配置中的每个账户开启一个独立会话，并定期打印每个账户的连接健康状况
+func runMulti(configPath string) {
+	cfg, err := loadMultiConfig(configPath)
+	if err != nil {
+		log.Fatalf("❌ %v", err)
+	}
+
+	interrupt := make(chan os.Signal, 1)
+	signal.Notify(interrupt, os.Interrupt)
+
+	sessions := make(map[string]*accountSession, len(cfg.Accounts))
+
+	var wg sync.WaitGroup
+	for _, account := range cfg.Accounts {
+		session := &accountSession{name: account.Name}
+		sessions[account.Name] = session
+
+		wg.Add(1)
+		go func(account AccountConfig, session *accountSession) {
+			defer wg.Done()
+			runAccountSession(interrupt, account, session)
+		}(account, session)
+	}
+
+	// 独立协程定期打印每个账户的健康状况
+	go func() {
+		ticker := time.NewTicker(15 * time.Second)
+		defer ticker.Stop()
+		for range ticker.C {
+			for _, account := range cfg.Accounts {
+				connected, lastMessage, lastErr := sessions[account.Name].snapshot()
+				log.Printf("📋 [%s] connected=%v last_message=%s last_error=%v",
+					account.Name, connected, lastMessage.Format(time.RFC3339), lastErr)
+			}
+		}
+	}()
+
+	wg.Wait()
+	log.Println("🛑 All account sessions closed")
+}
+
+func main() {
+	if len(os.Args) >= 3 && os.Args[1] == "--assert" {
+		if err := runAssert(os.Args[2]); err != nil {
+			log.Printf("❌ %v", err)
+			os.Exit(1)
+		}
+		os.Exit(0)
+	}
+
+	if len(os.Args) >= 3 && os.Args[1] == "--multi" {
+		runMulti(os.Args[2])
+		return
+	}
+
+	interrupt := make(chan os.Signal, 1)
+	signal.Notify(interrupt, os.Interrupt)
+
+	log.Printf("🔗 Connecting to wss://%s/user", WSHost)
+
+	c, err := wsclient.Dial(wsclient.Config{
+		Scheme:            "wss",
+		Host:              WSHost,
+		Path:              "/user",
+		HeartbeatInterval: 30 * time.Second,
+		OnMessage: func(messageType int, message []byte) {
 			// 解析并美化输出
 			var data interface{}
 			if err := json.Unmarshal(message, &data); err == nil {
@@ -61,8 +323,22 @@ func main() {
 			} else {
 				log.Printf("👤 Received: %s", message)
 			}
-		}
-	}()
+		},
+		OnClose: func(err error) {
+			if err != nil {
+				log.Println("read error:", err)
+			}
+		},
+		OnInterrupt: func(c *wsclient.Client) {
+			log.Println("🛑 Interrupt received, closing connection...")
+		},
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer c.Close()
+
+	log.Println("✅ Connected to WebSocket User Server")
 
 	// 先发送鉴权消息
 	authMsg := AuthMessage{
@@ -70,40 +346,11 @@ func main() {
 	}
 
 	authData, _ := json.Marshal(authMsg)
-	if err := c.WriteMessage(websocket.TextMessage, authData); err != nil {
+	if err := c.Send(websocket.TextMessage, authData); err != nil {
 		log.Println("auth write error:", err)
 		return
 	}
 	log.Printf("🔐 Sent authentication with Privy token")
 
-	// 等待中断信号
-	ticker := time.NewTicker(30 * time.Second)
-	defer ticker.Stop()
-
-	for {
-		select {
-		case <-done:
-			return
-		case <-ticker.C:
-			// 定期发送心跳 - 发送文本消息"ping"
-			if err := c.WriteMessage(websocket.TextMessage, []byte("ping")); err != nil {
-				log.Println("ping error:", err)
-				return
-			}
-		case <-interrupt:
-			log.Println("🛑 Interrupt received, closing connection...")
-
-			// 正常关闭连接
-			err := c.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""))
-			if err != nil {
-				log.Println("write close:", err)
-				return
-			}
-			select {
-			case <-done:
-			case <-time.After(time.Second):
-			}
-			return
-		}
-	}
+	c.Run(interrupt)
 }
@@ -0,0 +1,156 @@
+// Package wsclient provides a small, reusable websocket client with
+// heartbeat, pong-timeout detection and close-handshake handling, so the
+// depth client, user client and other future callers don't each hand-roll
+// the same dial/ping/interrupt loop.
+package wsclient
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// Config configures a Client.
+type Config struct {
+	// Scheme/Host/Path identify the server to dial, e.g. "wss", "host:port", "/depth".
+	Scheme string
+	Host   string
+	Path   string
+
+	// HeartbeatInterval is how often a heartbeat frame is sent while Run is
+	// active. Zero disables heartbeats.
+	HeartbeatInterval time.Duration
+
+	// HeartbeatPayload is the text frame sent on every heartbeat tick.
+	// Defaults to "ping" if empty, matching the mock services' protocol.
+	HeartbeatPayload []byte
+
+	// PongTimeout, if nonzero, makes Run treat the connection as dead when
+	// no pong control frame arrives within this duration of the last one
+	// (or of connecting, for the first interval).
+	PongTimeout time.Duration
+
+	// OnMessage is invoked for every application (text/binary) message
+	// received. It runs on the Run goroutine, so it must not block.
+	OnMessage func(messageType int, data []byte)
+
+	// OnClose is invoked once when the read loop stops, with the error
+	// that caused it (nil for a clean, requested close).
+	OnClose func(err error)
+
+	// OnInterrupt, if set, runs right before the close handshake is sent
+	// in response to an interrupt signal, so callers can send a final
+	// message (e.g. an unsubscribe) while the connection is still open.
+	OnInterrupt func(c *Client)
+}
+
+// Client wraps a gorilla/websocket connection with heartbeat and
+// close-handshake handling driven by Run.
+type Client struct {
+	conn *websocket.Conn
+	cfg  Config
+}
+
+// Dial connects to the server described by cfg and returns a ready Client.
+func Dial(cfg Config) (*Client, error) {
+	if len(cfg.HeartbeatPayload) == 0 {
+		cfg.HeartbeatPayload = []byte("ping")
+	}
+
+	u := url.URL{Scheme: cfg.Scheme, Host: cfg.Host, Path: cfg.Path}
+	conn, _, err := websocket.DefaultDialer.Dial(u.String(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("dial %s: %w", u.String(), err)
+	}
+
+	if cfg.PongTimeout > 0 {
+		conn.SetReadDeadline(time.Now().Add(cfg.PongTimeout))
+		conn.SetPongHandler(func(string) error {
+			return conn.SetReadDeadline(time.Now().Add(cfg.PongTimeout))
+		})
+	}
+
+	return &Client{conn: conn, cfg: cfg}, nil
+}
+
+// Send writes a single message frame (e.g. websocket.TextMessage) to the server.
+func (c *Client) Send(messageType int, data []byte) error {
+	return c.conn.WriteMessage(messageType, data)
+}
+
+// Run drives the read loop, sending heartbeats on the configured interval,
+// until the connection fails, interrupt fires, or PongTimeout is exceeded.
+// On interrupt it performs a normal close handshake before returning.
+func (c *Client) Run(interrupt <-chan os.Signal) error {
+	done := make(chan struct{})
+	var readErr error
+
+	go func() {
+		defer close(done)
+		for {
+			messageType, data, err := c.conn.ReadMessage()
+			if err != nil {
+				readErr = err
+				return
+			}
+			if c.cfg.OnMessage != nil {
+				c.cfg.OnMessage(messageType, data)
+			}
+		}
+	}()
+
+	var ticker *time.Ticker
+	var tickerC <-chan time.Time
+	if c.cfg.HeartbeatInterval > 0 {
+		ticker = time.NewTicker(c.cfg.HeartbeatInterval)
+		defer ticker.Stop()
+		tickerC = ticker.C
+	}
+
+	for {
+		select {
+		case <-done:
+			if c.cfg.OnClose != nil {
+				c.cfg.OnClose(readErr)
+			}
+			return readErr
+		case <-tickerC:
+			if err := c.conn.WriteMessage(websocket.TextMessage, c.cfg.HeartbeatPayload); err != nil {
+				if c.cfg.OnClose != nil {
+					c.cfg.OnClose(err)
+				}
+				return err
+			}
+		case <-interrupt:
+			return c.closeGracefully(done)
+		}
+	}
+}
+
+// closeGracefully performs the close handshake and waits briefly for the
+// read loop to observe it, or for a short timeout.
+func (c *Client) closeGracefully(done <-chan struct{}) error {
+	if c.cfg.OnInterrupt != nil {
+		c.cfg.OnInterrupt(c)
+	}
+
+	err := c.conn.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""))
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+	}
+
+	if c.cfg.OnClose != nil {
+		c.cfg.OnClose(nil)
+	}
+	return err
+}
+
+// Close closes the underlying connection without a close handshake.
+func (c *Client) Close() error {
+	return c.conn.Close()
+}
@@ -0,0 +1,125 @@
+// Package wsdepth defines the wire types for the production depth
+// websocket feed and reconstructs a depth.Snapshot from them, so every
+// mock_go tool that consumes the feed (depth_checker, ticker, ...) shares
+// one implementation of the subscribe protocol and of the feed's
+// total_quantity field naming, instead of each hand-rolling its own copy.
+package wsdepth
+
+import (
+	"sync"
+
+	"depth"
+)
+
+// Subscribe/Unsubscribe are the actions accepted by the feed's
+// subscribe protocol.
+const (
+	ActionSubscribe   = "subscribe"
+	ActionUnsubscribe = "unsubscribe"
+)
+
+// SubscribeMessage matches websocket_depth's subscribe protocol.
+type SubscribeMessage struct {
+	Action   string `json:"action"`
+	EventID  int64  `json:"event_id"`
+	MarketID int16  `json:"market_id"`
+}
+
+// PriceLevel is one price level as the websocket feed encodes it - unlike
+// the REST depth API's PriceLevel, the quantity field is named
+// total_quantity, so it can't reuse depth.PriceLevel directly.
+type PriceLevel struct {
+	Price         string `json:"price"`
+	TotalQuantity string `json:"total_quantity"`
+}
+
+func (l PriceLevel) toDepthLevel() depth.PriceLevel {
+	return depth.PriceLevel{Price: l.Price, Quantity: l.TotalQuantity}
+}
+
+// TokenBook is one token's book as pushed by the websocket feed.
+type TokenBook struct {
+	LatestTradePrice string       `json:"latest_trade_price"`
+	Bids             []PriceLevel `json:"bids"`
+	Asks             []PriceLevel `json:"asks"`
+}
+
+// ToDepthBook converts b into the shared depth package's Book shape.
+func (b TokenBook) ToDepthBook() depth.Book {
+	return depth.Book{
+		LatestTradePrice: b.LatestTradePrice,
+		Bids:             toDepthLevels(b.Bids),
+		Asks:             toDepthLevels(b.Asks),
+	}
+}
+
+func toDepthLevels(levels []PriceLevel) []depth.PriceLevel {
+	out := make([]depth.PriceLevel, len(levels))
+	for i, level := range levels {
+		out[i] = level.toDepthLevel()
+	}
+	return out
+}
+
+// Message matches both of the feed's push shapes: a full snapshot on
+// subscribe (Depths populated) and an incremental update after that
+// (Changes populated). Either way, each token entry present in the
+// message is a full replacement of that token's book rather than a
+// level-by-level delta, matching the server's own apply_price_changes
+// semantics - so applying either kind of message is the same operation.
+// Exactly one of Depths/Changes is ever populated on a given message, so
+// one struct covers both without needing a discriminator field.
+type Message struct {
+	EventID   int64                `json:"event_id"`
+	MarketID  int16                `json:"market_id"`
+	UpdateID  uint64               `json:"update_id"`
+	Timestamp int64                `json:"timestamp"`
+	Depths    map[string]TokenBook `json:"depths"`
+	Changes   map[string]TokenBook `json:"changes"`
+}
+
+// Updates returns whichever of Depths/Changes is populated on m.
+func (m Message) Updates() map[string]TokenBook {
+	if m.Depths != nil {
+		return m.Depths
+	}
+	return m.Changes
+}
+
+// BookState reconstructs one market's current snapshot from the stream of
+// messages seen so far, applying each token update as a full replacement.
+type BookState struct {
+	mu       sync.Mutex
+	updateID uint64
+	books    map[string]depth.Book
+}
+
+// Apply folds msg into the state, replacing every token it mentions.
+func (s *BookState) Apply(msg Message) {
+	updates := msg.Updates()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.books == nil {
+		s.books = make(map[string]depth.Book, len(updates))
+	}
+	for tokenID, book := range updates {
+		s.books[tokenID] = book.ToDepthBook()
+	}
+	s.updateID = msg.UpdateID
+}
+
+// Snapshot returns the current snapshot and whether one has been observed
+// yet.
+func (s *BookState) Snapshot() (depth.Snapshot, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.books == nil {
+		return depth.Snapshot{}, false
+	}
+	depths := make(map[string]depth.Book, len(s.books))
+	for tokenID, book := range s.books {
+		depths[tokenID] = book
+	}
+	return depth.Snapshot{UpdateID: s.updateID, Depths: depths}, true
+}
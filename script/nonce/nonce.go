@@ -0,0 +1,137 @@
+// Package nonce serializes transaction nonce allocation for one or more
+// sending addresses across goroutines, so concurrent submitters (a
+// settlement worker, an approval helper, a funding tool) never hand out the
+// same nonce twice or race PendingNonceAt against each other. It's kept
+// free of any chain-client dependency - addresses are plain strings and the
+// chain lookup is injected - the same way streams/tracing stay
+// transport-agnostic rather than pulling in a specific broker's client.
+package nonce
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// Source resolves the next nonce a fresh (non-local) view of the chain
+// would use for address, i.e. what ethclient.Client.PendingNonceAt reports.
+// Callers wrap their concrete client to satisfy this, e.g.
+// func(ctx, addr string) (uint64, error) { return client.PendingNonceAt(ctx, common.HexToAddress(addr)) }.
+type Source interface {
+	PendingNonceAt(ctx context.Context, address string) (uint64, error)
+}
+
+// addressState tracks one address's allocation cursor. mu is held for the
+// lifetime of one Acquire/Release pair, so only one goroutine at a time can
+// be mid-allocation for a given address.
+type addressState struct {
+	mu   sync.Mutex
+	next uint64
+	have bool
+}
+
+// Manager hands out nonces one at a time per address, resolving the
+// starting point from Source on first use and advancing locally after that
+// so concurrent submitters don't each pay for a PendingNonceAt round trip.
+type Manager struct {
+	source Source
+
+	mu     sync.Mutex
+	states map[string]*addressState
+}
+
+// NewManager builds a Manager that resolves unseen addresses' starting
+// nonce from source.
+func NewManager(source Source) *Manager {
+	return &Manager{source: source, states: make(map[string]*addressState)}
+}
+
+func (m *Manager) stateFor(address string) *addressState {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	s, ok := m.states[address]
+	if !ok {
+		s = &addressState{}
+		m.states[address] = s
+	}
+	return s
+}
+
+// Release, returned by Acquire, tells the Manager what became of the
+// allocated nonce: used=true advances the address's cursor past it (a
+// transaction was actually broadcast with it, whether or not it went on to
+// confirm), used=false leaves the cursor where it was so the same nonce is
+// handed to the next caller instead of leaving a permanent gap (e.g. a
+// signing error before SendTransaction was ever called). It must be called
+// exactly once; later calls are no-ops.
+type Release func(used bool)
+
+// Acquire locks address's allocation cursor and returns the next nonce to
+// use. The caller must call the returned Release exactly once before any
+// other goroutine can Acquire for the same address, so hold it only long
+// enough to sign and submit.
+func (m *Manager) Acquire(ctx context.Context, address string) (uint64, Release, error) {
+	state := m.stateFor(address)
+	state.mu.Lock()
+
+	if !state.have {
+		pending, err := m.source.PendingNonceAt(ctx, address)
+		if err != nil {
+			state.mu.Unlock()
+			return 0, nil, fmt.Errorf("fetch pending nonce for %s: %w", address, err)
+		}
+		state.next = pending
+		state.have = true
+	}
+
+	n := state.next
+	var released bool
+	release := func(used bool) {
+		if released {
+			return
+		}
+		released = true
+		if used {
+			state.next = n + 1
+		}
+		state.mu.Unlock()
+	}
+	return n, release, nil
+}
+
+// IsDesyncError reports whether err looks like the "nonce too low"/"nonce
+// too high" family of errors go-ethereum's RPC surfaces when a Manager's
+// local view has drifted from the chain's - e.g. after a process restart
+// with in-flight transactions, or another sender using the same address
+// outside this Manager. ReportDesync should be called when this returns
+// true.
+func IsDesyncError(err error) bool {
+	if err == nil {
+		return false
+	}
+	msg := strings.ToLower(err.Error())
+	return strings.Contains(msg, "nonce too low") ||
+		strings.Contains(msg, "nonce too high") ||
+		strings.Contains(msg, "nonce is too low") ||
+		strings.Contains(msg, "nonce is too high")
+}
+
+// ReportDesync resyncs address's cursor from Source - callers should call
+// this (after releasing their current Acquire with used=false, since the
+// transaction never actually consumed a nonce the chain accepted) once
+// IsDesyncError identifies a send failure as a nonce desync, then Acquire
+// again to get a corrected nonce.
+func (m *Manager) ReportDesync(ctx context.Context, address string) error {
+	state := m.stateFor(address)
+	state.mu.Lock()
+	defer state.mu.Unlock()
+
+	pending, err := m.source.PendingNonceAt(ctx, address)
+	if err != nil {
+		return fmt.Errorf("resync nonce for %s: %w", address, err)
+	}
+	state.next = pending
+	state.have = true
+	return nil
+}
@@ -0,0 +1,319 @@
+// Command ohlc_aggregator polls the trades table for newly matched trades
+// and rolls them up into 1m/5m/1h OHLCV candles per token, stored in the
+// candles table (see script/migrations for the schema). Strategies that
+// need price history for volatility-based spread sizing can then read
+// candles directly from Postgres, or via this binary's query subcommand.
+//
+// Usage:
+//
+//	go run . aggregate <config.yaml>
+//	go run . query <token_id> <interval> [limit]
+package main
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"time"
+
+	_ "github.com/lib/pq"
+	"github.com/shopspring/decimal"
+)
+
+const (
+	DBHost = "34.146.110.159"
+	DBPort = 5432
+	DBUser = "postgres"
+	DBName = "deepsense"
+)
+
+// Interval is one of the supported candle bucket sizes.
+type Interval string
+
+const (
+	Interval1m Interval = "1m"
+	Interval5m Interval = "5m"
+	Interval1h Interval = "1h"
+)
+
+func (i Interval) duration() (time.Duration, error) {
+	switch i {
+	case Interval1m:
+		return time.Minute, nil
+	case Interval5m:
+		return 5 * time.Minute, nil
+	case Interval1h:
+		return time.Hour, nil
+	default:
+		return 0, fmt.Errorf("unknown interval %q", i)
+	}
+}
+
+// bucketStart truncates t down to the start of the bucket it falls in for
+// the given interval, in UTC so that bucket boundaries are stable
+// regardless of the caller's local timezone.
+func bucketStart(t time.Time, interval Interval) (time.Time, error) {
+	d, err := interval.duration()
+	if err != nil {
+		return time.Time{}, err
+	}
+	return t.UTC().Truncate(d), nil
+}
+
+// trade is the subset of the trades table this aggregator needs.
+type trade struct {
+	MatchTimestamp time.Time
+	EventID        int64
+	MarketID       int16
+	TokenID        string
+	AvgPrice       decimal.Decimal
+	TokenAmount    decimal.Decimal
+}
+
+func fetchLastProcessed(db *sql.DB) (time.Time, error) {
+	var ts time.Time
+	err := db.QueryRow(`SELECT last_match_timestamp FROM candle_aggregator_state WHERE id = 1`).Scan(&ts)
+	if err == sql.ErrNoRows {
+		return time.Unix(0, 0).UTC(), nil
+	}
+	if err != nil {
+		return time.Time{}, fmt.Errorf("fetch last processed timestamp: %w", err)
+	}
+	return ts, nil
+}
+
+func saveLastProcessed(db *sql.DB, ts time.Time) error {
+	_, err := db.Exec(`
+		INSERT INTO candle_aggregator_state (id, last_match_timestamp)
+		VALUES (1, $1)
+		ON CONFLICT (id) DO UPDATE SET last_match_timestamp = EXCLUDED.last_match_timestamp
+	`, ts)
+	if err != nil {
+		return fmt.Errorf("save last processed timestamp: %w", err)
+	}
+	return nil
+}
+
+func fetchNewTrades(db *sql.DB, since time.Time) ([]trade, error) {
+	rows, err := db.Query(`
+		SELECT match_timestamp, event_id, market_id, token_id, avg_price, token_amount
+		FROM trades
+		WHERE match_timestamp > $1
+		ORDER BY match_timestamp
+	`, since)
+	if err != nil {
+		return nil, fmt.Errorf("query trades: %w", err)
+	}
+	defer rows.Close()
+
+	var trades []trade
+	for rows.Next() {
+		var t trade
+		var avgPrice, tokenAmount string
+		if err := rows.Scan(&t.MatchTimestamp, &t.EventID, &t.MarketID, &t.TokenID, &avgPrice, &tokenAmount); err != nil {
+			return nil, fmt.Errorf("scan trade: %w", err)
+		}
+		t.AvgPrice, err = decimal.NewFromString(avgPrice)
+		if err != nil {
+			return nil, fmt.Errorf("parse avg_price %q: %w", avgPrice, err)
+		}
+		t.TokenAmount, err = decimal.NewFromString(tokenAmount)
+		if err != nil {
+			return nil, fmt.Errorf("parse token_amount %q: %w", tokenAmount, err)
+		}
+		trades = append(trades, t)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate trades: %w", err)
+	}
+	return trades, nil
+}
+
+// upsertCandle folds one trade into the candle for its bucket. open is only
+// ever set on insert (ON CONFLICT DO UPDATE leaves it untouched), so the
+// first trade seen for a bucket fixes its open price.
+func upsertCandle(db *sql.DB, t trade, interval Interval) error {
+	start, err := bucketStart(t.MatchTimestamp, interval)
+	if err != nil {
+		return err
+	}
+
+	_, err = db.Exec(`
+		INSERT INTO candles (token_id, event_id, market_id, interval, bucket_start, open, high, low, close, volume, trade_count)
+		VALUES ($1, $2, $3, $4, $5, $6, $6, $6, $6, $7, 1)
+		ON CONFLICT (token_id, interval, bucket_start) DO UPDATE SET
+			high = GREATEST(candles.high, EXCLUDED.high),
+			low = LEAST(candles.low, EXCLUDED.low),
+			close = EXCLUDED.close,
+			volume = candles.volume + EXCLUDED.volume,
+			trade_count = candles.trade_count + 1,
+			updated_at = NOW()
+	`, t.TokenID, t.EventID, t.MarketID, string(interval), start, t.AvgPrice.String(), t.TokenAmount.String())
+	if err != nil {
+		return fmt.Errorf("upsert candle token_id=%s interval=%s bucket_start=%s: %w", t.TokenID, interval, start, err)
+	}
+	return nil
+}
+
+var allIntervals = []Interval{Interval1m, Interval5m, Interval1h}
+
+func runOnce(db *sql.DB) error {
+	since, err := fetchLastProcessed(db)
+	if err != nil {
+		return err
+	}
+
+	trades, err := fetchNewTrades(db, since)
+	if err != nil {
+		return err
+	}
+	if len(trades) == 0 {
+		return nil
+	}
+
+	for _, t := range trades {
+		for _, interval := range allIntervals {
+			if err := upsertCandle(db, t, interval); err != nil {
+				return err
+			}
+		}
+	}
+
+	latest := trades[len(trades)-1].MatchTimestamp
+	if err := saveLastProcessed(db, latest); err != nil {
+		return err
+	}
+
+	log.Printf("✅ aggregated %d trade(s) up to %s", len(trades), latest.Format(time.RFC3339))
+	return nil
+}
+
+func connectDB() (*sql.DB, error) {
+	dbPassword := os.Getenv("OHLC_DB_PASSWORD")
+	if dbPassword == "" {
+		return nil, fmt.Errorf("OHLC_DB_PASSWORD environment variable is not set")
+	}
+
+	connStr := fmt.Sprintf("host=%s port=%d user=%s password=%s dbname=%s sslmode=require",
+		DBHost, DBPort, DBUser, dbPassword, DBName)
+	db, err := sql.Open("postgres", connStr)
+	if err != nil {
+		return nil, fmt.Errorf("open database: %w", err)
+	}
+	if err := db.Ping(); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("ping database: %w", err)
+	}
+	return db, nil
+}
+
+func runAggregate(pollIntervalSec int) {
+	db, err := connectDB()
+	if err != nil {
+		log.Fatalf("❌ %v", err)
+	}
+	defer db.Close()
+	log.Println("Connected to database")
+
+	if pollIntervalSec <= 0 {
+		pollIntervalSec = 5
+	}
+	ticker := time.NewTicker(time.Duration(pollIntervalSec) * time.Second)
+	defer ticker.Stop()
+
+	log.Printf("🚀 OHLC aggregator started, polling every %ds", pollIntervalSec)
+	for {
+		if err := runOnce(db); err != nil {
+			log.Printf("⚠️  aggregation pass failed: %v", err)
+		}
+		<-ticker.C
+	}
+}
+
+func runQuery(tokenID string, interval Interval, limit int) {
+	db, err := connectDB()
+	if err != nil {
+		log.Fatalf("❌ %v", err)
+	}
+	defer db.Close()
+
+	rows, err := db.Query(`
+		SELECT bucket_start, open, high, low, close, volume, trade_count
+		FROM candles
+		WHERE token_id = $1 AND interval = $2
+		ORDER BY bucket_start DESC
+		LIMIT $3
+	`, tokenID, string(interval), limit)
+	if err != nil {
+		log.Fatalf("❌ query candles: %v", err)
+	}
+	defer rows.Close()
+
+	fmt.Printf("%-25s %-12s %-12s %-12s %-12s %-14s %s\n", "bucket_start", "open", "high", "low", "close", "volume", "trades")
+	for rows.Next() {
+		var start time.Time
+		var open, high, low, close_, volume string
+		var count int
+		if err := rows.Scan(&start, &open, &high, &low, &close_, &volume, &count); err != nil {
+			log.Fatalf("❌ scan candle: %v", err)
+		}
+		fmt.Printf("%-25s %-12s %-12s %-12s %-12s %-14s %d\n",
+			start.Format(time.RFC3339), open, high, low, close_, volume, count)
+	}
+	if err := rows.Err(); err != nil {
+		log.Fatalf("❌ iterate candles: %v", err)
+	}
+}
+
+func printUsage() {
+	fmt.Println("Usage:")
+	fmt.Println("  go run . aggregate [poll_interval_seconds]     - poll trades and roll up candles")
+	fmt.Println("  go run . query <token_id> <interval> [limit]   - print recent candles (interval: 1m, 5m, 1h)")
+}
+
+func main() {
+	if len(os.Args) < 2 {
+		printUsage()
+		os.Exit(1)
+	}
+
+	switch os.Args[1] {
+	case "aggregate":
+		pollIntervalSec := 5
+		if len(os.Args) >= 3 {
+			n, err := strconv.Atoi(os.Args[2])
+			if err != nil {
+				log.Fatalf("❌ invalid poll_interval_seconds: %v", err)
+			}
+			pollIntervalSec = n
+		}
+		runAggregate(pollIntervalSec)
+
+	case "query":
+		if len(os.Args) < 4 {
+			printUsage()
+			os.Exit(1)
+		}
+		tokenID := os.Args[2]
+		interval := Interval(os.Args[3])
+		if _, err := interval.duration(); err != nil {
+			log.Fatalf("❌ %v", err)
+		}
+		limit := 100
+		if len(os.Args) >= 5 {
+			n, err := strconv.Atoi(os.Args[4])
+			if err != nil {
+				log.Fatalf("❌ invalid limit: %v", err)
+			}
+			limit = n
+		}
+		runQuery(tokenID, interval, limit)
+
+	default:
+		fmt.Printf("Error: unknown subcommand %q\n", os.Args[1])
+		printUsage()
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"log"
+	"os"
+	"strconv"
+)
+
+// ChainConfig is the RPC endpoint this worker submits matchOrders
+// transactions to. It's deliberately its own small struct rather than a
+// shared import of bot_go's ChainConfig - this worker is a separate
+// deployable service with its own go.mod, not a bot_go subcommand, so it
+// keeps its own copy the same way reconcile keeps its own DB config
+// instead of importing bot_go's.
+type ChainConfig struct {
+	ChainID       int
+	RPCURL        string
+	Confirmations uint64
+}
+
+// chainIDEnv/rpcURLEnv let ops point this worker at a different chain or
+// RPC provider without a rebuild; unset, it falls back to BSC testnet (97)
+// and its public RPC, matching bot_go's own "no env set -> testnet" default.
+const (
+	chainIDEnv           = "ONCHAIN_SEND_CHAIN_ID"
+	rpcURLEnv            = "ONCHAIN_SEND_RPC_URL"
+	confirmationsEnv     = "ONCHAIN_SEND_CONFIRMATIONS"
+	defaultConfirmations = 3
+)
+
+// chainRPCURLs are the default public RPC endpoints for chains this worker
+// knows how to run against; ONCHAIN_SEND_RPC_URL overrides either one.
+var chainRPCURLs = map[int]string{
+	97: "https://data-seed-prebsc-1-s1.binance.org:8545/",
+	56: "https://bsc-dataseed.binance.org/",
+}
+
+// ActiveChain returns the chain this worker should submit transactions to,
+// log.Fatalf-ing on an unknown chain id with no explicit RPC override -
+// same reasoning as bot_go's ActiveChain: better to fail at startup than
+// the first time a real matchOrders call goes out.
+func ActiveChain() ChainConfig {
+	id := 97
+	if raw := os.Getenv(chainIDEnv); raw != "" {
+		parsed, err := strconv.Atoi(raw)
+		if err != nil {
+			log.Fatalf("invalid %s=%q: %v", chainIDEnv, raw, err)
+		}
+		id = parsed
+	}
+
+	rpcURL := os.Getenv(rpcURLEnv)
+	if rpcURL == "" {
+		known, ok := chainRPCURLs[id]
+		if !ok {
+			log.Fatalf("no default RPC URL for chain %d, set %s", id, rpcURLEnv)
+		}
+		rpcURL = known
+	}
+
+	confirmations := uint64(defaultConfirmations)
+	if raw := os.Getenv(confirmationsEnv); raw != "" {
+		parsed, err := strconv.ParseUint(raw, 10, 64)
+		if err != nil {
+			log.Fatalf("invalid %s=%q: %v", confirmationsEnv, raw, err)
+		}
+		confirmations = parsed
+	}
+
+	return ChainConfig{ChainID: id, RPCURL: rpcURL, Confirmations: confirmations}
+}
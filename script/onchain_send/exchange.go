@@ -0,0 +1,319 @@
+package main
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"errors"
+	"fmt"
+	"log"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethclient"
+
+	"eip712"
+	"mock_go/streamtypes"
+	"nonce"
+)
+
+// onchainOrder is CTFExchange's Order tuple exactly as matchOrders expects
+// it, including the signature bytes - eip712.Order (this repo's signing-side
+// struct) stops one field short of this, since signing produces the
+// signature rather than carrying it.
+type onchainOrder struct {
+	Salt          *big.Int
+	Maker         common.Address
+	Signer        common.Address
+	Taker         common.Address
+	TokenId       *big.Int
+	MakerAmount   *big.Int
+	TakerAmount   *big.Int
+	Expiration    *big.Int
+	Nonce         *big.Int
+	FeeRateBps    *big.Int
+	Side          uint8
+	SignatureType uint8
+	Signature     []byte
+}
+
+// ctfExchangeMatchOrdersABI only defines matchOrders - this worker doesn't
+// call anything else on the exchange, so there's no value in vendoring the
+// whole contract ABI the way a codegen'd binding would. Nested Order/Order[]
+// tuples aren't practical to hand-pack the way bot_go/onchain.go packs flat
+// erc20 calls, so this is the one place in the repo reaching for
+// go-ethereum's abi.Pack instead.
+const ctfExchangeMatchOrdersABI = `[{
+	"type": "function",
+	"name": "matchOrders",
+	"inputs": [
+		{"name": "takerOrder", "type": "tuple", "components": [
+			{"name": "Salt", "type": "uint256"},
+			{"name": "Maker", "type": "address"},
+			{"name": "Signer", "type": "address"},
+			{"name": "Taker", "type": "address"},
+			{"name": "TokenId", "type": "uint256"},
+			{"name": "MakerAmount", "type": "uint256"},
+			{"name": "TakerAmount", "type": "uint256"},
+			{"name": "Expiration", "type": "uint256"},
+			{"name": "Nonce", "type": "uint256"},
+			{"name": "FeeRateBps", "type": "uint256"},
+			{"name": "Side", "type": "uint8"},
+			{"name": "SignatureType", "type": "uint8"},
+			{"name": "Signature", "type": "bytes"}
+		]},
+		{"name": "makerOrders", "type": "tuple[]", "components": [
+			{"name": "Salt", "type": "uint256"},
+			{"name": "Maker", "type": "address"},
+			{"name": "Signer", "type": "address"},
+			{"name": "Taker", "type": "address"},
+			{"name": "TokenId", "type": "uint256"},
+			{"name": "MakerAmount", "type": "uint256"},
+			{"name": "TakerAmount", "type": "uint256"},
+			{"name": "Expiration", "type": "uint256"},
+			{"name": "Nonce", "type": "uint256"},
+			{"name": "FeeRateBps", "type": "uint256"},
+			{"name": "Side", "type": "uint8"},
+			{"name": "SignatureType", "type": "uint8"},
+			{"name": "Signature", "type": "bytes"}
+		]},
+		{"name": "takerFillAmount", "type": "uint256"},
+		{"name": "makerFillAmounts", "type": "uint256[]"}
+	],
+	"outputs": []
+}]`
+
+var ctfExchangeABI = mustParseABI(ctfExchangeMatchOrdersABI)
+
+func mustParseABI(raw string) abi.ABI {
+	parsed, err := abi.JSON(strings.NewReader(raw))
+	if err != nil {
+		panic(fmt.Sprintf("parse CTF exchange ABI: %v", err))
+	}
+	return parsed
+}
+
+// replacementAttempts/replacementTimeout/gasBumpPercent control how a
+// matchOrders transaction that hasn't confirmed gets replaced. 20% is the
+// common "speed up" bump most wallets/relayers use; less than that usually
+// doesn't clear a node's replace-by-fee threshold against the original tx
+// still sitting in its mempool.
+const (
+	replacementAttempts = 3
+	replacementTimeout  = 30 * time.Second
+	gasBumpPercent      = 20
+	matchOrdersGasLimit = 500000
+)
+
+// SettlementError wraps a matchOrders transaction that landed on-chain but
+// reverted - a deterministic outcome, so callers should surface it as a
+// settled failure (and publish a Success:false response) instead of
+// retrying the Redis message the way an infra-level error should be.
+type SettlementError struct {
+	TxHash string
+	Err    error
+}
+
+func (e *SettlementError) Error() string {
+	return fmt.Sprintf("tx %s reverted: %v", e.TxHash, e.Err)
+}
+
+func (e *SettlementError) Unwrap() error { return e.Err }
+
+// ethclientNonceSource adapts an *ethclient.Client to nonce.Source, so the
+// shared nonce package doesn't need a go-ethereum dependency of its own.
+type ethclientNonceSource struct {
+	client *ethclient.Client
+}
+
+func (s ethclientNonceSource) PendingNonceAt(ctx context.Context, address string) (uint64, error) {
+	return s.client.PendingNonceAt(ctx, common.HexToAddress(address))
+}
+
+// Sender holds the on-chain client and operator key that submits
+// matchOrders transactions, allocating nonces through nonce.Manager -
+// shared with bot_go's funding tool and prepare-condition helper - so
+// consecutive trades don't each pay for a fresh PendingNonceAt round trip
+// and a "nonce too low/high" desync is recovered from consistently.
+type Sender struct {
+	client          *ethclient.Client
+	privateKey      *ecdsa.PrivateKey
+	address         common.Address
+	chainID         *big.Int
+	exchangeAddress common.Address
+	fees            *FeeManager
+	receipts        *ReceiptWatcher
+	nonces          *nonce.Manager
+}
+
+// NewSender builds a Sender for chain, authenticating as the account behind
+// operatorPrivateKeyHex - this must be an address the CTFExchange contract
+// has actually authorized as an operator, or every matchOrders call reverts.
+func NewSender(client *ethclient.Client, chain ChainConfig, operatorPrivateKeyHex string) (*Sender, error) {
+	privateKey, err := crypto.HexToECDSA(strings.TrimPrefix(operatorPrivateKeyHex, "0x"))
+	if err != nil {
+		return nil, fmt.Errorf("parse operator private key: %w", err)
+	}
+	address := crypto.PubkeyToAddress(*privateKey.Public().(*ecdsa.PublicKey))
+
+	exchangeAddress, err := eip712.GetCTFExchangeAddress(chain.ChainID)
+	if err != nil {
+		return nil, err
+	}
+
+	feeCfg, err := FeeConfigFromEnv()
+	if err != nil {
+		return nil, err
+	}
+
+	return &Sender{
+		client:          client,
+		privateKey:      privateKey,
+		address:         address,
+		chainID:         big.NewInt(int64(chain.ChainID)),
+		exchangeAddress: exchangeAddress,
+		fees:            NewFeeManager(client, feeCfg),
+		receipts:        NewReceiptWatcher(client, chain.Confirmations),
+		nonces:          nonce.NewManager(ethclientNonceSource{client: client}),
+	}, nil
+}
+
+// SubmitMatch builds and submits the matchOrders transaction for match,
+// returning the confirmed transaction hash. A *SettlementError means the
+// transaction landed but reverted; any other error is worth retrying
+// (RPC/signing failure, or replacement attempts exhausted without ever
+// confirming).
+func (s *Sender) SubmitMatch(ctx context.Context, match streamtypes.MatchOrderInfo) (string, error) {
+	takerOrder, err := buildOnchainOrder(match.TakerOrder)
+	if err != nil {
+		return "", fmt.Errorf("taker order: %w", err)
+	}
+
+	makerOrders := make([]onchainOrder, len(match.MakerOrder))
+	for i, m := range match.MakerOrder {
+		makerOrder, err := buildOnchainOrder(m)
+		if err != nil {
+			return "", fmt.Errorf("maker order %d: %w", i, err)
+		}
+		makerOrders[i] = makerOrder
+	}
+
+	takerFillAmount, err := bigIntFromDecimalString("taker_fill_amount", match.TakerFillAmount)
+	if err != nil {
+		return "", err
+	}
+
+	makerFillAmounts := make([]*big.Int, len(match.MakerFillAmount))
+	for i, amount := range match.MakerFillAmount {
+		fillAmount, err := bigIntFromDecimalString(fmt.Sprintf("maker_fill_amount[%d]", i), amount)
+		if err != nil {
+			return "", err
+		}
+		makerFillAmounts[i] = fillAmount
+	}
+
+	data, err := ctfExchangeABI.Pack("matchOrders", takerOrder, makerOrders, takerFillAmount, makerFillAmounts)
+	if err != nil {
+		return "", fmt.Errorf("pack matchOrders calldata: %w", err)
+	}
+
+	return s.sendWithReplacement(ctx, data)
+}
+
+// sendWithReplacement signs and sends data to the exchange contract, using
+// s.fees for pricing and bumping/resubmitting on the same nonce if a
+// receipt doesn't show up within replacementTimeout.
+func (s *Sender) sendWithReplacement(ctx context.Context, data []byte) (string, error) {
+	txNonce, release, err := s.nonces.Acquire(ctx, s.address.Hex())
+	if err != nil {
+		return "", fmt.Errorf("acquire nonce: %w", err)
+	}
+	// used tracks whether this nonce was actually consumed by a broadcast
+	// transaction by the time release runs, so a failure before ever
+	// calling SendTransaction hands the same nonce to the next caller
+	// instead of leaving a gap.
+	used := false
+	defer func() { release(used) }()
+
+	quote, err := s.fees.Suggest(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	submittedAt := time.Now()
+	var lastTxHash common.Hash
+	for attempt := 0; attempt < replacementAttempts; attempt++ {
+		signedTx, err := s.signTx(txNonce, quote, data)
+		if err != nil {
+			return "", err
+		}
+		lastTxHash = signedTx.Hash()
+
+		if sendErr := s.client.SendTransaction(ctx, signedTx); sendErr != nil {
+			if nonce.IsDesyncError(sendErr) {
+				if resyncErr := s.nonces.ReportDesync(ctx, s.address.Hex()); resyncErr != nil {
+					return "", fmt.Errorf("send matchOrders transaction: %w (resync failed: %v)", sendErr, resyncErr)
+				}
+			}
+			return "", fmt.Errorf("send matchOrders transaction: %w", sendErr)
+		}
+		used = true
+
+		receipt, err := s.receipts.AwaitConfirmed(ctx, lastTxHash, replacementTimeout)
+		switch {
+		case err == nil:
+			s.fees.RecordInclusion(time.Since(submittedAt))
+			if receipt.Status == types.ReceiptStatusSuccessful {
+				return lastTxHash.Hex(), nil
+			}
+			return "", &SettlementError{TxHash: lastTxHash.Hex(), Err: fmt.Errorf("execution reverted")}
+
+		case errors.Is(err, ErrReorged):
+			// The tx was mined and then dropped by a reorg before reaching
+			// full confirmation depth, so the nonce is free again - retry
+			// immediately at the same nonce/price instead of waiting out a
+			// gas bump that isn't the problem here.
+			log.Printf("♻️  matchOrders tx %s reorged out before confirming, rebroadcasting (attempt %d/%d)",
+				lastTxHash.Hex(), attempt+1, replacementAttempts)
+
+		default:
+			log.Printf("⏱️  matchOrders tx %s unconfirmed after %s, bumping fees and replacing (attempt %d/%d)",
+				lastTxHash.Hex(), replacementTimeout, attempt+1, replacementAttempts)
+			quote = s.fees.Bump(quote)
+		}
+	}
+
+	return "", fmt.Errorf("matchOrders tx %s still unconfirmed after %d replacement attempt(s)", lastTxHash.Hex(), replacementAttempts)
+}
+
+// signTx builds and signs the matchOrders transaction for nonce/data at the
+// given quote - a dynamic-fee (EIP-1559) transaction if quote.Dynamic, a
+// legacy one otherwise. types.LatestSignerForChainID handles both types,
+// unlike the EIP155Signer used before dynamic-fee support existed here.
+func (s *Sender) signTx(nonce uint64, quote Quote, data []byte) (*types.Transaction, error) {
+	var tx *types.Transaction
+	if quote.Dynamic {
+		tx = types.NewTx(&types.DynamicFeeTx{
+			ChainID:   s.chainID,
+			Nonce:     nonce,
+			GasTipCap: quote.GasTipCap,
+			GasFeeCap: quote.GasFeeCap,
+			Gas:       matchOrdersGasLimit,
+			To:        &s.exchangeAddress,
+			Value:     big.NewInt(0),
+			Data:      data,
+		})
+	} else {
+		tx = types.NewTransaction(nonce, s.exchangeAddress, big.NewInt(0), matchOrdersGasLimit, quote.GasPrice, data)
+	}
+
+	signedTx, err := types.SignTx(tx, types.LatestSignerForChainID(s.chainID), s.privateKey)
+	if err != nil {
+		return nil, fmt.Errorf("sign matchOrders transaction: %w", err)
+	}
+	return signedTx, nil
+}
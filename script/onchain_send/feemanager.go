@@ -0,0 +1,168 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/ethclient"
+)
+
+// FeeConfig bounds what a FeeManager will ever quote, read from env so ops
+// can cap gas spend on a stuck-transaction bump storm without a rebuild.
+type FeeConfig struct {
+	MaxFeePerGasWei         *big.Int
+	MaxPriorityFeePerGasWei *big.Int
+}
+
+const (
+	maxFeePerGasGweiEnv         = "ONCHAIN_SEND_MAX_FEE_GWEI"
+	maxPriorityFeePerGasGweiEnv = "ONCHAIN_SEND_MAX_PRIORITY_FEE_GWEI"
+
+	// BSC's gas market runs far hotter than mainnet's post-1559 baseline;
+	// these defaults are generous enough to clear it without a config
+	// change for the common case.
+	defaultMaxFeePerGasGwei         = 20
+	defaultMaxPriorityFeePerGasGwei = 3
+)
+
+var gweiToWei = big.NewInt(1_000_000_000)
+
+// FeeConfigFromEnv reads the fee caps, falling back to the BSC-sized
+// defaults above when unset.
+func FeeConfigFromEnv() (FeeConfig, error) {
+	maxFee, err := gweiEnvOrDefault(maxFeePerGasGweiEnv, defaultMaxFeePerGasGwei)
+	if err != nil {
+		return FeeConfig{}, err
+	}
+	maxPriorityFee, err := gweiEnvOrDefault(maxPriorityFeePerGasGweiEnv, defaultMaxPriorityFeePerGasGwei)
+	if err != nil {
+		return FeeConfig{}, err
+	}
+	return FeeConfig{
+		MaxFeePerGasWei:         maxFee,
+		MaxPriorityFeePerGasWei: maxPriorityFee,
+	}, nil
+}
+
+func gweiEnvOrDefault(env string, def int64) (*big.Int, error) {
+	raw := os.Getenv(env)
+	if raw == "" {
+		return new(big.Int).Mul(big.NewInt(def), gweiToWei), nil
+	}
+	gwei, err := strconv.ParseInt(raw, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s=%q: %w", env, raw, err)
+	}
+	return new(big.Int).Mul(big.NewInt(gwei), gweiToWei), nil
+}
+
+// Quote is one set of gas parameters for a transaction attempt. Dynamic
+// selects which pair is populated: GasPrice for a legacy transaction, or
+// GasTipCap/GasFeeCap for an EIP-1559 one.
+type Quote struct {
+	Dynamic   bool
+	GasPrice  *big.Int
+	GasTipCap *big.Int
+	GasFeeCap *big.Int
+}
+
+// FeeManager quotes and bumps gas pricing for matchOrders submissions. It
+// picks EIP-1559 pricing when the chain's latest block reports a base fee
+// and falls back to legacy gasPrice otherwise, since BSC mainnet and
+// testnet nodes vary in how much of the fee market they actually expose.
+type FeeManager struct {
+	client *ethclient.Client
+	cfg    FeeConfig
+
+	mu             sync.Mutex
+	inclusionTimes []time.Duration
+}
+
+// NewFeeManager builds a FeeManager for client, quoting within cfg's caps.
+func NewFeeManager(client *ethclient.Client, cfg FeeConfig) *FeeManager {
+	return &FeeManager{client: client, cfg: cfg}
+}
+
+// Suggest returns the gas parameters for a fresh (non-replacement)
+// submission.
+func (fm *FeeManager) Suggest(ctx context.Context) (Quote, error) {
+	head, err := fm.client.HeaderByNumber(ctx, nil)
+	if err != nil {
+		return Quote{}, fmt.Errorf("fetch chain head: %w", err)
+	}
+	if head.BaseFee == nil {
+		gasPrice, err := fm.client.SuggestGasPrice(ctx)
+		if err != nil {
+			return Quote{}, fmt.Errorf("suggest gas price: %w", err)
+		}
+		return Quote{GasPrice: capAt(gasPrice, fm.cfg.MaxFeePerGasWei)}, nil
+	}
+
+	tipCap, err := fm.client.SuggestGasTipCap(ctx)
+	if err != nil {
+		return Quote{}, fmt.Errorf("suggest gas tip cap: %w", err)
+	}
+	tipCap = capAt(tipCap, fm.cfg.MaxPriorityFeePerGasWei)
+
+	// 2x base fee gives headroom for a couple of blocks of base fee growth
+	// before the cap below (rather than this margin) becomes binding.
+	feeCap := new(big.Int).Add(new(big.Int).Mul(head.BaseFee, big.NewInt(2)), tipCap)
+	feeCap = capAt(feeCap, fm.cfg.MaxFeePerGasWei)
+
+	return Quote{Dynamic: true, GasTipCap: tipCap, GasFeeCap: feeCap}, nil
+}
+
+// Bump returns a replacement quote for q with its price increased by
+// gasBumpPercent, still held under the configured caps.
+func (fm *FeeManager) Bump(q Quote) Quote {
+	if q.Dynamic {
+		q.GasTipCap = capAt(bumpByPercent(q.GasTipCap), fm.cfg.MaxPriorityFeePerGasWei)
+		q.GasFeeCap = capAt(bumpByPercent(q.GasFeeCap), fm.cfg.MaxFeePerGasWei)
+		return q
+	}
+	q.GasPrice = capAt(bumpByPercent(q.GasPrice), fm.cfg.MaxFeePerGasWei)
+	return q
+}
+
+func bumpByPercent(v *big.Int) *big.Int {
+	bumped := new(big.Int).Mul(v, big.NewInt(100+gasBumpPercent))
+	return bumped.Div(bumped, big.NewInt(100))
+}
+
+func capAt(v, max *big.Int) *big.Int {
+	if max != nil && v.Cmp(max) > 0 {
+		return max
+	}
+	return v
+}
+
+// RecordInclusion tracks how long a transaction took from submission to
+// confirmation. Kept as an in-memory sample like risk.Throttle's violation
+// counters - there's no metrics exporter wired into this worker yet, but
+// InclusionStats gives an operator something to log or poll in the
+// meantime.
+func (fm *FeeManager) RecordInclusion(d time.Duration) {
+	fm.mu.Lock()
+	defer fm.mu.Unlock()
+	fm.inclusionTimes = append(fm.inclusionTimes, d)
+}
+
+// InclusionStats reports how many confirmations have been recorded and
+// their average inclusion time.
+func (fm *FeeManager) InclusionStats() (count int, avg time.Duration) {
+	fm.mu.Lock()
+	defer fm.mu.Unlock()
+	if len(fm.inclusionTimes) == 0 {
+		return 0, 0
+	}
+	var total time.Duration
+	for _, d := range fm.inclusionTimes {
+		total += d
+	}
+	return len(fm.inclusionTimes), total / time.Duration(len(fm.inclusionTimes))
+}
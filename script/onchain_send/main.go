@@ -0,0 +1,173 @@
+// Command onchain_send is the real settlement worker behind
+// TradeOnchainSendRequest: it consumes matched trades from the onchain
+// send-request stream, submits the corresponding matchOrders transaction to
+// the CTFExchange contract, and publishes a TradeOnchainSendResponse with
+// the resulting tx hash. mock_go/trade_responder fakes this same contract
+// (a canned success response with no chain interaction at all) for local
+// dev and tests; this binary is what actually runs against a real RPC
+// endpoint.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/ethereum/go-ethereum/ethclient"
+
+	"mock_go/streamtypes"
+	"streams"
+	"tracing"
+)
+
+// Redis config matches mock_go/trade_responder's exactly - this worker
+// consumes the same send-request stream and publishes to the same
+// send-response stream, just with a real chain submission in between.
+const (
+	RedisAddr       = "127.0.0.1:8889"
+	RedisPassword   = "123456"
+	RedisDB         = 0
+	TradeSendStream = "deepsense:onchain:service:send_request"
+	TradeSendKey    = "send_request"
+	TradeRespStream = "deepsense:onchain:service:send_reponse"
+	TradeRespKey    = "send_response"
+	ConsumerGroup   = "onchain_send"
+	ConsumerName    = "onchain_send_1"
+)
+
+// OperatorPrivateKeyEnv names the env var holding the hex-encoded private
+// key this worker signs matchOrders transactions with. Kept to a bare
+// os.Getenv rather than bot_go's secrets.CredentialsProvider - this worker
+// only ever needs the one credential, so pulling in the GCP/Vault provider
+// machinery for it isn't worth the extra dependency surface.
+const OperatorPrivateKeyEnv = "ONCHAIN_SEND_OPERATOR_PRIVATE_KEY"
+
+func main() {
+	ctx := context.Background()
+
+	operatorPrivateKeyHex := mustGetenv(OperatorPrivateKeyEnv)
+
+	rdb := streams.NewRedisClient(streams.RedisConfig{
+		Addrs:    []string{RedisAddr},
+		Password: RedisPassword,
+		DB:       RedisDB,
+	}.OverrideFromEnv())
+	if err := rdb.Ping(ctx).Err(); err != nil {
+		log.Fatalf("Failed to connect to Redis: %v", err)
+	}
+	log.Println("✅ Connected to Redis")
+
+	chain := ActiveChain()
+	client, err := ethclient.DialContext(ctx, chain.RPCURL)
+	if err != nil {
+		log.Fatalf("Failed to dial chain RPC %s: %v", chain.RPCURL, err)
+	}
+	log.Printf("✅ Connected to chain %d via %s", chain.ChainID, chain.RPCURL)
+
+	sender, err := NewSender(client, chain, operatorPrivateKeyHex)
+	if err != nil {
+		log.Fatalf("Failed to build onchain sender: %v", err)
+	}
+
+	producer := streams.NewProducer(rdb, TradeRespStream, TradeRespKey)
+	consumer := streams.NewConsumer(rdb, streams.ConsumerConfig{
+		Stream:   TradeSendStream,
+		MsgKey:   TradeSendKey,
+		Group:    ConsumerGroup,
+		Consumer: ConsumerName,
+	})
+
+	log.Printf("🚀 Onchain Send worker started, listening on stream: %s", TradeSendStream)
+
+	if err := consumer.Run(ctx, func(ctx context.Context, raw []byte) error {
+		return handleMessage(ctx, sender, producer, raw)
+	}); err != nil {
+		log.Fatalf("Onchain Send worker stopped: %v", err)
+	}
+}
+
+func mustGetenv(name string) string {
+	value := os.Getenv(name)
+	if value == "" {
+		log.Fatalf("%s must be set", name)
+	}
+	return value
+}
+
+func handleMessage(ctx context.Context, sender *Sender, producer *streams.Producer, raw []byte) error {
+	var req streamtypes.TradeOnchainSendRequest
+	if err := json.Unmarshal(raw, &req); err != nil {
+		return fmt.Errorf("unmarshal request: %w", err)
+	}
+	if !streamtypes.SupportedVersion(req.Version) {
+		log.Printf("⚠️  trade request %s has version %d, newer than this worker's %d - decoding best-effort with the fields we know",
+			req.TradeID, req.Version, streamtypes.CurrentVersion)
+	}
+
+	tc, ok := tracing.FromContext(ctx)
+	if ok {
+		if child, err := tc.NewChild(); err == nil {
+			tc = child
+		}
+	} else if newTC, err := tracing.New(); err != nil {
+		log.Printf("generate trace context failed: %v", err)
+	} else {
+		tc = newTC
+	}
+
+	log.Printf("📨 Received trade request: trade_id=%s, event_id=%d, market_id=%d, trace_id=%s",
+		req.TradeID, req.EventID, req.MarketID, tc.TraceID)
+
+	txHash, sendErr := sender.SubmitMatch(ctx, req.MatchInfo)
+
+	var settleErr *SettlementError
+	switch {
+	case sendErr == nil:
+		resp := streamtypes.TradeOnchainSendResponse{
+			Version:         streamtypes.CurrentVersion,
+			TradeID:         req.TradeID,
+			EventID:         req.EventID,
+			MarketID:        req.MarketID,
+			TakerTradeInfo:  req.TakerTradeInfo,
+			MakerTradeInfos: req.MakerTradeInfos,
+			TxHash:          txHash,
+			Success:         true,
+		}
+		if _, err := producer.SendWithTrace(ctx, resp, tc); err != nil {
+			return fmt.Errorf("send response: %w", err)
+		}
+		log.Printf("✅ Sent trade response: trade_id=%s, tx_hash=%s, success=%v",
+			resp.TradeID, resp.TxHash, resp.Success)
+		return nil
+
+	case errors.As(sendErr, &settleErr):
+		// The transaction landed but reverted - that's a definitive outcome,
+		// not a transient failure, so report it and don't ask the consumer
+		// to redeliver this message.
+		resp := streamtypes.TradeOnchainSendResponse{
+			Version:         streamtypes.CurrentVersion,
+			TradeID:         req.TradeID,
+			EventID:         req.EventID,
+			MarketID:        req.MarketID,
+			TakerTradeInfo:  req.TakerTradeInfo,
+			MakerTradeInfos: req.MakerTradeInfos,
+			TxHash:          settleErr.TxHash,
+			Success:         false,
+		}
+		if _, err := producer.SendWithTrace(ctx, resp, tc); err != nil {
+			return fmt.Errorf("send failure response: %w", err)
+		}
+		log.Printf("❌ Trade settlement reverted: trade_id=%s, tx_hash=%s, err=%v",
+			req.TradeID, settleErr.TxHash, settleErr.Err)
+		return nil
+
+	default:
+		// RPC/signing/infra failure with no confirmed outcome - return the
+		// error so streams.Consumer redelivers rather than reporting a false
+		// success or a false permanent failure.
+		return fmt.Errorf("submit match for trade %s: %w", req.TradeID, sendErr)
+	}
+}
@@ -0,0 +1,105 @@
+package main
+
+import (
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"mock_go/streamtypes"
+)
+
+// sideToUint8 maps the wire-level "buy"/"sell" string (bot_go's
+// PlaceOrderRequest convention) to the uint8 CTFExchange's Order.side
+// expects (0 = buy, 1 = sell, matching load_gen's buildOrder).
+func sideToUint8(side string) (uint8, error) {
+	switch strings.ToLower(side) {
+	case "buy":
+		return 0, nil
+	case "sell":
+		return 1, nil
+	default:
+		return 0, fmt.Errorf("unknown side %q", side)
+	}
+}
+
+// bigIntFromDecimalString parses one of MatchOrderInfo's decimal-string
+// amount fields, naming the field in the error so a bad message points
+// straight at which one.
+func bigIntFromDecimalString(field, value string) (*big.Int, error) {
+	n, ok := new(big.Int).SetString(value, 10)
+	if !ok {
+		return nil, fmt.Errorf("invalid %s %q", field, value)
+	}
+	return n, nil
+}
+
+// decodeSignature accepts the signature hex with or without a leading 0x,
+// since neither eip712's Signature.Bytes nor bot_go's PlaceOrderRequest
+// pins down which form producers send.
+func decodeSignature(value string) ([]byte, error) {
+	trimmed := strings.TrimPrefix(strings.TrimPrefix(value, "0x"), "0X")
+	decoded, err := hex.DecodeString(trimmed)
+	if err != nil {
+		return nil, fmt.Errorf("invalid signature %q: %w", value, err)
+	}
+	return decoded, nil
+}
+
+// buildOnchainOrder converts one signed order leg off the wire into the
+// tuple matchOrders expects. streamtypes.SignatureOrderMsg already carries
+// every field the contract's Order struct needs (it mirrors bot_go's
+// PlaceOrderRequest field-for-field per its own doc comment), so this is a
+// straight parse, not a reconstruction from partial data.
+func buildOnchainOrder(msg streamtypes.SignatureOrderMsg) (onchainOrder, error) {
+	tokenID, err := bigIntFromDecimalString("token_id", msg.TokenID)
+	if err != nil {
+		return onchainOrder{}, err
+	}
+	makerAmount, err := bigIntFromDecimalString("maker_amount", msg.MakerAmount)
+	if err != nil {
+		return onchainOrder{}, err
+	}
+	takerAmount, err := bigIntFromDecimalString("taker_amount", msg.TakerAmount)
+	if err != nil {
+		return onchainOrder{}, err
+	}
+	expiration, err := bigIntFromDecimalString("expiration", msg.Expiration)
+	if err != nil {
+		return onchainOrder{}, err
+	}
+	nonce, err := bigIntFromDecimalString("nonce", msg.Nonce)
+	if err != nil {
+		return onchainOrder{}, err
+	}
+	feeRateBps, err := bigIntFromDecimalString("fee_rate_bps", msg.FeeRateBps)
+	if err != nil {
+		return onchainOrder{}, err
+	}
+	side, err := sideToUint8(msg.Side)
+	if err != nil {
+		return onchainOrder{}, err
+	}
+	signature, err := decodeSignature(msg.Signature)
+	if err != nil {
+		return onchainOrder{}, err
+	}
+
+	return onchainOrder{
+		Salt:          big.NewInt(msg.Salt),
+		Maker:         common.HexToAddress(msg.Maker),
+		Signer:        common.HexToAddress(msg.Signer),
+		Taker:         common.HexToAddress(msg.Taker),
+		TokenId:       tokenID,
+		MakerAmount:   makerAmount,
+		TakerAmount:   takerAmount,
+		Expiration:    expiration,
+		Nonce:         nonce,
+		FeeRateBps:    feeRateBps,
+		Side:          side,
+		SignatureType: uint8(msg.SignatureType),
+		Signature:     signature,
+	}, nil
+}
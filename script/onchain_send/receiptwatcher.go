@@ -0,0 +1,109 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+)
+
+// ErrReorged indicates a transaction that was mined got reorged out (its
+// block replaced by one with a different hash) before reaching the required
+// confirmation depth. Sender treats this as "nonce is free again, safe to
+// rebroadcast" rather than a settled outcome.
+var ErrReorged = errors.New("transaction reorged out before reaching required confirmations")
+
+// receiptPollInterval is how often ReceiptWatcher rechecks chain state
+// while waiting for a receipt or for confirmations to accumulate.
+const receiptPollInterval = 2 * time.Second
+
+// ReceiptWatcher waits for a transaction to reach a configured confirmation
+// depth rather than treating the first receipt seen as final, and detects
+// the transaction's block getting reorged out along the way.
+type ReceiptWatcher struct {
+	client        *ethclient.Client
+	confirmations uint64
+}
+
+// NewReceiptWatcher builds a watcher that requires confirmations blocks
+// mined on top of a transaction's block (inclusive of that block itself)
+// before treating it as final.
+func NewReceiptWatcher(client *ethclient.Client, confirmations uint64) *ReceiptWatcher {
+	if confirmations == 0 {
+		confirmations = 1
+	}
+	return &ReceiptWatcher{client: client, confirmations: confirmations}
+}
+
+// AwaitConfirmed blocks until txHash reaches the watcher's confirmation
+// depth, returning its receipt. It returns ErrReorged if the transaction's
+// block gets replaced before that depth is reached, and a plain error on
+// timeout or RPC failure - callers should only rebroadcast on ErrReorged,
+// since any other error leaves the transaction's fate unknown.
+func (w *ReceiptWatcher) AwaitConfirmed(ctx context.Context, txHash common.Hash, timeout time.Duration) (*types.Receipt, error) {
+	deadline := time.Now().Add(timeout)
+
+	receipt, err := w.awaitReceipt(ctx, txHash, deadline)
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		canonical, err := w.isCanonical(ctx, receipt)
+		if err != nil {
+			return nil, err
+		}
+		if !canonical {
+			return nil, ErrReorged
+		}
+
+		head, err := w.client.BlockNumber(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("fetch chain head: %w", err)
+		}
+		if head+1 >= receipt.BlockNumber.Uint64()+w.confirmations {
+			return receipt, nil
+		}
+
+		if time.Now().After(deadline) {
+			return nil, fmt.Errorf("timed out waiting for %d confirmations on tx %s", w.confirmations, txHash.Hex())
+		}
+		time.Sleep(receiptPollInterval)
+	}
+}
+
+// awaitReceipt polls for txHash's receipt until deadline, distinguishing
+// "not mined yet" (ethereum.NotFound, keep polling) from a genuine RPC
+// error (bail immediately, the caller shouldn't burn its budget on that).
+func (w *ReceiptWatcher) awaitReceipt(ctx context.Context, txHash common.Hash, deadline time.Time) (*types.Receipt, error) {
+	for time.Now().Before(deadline) {
+		receipt, err := w.client.TransactionReceipt(ctx, txHash)
+		if err == nil {
+			return receipt, nil
+		}
+		if err != ethereum.NotFound {
+			return nil, err
+		}
+		time.Sleep(receiptPollInterval)
+	}
+	return nil, fmt.Errorf("timed out waiting for receipt on tx %s", txHash.Hex())
+}
+
+// isCanonical reports whether receipt's block is still the one at that
+// height on the chain the client sees - a hash mismatch (or the block no
+// longer existing at all) means it was reorged out.
+func (w *ReceiptWatcher) isCanonical(ctx context.Context, receipt *types.Receipt) (bool, error) {
+	header, err := w.client.HeaderByNumber(ctx, receipt.BlockNumber)
+	if err != nil {
+		if err == ethereum.NotFound {
+			return false, nil
+		}
+		return false, fmt.Errorf("fetch header at block %s: %w", receipt.BlockNumber, err)
+	}
+	return header.Hash() == receipt.BlockHash, nil
+}
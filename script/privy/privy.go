@@ -0,0 +1,263 @@
+// Package privy implements the client side of a Privy (privy.io) SIWE
+// login: request a nonce, sign a SIWE message with a wallet, exchange the
+// signature for a session, and refresh/inspect that session's tokens. It
+// was extracted out of bot_go so websocket_user and any future script can
+// authenticate against Privy without copying the same HTTP calls.
+package privy
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"siwe"
+)
+
+// Config describes a Privy app and the endpoints/headers its API expects.
+// The zero value is not usable; start from DefaultConfig for this repo's
+// existing app, or fill in your own.
+type Config struct {
+	NonceURL   string
+	AuthURL    string
+	RefreshURL string
+
+	AppID        string
+	ClientHeader string
+	Origin       string
+
+	// SiteHost and ChainID are interpolated into the SIWE message text, so
+	// they must match what the Privy app was configured with.
+	SiteHost string
+	ChainID  int64
+
+	HTTPClient *http.Client
+}
+
+// DefaultConfig returns the configuration for this repo's existing Privy
+// app, matching the endpoints/headers bot_go used before extraction.
+func DefaultConfig() Config {
+	return Config{
+		NonceURL:     "https://auth.privy.io/api/v1/siwe/init",
+		AuthURL:      "https://auth.privy.io/api/v1/siwe/authenticate",
+		RefreshURL:   "https://auth.privy.io/api/v1/sessions/sync",
+		AppID:        "cmi5m5vdz006lks0cbixho6k0",
+		ClientHeader: "react-auth:3.6.1",
+		Origin:       "https://deepsense-website-290128242879.asia-northeast1.run.app",
+		SiteHost:     "deepsense-website-290128242879.asia-northeast1.run.app",
+		ChainID:      97,
+	}
+}
+
+// Signer produces the address and personal-sign signature Privy's SIWE
+// flow needs. Implementations plug in different wallet backends (a raw
+// private key today, a hardware or remote signer later) without Client
+// needing to know the difference.
+type Signer interface {
+	Address() string
+	SignPersonal(message string) (string, error)
+}
+
+// Session is the result of a successful Login, including the raw tokens
+// and their parsed JWT claims for callers that need to inspect expiry or
+// subject without a round trip.
+type Session struct {
+	IdentityToken string
+	Token         string
+	User          any
+
+	IdentityClaims Claims
+	TokenClaims    Claims
+}
+
+type nonceResponse struct {
+	Nonce     string `json:"nonce"`
+	Address   string `json:"address"`
+	ExpiresAt string `json:"expires_at"`
+}
+
+type authResponse struct {
+	User          any    `json:"user"`
+	Token         string `json:"token"`
+	IdentityToken string `json:"identity_token"`
+}
+
+// Client is a Privy API client for one Config.
+type Client struct {
+	cfg Config
+}
+
+// NewClient returns a Client for cfg.
+func NewClient(cfg Config) *Client {
+	if cfg.HTTPClient == nil {
+		cfg.HTTPClient = &http.Client{Timeout: 30 * time.Second}
+	}
+	return &Client{cfg: cfg}
+}
+
+// Login runs the full nonce -> SIWE sign -> authenticate flow for signer
+// and returns the resulting Session.
+func (c *Client) Login(ctx context.Context, signer Signer) (*Session, error) {
+	nonce, err := c.nonce(ctx, signer.Address())
+	if err != nil {
+		return nil, fmt.Errorf("get nonce: %w", err)
+	}
+
+	message := c.siweMessage(signer.Address(), nonce.Nonce)
+	signature, err := signer.SignPersonal(message)
+	if err != nil {
+		return nil, fmt.Errorf("sign siwe message: %w", err)
+	}
+
+	resp, err := c.authenticate(ctx, message, signature)
+	if err != nil {
+		return nil, fmt.Errorf("authenticate: %w", err)
+	}
+
+	return c.newSession(resp)
+}
+
+// Refresh exchanges session's current token for a new one, returning the
+// updated Session. The caller should keep using the old Session if this
+// call fails; the existing tokens are not invalidated by a failed refresh.
+func (c *Client) Refresh(ctx context.Context, session *Session) (*Session, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.cfg.RefreshURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("build refresh request: %w", err)
+	}
+	c.setCommonHeaders(req)
+	req.Header.Set("Authorization", "Bearer "+session.Token)
+
+	body, err := c.do(req)
+	if err != nil {
+		return nil, fmt.Errorf("refresh request: %w", err)
+	}
+
+	var resp authResponse
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return nil, fmt.Errorf("decode refresh response: %w", err)
+	}
+
+	return c.newSession(&resp)
+}
+
+func (c *Client) newSession(resp *authResponse) (*Session, error) {
+	session := &Session{
+		IdentityToken: resp.IdentityToken,
+		Token:         resp.Token,
+		User:          resp.User,
+	}
+
+	if session.IdentityToken != "" {
+		claims, err := ParseClaims(session.IdentityToken)
+		if err != nil {
+			return nil, fmt.Errorf("parse identity token claims: %w", err)
+		}
+		session.IdentityClaims = claims
+	}
+	if session.Token != "" {
+		claims, err := ParseClaims(session.Token)
+		if err != nil {
+			return nil, fmt.Errorf("parse token claims: %w", err)
+		}
+		session.TokenClaims = claims
+	}
+
+	return session, nil
+}
+
+func (c *Client) siweMessage(address, nonce string) string {
+	return siwe.Message{
+		Domain:    c.cfg.SiteHost,
+		Address:   address,
+		Statement: "By signing, you are proving you own this wallet and logging in. This does not initiate a transaction or cost any fees.",
+		URI:       "https://" + c.cfg.SiteHost,
+		Version:   "1",
+		ChainID:   c.cfg.ChainID,
+		Nonce:     nonce,
+		IssuedAt:  siwe.NowISO8601(),
+		Resources: []string{"https://privy.io"},
+	}.String()
+}
+
+func (c *Client) nonce(ctx context.Context, address string) (*nonceResponse, error) {
+	payload, err := json.Marshal(map[string]string{"address": address})
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.cfg.NonceURL, bytes.NewReader(payload))
+	if err != nil {
+		return nil, err
+	}
+	c.setCommonHeaders(req)
+
+	body, err := c.do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp nonceResponse
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+func (c *Client) authenticate(ctx context.Context, message, signature string) (*authResponse, error) {
+	payload, err := json.Marshal(map[string]any{
+		"message":          message,
+		"signature":        signature,
+		"walletClientType": "metamask",
+		"connectorType":    "injected",
+		"mode":             "login-or-sign-up",
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.cfg.AuthURL, bytes.NewReader(payload))
+	if err != nil {
+		return nil, err
+	}
+	c.setCommonHeaders(req)
+
+	body, err := c.do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp authResponse
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+func (c *Client) setCommonHeaders(req *http.Request) {
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Origin", c.cfg.Origin)
+	req.Header.Set("Referer", c.cfg.Origin+"/")
+	req.Header.Set("privy-app-id", c.cfg.AppID)
+	req.Header.Set("privy-client", c.cfg.ClientHeader)
+}
+
+func (c *Client) do(req *http.Request) ([]byte, error) {
+	resp, err := c.cfg.HTTPClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("request to %s failed with %s: %s", req.URL, resp.Status, string(body))
+	}
+	return body, nil
+}
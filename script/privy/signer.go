@@ -0,0 +1,48 @@
+package privy
+
+import (
+	"crypto/ecdsa"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// PrivateKeySigner is a Signer backed by a raw ECDSA private key, matching
+// the signing bot_go's accounts already use.
+type PrivateKeySigner struct {
+	privateKey *ecdsa.PrivateKey
+	address    string
+}
+
+// NewPrivateKeySigner derives the signer's address from privateKeyHex.
+func NewPrivateKeySigner(privateKeyHex string) (*PrivateKeySigner, error) {
+	privateKey, err := crypto.HexToECDSA(privateKeyHex)
+	if err != nil {
+		return nil, fmt.Errorf("invalid private key: %w", err)
+	}
+
+	return &PrivateKeySigner{
+		privateKey: privateKey,
+		address:    crypto.PubkeyToAddress(privateKey.PublicKey).Hex(),
+	}, nil
+}
+
+// Address returns the signer's checksummed address.
+func (s *PrivateKeySigner) Address() string {
+	return s.address
+}
+
+// SignPersonal signs message the way an EIP-191 personal_sign call would.
+func (s *PrivateKeySigner) SignPersonal(message string) (string, error) {
+	fullMessage := fmt.Sprintf("\x19Ethereum Signed Message:\n%d%s", len(message), message)
+	hash := crypto.Keccak256Hash([]byte(fullMessage))
+
+	signature, err := crypto.Sign(hash.Bytes(), s.privateKey)
+	if err != nil {
+		return "", err
+	}
+	signature[64] += 27
+
+	return hexutil.Encode(signature), nil
+}
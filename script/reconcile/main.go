@@ -0,0 +1,287 @@
+// Package main implements a trade settlement reconciliation tool. It
+// cross-checks trades recorded in Postgres against the settlement ledger
+// (asset_history) that trade_responder eventually writes once an on-chain
+// send confirms, flagging trades that never got a settlement recorded and
+// trades whose settled amounts or tx hash don't match what was executed,
+// so ledger drift shows up here first instead of in a support ticket.
+package main
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"time"
+
+	_ "github.com/lib/pq"
+	"github.com/shopspring/decimal"
+)
+
+const (
+	DBHost = "34.146.110.159"
+	DBPort = 5432
+	DBUser = "postgres"
+	DBName = "deepsense"
+)
+
+// StuckSettlementThreshold is how long a trade may sit with
+// onchain_send_handled = false before it's flagged as stuck rather than
+// merely still in flight to trade_responder.
+const StuckSettlementThreshold = 10 * time.Minute
+
+// AmountMismatchTolerance absorbs decimal rounding between a trade's
+// executed token_amount and what asset_history recorded as settled.
+var AmountMismatchTolerance = decimal.NewFromFloat(0.000001)
+
+// Trade is the subset of the trades table reconciliation needs.
+type Trade struct {
+	BatchID            string
+	OrderID            string
+	MatchTimestamp     time.Time
+	UserID             int64
+	EventID            int64
+	MarketID           int16
+	TokenID            string
+	Side               string
+	UsdcAmount         decimal.Decimal
+	TokenAmount        decimal.Decimal
+	OnchainSendHandled bool
+	TxHash             sql.NullString
+}
+
+// SettlementRecord is the most recent on-chain settlement asset_history
+// recorded for a trade's order.
+type SettlementRecord struct {
+	TxHash      sql.NullString
+	TokenAmount decimal.Decimal
+	UsdcAmount  decimal.Decimal
+}
+
+// Finding is one reconciliation problem flagged against a trade.
+type Finding struct {
+	Trade  Trade
+	Reason string
+	Detail string
+}
+
+func scanTrade(scan func(dest ...any) error) (*Trade, error) {
+	var t Trade
+	var usdcAmount, tokenAmount string
+	if err := scan(&t.BatchID, &t.OrderID, &t.MatchTimestamp, &t.UserID, &t.EventID, &t.MarketID,
+		&t.TokenID, &t.Side, &usdcAmount, &tokenAmount, &t.OnchainSendHandled, &t.TxHash); err != nil {
+		return nil, err
+	}
+
+	var err error
+	if t.UsdcAmount, err = decimal.NewFromString(usdcAmount); err != nil {
+		return nil, fmt.Errorf("parse usdc_amount %q: %w", usdcAmount, err)
+	}
+	if t.TokenAmount, err = decimal.NewFromString(tokenAmount); err != nil {
+		return nil, fmt.Errorf("parse token_amount %q: %w", tokenAmount, err)
+	}
+	return &t, nil
+}
+
+const tradeColumns = `batch_id, order_id, match_timestamp, user_id, event_id, market_id, token_id, side, usdc_amount, token_amount, onchain_send_handled, tx_hash`
+
+// fetchStuckTrades returns trades that have been waiting for on-chain
+// settlement longer than olderThan.
+func fetchStuckTrades(db *sql.DB, olderThan time.Duration) ([]Trade, error) {
+	rows, err := db.Query(
+		`SELECT `+tradeColumns+` FROM trades WHERE onchain_send_handled = false AND match_timestamp < $1 ORDER BY match_timestamp`,
+		time.Now().Add(-olderThan),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("query stuck trades: %w", err)
+	}
+	defer rows.Close()
+
+	var trades []Trade
+	for rows.Next() {
+		t, err := scanTrade(rows.Scan)
+		if err != nil {
+			return nil, err
+		}
+		trades = append(trades, *t)
+	}
+	return trades, rows.Err()
+}
+
+// fetchSettledTrades returns trades marked as handled since since, whose
+// settlement ledger entries should now exist.
+func fetchSettledTrades(db *sql.DB, since time.Time) ([]Trade, error) {
+	rows, err := db.Query(
+		`SELECT `+tradeColumns+` FROM trades WHERE onchain_send_handled = true AND match_timestamp >= $1 ORDER BY match_timestamp`,
+		since,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("query settled trades: %w", err)
+	}
+	defer rows.Close()
+
+	var trades []Trade
+	for rows.Next() {
+		t, err := scanTrade(rows.Scan)
+		if err != nil {
+			return nil, err
+		}
+		trades = append(trades, *t)
+	}
+	return trades, rows.Err()
+}
+
+// fetchSettlementLedger returns the most recent on-chain settlement
+// asset_history recorded for orderID, or nil if none exists yet.
+func fetchSettlementLedger(db *sql.DB, orderID string) (*SettlementRecord, error) {
+	var rec SettlementRecord
+	var tokenAmount, usdcAmount sql.NullString
+
+	err := db.QueryRow(
+		`SELECT tx_hash, token_amount, usdc_amount FROM asset_history
+		 WHERE order_id = $1 AND history_type IN ('on_chain_buy_success', 'on_chain_sell_success')
+		 ORDER BY created_at DESC LIMIT 1`,
+		orderID,
+	).Scan(&rec.TxHash, &tokenAmount, &usdcAmount)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("query settlement ledger for order %s: %w", orderID, err)
+	}
+
+	if tokenAmount.Valid {
+		if rec.TokenAmount, err = decimal.NewFromString(tokenAmount.String); err != nil {
+			return nil, fmt.Errorf("parse ledger token_amount %q: %w", tokenAmount.String, err)
+		}
+	}
+	if usdcAmount.Valid {
+		if rec.UsdcAmount, err = decimal.NewFromString(usdcAmount.String); err != nil {
+			return nil, fmt.Errorf("parse ledger usdc_amount %q: %w", usdcAmount.String, err)
+		}
+	}
+	return &rec, nil
+}
+
+// Reconcile cross-checks trades against the settlement ledger over the
+// last lookback and returns every problem it finds.
+func Reconcile(db *sql.DB, lookback time.Duration) ([]Finding, error) {
+	var findings []Finding
+
+	stuck, err := fetchStuckTrades(db, StuckSettlementThreshold)
+	if err != nil {
+		return nil, err
+	}
+	for _, t := range stuck {
+		findings = append(findings, Finding{
+			Trade:  t,
+			Reason: "stuck_settlement",
+			Detail: fmt.Sprintf("onchain_send_handled still false %s after match", time.Since(t.MatchTimestamp).Round(time.Second)),
+		})
+	}
+
+	settled, err := fetchSettledTrades(db, time.Now().Add(-lookback))
+	if err != nil {
+		return nil, err
+	}
+	for _, t := range settled {
+		ledger, err := fetchSettlementLedger(db, t.OrderID)
+		if err != nil {
+			return nil, err
+		}
+		if ledger == nil {
+			findings = append(findings, Finding{
+				Trade:  t,
+				Reason: "missing_ledger_entry",
+				Detail: "onchain_send_handled=true but no asset_history settlement row found",
+			})
+			continue
+		}
+
+		if t.TxHash.Valid && ledger.TxHash.Valid && t.TxHash.String != ledger.TxHash.String {
+			findings = append(findings, Finding{
+				Trade:  t,
+				Reason: "tx_hash_mismatch",
+				Detail: fmt.Sprintf("trades.tx_hash=%s asset_history.tx_hash=%s", t.TxHash.String, ledger.TxHash.String),
+			})
+		}
+
+		if diff := t.TokenAmount.Sub(ledger.TokenAmount).Abs(); diff.GreaterThan(AmountMismatchTolerance) {
+			findings = append(findings, Finding{
+				Trade:  t,
+				Reason: "amount_mismatch",
+				Detail: fmt.Sprintf("trades.token_amount=%s asset_history.token_amount=%s diff=%s", t.TokenAmount, ledger.TokenAmount, diff),
+			})
+		}
+	}
+
+	return findings, nil
+}
+
+func printFindings(findings []Finding) {
+	if len(findings) == 0 {
+		fmt.Println("no discrepancies found")
+		return
+	}
+	for _, f := range findings {
+		fmt.Printf("[%s] order_id=%s event_id=%d market_id=%d token_id=%s user_id=%d: %s\n",
+			f.Reason, f.Trade.OrderID, f.Trade.EventID, f.Trade.MarketID, f.Trade.TokenID, f.Trade.UserID, f.Detail)
+	}
+	fmt.Printf("%d discrepanc(y/ies) found\n", len(findings))
+}
+
+func connectDB() (*sql.DB, error) {
+	password := os.Getenv("RECONCILE_DB_PASSWORD")
+	connStr := fmt.Sprintf("host=%s port=%d user=%s password=%s dbname=%s sslmode=require",
+		DBHost, DBPort, DBUser, password, DBName)
+	db, err := sql.Open("postgres", connStr)
+	if err != nil {
+		return nil, fmt.Errorf("connect to database: %w", err)
+	}
+	if err := db.Ping(); err != nil {
+		return nil, fmt.Errorf("ping database: %w", err)
+	}
+	return db, nil
+}
+
+func printUsage() {
+	fmt.Println("Usage: reconcile run [lookback_hours]")
+}
+
+func main() {
+	if len(os.Args) < 2 {
+		printUsage()
+		os.Exit(1)
+	}
+
+	switch os.Args[1] {
+	case "run":
+		lookbackHours := 24
+		if len(os.Args) > 2 {
+			var err error
+			lookbackHours, err = strconv.Atoi(os.Args[2])
+			if err != nil {
+				log.Fatalf("invalid lookback_hours: %v", err)
+			}
+		}
+
+		db, err := connectDB()
+		if err != nil {
+			log.Fatalf("%v", err)
+		}
+		defer db.Close()
+
+		findings, err := Reconcile(db, time.Duration(lookbackHours)*time.Hour)
+		if err != nil {
+			log.Fatalf("reconcile: %v", err)
+		}
+		printFindings(findings)
+		if len(findings) > 0 {
+			os.Exit(1)
+		}
+
+	default:
+		printUsage()
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,182 @@
+// Package risk enforces per-account trading limits so one bad loop
+// iteration can't place unlimited orders or blow through a loss budget.
+// The engine only tracks state in memory - the bot has no other
+// persistent store for this - so a process restart clears exposure
+// tracking along with whatever open orders it forgot about.
+package risk
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// Limits are the risk limits enforced for one account.
+type Limits struct {
+	MaxOpenNotional    decimal.Decimal
+	MaxOrdersPerMarket int
+	MaxDailyLossUSDC   decimal.Decimal
+	BannedMarkets      map[string]bool
+}
+
+// DefaultLimits returns a conservative starting point; callers override
+// per account via Engine.SetLimits as needed.
+func DefaultLimits() Limits {
+	return Limits{
+		MaxOpenNotional:    decimal.NewFromInt(50),
+		MaxOrdersPerMarket: 5,
+		MaxDailyLossUSDC:   decimal.NewFromInt(20),
+		BannedMarkets:      make(map[string]bool),
+	}
+}
+
+// accountState is the exposure the engine has observed for one account.
+type accountState struct {
+	openNotional    decimal.Decimal
+	ordersPerMarket map[string]int
+	dailyLoss       decimal.Decimal
+	dailyLossDate   string // YYYY-MM-DD, reset when the date rolls over
+}
+
+// Engine checks proposed orders against per-account Limits before they're
+// placed, and tracks the exposure they create.
+type Engine struct {
+	mu     sync.Mutex
+	limits map[string]Limits
+	state  map[string]*accountState
+}
+
+// NewEngine creates a risk engine with no accounts configured; any
+// account not passed to SetLimits is checked against DefaultLimits.
+func NewEngine() *Engine {
+	return &Engine{
+		limits: make(map[string]Limits),
+		state:  make(map[string]*accountState),
+	}
+}
+
+// SetLimits configures (or replaces) the limits for account, for runtime
+// adjustment from the CLI without restarting the bot process.
+func (e *Engine) SetLimits(account string, limits Limits) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if limits.BannedMarkets == nil {
+		limits.BannedMarkets = make(map[string]bool)
+	}
+	e.limits[account] = limits
+}
+
+// Limits returns the currently configured limits for account, falling
+// back to DefaultLimits if none were set.
+func (e *Engine) Limits(account string) Limits {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.limitsLocked(account)
+}
+
+func (e *Engine) limitsLocked(account string) Limits {
+	if limits, ok := e.limits[account]; ok {
+		return limits
+	}
+	return DefaultLimits()
+}
+
+func (e *Engine) stateLocked(account string) *accountState {
+	st, ok := e.state[account]
+	if !ok {
+		st = &accountState{ordersPerMarket: make(map[string]int)}
+		e.state[account] = st
+	}
+	return st
+}
+
+// Allow checks whether account may place an order of the given notional
+// (price * shares) on marketKey, and if so records it against the
+// account's open notional and per-market order count. Call this
+// immediately before PlaceOrder; a non-nil error means the order must be
+// skipped, not retried.
+func (e *Engine) Allow(account, marketKey string, notional decimal.Decimal) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	limits := e.limitsLocked(account)
+	if limits.BannedMarkets[marketKey] {
+		return fmt.Errorf("risk: market %s is banned for account %s", marketKey, account)
+	}
+
+	st := e.stateLocked(account)
+	e.resetDailyLossLocked(st)
+
+	if st.dailyLoss.GreaterThanOrEqual(limits.MaxDailyLossUSDC) {
+		return fmt.Errorf("risk: account %s hit max daily loss %s", account, limits.MaxDailyLossUSDC.String())
+	}
+
+	if st.ordersPerMarket[marketKey] >= limits.MaxOrdersPerMarket {
+		return fmt.Errorf("risk: account %s hit max orders per market (%d) on %s", account, limits.MaxOrdersPerMarket, marketKey)
+	}
+
+	if st.openNotional.Add(notional).GreaterThan(limits.MaxOpenNotional) {
+		return fmt.Errorf("risk: account %s open notional %s + %s would exceed max %s",
+			account, st.openNotional.String(), notional.String(), limits.MaxOpenNotional.String())
+	}
+
+	st.openNotional = st.openNotional.Add(notional)
+	st.ordersPerMarket[marketKey]++
+	return nil
+}
+
+// ReleaseNotional gives back open notional and one marketKey order slot
+// once a position is closed or an order is cancelled/expired, so an
+// account isn't permanently penalized for exposure and order count it no
+// longer carries.
+func (e *Engine) ReleaseNotional(account, marketKey string, notional decimal.Decimal) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	st := e.stateLocked(account)
+	st.openNotional = st.openNotional.Sub(notional)
+	if st.openNotional.IsNegative() {
+		st.openNotional = decimal.Zero
+	}
+	if st.ordersPerMarket[marketKey] > 0 {
+		st.ordersPerMarket[marketKey]--
+	}
+}
+
+// RecordLoss adds realized loss (a positive value) against account's
+// daily loss budget, e.g. once a settlement comes back worse than quoted.
+func (e *Engine) RecordLoss(account string, lossUSDC decimal.Decimal) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	st := e.stateLocked(account)
+	e.resetDailyLossLocked(st)
+	st.dailyLoss = st.dailyLoss.Add(lossUSDC)
+}
+
+// BanMarket blocks account from trading marketKey until UnbanMarket is
+// called, so the CLI can react to a misbehaving market without a restart.
+func (e *Engine) BanMarket(account, marketKey string) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	limits := e.limitsLocked(account)
+	limits.BannedMarkets[marketKey] = true
+	e.limits[account] = limits
+}
+
+// UnbanMarket lifts a previous BanMarket for account/marketKey.
+func (e *Engine) UnbanMarket(account, marketKey string) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	limits := e.limitsLocked(account)
+	delete(limits.BannedMarkets, marketKey)
+	e.limits[account] = limits
+}
+
+func (e *Engine) resetDailyLossLocked(st *accountState) {
+	today := time.Now().Format("2006-01-02")
+	if st.dailyLossDate != today {
+		st.dailyLossDate = today
+		st.dailyLoss = decimal.Zero
+	}
+}
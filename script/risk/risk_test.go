@@ -0,0 +1,85 @@
+package risk
+
+import (
+	"testing"
+
+	"github.com/shopspring/decimal"
+)
+
+func TestReleaseNotionalAllowsFollowUpOrder(t *testing.T) {
+	e := NewEngine()
+	e.SetLimits("acct1", Limits{
+		MaxOpenNotional:    decimal.NewFromInt(10),
+		MaxOrdersPerMarket: 5,
+		MaxDailyLossUSDC:   decimal.NewFromInt(20),
+		BannedMarkets:      make(map[string]bool),
+	})
+
+	notional := decimal.NewFromInt(10)
+	if err := e.Allow("acct1", "market1", notional); err != nil {
+		t.Fatalf("first Allow: %v", err)
+	}
+
+	if err := e.Allow("acct1", "market1", decimal.NewFromInt(1)); err == nil {
+		t.Fatalf("expected second Allow to fail while first order's notional is still open")
+	}
+
+	e.ReleaseNotional("acct1", "market1", notional)
+
+	if err := e.Allow("acct1", "market1", notional); err != nil {
+		t.Fatalf("Allow after ReleaseNotional: %v", err)
+	}
+}
+
+func TestReleaseNotionalClampsAtZero(t *testing.T) {
+	e := NewEngine()
+	e.ReleaseNotional("acct1", "market1", decimal.NewFromInt(5))
+
+	if err := e.Allow("acct1", "market1", DefaultLimits().MaxOpenNotional); err != nil {
+		t.Fatalf("Allow after over-releasing: %v", err)
+	}
+}
+
+func TestReleaseNotionalDecrementsOrdersPerMarket(t *testing.T) {
+	e := NewEngine()
+	e.SetLimits("acct1", Limits{
+		MaxOpenNotional:    decimal.NewFromInt(1000),
+		MaxOrdersPerMarket: 2,
+		MaxDailyLossUSDC:   decimal.NewFromInt(20),
+		BannedMarkets:      make(map[string]bool),
+	})
+
+	one := decimal.NewFromInt(1)
+	if err := e.Allow("acct1", "market1", one); err != nil {
+		t.Fatalf("order 1: %v", err)
+	}
+	if err := e.Allow("acct1", "market1", one); err != nil {
+		t.Fatalf("order 2: %v", err)
+	}
+	if err := e.Allow("acct1", "market1", one); err == nil {
+		t.Fatalf("expected order 3 to be refused at MaxOrdersPerMarket")
+	}
+
+	// Releasing one of the two filled/cancelled orders should free up a
+	// slot, not just notional.
+	e.ReleaseNotional("acct1", "market1", one)
+
+	if err := e.Allow("acct1", "market1", one); err != nil {
+		t.Fatalf("Allow after ReleaseNotional freed an order slot: %v", err)
+	}
+}
+
+func TestReleaseNotionalOrdersPerMarketFloorsAtZero(t *testing.T) {
+	e := NewEngine()
+	e.ReleaseNotional("acct1", "market1", decimal.Zero)
+
+	e.SetLimits("acct1", Limits{
+		MaxOpenNotional:    decimal.NewFromInt(1000),
+		MaxOrdersPerMarket: 1,
+		MaxDailyLossUSDC:   decimal.NewFromInt(20),
+		BannedMarkets:      make(map[string]bool),
+	})
+	if err := e.Allow("acct1", "market1", decimal.NewFromInt(1)); err != nil {
+		t.Fatalf("Allow after releasing with no orders outstanding: %v", err)
+	}
+}
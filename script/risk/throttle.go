@@ -0,0 +1,151 @@
+package risk
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// ThrottleLimits bounds how often one account may act (place, cancel or
+// replace an order) so a strategy bug spinning in a cancel/replace loop
+// can't spam the API.
+type ThrottleLimits struct {
+	// MinRequoteInterval is the minimum time between two actions on the
+	// same market for the same account.
+	MinRequoteInterval time.Duration
+	// MaxActionsPerMinute caps total order actions per account across all
+	// markets, in a rolling one-minute window.
+	MaxActionsPerMinute int
+}
+
+// DefaultThrottleLimits is used for any account without an explicit
+// SetLimits call.
+func DefaultThrottleLimits() ThrottleLimits {
+	return ThrottleLimits{
+		MinRequoteInterval:  250 * time.Millisecond,
+		MaxActionsPerMinute: 120,
+	}
+}
+
+type marketThrottleState struct {
+	lastActionAt time.Time
+}
+
+type accountThrottleState struct {
+	windowStart       time.Time
+	actionsThisWindow int
+}
+
+// Throttle enforces ThrottleLimits per account and counts violations so
+// they can be exposed as metrics. Like Engine, it only tracks state in
+// memory and resets on restart.
+type Throttle struct {
+	mu         sync.Mutex
+	limits     map[string]ThrottleLimits
+	perMarket  map[string]*marketThrottleState
+	perAccount map[string]*accountThrottleState
+	violations map[string]int64
+}
+
+// NewThrottle creates a throttle with no accounts configured; any account
+// not passed to SetLimits is checked against DefaultThrottleLimits.
+func NewThrottle() *Throttle {
+	return &Throttle{
+		limits:     make(map[string]ThrottleLimits),
+		perMarket:  make(map[string]*marketThrottleState),
+		perAccount: make(map[string]*accountThrottleState),
+		violations: make(map[string]int64),
+	}
+}
+
+// SetLimits configures (or replaces) the throttle limits for account.
+func (t *Throttle) SetLimits(account string, limits ThrottleLimits) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.limits[account] = limits
+}
+
+// Limits returns the currently configured limits for account, falling
+// back to DefaultThrottleLimits if none were set.
+func (t *Throttle) Limits(account string) ThrottleLimits {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.limitsLocked(account)
+}
+
+func (t *Throttle) limitsLocked(account string) ThrottleLimits {
+	if limits, ok := t.limits[account]; ok {
+		return limits
+	}
+	return DefaultThrottleLimits()
+}
+
+// Allow checks whether account may perform an order action (place, cancel
+// or replace) on marketKey right now, and if so records it. Call this
+// immediately before sending the action to the API; a non-nil error means
+// the action must be dropped, not retried in a tight loop.
+func (t *Throttle) Allow(account, marketKey string) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	limits := t.limitsLocked(account)
+	now := time.Now()
+
+	marketState := t.marketStateLocked(account, marketKey)
+	if !marketState.lastActionAt.IsZero() {
+		if elapsed := now.Sub(marketState.lastActionAt); elapsed < limits.MinRequoteInterval {
+			t.recordViolationLocked(account, "min_requote_interval")
+			return fmt.Errorf("risk: account %s market %s requoted after %s, below min interval %s",
+				account, marketKey, elapsed, limits.MinRequoteInterval)
+		}
+	}
+
+	accountState := t.accountStateLocked(account)
+	if now.Sub(accountState.windowStart) >= time.Minute {
+		accountState.windowStart = now
+		accountState.actionsThisWindow = 0
+	}
+	if accountState.actionsThisWindow >= limits.MaxActionsPerMinute {
+		t.recordViolationLocked(account, "actions_per_minute")
+		return fmt.Errorf("risk: account %s exceeded %d order actions/minute", account, limits.MaxActionsPerMinute)
+	}
+
+	marketState.lastActionAt = now
+	accountState.actionsThisWindow++
+	return nil
+}
+
+func (t *Throttle) marketStateLocked(account, marketKey string) *marketThrottleState {
+	key := account + "/" + marketKey
+	st, ok := t.perMarket[key]
+	if !ok {
+		st = &marketThrottleState{}
+		t.perMarket[key] = st
+	}
+	return st
+}
+
+func (t *Throttle) accountStateLocked(account string) *accountThrottleState {
+	st, ok := t.perAccount[account]
+	if !ok {
+		st = &accountThrottleState{windowStart: time.Now()}
+		t.perAccount[account] = st
+	}
+	return st
+}
+
+func (t *Throttle) recordViolationLocked(account, reason string) {
+	t.violations[account+":"+reason]++
+}
+
+// Violations returns a snapshot of violation counts keyed by
+// "account:reason", for a metrics/health endpoint or CLI to report.
+func (t *Throttle) Violations() map[string]int64 {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	out := make(map[string]int64, len(t.violations))
+	for k, v := range t.violations {
+		out[k] = v
+	}
+	return out
+}
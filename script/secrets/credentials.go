@@ -0,0 +1,109 @@
+package secrets
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// CredentialsProvider resolves a named credential (a DB password, a Redis
+// password, a private key, ...) from wherever it actually lives, so no
+// call site needs to know if that's an env var, a mounted file, GCP Secret
+// Manager, or Vault. Every place in this repo that previously held a
+// literal password or private key should go through one of these instead.
+type CredentialsProvider interface {
+	GetCredential(ctx context.Context, name string) (string, error)
+}
+
+// EnvProvider resolves credentials from environment variables, using name
+// verbatim as the variable name (e.g. "ACCOUNT1_PRIVATE_KEY").
+type EnvProvider struct{}
+
+// GetCredential returns the environment variable named name, erroring if unset.
+func (EnvProvider) GetCredential(_ context.Context, name string) (string, error) {
+	value, ok := os.LookupEnv(name)
+	if !ok || value == "" {
+		return "", fmt.Errorf("credential %q: environment variable not set", name)
+	}
+	return value, nil
+}
+
+// FileProvider resolves credentials from files in Dir, one credential per
+// file named after it (matching how Cloud Run/Kubernetes mount secrets).
+type FileProvider struct {
+	Dir string
+}
+
+// GetCredential reads Dir/name and trims surrounding whitespace/newlines.
+func (p FileProvider) GetCredential(_ context.Context, name string) (string, error) {
+	data, err := os.ReadFile(filepath.Join(p.Dir, name))
+	if err != nil {
+		return "", fmt.Errorf("credential %q: %w", name, err)
+	}
+	return strings.TrimSpace(string(data)), nil
+}
+
+// SecretManagerProvider resolves credentials from GCP Secret Manager via a
+// Manager, so callers get caching/refresh for free.
+type SecretManagerProvider struct {
+	Manager *Manager
+}
+
+// GetCredential fetches name as a Secret Manager secret ID.
+func (p SecretManagerProvider) GetCredential(ctx context.Context, name string) (string, error) {
+	return p.Manager.GetSecret(ctx, name)
+}
+
+// VaultProvider resolves credentials from a HashiCorp Vault KV v2 mount
+// using the HTTP API directly, avoiding a heavyweight client dependency
+// for what these scripts need: one string per credential name.
+type VaultProvider struct {
+	Addr       string // e.g. "https://vault.internal:8200"
+	Token      string
+	MountPath  string // KV v2 mount, e.g. "secret"
+	HTTPClient *http.Client
+}
+
+// GetCredential reads MountPath/data/name and returns its "value" field.
+func (p VaultProvider) GetCredential(ctx context.Context, name string) (string, error) {
+	client := p.HTTPClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	url := fmt.Sprintf("%s/v1/%s/data/%s", strings.TrimRight(p.Addr, "/"), p.MountPath, name)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return "", fmt.Errorf("credential %q: %w", name, err)
+	}
+	req.Header.Set("X-Vault-Token", p.Token)
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("credential %q: %w", name, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("credential %q: vault returned %s", name, resp.Status)
+	}
+
+	var body struct {
+		Data struct {
+			Data map[string]string `json:"data"`
+		} `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return "", fmt.Errorf("credential %q: decode vault response: %w", name, err)
+	}
+
+	value, ok := body.Data.Data["value"]
+	if !ok {
+		return "", fmt.Errorf("credential %q: vault secret has no \"value\" field", name)
+	}
+	return value, nil
+}
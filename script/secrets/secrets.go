@@ -0,0 +1,96 @@
+// Package secrets fetches runtime credentials (DB passwords, Redis
+// passwords, private keys) from GCP Secret Manager at startup, with
+// in-memory caching and periodic refresh, so services running on Cloud Run
+// don't need plaintext credentials baked into the source.
+package secrets
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	secretmanager "cloud.google.com/go/secretmanager/apiv1"
+	secretmanagerpb "cloud.google.com/go/secretmanager/apiv1/secretmanagerpb"
+)
+
+// Manager fetches secrets from GCP Secret Manager and caches them for TTL,
+// avoiding an API call on every access.
+type Manager struct {
+	client    *secretmanager.Client
+	projectID string
+	ttl       time.Duration
+
+	mu    sync.Mutex
+	cache map[string]cacheEntry
+}
+
+type cacheEntry struct {
+	value     string
+	fetchedAt time.Time
+}
+
+// NewManager creates a Manager backed by application-default credentials.
+// ttl controls how long a fetched secret value is reused before the next
+// access triggers a refresh; a ttl of zero disables caching.
+func NewManager(ctx context.Context, projectID string, ttl time.Duration) (*Manager, error) {
+	client, err := secretmanager.NewClient(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("create secret manager client: %w", err)
+	}
+
+	return &Manager{
+		client:    client,
+		projectID: projectID,
+		ttl:       ttl,
+		cache:     make(map[string]cacheEntry),
+	}, nil
+}
+
+// GetSecret returns the latest enabled version of the named secret,
+// serving from cache when the entry is still within ttl.
+func (m *Manager) GetSecret(ctx context.Context, name string) (string, error) {
+	m.mu.Lock()
+	if entry, ok := m.cache[name]; ok && (m.ttl == 0 || time.Since(entry.fetchedAt) < m.ttl) {
+		m.mu.Unlock()
+		return entry.value, nil
+	}
+	m.mu.Unlock()
+
+	value, err := m.fetch(ctx, name)
+	if err != nil {
+		return "", err
+	}
+
+	m.mu.Lock()
+	m.cache[name] = cacheEntry{value: value, fetchedAt: time.Now()}
+	m.mu.Unlock()
+
+	return value, nil
+}
+
+func (m *Manager) fetch(ctx context.Context, name string) (string, error) {
+	req := &secretmanagerpb.AccessSecretVersionRequest{
+		Name: fmt.Sprintf("projects/%s/secrets/%s/versions/latest", m.projectID, name),
+	}
+
+	resp, err := m.client.AccessSecretVersion(ctx, req)
+	if err != nil {
+		return "", fmt.Errorf("access secret %q: %w", name, err)
+	}
+
+	return string(resp.Payload.Data), nil
+}
+
+// Refresh drops the cached value for name, forcing the next GetSecret call
+// to fetch a fresh copy. Useful after a known secret rotation.
+func (m *Manager) Refresh(name string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.cache, name)
+}
+
+// Close releases the underlying Secret Manager client.
+func (m *Manager) Close() error {
+	return m.client.Close()
+}
@@ -0,0 +1,328 @@
+// Command simulator drives strategy.VolatilitySpreadStrategy's pricing
+// against synthetic taker flow - noise traders trading at random, and
+// informed traders drifting toward a latent true probability - so we can
+// estimate adverse selection costs before quoting a new topic live.
+//
+// Usage:
+//
+//	go run . <sim_config.yaml>
+package main
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"os"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"gopkg.in/yaml.v3"
+
+	"strategy"
+)
+
+// SimConfig describes one simulation run: how the latent true probability
+// evolves, how noise and informed taker flow is generated, and the
+// strategy parameters our quotes are priced with.
+type SimConfig struct {
+	Ticks int64 `yaml:"ticks"`
+	Seed  int64 `yaml:"seed"`
+
+	StartingTrueProb    float64 `yaml:"starting_true_prob"`
+	TrueProbDriftStddev float64 `yaml:"true_prob_drift_stddev"`
+
+	NoiseTraderRate float64 `yaml:"noise_trader_rate"` // avg trades/tick
+	NoiseTraderSize int64   `yaml:"noise_trader_size"`
+
+	InformedTraderRate       float64 `yaml:"informed_trader_rate"` // avg trades/tick
+	InformedTraderSize       int64   `yaml:"informed_trader_size"`
+	InformedEdgeThresholdBps int64   `yaml:"informed_edge_threshold_bps"` // min edge before an informed trader bothers trading
+	InformedMarkoutTicks     int64   `yaml:"informed_markout_ticks"`      // how far ahead adverse selection is measured
+
+	Strategy strategy.VolatilityParams `yaml:"strategy"`
+}
+
+// loadSimConfig reads and validates the simulator's YAML config file.
+func loadSimConfig(path string) (*SimConfig, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read sim config: %w", err)
+	}
+
+	var cfg SimConfig
+	if err := yaml.Unmarshal(data, &cfg); err != nil {
+		return nil, fmt.Errorf("parse sim config: %w", err)
+	}
+	if cfg.Ticks <= 0 {
+		return nil, fmt.Errorf("sim config: ticks must be positive")
+	}
+	if cfg.StartingTrueProb <= 0 || cfg.StartingTrueProb >= 1 {
+		return nil, fmt.Errorf("sim config: starting_true_prob must be in (0, 1)")
+	}
+	if cfg.InformedMarkoutTicks <= 0 {
+		cfg.InformedMarkoutTicks = 10
+	}
+	return &cfg, nil
+}
+
+// side is which side of our quote a taker traded against.
+type side string
+
+const (
+	sideBuy  side = "buy"  // taker bought, i.e. lifted our ask
+	sideSell side = "sell" // taker sold, i.e. hit our bid
+)
+
+// fill is one taker trade against our resting quote.
+type fill struct {
+	Tick         int64
+	Trader       string // "noise" or "informed"
+	Side         side
+	Price        decimal.Decimal
+	Shares       int64
+	TrueProbThen float64
+}
+
+// clampProb keeps a probability walk inside (0.01, 0.99) - true 0 or 1
+// would make the informed trader's edge diverge to infinity.
+func clampProb(p float64) float64 {
+	if p < 0.01 {
+		return 0.01
+	}
+	if p > 0.99 {
+		return 0.99
+	}
+	return p
+}
+
+// stepTrueProb advances the latent true probability one tick via a
+// Gaussian random walk.
+func stepTrueProb(p float64, stddev float64, rng *rand.Rand) float64 {
+	return clampProb(p + rng.NormFloat64()*stddev)
+}
+
+// noiseFills generates this tick's noise trader flow: traders with no
+// information who buy or sell at random regardless of where the true
+// probability sits, sized around config.NoiseTraderSize.
+func noiseFills(tick int64, quote strategy.Quote, cfg *SimConfig, trueProb float64, rng *rand.Rand) []fill {
+	count := poisson(cfg.NoiseTraderRate, rng)
+	fills := make([]fill, 0, count)
+	for i := 0; i < count; i++ {
+		s := sideBuy
+		price := quote.AskPrice
+		if rng.Float64() < 0.5 {
+			s = sideSell
+			price = quote.BidPrice
+		}
+		fills = append(fills, fill{
+			Tick: tick, Trader: "noise", Side: s, Price: price,
+			Shares: sizeAround(cfg.NoiseTraderSize, rng), TrueProbThen: trueProb,
+		})
+	}
+	return fills
+}
+
+// informedFills generates this tick's informed trader flow: traders who
+// see the true probability and only trade when our quote is mispriced
+// relative to it by more than InformedEdgeThresholdBps, buying when our
+// ask is too cheap and selling when our bid is too rich.
+func informedFills(tick int64, quote strategy.Quote, cfg *SimConfig, trueProb float64, rng *rand.Rand) []fill {
+	count := poisson(cfg.InformedTraderRate, rng)
+	fills := make([]fill, 0, count)
+	threshold := decimal.NewFromInt(cfg.InformedEdgeThresholdBps).Div(decimal.NewFromInt(10000))
+	trueDec := decimal.NewFromFloat(trueProb)
+
+	for i := 0; i < count; i++ {
+		edgeToBuy := trueDec.Sub(quote.AskPrice)
+		edgeToSell := quote.BidPrice.Sub(trueDec)
+
+		var s side
+		var price decimal.Decimal
+		switch {
+		case edgeToBuy.GreaterThan(threshold):
+			s, price = sideBuy, quote.AskPrice
+		case edgeToSell.GreaterThan(threshold):
+			s, price = sideSell, quote.BidPrice
+		default:
+			continue // no edge worth trading on this tick
+		}
+
+		fills = append(fills, fill{
+			Tick: tick, Trader: "informed", Side: s, Price: price,
+			Shares: sizeAround(cfg.InformedTraderSize, rng), TrueProbThen: trueProb,
+		})
+	}
+	return fills
+}
+
+// poisson draws a small non-negative trade count with mean rate, via
+// direct Knuth simulation - rates here are low enough (a handful of
+// trades/tick at most) that this is plenty accurate without a dedicated
+// distribution library.
+func poisson(rate float64, rng *rand.Rand) int {
+	if rate <= 0 {
+		return 0
+	}
+	l := math.Exp(-rate)
+	k := 0
+	p := 1.0
+	for {
+		k++
+		p *= rng.Float64()
+		if p <= l {
+			return k - 1
+		}
+	}
+}
+
+// sizeAround jitters base by +/-25% so fills aren't all identically sized.
+func sizeAround(base int64, rng *rand.Rand) int64 {
+	jitter := 0.75 + rng.Float64()*0.5
+	size := int64(float64(base) * jitter)
+	if size < 1 {
+		size = 1
+	}
+	return size
+}
+
+// report is the adverse-selection summary printed at the end of a run.
+type report struct {
+	NoiseFillCount     int
+	NoiseVolume        int64
+	InformedFillCount  int
+	InformedVolume     int64
+	AdverseSelectionBp decimal.Decimal // avg per-share cost, in bps of price, on informed fills only
+}
+
+// runSimulation drives cfg.Ticks ticks of quoting against synthetic flow,
+// tracking a rolling one-candle-per-tick history for strategy.QuoteFromCandles
+// and measuring adverse selection on informed fills: the true probability's
+// move, over the following InformedMarkoutTicks, further in the direction
+// that hurt us as the maker.
+//
+// The market mid our quotes are centered on only moves when a trade prints
+// (mark-to-last-trade), while the latent true probability random-walks
+// independently every tick - that gap is what gives informed traders an
+// edge to trade on, and what we're measuring the cost of.
+func runSimulation(cfg *SimConfig) report {
+	rng := rand.New(rand.NewSource(cfg.Seed))
+
+	trueProb := cfg.StartingTrueProb
+	trueProbHistory := make([]float64, 0, cfg.Ticks+1)
+	trueProbHistory = append(trueProbHistory, trueProb)
+
+	var candles []strategy.Candle // most recent first, matching HistoryReader's ordering
+	var fills []fill
+
+	marketMid := decimal.NewFromFloat(trueProb)
+
+	for tick := int64(0); tick < cfg.Ticks; tick++ {
+		quote := strategy.QuoteFromCandles(candles, cfg.Strategy, marketMid)
+
+		tickFills := append(noiseFills(tick, quote, cfg, trueProb, rng), informedFills(tick, quote, cfg, trueProb, rng)...)
+		fills = append(fills, tickFills...)
+		if len(tickFills) > 0 {
+			marketMid = tickFills[len(tickFills)-1].Price
+		}
+
+		candles = append([]strategy.Candle{buildCandle(tick, tickFills, marketMid)}, candles...)
+		if len(candles) > cfg.Strategy.Window {
+			candles = candles[:cfg.Strategy.Window]
+		}
+
+		trueProb = stepTrueProb(trueProb, cfg.TrueProbDriftStddev, rng)
+		trueProbHistory = append(trueProbHistory, trueProb)
+	}
+
+	return summarize(fills, trueProbHistory, cfg.InformedMarkoutTicks)
+}
+
+// buildCandle folds one tick's fills into a synthetic 1-tick candle, using
+// marketMid as open/high/low/close when the tick saw no trades.
+func buildCandle(tick int64, tickFills []fill, marketMid decimal.Decimal) strategy.Candle {
+	c := strategy.Candle{
+		BucketStart: time.Unix(tick, 0).UTC(),
+		Open:        marketMid, High: marketMid, Low: marketMid, Close: marketMid,
+		Volume: decimal.Zero, TradeCount: len(tickFills),
+	}
+	for _, f := range tickFills {
+		if f.Price.GreaterThan(c.High) {
+			c.High = f.Price
+		}
+		if f.Price.LessThan(c.Low) {
+			c.Low = f.Price
+		}
+		c.Close = f.Price
+		c.Volume = c.Volume.Add(decimal.NewFromInt(f.Shares))
+	}
+	return c
+}
+
+// summarize computes fill/volume counts and the adverse selection markout
+// on informed fills: for a fill where we sold (taker bought), the true
+// probability rising further over the markout window means we sold too
+// cheap; for a fill where we bought (taker sold), it falling further means
+// we bought too rich. Both cases are "the true probability moved against
+// the side we ended up on", expressed in bps of the fill price.
+func summarize(fills []fill, trueProbHistory []float64, markoutTicks int64) report {
+	var r report
+	var markoutBpsTotal decimal.Decimal
+	var markoutCount int64
+
+	for _, f := range fills {
+		if f.Trader == "noise" {
+			r.NoiseFillCount++
+			r.NoiseVolume += f.Shares
+			continue
+		}
+
+		r.InformedFillCount++
+		r.InformedVolume += f.Shares
+
+		markoutIdx := f.Tick + markoutTicks
+		if markoutIdx >= int64(len(trueProbHistory)) {
+			markoutIdx = int64(len(trueProbHistory)) - 1
+		}
+		laterProb := trueProbHistory[markoutIdx]
+
+		var moveAgainstUs float64
+		if f.Side == sideBuy { // taker bought from us (we sold) - hurts if prob rose further
+			moveAgainstUs = laterProb - f.TrueProbThen
+		} else { // taker sold to us (we bought) - hurts if prob fell further
+			moveAgainstUs = f.TrueProbThen - laterProb
+		}
+
+		priceFloat, _ := f.Price.Float64()
+		if priceFloat == 0 {
+			continue
+		}
+		markoutBpsTotal = markoutBpsTotal.Add(decimal.NewFromFloat(moveAgainstUs / priceFloat * 10000))
+		markoutCount++
+	}
+
+	if markoutCount > 0 {
+		r.AdverseSelectionBp = markoutBpsTotal.Div(decimal.NewFromInt(markoutCount))
+	}
+	return r
+}
+
+func printReport(r report) {
+	fmt.Printf("Noise fills:     %d (%d shares)\n", r.NoiseFillCount, r.NoiseVolume)
+	fmt.Printf("Informed fills:  %d (%d shares)\n", r.InformedFillCount, r.InformedVolume)
+	fmt.Printf("Adverse selection (informed fills, avg markout): %s bps\n", r.AdverseSelectionBp.StringFixed(2))
+}
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Println("Usage: go run . <sim_config.yaml>")
+		os.Exit(1)
+	}
+
+	cfg, err := loadSimConfig(os.Args[1])
+	if err != nil {
+		fmt.Printf("❌ %v\n", err)
+		os.Exit(1)
+	}
+
+	printReport(runSimulation(cfg))
+}
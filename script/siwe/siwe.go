@@ -0,0 +1,186 @@
+// Package siwe builds and parses EIP-4361 ("Sign-In with Ethereum")
+// messages. It replaces the hand-rolled fmt.Sprintf that used to build
+// the login message inline in bot_go/privy, so a change to the message
+// format (a new field, a reordered line) can't silently drift from what
+// the signature actually covers on one side but not the other.
+package siwe
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Message is an EIP-4361 Sign-In with Ethereum message. Domain, Address,
+// URI, Version, ChainID, Nonce and IssuedAt are required; the rest are
+// optional per the spec.
+type Message struct {
+	Domain    string
+	Address   string
+	Statement string
+	URI       string
+	Version   string
+	ChainID   int64
+	Nonce     string
+	IssuedAt  string
+
+	ExpirationTime string
+	NotBefore      string
+	RequestID      string
+	Resources      []string
+}
+
+// Validate checks that the required fields are set.
+func (m Message) Validate() error {
+	switch {
+	case m.Domain == "":
+		return fmt.Errorf("siwe: domain is required")
+	case m.Address == "":
+		return fmt.Errorf("siwe: address is required")
+	case m.URI == "":
+		return fmt.Errorf("siwe: uri is required")
+	case m.Version == "":
+		return fmt.Errorf("siwe: version is required")
+	case m.Nonce == "":
+		return fmt.Errorf("siwe: nonce is required")
+	case m.IssuedAt == "":
+		return fmt.Errorf("siwe: issued at is required")
+	}
+	return nil
+}
+
+// String renders m as the EIP-4361 message text that gets personally
+// signed. It panics if m fails Validate, since a caller building a
+// message to sign has no sane fallback for a missing required field.
+func (m Message) String() string {
+	if err := m.Validate(); err != nil {
+		panic(err)
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s wants you to sign in with your Ethereum account:\n%s\n", m.Domain, m.Address)
+
+	if m.Statement != "" {
+		fmt.Fprintf(&b, "\n%s\n", m.Statement)
+	} else {
+		b.WriteString("\n")
+	}
+
+	fmt.Fprintf(&b, "\nURI: %s\n", m.URI)
+	fmt.Fprintf(&b, "Version: %s\n", m.Version)
+	fmt.Fprintf(&b, "Chain ID: %d\n", m.ChainID)
+	fmt.Fprintf(&b, "Nonce: %s\n", m.Nonce)
+	fmt.Fprintf(&b, "Issued At: %s", m.IssuedAt)
+
+	if m.ExpirationTime != "" {
+		fmt.Fprintf(&b, "\nExpiration Time: %s", m.ExpirationTime)
+	}
+	if m.NotBefore != "" {
+		fmt.Fprintf(&b, "\nNot Before: %s", m.NotBefore)
+	}
+	if m.RequestID != "" {
+		fmt.Fprintf(&b, "\nRequest ID: %s", m.RequestID)
+	}
+	if len(m.Resources) > 0 {
+		b.WriteString("\nResources:")
+		for _, r := range m.Resources {
+			fmt.Fprintf(&b, "\n- %s", r)
+		}
+	}
+
+	return b.String()
+}
+
+// Parse recovers a Message from raw EIP-4361 message text, the inverse of
+// Message.String. It returns an error if raw isn't well-formed, so a
+// caller can detect a format drift by round-tripping String -> Parse and
+// comparing.
+func Parse(raw string) (*Message, error) {
+	lines := strings.Split(raw, "\n")
+	if len(lines) < 2 {
+		return nil, fmt.Errorf("siwe: message too short")
+	}
+
+	const wantsToSignIn = " wants you to sign in with your Ethereum account:"
+	if !strings.HasSuffix(lines[0], wantsToSignIn) {
+		return nil, fmt.Errorf("siwe: missing greeting line")
+	}
+
+	m := &Message{
+		Domain:  strings.TrimSuffix(lines[0], wantsToSignIn),
+		Address: lines[1],
+	}
+
+	rest := lines[2:]
+	if len(rest) > 0 && rest[0] == "" {
+		rest = rest[1:]
+	}
+
+	// The statement, if present, is the block of lines up to the next
+	// blank line; everything after that blank line is "URI: ..." onward.
+	blankIdx := -1
+	for i, line := range rest {
+		if line == "" {
+			blankIdx = i
+			break
+		}
+	}
+	if blankIdx == -1 {
+		return nil, fmt.Errorf("siwe: missing blank line before fields")
+	}
+	if blankIdx > 0 {
+		m.Statement = strings.Join(rest[:blankIdx], "\n")
+	}
+	fieldLines := rest[blankIdx+1:]
+
+	for _, line := range fieldLines {
+		if line == "Resources:" {
+			continue
+		}
+		if strings.HasPrefix(line, "- ") {
+			m.Resources = append(m.Resources, strings.TrimPrefix(line, "- "))
+			continue
+		}
+
+		key, value, ok := strings.Cut(line, ": ")
+		if !ok {
+			return nil, fmt.Errorf("siwe: malformed field line %q", line)
+		}
+
+		switch key {
+		case "URI":
+			m.URI = value
+		case "Version":
+			m.Version = value
+		case "Chain ID":
+			chainID, err := strconv.ParseInt(value, 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("siwe: invalid chain id %q: %w", value, err)
+			}
+			m.ChainID = chainID
+		case "Nonce":
+			m.Nonce = value
+		case "Issued At":
+			m.IssuedAt = value
+		case "Expiration Time":
+			m.ExpirationTime = value
+		case "Not Before":
+			m.NotBefore = value
+		case "Request ID":
+			m.RequestID = value
+		default:
+			return nil, fmt.Errorf("siwe: unknown field %q", key)
+		}
+	}
+
+	if err := m.Validate(); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// NowISO8601 formats now the way IssuedAt/ExpirationTime/NotBefore expect.
+func NowISO8601() string {
+	return time.Now().UTC().Format("2006-01-02T15:04:05.000Z")
+}
@@ -0,0 +1,102 @@
+package strategy
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/shopspring/decimal"
+)
+
+// PositionTracker reads net token inventory from the positions table so
+// strategies can react to how much exposure an account is currently
+// carrying.
+type PositionTracker struct {
+	db *sql.DB
+}
+
+// NewPositionTracker wraps db (same connection convention as
+// HistoryReader) for position reads.
+func NewPositionTracker(db *sql.DB) *PositionTracker {
+	return &PositionTracker{db: db}
+}
+
+// NetPosition returns userID's current balance in tokenID, i.e. the net
+// inventory a skew strategy should react to. A user with no row in
+// positions holds none of the token, so that case is not an error.
+func (t *PositionTracker) NetPosition(userID int64, tokenID string) (decimal.Decimal, error) {
+	var balance string
+	err := t.db.QueryRow(`SELECT balance FROM positions WHERE user_id = $1 AND token_id = $2`, userID, tokenID).Scan(&balance)
+	if err == sql.ErrNoRows {
+		return decimal.Zero, nil
+	}
+	if err != nil {
+		return decimal.Zero, fmt.Errorf("query position for user_id=%d token_id=%s: %w", userID, tokenID, err)
+	}
+	net, err := decimal.NewFromString(balance)
+	if err != nil {
+		return decimal.Zero, fmt.Errorf("parse balance %q: %w", balance, err)
+	}
+	return net, nil
+}
+
+// InventorySkewStrategy shifts both sides of a base quote toward
+// offloading excess inventory: quotes drift down (cheaper to buy from,
+// harder to sell to) when net position is long past MaxInventoryShares,
+// and up when short past it, in proportion to SkewCoefficientBps.
+type InventorySkewStrategy struct {
+	positions *PositionTracker
+
+	// SkewCoefficientBps controls how hard quotes are pushed: at exactly
+	// MaxInventoryShares of net inventory, both sides shift by
+	// SkewCoefficientBps of mid price.
+	SkewCoefficientBps int64
+	// MaxInventoryShares is the inventory level at which skew saturates;
+	// inventory ratios beyond it are clamped to +/-1.
+	MaxInventoryShares decimal.Decimal
+}
+
+// NewInventorySkewStrategy builds a skew strategy reading inventory from
+// positions, applying at most SkewCoefficientBps of shift once net
+// inventory reaches maxInventoryShares.
+func NewInventorySkewStrategy(positions *PositionTracker, skewCoefficientBps int64, maxInventoryShares decimal.Decimal) *InventorySkewStrategy {
+	return &InventorySkewStrategy{
+		positions:          positions,
+		SkewCoefficientBps: skewCoefficientBps,
+		MaxInventoryShares: maxInventoryShares,
+	}
+}
+
+// Skew shifts base's bid and ask by the same amount, computed from net
+// inventory relative to MaxInventoryShares, around midPrice.
+func (s *InventorySkewStrategy) Skew(base Quote, midPrice, netInventory decimal.Decimal) Quote {
+	if s.MaxInventoryShares.IsZero() {
+		return base
+	}
+
+	ratio := netInventory.Div(s.MaxInventoryShares)
+	one := decimal.NewFromInt(1)
+	if ratio.GreaterThan(one) {
+		ratio = one
+	}
+	if ratio.LessThan(one.Neg()) {
+		ratio = one.Neg()
+	}
+
+	shift := midPrice.Mul(decimal.NewFromInt(s.SkewCoefficientBps)).Div(decimal.NewFromInt(10000)).Mul(ratio)
+
+	return Quote{
+		BidPrice:   base.BidPrice.Sub(shift),
+		AskPrice:   base.AskPrice.Sub(shift),
+		SizeShares: base.SizeShares,
+	}
+}
+
+// QuoteWithSkew looks up userID's current net position in tokenID and
+// applies Skew to base around midPrice.
+func (s *InventorySkewStrategy) QuoteWithSkew(userID int64, tokenID string, base Quote, midPrice decimal.Decimal) (Quote, error) {
+	net, err := s.positions.NetPosition(userID, tokenID)
+	if err != nil {
+		return Quote{}, err
+	}
+	return s.Skew(base, midPrice, net), nil
+}
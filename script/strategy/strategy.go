@@ -0,0 +1,255 @@
+// Package strategy holds quoting strategies that turn a market's recent
+// price history into a bid/ask/size to quote. Strategies read candles
+// produced by ohlc_aggregator directly from Postgres rather than
+// recomputing OHLCV themselves, so they see the same history the
+// aggregator's query CLI shows.
+package strategy
+
+import (
+	"database/sql"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// Candle mirrors one row of ohlc_aggregator's candles table.
+type Candle struct {
+	BucketStart time.Time
+	Open        decimal.Decimal
+	High        decimal.Decimal
+	Low         decimal.Decimal
+	Close       decimal.Decimal
+	Volume      decimal.Decimal
+	TradeCount  int
+}
+
+// HistoryReader fetches recent candles for a token, most recent first.
+type HistoryReader struct {
+	db *sql.DB
+}
+
+// NewHistoryReader wraps db (opened by the caller, same as bot_go's
+// sql.Open("postgres", ...) pattern) for candle reads.
+func NewHistoryReader(db *sql.DB) *HistoryReader {
+	return &HistoryReader{db: db}
+}
+
+// RecentCandles returns up to limit candles for tokenID at interval
+// ("1m", "5m" or "1h"), ordered most recent first.
+func (h *HistoryReader) RecentCandles(tokenID, interval string, limit int) ([]Candle, error) {
+	rows, err := h.db.Query(`
+		SELECT bucket_start, open, high, low, close, volume, trade_count
+		FROM candles
+		WHERE token_id = $1 AND interval = $2
+		ORDER BY bucket_start DESC
+		LIMIT $3
+	`, tokenID, interval, limit)
+	if err != nil {
+		return nil, fmt.Errorf("query candles for %s/%s: %w", tokenID, interval, err)
+	}
+	defer rows.Close()
+
+	var candles []Candle
+	for rows.Next() {
+		var c Candle
+		var open, high, low, close_, volume string
+		if err := rows.Scan(&c.BucketStart, &open, &high, &low, &close_, &volume, &c.TradeCount); err != nil {
+			return nil, fmt.Errorf("scan candle: %w", err)
+		}
+		if c.Open, err = decimal.NewFromString(open); err != nil {
+			return nil, fmt.Errorf("parse open %q: %w", open, err)
+		}
+		if c.High, err = decimal.NewFromString(high); err != nil {
+			return nil, fmt.Errorf("parse high %q: %w", high, err)
+		}
+		if c.Low, err = decimal.NewFromString(low); err != nil {
+			return nil, fmt.Errorf("parse low %q: %w", low, err)
+		}
+		if c.Close, err = decimal.NewFromString(close_); err != nil {
+			return nil, fmt.Errorf("parse close %q: %w", close_, err)
+		}
+		if c.Volume, err = decimal.NewFromString(volume); err != nil {
+			return nil, fmt.Errorf("parse volume %q: %w", volume, err)
+		}
+		candles = append(candles, c)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate candles: %w", err)
+	}
+	return candles, nil
+}
+
+// Quote is a strategy's recommended two-sided market for one token.
+type Quote struct {
+	BidPrice   decimal.Decimal
+	AskPrice   decimal.Decimal
+	SizeShares int64
+}
+
+// VolatilityParams tunes VolatilitySpreadStrategy for one topic. Spreads
+// are expressed in basis points of mid price (100 bps = 1%).
+type VolatilityParams struct {
+	Interval                string // candle interval to sample, e.g. "1m"
+	Window                  int    // number of trailing candles to consider
+	BaseSpreadBps           int64
+	MinSpreadBps            int64
+	MaxSpreadBps            int64
+	BaseSizeShares          int64
+	MinSizeShares           int64
+	VolSpreadMultiplier     decimal.Decimal // spreadBps += multiplier * volatility(%)
+	TradeFrequencyThreshold decimal.Decimal // avg trades/candle above this counts as a spike
+}
+
+// DefaultVolatilityParams is used for any topic without an explicit entry
+// in VolatilitySpreadStrategy's params map.
+func DefaultVolatilityParams() VolatilityParams {
+	return VolatilityParams{
+		Interval:                "1m",
+		Window:                  20,
+		BaseSpreadBps:           50,
+		MinSpreadBps:            20,
+		MaxSpreadBps:            500,
+		BaseSizeShares:          100,
+		MinSizeShares:           10,
+		VolSpreadMultiplier:     decimal.NewFromInt(10),
+		TradeFrequencyThreshold: decimal.NewFromInt(5),
+	}
+}
+
+// VolatilitySpreadStrategy widens spreads and shrinks size when recent
+// realized volatility or trade frequency spikes, and tightens back up
+// during calm periods. Parameters are configured per topic (see the
+// events table's topic column) since different topics trade very
+// differently - sports markets move in bursts around events, while
+// slower-moving markets stay calm for long stretches.
+type VolatilitySpreadStrategy struct {
+	history       *HistoryReader
+	params        map[string]VolatilityParams
+	defaultParams VolatilityParams
+}
+
+// NewVolatilitySpreadStrategy builds a strategy backed by history, using
+// params[topic] when present and defaultParams otherwise.
+func NewVolatilitySpreadStrategy(history *HistoryReader, params map[string]VolatilityParams, defaultParams VolatilityParams) *VolatilitySpreadStrategy {
+	if params == nil {
+		params = make(map[string]VolatilityParams)
+	}
+	return &VolatilitySpreadStrategy{history: history, params: params, defaultParams: defaultParams}
+}
+
+// ParamsForTopic returns the params configured for topic, or
+// s.defaultParams if none were set.
+func (s *VolatilitySpreadStrategy) ParamsForTopic(topic string) VolatilityParams {
+	if p, ok := s.params[topic]; ok {
+		return p
+	}
+	return s.defaultParams
+}
+
+// SetParams configures (or replaces) the params for topic.
+func (s *VolatilitySpreadStrategy) SetParams(topic string, params VolatilityParams) {
+	s.params[topic] = params
+}
+
+// realizedVolatilityPct returns the standard deviation of candle-to-candle
+// close returns, as a percentage (5.0 means 5%). Fewer than two candles
+// means there's nothing to measure yet, so it returns zero.
+func realizedVolatilityPct(candles []Candle) decimal.Decimal {
+	if len(candles) < 2 {
+		return decimal.Zero
+	}
+
+	returns := make([]float64, 0, len(candles)-1)
+	for i := 0; i < len(candles)-1; i++ {
+		curr, _ := candles[i].Close.Float64()
+		prev, _ := candles[i+1].Close.Float64()
+		if prev == 0 {
+			continue
+		}
+		returns = append(returns, (curr-prev)/prev)
+	}
+	if len(returns) == 0 {
+		return decimal.Zero
+	}
+
+	var mean float64
+	for _, r := range returns {
+		mean += r
+	}
+	mean /= float64(len(returns))
+
+	var variance float64
+	for _, r := range returns {
+		variance += (r - mean) * (r - mean)
+	}
+	variance /= float64(len(returns))
+
+	return decimal.NewFromFloat(math.Sqrt(variance) * 100)
+}
+
+// avgTradeCount returns the average trade_count across candles.
+func avgTradeCount(candles []Candle) decimal.Decimal {
+	if len(candles) == 0 {
+		return decimal.Zero
+	}
+	total := 0
+	for _, c := range candles {
+		total += c.TradeCount
+	}
+	return decimal.NewFromInt(int64(total)).Div(decimal.NewFromInt(int64(len(candles))))
+}
+
+// Quote computes a bid/ask/size around midPrice for tokenID, using
+// topic's VolatilityParams and its recent candle history.
+func (s *VolatilitySpreadStrategy) Quote(tokenID, topic string, midPrice decimal.Decimal) (Quote, error) {
+	params := s.ParamsForTopic(topic)
+
+	candles, err := s.history.RecentCandles(tokenID, params.Interval, params.Window)
+	if err != nil {
+		return Quote{}, err
+	}
+
+	return QuoteFromCandles(candles, params, midPrice), nil
+}
+
+// QuoteFromCandles is the pure spread/size computation behind Quote,
+// factored out so callers that already have candle history in hand (e.g.
+// the simulator's synthetic history) can price a quote without needing a
+// HistoryReader backed by a real database.
+func QuoteFromCandles(candles []Candle, params VolatilityParams, midPrice decimal.Decimal) Quote {
+	volatilityPct := realizedVolatilityPct(candles)
+	spreadBps := decimal.NewFromInt(params.BaseSpreadBps).Add(params.VolSpreadMultiplier.Mul(volatilityPct))
+
+	frequencySpike := avgTradeCount(candles).GreaterThan(params.TradeFrequencyThreshold)
+	if frequencySpike {
+		spreadBps = spreadBps.Add(decimal.NewFromInt(params.BaseSpreadBps))
+	}
+
+	minSpread := decimal.NewFromInt(params.MinSpreadBps)
+	maxSpread := decimal.NewFromInt(params.MaxSpreadBps)
+	if spreadBps.LessThan(minSpread) {
+		spreadBps = minSpread
+	}
+	if spreadBps.GreaterThan(maxSpread) {
+		spreadBps = maxSpread
+	}
+
+	halfSpread := midPrice.Mul(spreadBps).Div(decimal.NewFromInt(20000))
+	bid := midPrice.Sub(halfSpread)
+	ask := midPrice.Add(halfSpread)
+
+	// Shrink size proportionally to how far the spread has widened past
+	// its base, down to MinSizeShares in calm-to-chaotic markets.
+	size := params.BaseSizeShares
+	if spreadBps.GreaterThan(decimal.NewFromInt(params.BaseSpreadBps)) && params.BaseSpreadBps > 0 {
+		ratio := decimal.NewFromInt(params.BaseSpreadBps).Div(spreadBps)
+		size = decimal.NewFromInt(params.BaseSizeShares).Mul(ratio).IntPart()
+	}
+	if size < params.MinSizeShares {
+		size = params.MinSizeShares
+	}
+
+	return Quote{BidPrice: bid, AskPrice: ask, SizeShares: size}
+}
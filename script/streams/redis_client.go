@@ -0,0 +1,70 @@
+package streams
+
+import (
+	"crypto/tls"
+	"os"
+	"strings"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// RedisConfig describes how to reach the Redis deployment backing a
+// Producer/Consumer, covering the three topologies our environments use:
+// a single node (dev/local), Sentinel-managed primary/replica (prod), and
+// Cluster (staging's managed Redis). It maps directly onto
+// redis.UniversalOptions, which already picks the right client type for
+// whichever of these is configured.
+type RedisConfig struct {
+	// Addrs is one "host:port" for a single node, the Sentinel addresses
+	// when MasterName is set, or every seed node for a Cluster.
+	Addrs []string
+	// MasterName selects Sentinel mode: NewRedisClient returns a
+	// Sentinel-backed failover client for the named master instead of
+	// connecting to Addrs directly.
+	MasterName string
+	Password   string
+	// DB selects a logical database. Ignored in Cluster mode, where Redis
+	// only has DB 0.
+	DB int
+	// TLS enables TLS with the system cert pool, matching a managed Redis
+	// deployment that only accepts TLS connections.
+	TLS bool
+}
+
+// NewRedisClient builds a redis.UniversalClient for cfg: a Sentinel
+// failover client if MasterName is set, a Cluster client if there are two
+// or more Addrs, or a plain single-node client otherwise. Producer and
+// Consumer only need the Cmdable subset every one of these implements, so
+// callers can switch topologies here without touching call sites.
+func NewRedisClient(cfg RedisConfig) redis.UniversalClient {
+	opts := &redis.UniversalOptions{
+		Addrs:      cfg.Addrs,
+		MasterName: cfg.MasterName,
+		Password:   cfg.Password,
+		DB:         cfg.DB,
+	}
+	if cfg.TLS {
+		opts.TLSConfig = &tls.Config{}
+	}
+	return redis.NewUniversalClient(opts)
+}
+
+// OverrideFromEnv returns a copy of cfg with REDIS_ADDRS (comma-separated
+// host:port list), REDIS_MASTER_NAME and REDIS_TLS ("1"/"true") applied on
+// top of it when set, leaving cfg untouched otherwise. This lets every
+// mock tool keep its hardcoded single-node default while staging can point
+// the same binary at a Sentinel or Cluster deployment purely through
+// environment configuration, the same opt-in-via-env pattern chaos.Config
+// uses.
+func (cfg RedisConfig) OverrideFromEnv() RedisConfig {
+	if raw := os.Getenv("REDIS_ADDRS"); raw != "" {
+		cfg.Addrs = strings.Split(raw, ",")
+	}
+	if name := os.Getenv("REDIS_MASTER_NAME"); name != "" {
+		cfg.MasterName = name
+	}
+	if tlsFlag := os.Getenv("REDIS_TLS"); tlsFlag == "1" || strings.EqualFold(tlsFlag, "true") {
+		cfg.TLS = true
+	}
+	return cfg
+}
@@ -0,0 +1,233 @@
+// Package streams gives producers and consumers of our Redis Stream
+// message queues a shared implementation instead of each tool hand-rolling
+// its own XADD/XReadGroup/XAck plumbing. It only wraps the pieces every
+// consumer already needed by hand: group creation, JSON envelope encoding
+// under a single message key, ack-on-success, and dead-lettering messages
+// that fail repeatedly.
+package streams
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"tracing"
+)
+
+// Producer publishes JSON-encoded payloads to one stream under a single
+// message key, matching the envelope shape (`{msgKey: "<json>"}`) every
+// consumer in this repo already expects. rdb is a redis.UniversalClient
+// rather than *redis.Client so a Producer works unmodified whether it's
+// handed a single-node, Sentinel-backed, or Cluster client - see
+// NewRedisClient.
+type Producer struct {
+	rdb    redis.UniversalClient
+	stream string
+	msgKey string
+}
+
+// NewProducer returns a Producer for stream, encoding each payload under
+// msgKey.
+func NewProducer(rdb redis.UniversalClient, stream, msgKey string) *Producer {
+	return &Producer{rdb: rdb, stream: stream, msgKey: msgKey}
+}
+
+// Send marshals payload as JSON and XADDs it, returning the assigned
+// stream entry ID.
+func (p *Producer) Send(ctx context.Context, payload interface{}) (string, error) {
+	return p.send(ctx, payload, "")
+}
+
+// SendWithTrace is Send, additionally carrying tc alongside the payload
+// under the tracing.HeaderName field, so a consumer on the other end of
+// the stream can pick up the same trace with Consumer.Run.
+func (p *Producer) SendWithTrace(ctx context.Context, payload interface{}, tc tracing.TraceContext) (string, error) {
+	return p.send(ctx, payload, tc.String())
+}
+
+func (p *Producer) send(ctx context.Context, payload interface{}, traceparent string) (string, error) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return "", fmt.Errorf("streams: marshal payload for %s: %w", p.stream, err)
+	}
+
+	values := map[string]interface{}{p.msgKey: string(data)}
+	if traceparent != "" {
+		values[tracing.HeaderName] = traceparent
+	}
+
+	id, err := p.rdb.XAdd(ctx, &redis.XAddArgs{
+		Stream: p.stream,
+		Values: values,
+	}).Result()
+	if err != nil {
+		return "", fmt.Errorf("streams: XAdd to %s: %w", p.stream, err)
+	}
+	return id, nil
+}
+
+// Handler processes one message's raw JSON payload. A non-nil error
+// leaves the message unacked; Consumer.Run retries it up to MaxRetries
+// times before dead-lettering it.
+type Handler func(ctx context.Context, raw []byte) error
+
+// ConsumerConfig configures a Consumer. Stream, MsgKey, Group and
+// Consumer are required; the rest have sane defaults applied by
+// NewConsumer.
+type ConsumerConfig struct {
+	Stream   string
+	MsgKey   string
+	Group    string
+	Consumer string
+
+	// Count and Block tune the XReadGroup call; defaults are 10 messages
+	// and a 2s block, matching what trade_responder already used.
+	Count int64
+	Block time.Duration
+
+	// MaxRetries is how many times a message's Handler may fail before
+	// it is moved to DeadLetterStream (if set) and acked off the
+	// pending list. 0 disables the retry limit (a failing message is
+	// retried forever and never dead-lettered).
+	MaxRetries int
+	// DeadLetterStream receives messages that exceeded MaxRetries,
+	// wrapped in a DeadLetter envelope. Left empty, dead-lettering is
+	// disabled and messages are simply retried forever.
+	DeadLetterStream string
+}
+
+func (cfg ConsumerConfig) withDefaults() ConsumerConfig {
+	if cfg.Count <= 0 {
+		cfg.Count = 10
+	}
+	if cfg.Block <= 0 {
+		cfg.Block = 2 * time.Second
+	}
+	return cfg
+}
+
+// DeadLetter is the envelope written to DeadLetterStream for a message
+// that exceeded MaxRetries.
+type DeadLetter struct {
+	OriginalStream string `json:"original_stream"`
+	MessageID      string `json:"message_id"`
+	Payload        string `json:"payload"`
+	Error          string `json:"error"`
+	Attempts       int    `json:"attempts"`
+}
+
+// Consumer reads a group's pending messages off one stream and dispatches
+// them to a Handler, acking on success and dead-lettering on repeated
+// failure.
+type Consumer struct {
+	rdb      redis.UniversalClient
+	cfg      ConsumerConfig
+	attempts map[string]int
+}
+
+// NewConsumer returns a Consumer for cfg.Stream in cfg.Group.
+func NewConsumer(rdb redis.UniversalClient, cfg ConsumerConfig) *Consumer {
+	return &Consumer{rdb: rdb, cfg: cfg.withDefaults(), attempts: make(map[string]int)}
+}
+
+// EnsureGroup creates the consumer group (and stream) if they don't
+// already exist. It's safe to call on every startup.
+func (c *Consumer) EnsureGroup(ctx context.Context) error {
+	err := c.rdb.XGroupCreateMkStream(ctx, c.cfg.Stream, c.cfg.Group, "0").Err()
+	if err != nil && !strings.Contains(err.Error(), "BUSYGROUP") {
+		return fmt.Errorf("streams: create group %s on %s: %w", c.cfg.Group, c.cfg.Stream, err)
+	}
+	return nil
+}
+
+// Ack acknowledges id, removing it from the group's pending list.
+func (c *Consumer) Ack(ctx context.Context, id string) error {
+	delete(c.attempts, id)
+	return c.rdb.XAck(ctx, c.cfg.Stream, c.cfg.Group, id).Err()
+}
+
+// Run blocks, reading and dispatching messages to handle until ctx is
+// done or handle returns a non-recoverable read error. Each message is
+// acked after a successful handle call; a failing handle call is retried
+// on the next XReadGroup poll (Redis redelivers unacked pending entries)
+// up to MaxRetries times, after which it is dead-lettered (if configured)
+// and acked off the pending list so it stops being redelivered.
+func (c *Consumer) Run(ctx context.Context, handle Handler) error {
+	if err := c.EnsureGroup(ctx); err != nil {
+		return err
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		default:
+		}
+
+		result, err := c.rdb.XReadGroup(ctx, &redis.XReadGroupArgs{
+			Group:    c.cfg.Group,
+			Consumer: c.cfg.Consumer,
+			Streams:  []string{c.cfg.Stream, ">"},
+			Count:    c.cfg.Count,
+			Block:    c.cfg.Block,
+		}).Result()
+		if err != nil {
+			if err == redis.Nil {
+				continue
+			}
+			return fmt.Errorf("streams: XReadGroup on %s: %w", c.cfg.Stream, err)
+		}
+
+		for _, stream := range result {
+			for _, message := range stream.Messages {
+				c.dispatch(ctx, message, handle)
+			}
+		}
+	}
+}
+
+func (c *Consumer) dispatch(ctx context.Context, message redis.XMessage, handle Handler) {
+	raw, ok := message.Values[c.cfg.MsgKey].(string)
+	if !ok {
+		c.deadLetter(ctx, message.ID, "", fmt.Errorf("streams: message missing key %q", c.cfg.MsgKey))
+		return
+	}
+
+	if traceparent, ok := message.Values[tracing.HeaderName].(string); ok {
+		if tc, err := tracing.Parse(traceparent); err == nil {
+			ctx = tracing.ContextWith(ctx, tc)
+		}
+	}
+
+	if err := handle(ctx, []byte(raw)); err != nil {
+		c.attempts[message.ID]++
+		if c.cfg.MaxRetries > 0 && c.attempts[message.ID] >= c.cfg.MaxRetries {
+			c.deadLetter(ctx, message.ID, raw, err)
+		}
+		return
+	}
+
+	if err := c.Ack(ctx, message.ID); err != nil {
+		// The handler already succeeded; a failed ack just means this
+		// message gets redelivered and re-processed next poll.
+		return
+	}
+}
+
+func (c *Consumer) deadLetter(ctx context.Context, id, payload string, cause error) {
+	if c.cfg.DeadLetterStream != "" {
+		dead := NewProducer(c.rdb, c.cfg.DeadLetterStream, c.cfg.MsgKey)
+		entry := DeadLetter{
+			OriginalStream: c.cfg.Stream,
+			MessageID:      id,
+			Payload:        payload,
+			Error:          cause.Error(),
+			Attempts:       c.attempts[id],
+		}
+		_, _ = dead.Send(ctx, entry)
+	}
+	_ = c.Ack(ctx, id)
+}
@@ -0,0 +1,169 @@
+// Package testenv provisions disposable Postgres and Redis containers
+// (via testcontainers-go) pre-loaded with schema and seed data, so
+// integration tests across bot_go and the mock tools can run the whole
+// stack locally without touching the production IPs baked into the
+// tools' sources.
+package testenv
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/testcontainers/testcontainers-go"
+	"github.com/testcontainers/testcontainers-go/modules/postgres"
+	"github.com/testcontainers/testcontainers-go/modules/redis"
+	"github.com/testcontainers/testcontainers-go/wait"
+)
+
+// Env bundles a provisioned Postgres and Redis instance for a test run.
+type Env struct {
+	Postgres *PostgresEnv
+	Redis    *RedisEnv
+}
+
+// PostgresEnv describes a running Postgres container.
+type PostgresEnv struct {
+	container *postgres.PostgresContainer
+	DSN       string
+}
+
+// RedisEnv describes a running Redis container.
+type RedisEnv struct {
+	container *redis.RedisContainer
+	Addr      string
+}
+
+// Config controls what schema/seed data is loaded after the containers
+// come up. SchemaSQL and SeedSQL are executed in order against Postgres.
+type Config struct {
+	PostgresImage string
+	RedisImage    string
+	Database      string
+	User          string
+	Password      string
+	SchemaSQL     []string
+	SeedSQL       []string
+}
+
+// DefaultConfig returns sane defaults matching the schema the scripts expect.
+func DefaultConfig() Config {
+	return Config{
+		PostgresImage: "postgres:15-alpine",
+		RedisImage:    "redis:7-alpine",
+		Database:      "prediction_market",
+		User:          "postgres",
+		Password:      "postgres",
+	}
+}
+
+// Start provisions Postgres and Redis containers and applies the configured
+// schema and seed SQL. Callers must call Env.Close to tear the stack down.
+func Start(ctx context.Context, cfg Config) (*Env, error) {
+	pg, err := startPostgres(ctx, cfg)
+	if err != nil {
+		return nil, fmt.Errorf("start postgres: %w", err)
+	}
+
+	rdb, err := startRedis(ctx, cfg)
+	if err != nil {
+		pg.Close(ctx)
+		return nil, fmt.Errorf("start redis: %w", err)
+	}
+
+	return &Env{Postgres: pg, Redis: rdb}, nil
+}
+
+// Close tears down both containers, best-effort.
+func (e *Env) Close(ctx context.Context) {
+	if e.Redis != nil {
+		e.Redis.Close(ctx)
+	}
+	if e.Postgres != nil {
+		e.Postgres.Close(ctx)
+	}
+}
+
+func startPostgres(ctx context.Context, cfg Config) (*PostgresEnv, error) {
+	container, err := postgres.RunContainer(ctx,
+		testcontainers.WithImage(cfg.PostgresImage),
+		postgres.WithDatabase(cfg.Database),
+		postgres.WithUsername(cfg.User),
+		postgres.WithPassword(cfg.Password),
+		testcontainers.WithWaitStrategy(
+			wait.ForLog("database system is ready to accept connections"),
+		),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	dsn, err := container.ConnectionString(ctx, "sslmode=disable")
+	if err != nil {
+		container.Terminate(ctx)
+		return nil, fmt.Errorf("get connection string: %w", err)
+	}
+
+	env := &PostgresEnv{container: container, DSN: dsn}
+
+	for _, stmt := range cfg.SchemaSQL {
+		if err := env.Exec(ctx, stmt); err != nil {
+			env.Close(ctx)
+			return nil, fmt.Errorf("apply schema: %w", err)
+		}
+	}
+	for _, stmt := range cfg.SeedSQL {
+		if err := env.Exec(ctx, stmt); err != nil {
+			env.Close(ctx)
+			return nil, fmt.Errorf("apply seed data: %w", err)
+		}
+	}
+
+	return env, nil
+}
+
+// Exec runs a single SQL statement against the container using its own
+// short-lived connection, so callers don't need to bring their own
+// database/sql driver import just to load fixtures.
+func (p *PostgresEnv) Exec(ctx context.Context, stmt string) error {
+	code, _, err := p.container.Exec(ctx, []string{
+		"psql", "-U", "postgres", "-d", "postgres", "-c", stmt,
+	})
+	if err != nil {
+		return err
+	}
+	if code != 0 {
+		return fmt.Errorf("psql exited with code %d", code)
+	}
+	return nil
+}
+
+// Close terminates the Postgres container.
+func (p *PostgresEnv) Close(ctx context.Context) {
+	if p.container != nil {
+		p.container.Terminate(ctx)
+	}
+}
+
+func startRedis(ctx context.Context, cfg Config) (*RedisEnv, error) {
+	container, err := redis.RunContainer(ctx,
+		testcontainers.WithImage(cfg.RedisImage),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	addr, err := container.Endpoint(ctx, "")
+	if err != nil {
+		container.Terminate(ctx)
+		return nil, fmt.Errorf("get endpoint: %w", err)
+	}
+
+	return &RedisEnv{container: container, Addr: addr}, nil
+}
+
+// Close terminates the Redis container.
+func (r *RedisEnv) Close(ctx context.Context) {
+	if r.container != nil {
+		r.container.Terminate(ctx)
+	}
+}
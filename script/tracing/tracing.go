@@ -0,0 +1,105 @@
+// Package tracing carries a trace/span id pair across the HTTP calls and
+// Redis stream messages that make up one trade's lifecycle (bot -> API ->
+// trade_responder -> response stream), using the W3C traceparent wire
+// format so the ids already flowing through headers and stream fields can
+// be picked up by a real OpenTelemetry SDK later without changing the
+// wire format anything downstream already reads.
+package tracing
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// HeaderName is the HTTP header and Redis stream field a TraceContext is
+// carried under.
+const HeaderName = "traceparent"
+
+// TraceContext identifies a distributed trace (TraceID) and the span
+// currently executing within it (SpanID).
+type TraceContext struct {
+	TraceID string // 32 hex chars
+	SpanID  string // 16 hex chars
+}
+
+func randomHex(n int) (string, error) {
+	buf := make([]byte, n)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("tracing: generate random id: %w", err)
+	}
+	return hex.EncodeToString(buf), nil
+}
+
+// New starts a new trace with a fresh root span.
+func New() (TraceContext, error) {
+	traceID, err := randomHex(16)
+	if err != nil {
+		return TraceContext{}, err
+	}
+	spanID, err := randomHex(8)
+	if err != nil {
+		return TraceContext{}, err
+	}
+	return TraceContext{TraceID: traceID, SpanID: spanID}, nil
+}
+
+// NewChild derives a new span within the same trace as tc, for use when a
+// trace crosses a service boundary (e.g. the responder picking up a
+// request the bot sent).
+func (tc TraceContext) NewChild() (TraceContext, error) {
+	spanID, err := randomHex(8)
+	if err != nil {
+		return TraceContext{}, err
+	}
+	return TraceContext{TraceID: tc.TraceID, SpanID: spanID}, nil
+}
+
+// String renders tc in W3C traceparent format: "00-<trace-id>-<span-id>-01".
+func (tc TraceContext) String() string {
+	return fmt.Sprintf("00-%s-%s-01", tc.TraceID, tc.SpanID)
+}
+
+// Parse parses a traceparent-formatted string.
+func Parse(s string) (TraceContext, error) {
+	parts := strings.Split(s, "-")
+	if len(parts) != 4 || len(parts[1]) != 32 || len(parts[2]) != 16 {
+		return TraceContext{}, fmt.Errorf("tracing: malformed traceparent %q", s)
+	}
+	return TraceContext{TraceID: parts[1], SpanID: parts[2]}, nil
+}
+
+// InjectHeader sets the traceparent header on an outgoing HTTP request.
+func (tc TraceContext) InjectHeader(h http.Header) {
+	h.Set(HeaderName, tc.String())
+}
+
+// ExtractHeader reads a traceparent header off an incoming request, if present.
+func ExtractHeader(h http.Header) (TraceContext, bool) {
+	v := h.Get(HeaderName)
+	if v == "" {
+		return TraceContext{}, false
+	}
+	tc, err := Parse(v)
+	if err != nil {
+		return TraceContext{}, false
+	}
+	return tc, true
+}
+
+type contextKey struct{}
+
+// ContextWith attaches tc to ctx, so a Redis stream Handler or HTTP
+// handler further down the call stack can pick it up with FromContext.
+func ContextWith(ctx context.Context, tc TraceContext) context.Context {
+	return context.WithValue(ctx, contextKey{}, tc)
+}
+
+// FromContext returns the TraceContext attached to ctx, if any.
+func FromContext(ctx context.Context) (TraceContext, bool) {
+	tc, ok := ctx.Value(contextKey{}).(TraceContext)
+	return tc, ok
+}
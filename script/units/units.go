@@ -0,0 +1,57 @@
+// Package units centralizes the decimal-safe conversions between a
+// prediction market price (0-1), a share count, a USDC amount and the
+// wei-denominated maker/taker amounts the exchange contract signs and
+// settles in. Every caller doing this math inline risks a subtly
+// different rounding rule (truncate vs round, wrong decimals) from every
+// other caller; this package is the one place it's defined.
+package units
+
+import (
+	"math/big"
+
+	"github.com/shopspring/decimal"
+)
+
+// SharesDecimals is the fixed-point precision outcome token (share)
+// amounts use on-chain, matching the CTF exchange's 18-decimal ERC-1155
+// convention.
+const SharesDecimals int32 = 18
+
+// Notional returns the USDC cost of shares outcome shares at price (a
+// 0-1 probability-scaled price), in whole USDC, not token units.
+func Notional(price decimal.Decimal, shares int64) decimal.Decimal {
+	return price.Mul(decimal.NewFromInt(shares))
+}
+
+// ToTokenUnits converts a decimal amount (whole USDC, whole shares, ...)
+// at decimals precision to its raw on-chain integer representation.
+// Fractional amounts beyond decimals are truncated, not rounded, since
+// rounding up would sign for more than the caller actually holds.
+func ToTokenUnits(amount decimal.Decimal, decimals int32) *big.Int {
+	scale := decimal.NewFromInt(10).Pow(decimal.NewFromInt32(decimals))
+	return amount.Mul(scale).Truncate(0).BigInt()
+}
+
+// FromTokenUnits converts a raw on-chain integer amount at decimals
+// precision back to a decimal amount in whole units.
+func FromTokenUnits(raw *big.Int, decimals int32) decimal.Decimal {
+	return decimal.NewFromBigInt(raw, -decimals)
+}
+
+// SharesToTokenUnits converts a whole share count to its wei-denominated
+// on-chain representation (shares * 10^SharesDecimals).
+func SharesToTokenUnits(shares int64) *big.Int {
+	return ToTokenUnits(decimal.NewFromInt(shares), SharesDecimals)
+}
+
+// TakerAmountUnits returns the wei-denominated taker amount for a buy
+// order of shares: the shares themselves, in token units.
+func TakerAmountUnits(shares int64) *big.Int {
+	return SharesToTokenUnits(shares)
+}
+
+// MakerAmountUnits returns the wei-denominated maker amount (USDC paid)
+// for a buy order of shares outcome shares at price.
+func MakerAmountUnits(price decimal.Decimal, shares int64) *big.Int {
+	return ToTokenUnits(Notional(price, shares), SharesDecimals)
+}
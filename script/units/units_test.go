@@ -0,0 +1,151 @@
+package units
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/shopspring/decimal"
+)
+
+func TestToTokenUnits_Rounding(t *testing.T) {
+	cases := []struct {
+		name     string
+		amount   string
+		decimals int32
+		want     string
+	}{
+		{"whole amount", "5", 18, "5000000000000000000"},
+		{"zero", "0", 18, "0"},
+		{"exact fraction", "0.5", 18, "500000000000000000"},
+		{"truncates trailing dust", "1.0000000000000000001", 18, "1000000000000000000"},
+		{"truncates below smallest unit", "0.0000000000000000004", 18, "0"},
+		{"rounds toward zero, not nearest", "0.0000000000000000009", 18, "0"},
+		{"small decimals", "1.999999", 6, "1999999"},
+		{"small decimals truncates", "1.9999994", 6, "1999999"},
+		{"zero decimals is a no-op", "3", 0, "3"},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			amount, err := decimal.NewFromString(c.amount)
+			if err != nil {
+				t.Fatalf("parse amount %q: %v", c.amount, err)
+			}
+			got := ToTokenUnits(amount, c.decimals)
+			want, ok := new(big.Int).SetString(c.want, 10)
+			if !ok {
+				t.Fatalf("parse want %q", c.want)
+			}
+			if got.Cmp(want) != 0 {
+				t.Errorf("ToTokenUnits(%s, %d) = %s, want %s", c.amount, c.decimals, got.String(), want.String())
+			}
+		})
+	}
+}
+
+func TestFromTokenUnits_RoundTrip(t *testing.T) {
+	cases := []struct {
+		raw      string
+		decimals int32
+		want     string
+	}{
+		{"5000000000000000000", 18, "5"},
+		{"0", 18, "0"},
+		{"500000000000000000", 18, "0.5"},
+		{"1999999", 6, "1.999999"},
+	}
+
+	for _, c := range cases {
+		raw, ok := new(big.Int).SetString(c.raw, 10)
+		if !ok {
+			t.Fatalf("parse raw %q", c.raw)
+		}
+		got := FromTokenUnits(raw, c.decimals)
+		want, err := decimal.NewFromString(c.want)
+		if err != nil {
+			t.Fatalf("parse want %q: %v", c.want, err)
+		}
+		if !got.Equal(want) {
+			t.Errorf("FromTokenUnits(%s, %d) = %s, want %s", c.raw, c.decimals, got.String(), want.String())
+		}
+	}
+}
+
+func TestToTokenUnits_ThenFromTokenUnits_LosesOnlySubUnitPrecision(t *testing.T) {
+	// Converting to token units and back should reproduce the original
+	// amount whenever it had no more precision than `decimals` allows.
+	for _, amount := range []string{"0", "1", "0.5", "123.456789", "0.000001"} {
+		dec, err := decimal.NewFromString(amount)
+		if err != nil {
+			t.Fatalf("parse %q: %v", amount, err)
+		}
+		roundTripped := FromTokenUnits(ToTokenUnits(dec, SharesDecimals), SharesDecimals)
+		if !roundTripped.Equal(dec) {
+			t.Errorf("round trip of %s changed value to %s", amount, roundTripped.String())
+		}
+	}
+}
+
+func TestSharesToTokenUnits(t *testing.T) {
+	cases := []struct {
+		shares int64
+		want   string
+	}{
+		{0, "0"},
+		{1, "1000000000000000000"},
+		{100, "100000000000000000000"},
+	}
+	for _, c := range cases {
+		got := SharesToTokenUnits(c.shares)
+		want, _ := new(big.Int).SetString(c.want, 10)
+		if got.Cmp(want) != 0 {
+			t.Errorf("SharesToTokenUnits(%d) = %s, want %s", c.shares, got.String(), c.want)
+		}
+	}
+}
+
+func TestNotional(t *testing.T) {
+	cases := []struct {
+		price  string
+		shares int64
+		want   string
+	}{
+		{"0.5", 10, "5"},
+		{"0.01", 100, "1"},
+		{"0.999", 1, "0.999"},
+		{"1", 0, "0"},
+	}
+	for _, c := range cases {
+		price, err := decimal.NewFromString(c.price)
+		if err != nil {
+			t.Fatalf("parse price %q: %v", c.price, err)
+		}
+		want, err := decimal.NewFromString(c.want)
+		if err != nil {
+			t.Fatalf("parse want %q: %v", c.want, err)
+		}
+		got := Notional(price, c.shares)
+		if !got.Equal(want) {
+			t.Errorf("Notional(%s, %d) = %s, want %s", c.price, c.shares, got.String(), want.String())
+		}
+	}
+}
+
+func TestMakerAmountUnits_MatchesNotionalThenToTokenUnits(t *testing.T) {
+	price := decimal.RequireFromString("0.37")
+	shares := int64(250)
+
+	got := MakerAmountUnits(price, shares)
+	want := ToTokenUnits(Notional(price, shares), SharesDecimals)
+
+	if got.Cmp(want) != 0 {
+		t.Errorf("MakerAmountUnits(%s, %d) = %s, want %s", price, shares, got.String(), want.String())
+	}
+}
+
+func TestTakerAmountUnits_MatchesSharesToTokenUnits(t *testing.T) {
+	shares := int64(42)
+	if got, want := TakerAmountUnits(shares), SharesToTokenUnits(shares); got.Cmp(want) != 0 {
+		t.Errorf("TakerAmountUnits(%d) = %s, want %s", shares, got.String(), want.String())
+	}
+}